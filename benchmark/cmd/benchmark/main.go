@@ -24,6 +24,7 @@ var (
 	verbose    bool
 	buildFirst bool
 	profile    bool
+	parallel   int
 )
 
 func init() {
@@ -34,6 +35,7 @@ func init() {
 	flag.StringVar(&jsonPath, "json-path", "", "Path to save JSON output (defaults to stdout)")
 	flag.BoolVar(&verbose, "verbose", true, "Verbose output")
 	flag.BoolVar(&buildFirst, "build", true, "Build graphql-go-gen before running benchmarks")
+	flag.IntVar(&parallel, "parallel", 1, "Number of test sets to run concurrently with -test-set all")
 	flag.BoolVar(&profile, "profile", false, "Enable CPU/memory profiling (not implemented yet)")
 }
 
@@ -61,6 +63,7 @@ func run() error {
 
 	// Create runner
 	r := runner.NewRunner(outputDir, keepFiles, verbose)
+	r.SetParallel(parallel)
 
 	// Build graphql-go-gen if requested
 	if buildFirst {
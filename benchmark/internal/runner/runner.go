@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/jzeiders/graphql-go-gen/benchmark/internal/generator"
@@ -24,11 +25,28 @@ type BenchmarkResult struct {
 	Errors         []error
 }
 
+// Benchmark is a named test set and the generator of its files
+type Benchmark struct {
+	Name      string
+	Generator generator.Generator
+}
+
+// DefaultBenchmarks returns the tiny, mid and large test sets
+func DefaultBenchmarks() []Benchmark {
+	return []Benchmark{
+		{"tiny-ts", generator.NewTinyGenerator()},
+		{"mid-ts", generator.NewMidGenerator()},
+		{"large-ts", generator.NewLargeGenerator()},
+	}
+}
+
 type Runner struct {
 	outputDir   string
 	keepFiles   bool
 	verbose     bool
 	graphqlPath string
+	// parallel is the number of test sets run at once
+	parallel int
 }
 
 func NewRunner(outputDir string, keepFiles, verbose bool) *Runner {
@@ -50,7 +68,19 @@ func NewRunner(outputDir string, keepFiles, verbose bool) *Runner {
 		keepFiles:   keepFiles,
 		verbose:     verbose,
 		graphqlPath: graphqlPath,
+		parallel:    1,
+	}
+}
+
+// SetParallel sets how many test sets RunAll runs at once. Each set has its
+// own directory under the output directory; values below 1 run them one at
+// a time. Memory is measured for the whole process, so MemoryUsed of sets
+// run concurrently includes the others'.
+func (r *Runner) SetParallel(n int) {
+	if n < 1 {
+		n = 1
 	}
+	r.parallel = n
 }
 
 func (r *Runner) Run(ctx context.Context, name string, gen generator.Generator) (*BenchmarkResult, error) {
@@ -136,38 +166,63 @@ func (r *Runner) Run(ctx context.Context, name string, gen generator.Generator)
 }
 
 func (r *Runner) RunAll(ctx context.Context) ([]*BenchmarkResult, error) {
-	benchmarks := []struct {
-		name string
-		gen  generator.Generator
-	}{
-		{"tiny-ts", generator.NewTinyGenerator()},
-		{"mid-ts", generator.NewMidGenerator()},
-		{"large-ts", generator.NewLargeGenerator()},
+	return r.RunBenchmarks(ctx, DefaultBenchmarks())
+}
+
+// RunBenchmarks runs the test sets, up to the runner's parallel setting at
+// once, and returns their results in the order of benchmarks. It returns
+// once every set has finished and cleaned up its directory, also when ctx
+// is canceled.
+func (r *Runner) RunBenchmarks(ctx context.Context, benchmarks []Benchmark) ([]*BenchmarkResult, error) {
+	results := make([]*BenchmarkResult, len(benchmarks))
+
+	sem := make(chan struct{}, r.parallel)
+	var wg sync.WaitGroup
+	for i, bm := range benchmarks {
+		// Sets not started when interrupted are skipped
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if err := ctx.Err(); err != nil {
+			results[i] = &BenchmarkResult{Name: bm.Name, Errors: []error{err}}
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = r.runBenchmark(ctx, bm)
+		}()
 	}
+	wg.Wait()
 
-	results := make([]*BenchmarkResult, 0, len(benchmarks))
+	return results, nil
+}
 
-	for _, bm := range benchmarks {
+// runBenchmark runs one test set, turning a failure into the errors of its
+// result
+func (r *Runner) runBenchmark(ctx context.Context, bm Benchmark) *BenchmarkResult {
+	if r.parallel == 1 {
 		r.log("\n" + strings.Repeat("=", 60))
-		r.log("Running benchmark: %s", bm.name)
+		r.log("Running benchmark: %s", bm.Name)
 		r.log(strings.Repeat("=", 60))
+	} else {
+		r.log("Running benchmark: %s", bm.Name)
+	}
 
-		result, err := r.Run(ctx, bm.name, bm.gen)
-		if err != nil {
-			r.log("ERROR: %v", err)
-			// Still add the result even if there was an error
-			if result == nil {
-				result = &BenchmarkResult{
-					Name:   bm.name,
-					Errors: []error{err},
-				}
+	result, err := r.Run(ctx, bm.Name, bm.Generator)
+	if err != nil {
+		r.log("ERROR: %v", err)
+		// Still add the result even if there was an error
+		if result == nil {
+			result = &BenchmarkResult{
+				Name:   bm.Name,
+				Errors: []error{err},
 			}
 		}
-
-		results = append(results, result)
 	}
-
-	return results, nil
+	return result
 }
 
 func (r *Runner) BuildGenerator() error {
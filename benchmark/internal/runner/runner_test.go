@@ -0,0 +1,119 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/jzeiders/graphql-go-gen/benchmark/internal/generator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGenerator writes a fixed number of source files, each holding one
+// query per line
+type fakeGenerator struct {
+	*generator.BaseGenerator
+	files int
+}
+
+func newFakeGenerator(files int) *fakeGenerator {
+	return &fakeGenerator{BaseGenerator: generator.NewBaseGenerator(1), files: files}
+}
+
+func (g *fakeGenerator) Generate(ctx context.Context, dir string) error {
+	for i := 0; i < g.files; i++ {
+		content := strings.Repeat(fmt.Sprintf("const q%d = gql`query Q%d { ping }`;\n", i, i), i+1)
+		if err := g.WriteFile(filepath.Join(dir, "src", fmt.Sprintf("file%d.ts", i)), content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newTestRunner returns a runner whose graphql-go-gen is a script writing
+// the output file the runner checks for
+func newTestRunner(t *testing.T) *Runner {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake generator is a shell script")
+	}
+
+	script := filepath.Join(t.TempDir(), "graphql-go-gen")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\nmkdir -p src/generated && echo 'export {};' > src/generated/graphql.ts\n"), 0755))
+
+	r := NewRunner(t.TempDir(), false, false)
+	r.graphqlPath = script
+	return r
+}
+
+func TestRunner_ParallelMatchesSequential(t *testing.T) {
+	benchmarks := func() []Benchmark {
+		return []Benchmark{
+			{"tiny-ts", newFakeGenerator(2)},
+			{"mid-ts", newFakeGenerator(20)},
+			{"large-ts", newFakeGenerator(50)},
+		}
+	}
+	// Timings and memory differ between runs
+	summarize := func(results []*BenchmarkResult) []BenchmarkResult {
+		summaries := make([]BenchmarkResult, len(results))
+		for i, result := range results {
+			require.NotNil(t, result)
+			assert.Empty(t, result.Errors, result.Name)
+			summaries[i] = BenchmarkResult{
+				Name:      result.Name,
+				FileCount: result.FileCount,
+				TagCount:  result.TagCount,
+				TotalLOC:  result.TotalLOC,
+			}
+		}
+		return summaries
+	}
+
+	sequential := newTestRunner(t)
+	sequentialResults, err := sequential.RunBenchmarks(context.Background(), benchmarks())
+	require.NoError(t, err)
+
+	parallel := newTestRunner(t)
+	parallel.SetParallel(3)
+	parallelResults, err := parallel.RunBenchmarks(context.Background(), benchmarks())
+	require.NoError(t, err)
+
+	want := summarize(sequentialResults)
+	assert.Equal(t, []string{"tiny-ts", "mid-ts", "large-ts"}, []string{want[0].Name, want[1].Name, want[2].Name})
+	assert.Equal(t, 20, want[1].FileCount)
+	assert.Equal(t, 210, want[1].TagCount)
+	assert.Equal(t, want, summarize(parallelResults), "results keep the order of the test sets")
+
+	entries, err := os.ReadDir(parallel.outputDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "test set directories are cleaned up")
+}
+
+func TestRunner_CanceledRunCleansUp(t *testing.T) {
+	r := newTestRunner(t)
+	r.SetParallel(2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err := r.RunBenchmarks(ctx, []Benchmark{
+		{"tiny-ts", newFakeGenerator(2)},
+		{"mid-ts", newFakeGenerator(20)},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, results, 2)
+	for _, result := range results {
+		assert.NotEmpty(t, result.Errors, result.Name)
+	}
+	entries, err := os.ReadDir(r.outputDir)
+	if !os.IsNotExist(err) {
+		require.NoError(t, err)
+	}
+	assert.Empty(t, entries)
+}
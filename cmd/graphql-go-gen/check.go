@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jzeiders/graphql-go-gen/internal/codegen"
+	"github.com/jzeiders/graphql-go-gen/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Verify that generated files are up to date",
+	Long: `Run the generation pipeline in memory and compare every output with the file
+on disk. Exits non-zero when a file is missing or differs, e.g. in CI after a
+schema or document change that was not regenerated. With --quiet only the
+stale paths are printed.`,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configPath, err := resolveConfigPath()
+		if err != nil {
+			return err
+		}
+
+		cfg, err := loadConfig(configPath)
+		if err != nil {
+			return err
+		}
+
+		return runCheck(context.Background(), cfg, cmd.OutOrStdout())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+}
+
+// staleOutput is a generated file whose content on disk is out of date
+type staleOutput struct {
+	path    string
+	missing bool
+	// added and removed count the lines that differ; firstLine is the first
+	// line that differs
+	added     int
+	removed   int
+	firstLine int
+}
+
+// runCheck generates into memory and reports the outputs that differ from
+// the files on disk
+func runCheck(ctx context.Context, cfg *config.Config, out io.Writer) error {
+	writer := codegen.NewMemoryFileWriter()
+	for _, project := range configProjects(cfg) {
+		gen, err := newGenerator(project.config)
+		if err != nil {
+			return err
+		}
+		gen.writer = writer
+		gen.quiet = true
+
+		if err := gen.Generate(ctx); err != nil {
+			return project.wrap(err)
+		}
+	}
+
+	stale, err := findStaleOutputs(writer.Files())
+	if err != nil {
+		return err
+	}
+
+	for _, output := range stale {
+		path := displayPath(output.path)
+		switch {
+		case quiet:
+			fmt.Fprintln(out, path)
+		case output.missing:
+			fmt.Fprintf(out, "  missing:  %s\n", path)
+		default:
+			fmt.Fprintf(out, "  modified: %s (+%d -%d lines, first change at line %d)\n",
+				path, output.added, output.removed, output.firstLine)
+		}
+	}
+
+	if len(stale) > 0 {
+		return fmt.Errorf("%d of %d generated file(s) out of date; run graphql-go-gen generate", len(stale), len(writer.Paths()))
+	}
+	if !quiet {
+		fmt.Fprintf(out, "All %d generated file(s) are up to date\n", len(writer.Paths()))
+	}
+	return nil
+}
+
+// findStaleOutputs compares generated content with the files on disk
+func findStaleOutputs(files map[string][]byte) ([]staleOutput, error) {
+	var stale []staleOutput
+	for path, generated := range files {
+		existing, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			stale = append(stale, staleOutput{path: path, missing: true})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		if bytes.Equal(existing, generated) {
+			continue
+		}
+
+		added, removed, firstLine := lineDiff(existing, generated)
+		stale = append(stale, staleOutput{path: path, added: added, removed: removed, firstLine: firstLine})
+	}
+
+	sort.Slice(stale, func(i, j int) bool { return stale[i].path < stale[j].path })
+	return stale, nil
+}
+
+// lineDiff counts the lines only in new (added) and only in old (removed),
+// and returns the first line where the two differ
+func lineDiff(old, new []byte) (added, removed, firstLine int) {
+	oldLines := strings.Split(string(old), "\n")
+	newLines := strings.Split(string(new), "\n")
+
+	firstLine = 1
+	for firstLine <= len(oldLines) && firstLine <= len(newLines) && oldLines[firstLine-1] == newLines[firstLine-1] {
+		firstLine++
+	}
+
+	counts := make(map[string]int)
+	for _, line := range oldLines {
+		counts[line]++
+	}
+	for _, line := range newLines {
+		if counts[line] > 0 {
+			counts[line]--
+		} else {
+			added++
+		}
+	}
+	for _, remaining := range counts {
+		removed += remaining
+	}
+	return added, removed, firstLine
+}
+
+// displayPath shortens a path relative to the working directory
+func displayPath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
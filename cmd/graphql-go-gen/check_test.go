@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunCheck(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	writeFile("schema.graphql", `type Query { user: User } type User { id: ID! name: String! }`)
+	writeFile("user.graphql", `query GetUser { user { id } }`)
+	writeFile("graphql-go-gen.yaml", `
+schema:
+  - path: schema.graphql
+documents:
+  include:
+    - "*.graphql"
+generates:
+  types.ts:
+    plugins:
+      - typescript-operations
+`)
+
+	cfg, err := loadConfig(filepath.Join(dir, "graphql-go-gen.yaml"))
+	require.NoError(t, err)
+	output := filepath.Join(dir, "types.ts")
+
+	defer func() { quiet = false }()
+
+	t.Run("missing output", func(t *testing.T) {
+		var out bytes.Buffer
+		err := runCheck(context.Background(), cfg, &out)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "1 of 1 generated file(s) out of date")
+		assert.Contains(t, out.String(), "missing:  "+output)
+	})
+
+	gen, err := newGenerator(cfg)
+	require.NoError(t, err)
+	gen.quiet = true
+	require.NoError(t, gen.Generate(context.Background()))
+
+	t.Run("up to date", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, runCheck(context.Background(), cfg, &out))
+		assert.Equal(t, "All 1 generated file(s) are up to date\n", out.String())
+	})
+
+	t.Run("does not write outputs", func(t *testing.T) {
+		writeFile("user.graphql", `query GetUser { user { id name } }`)
+		before, err := os.ReadFile(output)
+		require.NoError(t, err)
+
+		var out bytes.Buffer
+		err = runCheck(context.Background(), cfg, &out)
+		require.Error(t, err)
+		assert.Contains(t, out.String(), "modified: "+output+" (+")
+
+		after, err := os.ReadFile(output)
+		require.NoError(t, err)
+		assert.Equal(t, before, after)
+	})
+
+	t.Run("quiet prints only stale paths", func(t *testing.T) {
+		quiet = true
+		defer func() { quiet = false }()
+
+		var out bytes.Buffer
+		require.Error(t, runCheck(context.Background(), cfg, &out))
+		assert.Equal(t, output+"\n", out.String())
+	})
+}
+
+func TestLineDiff(t *testing.T) {
+	added, removed, firstLine := lineDiff([]byte("a\nb\nc\n"), []byte("a\nB\nc\nd\n"))
+	assert.Equal(t, 2, added)
+	assert.Equal(t, 1, removed)
+	assert.Equal(t, 2, firstLine)
+}
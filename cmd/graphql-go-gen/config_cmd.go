@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jzeiders/graphql-go-gen/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the resolved configuration",
+}
+
+var configExplainCmd = &cobra.Command{
+	Use:   "explain <key.path>",
+	Short: "Show how a config value was resolved",
+	Long: `Print the final value of a config key and each stage that set it:
+built-in default, config file, environment variable expansion and relative
+path resolution. Keys are dot-separated, e.g. schema.0.url or documents.include.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configPath, err := resolveConfigPath()
+		if err != nil {
+			return err
+		}
+		origin := "discovered"
+		if cfgFile != "" {
+			origin = "--config"
+		}
+
+		explanation, err := config.Explain(configPath, args[0])
+		if err != nil {
+			return fmt.Errorf("explaining %s: %w", args[0], err)
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "config: %s (%s)\n", configPath, origin)
+		return writeExplanation(cmd.OutOrStdout(), explanation)
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configExplainCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// writeExplanation prints the final value followed by one line per stage
+func writeExplanation(w io.Writer, explanation *config.Explanation) error {
+	if !explanation.Found {
+		return fmt.Errorf("%s is not set", explanation.Key)
+	}
+
+	fmt.Fprintf(w, "%s = %s\n", explanation.Key, formatConfigValue(explanation.Value))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, step := range explanation.Steps {
+		line := fmt.Sprintf("  %s\t%s", step.Source, formatConfigValue(step.Value))
+		if step.Detail != "" {
+			line += "\t(" + step.Detail + ")"
+		}
+		fmt.Fprintln(tw, line)
+	}
+	return tw.Flush()
+}
+
+func formatConfigValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return strings.TrimSpace(string(data))
+}
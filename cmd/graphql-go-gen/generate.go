@@ -1,11 +1,18 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/jzeiders/graphql-go-gen/internal/codegen"
 	// Import the new plugins
@@ -13,11 +20,15 @@ import (
 	tdn_plugin "github.com/jzeiders/graphql-go-gen/pkg/plugins/typed_document_node"
 	ts_plugin "github.com/jzeiders/graphql-go-gen/pkg/plugins/typescript"
 	ts_ops_plugin "github.com/jzeiders/graphql-go-gen/pkg/plugins/typescript_operations"
+	ts_resolvers_plugin "github.com/jzeiders/graphql-go-gen/pkg/plugins/typescript_resolvers"
 
 	// Import additional plugins for client preset
 	add_plugin "github.com/jzeiders/graphql-go-gen/pkg/plugins/add"
+	apollo_ops_plugin "github.com/jzeiders/graphql-go-gen/pkg/plugins/apollo_operations"
 	fragment_plugin "github.com/jzeiders/graphql-go-gen/pkg/plugins/fragment_masking"
+	hooks_plugin "github.com/jzeiders/graphql-go-gen/pkg/plugins/framework_hooks"
 	gql_tag_plugin "github.com/jzeiders/graphql-go-gen/pkg/plugins/gql_tag_operations"
+	op_docs_plugin "github.com/jzeiders/graphql-go-gen/pkg/plugins/operation_documents"
 
 	// Import presets
 	"github.com/jzeiders/graphql-go-gen/pkg/presets"
@@ -30,59 +41,195 @@ import (
 	"github.com/jzeiders/graphql-go-gen/pkg/schema"
 )
 
-// runGenerate executes the code generation using gqlparser
+// runGenerate executes the code generation using gqlparser. The projects of
+// a multi-project config are generated one after another, each from its own
+// schema and documents.
 func runGenerate(cfg *config.Config) error {
-	ctx := context.Background()
+	var writer *codegen.MemoryFileWriter
+	if dryRun {
+		writer = codegen.NewMemoryFileWriter()
+	}
+
+	for i, project := range configProjects(cfg) {
+		gen, err := newGenerator(project.config)
+		if err != nil {
+			return err
+		}
+
+		if !quiet && i == 0 {
+			fmt.Println("Registered plugins:", gen.registry.List())
+		}
+		if !quiet && !dryRun && project.name != "" {
+			fmt.Printf("\nProject %s\n", project.name)
+		}
+
+		if writer != nil {
+			gen.writer = writer
+			gen.quiet = true
+		}
+		if err := gen.Generate(context.Background()); err != nil {
+			return project.wrap(err)
+		}
+	}
+
+	if writer != nil {
+		writeDryRun(os.Stdout, writer)
+	}
+	return nil
+}
+
+// configProject is the config of one project of a multi-project config
+type configProject struct {
+	// name is empty for a config without projects
+	name   string
+	config *config.Config
+}
+
+// wrap names the project in an error of its generation
+func (p configProject) wrap(err error) error {
+	if p.name == "" {
+		return err
+	}
+	return fmt.Errorf("project %s: %w", p.name, err)
+}
+
+// configProjects returns the projects of cfg in name order, or cfg itself
+// when it has none. With --target, only the project holding that output is
+// returned when there is one.
+func configProjects(cfg *config.Config) []configProject {
+	if len(cfg.Projects) == 0 {
+		return []configProject{{config: cfg}}
+	}
+
+	projects := make([]configProject, 0, len(cfg.Projects))
+	for _, name := range cfg.ProjectNames() {
+		project := configProject{name: name, config: cfg.ForProject(name)}
+		if target != "" {
+			for outputPath := range project.config.Generates {
+				if sameOutputPath(outputPath, target) {
+					return []configProject{project}
+				}
+			}
+		}
+		projects = append(projects, project)
+	}
+	return projects
+}
+
+// writeDryRun lists the files a dry run would have written
+func writeDryRun(out io.Writer, writer *codegen.MemoryFileWriter) {
+	files := writer.Files()
+	total := 0
+	for _, path := range writer.Paths() {
+		fmt.Fprintf(out, "  %s (%d bytes)\n", displayPath(path), len(files[path]))
+		total += len(files[path])
+	}
+	fmt.Fprintf(out, "Dry run: %d file(s), %d bytes would be written\n", len(files), total)
+}
 
+// writeScalarUsage prints where each custom scalar is used
+func writeScalarUsage(out io.Writer, usages []documents.ScalarUsage) {
+	fmt.Fprintln(out, "Scalar usage:")
+	if len(usages) == 0 {
+		fmt.Fprintln(out, "  no custom scalars")
+		return
+	}
+	for _, usage := range usages {
+		fmt.Fprintf(out, "  %s: %d schema, %d operation usage(s)\n", usage.Scalar, len(usage.Schema), len(usage.Operations))
+		if len(usage.Schema) > 0 {
+			fmt.Fprintf(out, "    schema: %s\n", strings.Join(usage.Schema, ", "))
+		}
+		if len(usage.Operations) > 0 {
+			fmt.Fprintf(out, "    operations: %s\n", strings.Join(usage.Operations, ", "))
+		}
+	}
+}
+
+// newGenerator creates a generator with the built-in plugins registered and
+// the output settings taken from the command line flags
+func newGenerator(cfg *config.Config) (*Generator, error) {
 	// Create plugin registry and register built-in plugins
 	registry := plugin.NewRegistry()
 
 	// Register all built-in plugins
 	if err := registry.Register(ts_plugin.New()); err != nil {
-		return fmt.Errorf("registering typescript plugin: %w", err)
+		return nil, fmt.Errorf("registering typescript plugin: %w", err)
 	}
 
 	if err := registry.Register(ts_ops_plugin.New()); err != nil {
-		return fmt.Errorf("registering typescript-operations plugin: %w", err)
+		return nil, fmt.Errorf("registering typescript-operations plugin: %w", err)
+	}
+
+	if err := registry.Register(ts_resolvers_plugin.New()); err != nil {
+		return nil, fmt.Errorf("registering typescript-resolvers plugin: %w", err)
 	}
 
 	if err := registry.Register(tdn_plugin.New()); err != nil {
-		return fmt.Errorf("registering typed-document-node plugin: %w", err)
+		return nil, fmt.Errorf("registering typed-document-node plugin: %w", err)
 	}
 
 	if err := registry.Register(schema_ast_plugin.New()); err != nil {
-		return fmt.Errorf("registering schema-ast plugin: %w", err)
+		return nil, fmt.Errorf("registering schema-ast plugin: %w", err)
 	}
 
 	if err := registry.Register(add_plugin.New()); err != nil {
-		return fmt.Errorf("registering add plugin: %w", err)
+		return nil, fmt.Errorf("registering add plugin: %w", err)
 	}
 
 	if err := registry.Register(gql_tag_plugin.New()); err != nil {
-		return fmt.Errorf("registering gql-tag-operations plugin: %w", err)
+		return nil, fmt.Errorf("registering gql-tag-operations plugin: %w", err)
 	}
 
 	if err := registry.Register(fragment_plugin.New()); err != nil {
-		return fmt.Errorf("registering fragment-masking plugin: %w", err)
+		return nil, fmt.Errorf("registering fragment-masking plugin: %w", err)
 	}
 
-	// Persisted documents are handled within the client preset, not as a separate plugin
+	if err := registry.Register(op_docs_plugin.New()); err != nil {
+		return nil, fmt.Errorf("registering operation-documents plugin: %w", err)
+	}
 
-	if !quiet {
-		fmt.Println("Registered plugins:", registry.List())
+	if err := registry.Register(apollo_ops_plugin.New()); err != nil {
+		return nil, fmt.Errorf("registering apollo-operations plugin: %w", err)
 	}
 
-	// Create and run generator
-	gen := &Generator{
-		config:   cfg,
-		registry: registry,
-		quiet:    quiet,
-		verbose:  verbose,
+	if err := registry.Register(hooks_plugin.New()); err != nil {
+		return nil, fmt.Errorf("registering framework-hooks plugin: %w", err)
 	}
 
-	return gen.Generate(ctx)
+	// Persisted documents are handled within the client preset, not as a separate plugin
+
+	gen := &Generator{
+		config:          cfg,
+		registry:        registry,
+		quiet:           quiet,
+		verbose:         verbose,
+		strictDocuments: strictDocuments || cfg.Documents.Strict,
+		allowDuplicates: allowDuplicateNames,
+		concurrency:     concurrency,
+		scalarUsage:     showScalarUsage,
+		dependencyGraph: dependencyGraph,
+		force:           force,
+		target:          target,
+
+		fragments:          fragmentNames,
+		fragmentOperations: fragmentOperations,
+	}
+	if useCache {
+		gen.cacheFile = codegen.CacheFileName
+	}
+	if showStats {
+		gen.stats = codegen.NewGenerationStats()
+	}
+	if showChanges {
+		gen.changes = codegen.NewChangeSummary()
+	}
+	return gen, nil
 }
 
+// OutputTransform post-processes the content of a generated file before it
+// is written, e.g. to add a license header or rewrite imports
+type OutputTransform func(path string, content []byte) ([]byte, error)
+
 // Generator handles the code generation process using gqlparser
 type Generator struct {
 	config   *config.Config
@@ -91,34 +238,102 @@ type Generator struct {
 	docs     []*documents.Document
 	quiet    bool
 	verbose  bool
+
+	// strictDocuments fails the run on invalid documents instead of skipping them
+	strictDocuments bool
+	// allowDuplicates skips the check for operations and fragments sharing
+	// a name for --allow-duplicate-names
+	allowDuplicates bool
+
+	// writer receives the generated files; nil writes them to disk
+	writer codegen.FileWriter
+	// concurrency bounds the output targets generated at once; 0 uses one
+	// worker per CPU
+	concurrency int
+	// stats collects per target counts for --stats; nil disables them
+	stats *codegen.GenerationStats
+	// changes collects the types each output file changes for --changes;
+	// nil disables them
+	changes *codegen.ChangeSummary
+	// scalarUsage prints where custom scalars are used for --scalar-usage
+	scalarUsage bool
+	// dependencyGraph is the file the fragment dependency graph is written
+	// to for --dependency-graph; empty disables it
+	dependencyGraph string
+
+	// force rewrites output files whose content did not change
+	force bool
+	// target restricts generation to the output with this path for
+	// --target; empty generates every output
+	target string
+	// fragments restricts the documents to these fragments for --fragment;
+	// fragmentOperations keeps the operations spreading them as well
+	fragments          []string
+	fragmentOperations bool
+
+	// transforms post-process the generated files in order before they are
+	// written
+	transforms []OutputTransform
+
+	// cacheFile is the manifest of --cache; empty disables the cache. Output
+	// transforms are not part of the cache key.
+	cacheFile string
+	// cache holds the manifest loaded from cacheFile during a run
+	cache *codegen.CacheManifest
+
+	// outputFiles collects the files of each output target this run, for
+	// the cache manifest
+	outputFiles   map[string][]string
+	outputFilesMu sync.Mutex
+
+	// written collects the output files written this run for the
+	// afterAllFileWrite hooks
+	written   []string
+	writtenMu sync.Mutex
 }
 
 // Generate runs the complete generation pipeline
 func (g *Generator) Generate(ctx context.Context) error {
+	// Report every misspelled plugin before loading anything
+	if err := g.config.CheckPlugins(g.registry.List()); err != nil {
+		return err
+	}
+
+	outputPaths, err := g.outputPaths()
+	if err != nil {
+		return err
+	}
+	if err := g.checkOutputDirs(outputPaths); err != nil {
+		return err
+	}
+
 	// Step 1: Load schema using gqlparser
 	if !g.quiet {
 		fmt.Println("Loading schema...")
 	}
 
 	schemaLoader := loader.NewUniversalSchemaLoader()
-	sources := make([]schema.Source, len(g.config.Schema))
-
-	for i, src := range g.config.Schema {
-		sources[i] = schema.Source{
-			ID:      schema.SourceID(fmt.Sprintf("source-%d", i)),
-			Kind:    src.Type,
-			Path:    src.Path,
-			URL:     src.URL,
-			Headers: src.Headers,
-		}
+	sources, err := g.schemaSources()
+	if err != nil {
+		return err
 	}
 
 	loadedSchema, err := schemaLoader.Load(ctx, sources)
 	if err != nil {
 		return fmt.Errorf("loading schema: %w", err)
 	}
+	for _, warning := range schemaLoader.Warnings() {
+		fmt.Printf("Warning: %s\n", warning)
+	}
 	g.schema = loadedSchema
 
+	// Catch scalar mappings that are never used, e.g. typos like "DateTiem"
+	if !g.quiet {
+		for _, warning := range g.config.CheckScalarMappings(g.schema.Raw()) {
+			fmt.Printf("Warning: %s\n", warning)
+		}
+	}
+
 	if !g.quiet {
 		fmt.Printf("Schema loaded successfully (hash: %s)\n", g.schema.Hash())
 
@@ -144,31 +359,45 @@ func (g *Generator) Generate(ctx context.Context) error {
 
 	// Load GraphQL documents
 	gqlLoader := loader.NewGraphQLDocumentLoader()
+	gqlLoader.SetResolveImports(g.config.Documents.ResolveImports)
+	if directive := g.config.Documents.RequireGenerateDirective; directive != "" {
+		gqlLoader.SetAllowedDirectives(directive)
+	}
 	gqlDocs, err := gqlLoader.Load(ctx, g.schema, g.config.Documents.Include, g.config.Documents.Exclude)
 	if err != nil {
 		return fmt.Errorf("loading GraphQL documents: %w", err)
 	}
+	docErrs := gqlLoader.Errors()
+	if g.verbose {
+		for _, sourceErr := range docErrs {
+			fmt.Printf("  Warning: invalid GraphQL at %v\n", sourceErr)
+		}
+	}
 
 	// Extract from TypeScript files
-	tsExtractor := pluck.NewTypeScriptExtractor()
+	tsExtractor := pluck.NewTypeScriptExtractorWithOptions(pluck.ExtractorOptions{
+		Tags: g.config.Documents.PluckConfig.Tags,
+	})
 	var tsDocs []*documents.Document
+	// Files matched by several include patterns are extracted once
+	seenFiles := make(map[string]bool)
 
 	for _, pattern := range g.config.Documents.Include {
-		matches, err := filepath.Glob(pattern)
+		matches, err := loader.Glob(pattern)
 		if err != nil {
 			continue
 		}
 
 		for _, path := range matches {
-			if !tsExtractor.CanExtract(path) {
+			if seenFiles[path] || !tsExtractor.CanExtract(path) {
 				continue
 			}
+			seenFiles[path] = true
 
 			// Check if should be excluded
 			shouldSkip := false
 			for _, excludePattern := range g.config.Documents.Exclude {
-				matched, _ := filepath.Match(excludePattern, path)
-				if matched {
+				if loader.MatchGlob(excludePattern, path) {
 					shouldSkip = true
 					break
 				}
@@ -197,20 +426,61 @@ func (g *Generator) Generate(ctx context.Context) error {
 			for _, extractedDoc := range extracted {
 				// Use the V2 loader to validate the extracted GraphQL
 				docLoader := loader.NewGraphQLDocumentLoader()
+				if directive := g.config.Documents.RequireGenerateDirective; directive != "" {
+					docLoader.SetAllowedDirectives(directive)
+				}
 				validatedDoc, err := docLoader.LoadString(ctx, g.schema, extractedDoc.Content, extractedDoc.FilePath)
 				if err != nil {
+					located := documents.LocateErrors(extractedDoc, err)
+					docErrs = append(docErrs, located...)
 					if g.verbose {
-						fmt.Printf("  Warning: invalid GraphQL in %s: %v\n", extractedDoc.FilePath, err)
+						for _, sourceErr := range located {
+							fmt.Printf("  Warning: invalid GraphQL at %v\n", sourceErr)
+						}
 					}
 					continue
 				}
+				validatedDoc.Line, validatedDoc.Column = extractedDoc.Line, extractedDoc.Column
 				tsDocs = append(tsDocs, validatedDoc)
 			}
 		}
 	}
 
-	// Combine all documents
-	g.docs = append(gqlDocs, tsDocs...)
+	if g.strictDocuments && len(docErrs) > 0 {
+		return docErrs
+	}
+
+	// Combine all documents, dropping definitions loaded twice, which
+	// would otherwise be declared twice in the output
+	var dedupeWarnings []string
+	g.docs, dedupeWarnings = documents.DedupeDefinitions(append(gqlDocs, tsDocs...))
+	if !g.quiet {
+		for _, warning := range dedupeWarnings {
+			fmt.Printf("Warning: %s\n", warning)
+		}
+	}
+
+	if directive := g.config.Documents.RequireGenerateDirective; directive != "" {
+		g.docs = documents.SelectTagged(g.docs, directive)
+	}
+
+	if !g.allowDuplicates {
+		if errs := documents.CheckDuplicateNames(g.docs); len(errs) > 0 {
+			return errs
+		}
+	}
+
+	if errs := documents.CheckOperationTypes(g.docs, g.config.Documents.AllowedOperationTypes); len(errs) > 0 {
+		return errs
+	}
+
+	if len(g.fragments) > 0 {
+		selected, err := documents.SelectFragments(g.docs, g.fragments, g.fragmentOperations)
+		if err != nil {
+			return fmt.Errorf("--fragment: %w", err)
+		}
+		g.docs = selected
+	}
 
 	if !g.quiet {
 		fmt.Printf("Found %d documents (%d from .graphql/.gql, %d from TypeScript)\n",
@@ -223,15 +493,73 @@ func (g *Generator) Generate(ctx context.Context) error {
 		fmt.Printf("  Fragments: %d\n", len(allFrags))
 	}
 
+	if g.config.Documents.RequireIdSelection && !g.quiet {
+		for _, warning := range documents.CheckIdSelections(g.schema.Raw(), g.docs) {
+			fmt.Printf("Warning: %s\n", warning)
+		}
+	}
+
 	// Step 3: Generate code for each output target
-	for outputPath, target := range g.config.Generates {
-		if !g.quiet {
-			fmt.Printf("\nGenerating %s...\n", outputPath)
+	// Logs are buffered per target and emitted in output path order
+	logs := codegen.NewTargetLogs(os.Stdout, outputPaths)
+
+	g.written = nil
+	g.outputFiles = make(map[string][]string)
+	g.cache = nil
+	if g.cacheFile != "" && g.writer == nil {
+		g.cache = codegen.LoadCacheManifest(g.cacheFile)
+	}
+	if g.runsHooks() {
+		if err := runHooks(ctx, "beforeAllFileWrite", g.config.Hooks.BeforeAllFileWrite, nil, os.Stdout); err != nil {
+			return err
+		}
+	}
+
+	err = g.generateTargets(ctx, logs, outputPaths)
+	if g.cache != nil {
+		// Targets generated before a failure stay cached
+		if saveErr := g.cache.Save(g.cacheFile); saveErr != nil && err == nil {
+			err = fmt.Errorf("writing %s: %w", g.cacheFile, saveErr)
 		}
+	}
+	if err != nil {
+		logs.Flush()
+		return err
+	}
 
-		if err := g.generateTarget(ctx, outputPath, target); err != nil {
-			return fmt.Errorf("generating %s: %w", outputPath, err)
+	if g.runsHooks() {
+		sort.Strings(g.written)
+		if err := runHooks(ctx, "afterAllFileWrite", g.config.Hooks.AfterAllFileWrite, g.written, os.Stdout); err != nil {
+			return err
+		}
+	}
+
+	if g.stats != nil {
+		fmt.Println()
+		g.stats.Write(os.Stdout, outputPaths, displayPath)
+	}
+
+	if g.changes != nil {
+		fmt.Println()
+		g.changes.Write(os.Stdout, displayPath)
+	}
+
+	if g.dependencyGraph != "" {
+		data, err := documents.BuildDependencyGraph(g.docs).JSON()
+		if err != nil {
+			return fmt.Errorf("encoding dependency graph: %w", err)
 		}
+		if _, err := g.writeOutput(g.dependencyGraph, data); err != nil {
+			return fmt.Errorf("writing %s: %w", g.dependencyGraph, err)
+		}
+		if !g.quiet {
+			fmt.Printf("Generated: %s (%d bytes)\n", g.dependencyGraph, len(data))
+		}
+	}
+
+	if g.scalarUsage {
+		fmt.Println()
+		writeScalarUsage(os.Stdout, documents.CollectScalarUsage(g.schema.Raw(), g.docs))
 	}
 
 	if !g.quiet {
@@ -241,7 +569,213 @@ func (g *Generator) Generate(ctx context.Context) error {
 	return nil
 }
 
-func mergeGenerateResponse(combined map[string][]byte, basePath string, resp *plugin.GenerateResponse) {
+// outputPaths returns the output paths to generate in sorted order: every
+// configured output, or only the one selected with --target
+func (g *Generator) outputPaths() ([]string, error) {
+	outputPaths := make([]string, 0, len(g.config.Generates))
+	for outputPath := range g.config.Generates {
+		outputPaths = append(outputPaths, outputPath)
+	}
+	sort.Strings(outputPaths)
+
+	if g.target == "" {
+		return outputPaths, nil
+	}
+	for _, outputPath := range outputPaths {
+		if sameOutputPath(outputPath, g.target) {
+			return []string{outputPath}, nil
+		}
+	}
+	return nil, fmt.Errorf("no output target %q in the config; targets are: %s", g.target, strings.Join(outputPaths, ", "))
+}
+
+// checkOutputDirs fails early when the directory of an output cannot be
+// created or written, rather than after loading the schema and documents.
+// Runs that do not write files skip the check.
+func (g *Generator) checkOutputDirs(outputPaths []string) error {
+	if g.writer != nil {
+		return nil
+	}
+	for _, outputPath := range outputPaths {
+		// Presets write their files into the output path
+		dir := filepath.Dir(outputPath)
+		if g.config.Generates[outputPath].Preset != "" {
+			dir = outputPath
+		}
+		if err := codegen.CheckOutputDir(dir); err != nil {
+			return fmt.Errorf("output target %s: %w", outputPath, err)
+		}
+	}
+	return nil
+}
+
+// sameOutputPath reports whether a configured output path and a path given
+// on the command line name the same output, ignoring trailing slashes and
+// relative path differences
+func sameOutputPath(outputPath, path string) bool {
+	if outputPath == path {
+		return true
+	}
+	absOutput, err := filepath.Abs(outputPath)
+	if err != nil {
+		return false
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	return absOutput == absPath
+}
+
+// generateTargets generates the output targets on up to g.concurrency
+// workers. Targets write disjoint paths, so they run independently; after a
+// failure no further targets are started, and the error of the first failed
+// target in output path order is returned.
+func (g *Generator) generateTargets(ctx context.Context, logs *codegen.TargetLogs, outputPaths []string) error {
+	workers := g.concurrency
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(outputPaths) {
+		workers = len(outputPaths)
+	}
+
+	errs := make([]error, len(outputPaths))
+	jobs := make(chan int)
+	var failed atomic.Bool
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				outputPath := outputPaths[i]
+				log := logs.Target(outputPath)
+				if !g.quiet {
+					log.Printf("\nGenerating %s...\n", outputPath)
+				}
+
+				err := g.generateCachedTarget(ctx, log, outputPath, g.config.Generates[outputPath])
+				log.Close()
+				if err != nil {
+					errs[i] = fmt.Errorf("generating %s: %w", outputPath, err)
+					failed.Store(true)
+				}
+			}
+		}()
+	}
+
+	for i := range outputPaths {
+		if failed.Load() || ctx.Err() != nil {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// generateCachedTarget generates a target unless the cache manifest holds
+// it with the same key and its files still exist. --force ignores cached
+// entries but still records them.
+func (g *Generator) generateCachedTarget(ctx context.Context, log *codegen.TargetLog, outputPath string, target config.OutputTarget) error {
+	if g.cache == nil {
+		return g.generateTarget(ctx, log, outputPath, target)
+	}
+
+	key, err := codegen.CacheKey(g.schema.Hash(), g.docs, struct {
+		Version            string
+		Commit             string
+		Target             config.OutputTarget
+		Scalars            map[string]string
+		Fragments          []string
+		FragmentOperations bool
+	}{version, commit, target, g.config.Scalars, g.fragments, g.fragmentOperations})
+	if err != nil {
+		// A config the key cannot encode is generated every time
+		g.cache.Forget(outputPath)
+		return g.generateTarget(ctx, log, outputPath, target)
+	}
+
+	if !g.force && g.cache.Fresh(outputPath, key) {
+		if !g.quiet {
+			log.Printf("  Cached: schema, documents and config unchanged\n")
+		}
+		return nil
+	}
+
+	if err := g.generateTarget(ctx, log, outputPath, target); err != nil {
+		g.cache.Forget(outputPath)
+		return err
+	}
+	g.outputFilesMu.Lock()
+	files := g.outputFiles[outputPath]
+	g.outputFilesMu.Unlock()
+	g.cache.Record(outputPath, key, files)
+	return nil
+}
+
+// recordOutputFile adds a file generated for an output target, written or
+// unchanged, for the cache manifest
+func (g *Generator) recordOutputFile(outputPath, path string) {
+	g.outputFilesMu.Lock()
+	defer g.outputFilesMu.Unlock()
+	if g.outputFiles != nil {
+		g.outputFiles[outputPath] = append(g.outputFiles[outputPath], path)
+	}
+}
+
+// schemaSources converts the configured schema sources for the schema loader
+func (g *Generator) schemaSources() ([]schema.Source, error) {
+	sources := make([]schema.Source, len(g.config.Schema))
+
+	for i, src := range g.config.Schema {
+		var timeout time.Duration
+		if src.Timeout != "" {
+			parsed, err := time.ParseDuration(src.Timeout)
+			if err != nil {
+				return nil, fmt.Errorf("schema source %d: invalid timeout %q: %w", i, src.Timeout, err)
+			}
+			timeout = parsed
+		}
+
+		sources[i] = schema.Source{
+			ID:        schema.SourceID(fmt.Sprintf("source-%d", i)),
+			Kind:      src.Type,
+			Path:      src.Path,
+			URL:       src.URL,
+			Headers:   src.Headers,
+			CacheFile: src.CacheFile,
+			Timeout:   timeout,
+
+			RetryableStatusCodes: src.RetryableStatusCodes,
+		}
+		if introspection := src.Introspection; introspection != nil {
+			sources[i].Introspection = schema.IntrospectionOptions{
+				Method:         strings.ToUpper(introspection.Method),
+				OperationName:  introspection.OperationName,
+				PersistedQuery: introspection.PersistedQuery,
+				Variables:      introspection.Variables,
+				Body:           introspection.Body,
+			}
+		}
+	}
+
+	return sources, nil
+}
+
+// mergeGenerateResponse merges the files of a plugin response into combined.
+// Source mappings are merged into mappings and kept in step with the content
+// placed before or replacing them.
+func mergeGenerateResponse(combined map[string][]byte, mappings map[string][]plugin.SourceMapping, basePath string, resp *plugin.GenerateResponse) {
 	if resp == nil {
 		return
 	}
@@ -251,7 +785,14 @@ func mergeGenerateResponse(combined map[string][]byte, basePath string, resp *pl
 		if resolvedPath == "" {
 			continue
 		}
+		before := len(combined[resolvedPath])
 		combined[resolvedPath] = mergeContent(combined[resolvedPath], file.Content, file.Placement)
+		switch strings.ToLower(file.Placement) {
+		case add_plugin.PlacementPrepend:
+			mappings[resolvedPath] = shiftMappings(mappings[resolvedPath], len(combined[resolvedPath])-before)
+		case add_plugin.PlacementContent:
+			delete(mappings, resolvedPath)
+		}
 	}
 
 	for path, content := range resp.Files {
@@ -259,10 +800,93 @@ func mergeGenerateResponse(combined map[string][]byte, basePath string, resp *pl
 		if resolvedPath == "" {
 			continue
 		}
+		before := len(combined[resolvedPath])
 		combined[resolvedPath] = mergeContent(combined[resolvedPath], content, add_plugin.PlacementAppend)
+		if fileMappings := resp.SourceMappings[path]; len(fileMappings) > 0 {
+			mappings[resolvedPath] = append(mappings[resolvedPath], shiftMappings(fileMappings, before)...)
+		}
 	}
 }
 
+// shiftMappings returns a copy of mappings moved by offset bytes
+func shiftMappings(mappings []plugin.SourceMapping, offset int) []plugin.SourceMapping {
+	if len(mappings) == 0 {
+		return nil
+	}
+	shifted := make([]plugin.SourceMapping, len(mappings))
+	for i, m := range mappings {
+		m.Start += offset
+		m.End += offset
+		shifted[i] = m
+	}
+	return shifted
+}
+
+// AddOutputTransform appends a transform run on every generated file after
+// the plugins' output is merged and before it is written. Transforms run in
+// the order they were added, each on the previous one's result.
+func (g *Generator) AddOutputTransform(transform OutputTransform) {
+	g.transforms = append(g.transforms, transform)
+}
+
+// transformOutputs applies the output transforms to the combined files. A
+// file whose content a transform changed loses its source mappings, since
+// their offsets may no longer hold.
+func (g *Generator) transformOutputs(combined map[string][]byte, mappings map[string][]plugin.SourceMapping) error {
+	if len(g.transforms) == 0 {
+		return nil
+	}
+	paths := make([]string, 0, len(combined))
+	for path := range combined {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		content := combined[path]
+		for _, transform := range g.transforms {
+			var err error
+			if content, err = transform(path, content); err != nil {
+				return fmt.Errorf("transforming %s: %w", path, err)
+			}
+		}
+		if !bytes.Equal(content, combined[path]) {
+			delete(mappings, path)
+		}
+		combined[path] = content
+	}
+	return nil
+}
+
+// addModuleMarkers appends `export {}` to the TypeScript files of a target
+// with emitModuleMarker set that have no imports or exports, so they are
+// compiled as modules rather than scripts sharing the global scope
+func addModuleMarkers(combined map[string][]byte, targetConfig map[string]interface{}) {
+	if !getBool(targetConfig, "emitModuleMarker", false) {
+		return
+	}
+	for path, content := range combined {
+		combined[path] = codegen.AddModuleMarker(path, content)
+	}
+}
+
+// addSourceMaps adds the source map sidecar of each combined file with
+// mappings, written next to it with the generated files
+func addSourceMaps(combined map[string][]byte, mappings map[string][]plugin.SourceMapping) error {
+	for path, fileMappings := range mappings {
+		content, ok := combined[path]
+		if !ok || len(fileMappings) == 0 {
+			continue
+		}
+		data, err := codegen.BuildSourceMap(path, content, fileMappings).JSON()
+		if err != nil {
+			return fmt.Errorf("building source map for %s: %w", path, err)
+		}
+		combined[path+codegen.SourceMapSuffix] = data
+	}
+	return nil
+}
+
 func normalizeOutputPath(basePath, rawPath string) string {
 	finalPath := rawPath
 	if finalPath == "" {
@@ -316,13 +940,14 @@ func mergeContent(existing []byte, addition []byte, placement string) []byte {
 }
 
 // generateTarget generates code for a specific output target
-func (g *Generator) generateTarget(ctx context.Context, outputPath string, target config.OutputTarget) error {
+func (g *Generator) generateTarget(ctx context.Context, log *codegen.TargetLog, outputPath string, target config.OutputTarget) error {
 	// Check if using preset
 	if target.Preset != "" {
-		return g.generateWithPreset(ctx, outputPath, target)
+		return g.generateWithPreset(ctx, log, outputPath, target)
 	}
 
 	combinedFiles := make(map[string][]byte)
+	sourceMappings := make(map[string][]plugin.SourceMapping)
 
 	// Run each plugin for this target
 	for _, pluginName := range target.Plugins {
@@ -332,7 +957,7 @@ func (g *Generator) generateTarget(ctx context.Context, outputPath string, targe
 		}
 
 		if !g.quiet {
-			fmt.Printf("  Running plugin: %s\n", pluginName)
+			log.Printf("  Running plugin: %s\n", pluginName)
 		}
 
 		// Create generation request
@@ -355,33 +980,101 @@ func (g *Generator) generateTarget(ctx context.Context, outputPath string, targe
 			return fmt.Errorf("plugin %q: %w", pluginName, err)
 		}
 
-		mergeGenerateResponse(combinedFiles, outputPath, resp)
+		mergeGenerateResponse(combinedFiles, sourceMappings, outputPath, resp)
 
 		// Log warnings
 		for _, warning := range resp.Warnings {
 			if g.verbose {
-				fmt.Printf("  Warning [%s]: %s\n", pluginName, warning)
+				log.Printf("  Warning [%s]: %s\n", pluginName, warning)
 			}
 		}
 	}
 
+	addModuleMarkers(combinedFiles, target.Config)
+	if err := g.transformOutputs(combinedFiles, sourceMappings); err != nil {
+		return err
+	}
+	if err := addSourceMaps(combinedFiles, sourceMappings); err != nil {
+		return err
+	}
+
 	// Write all generated files
-	writer := &codegen.DefaultFileWriter{}
 	for path, content := range combinedFiles {
-		if err := writer.Write(path, content); err != nil {
+		written, err := g.writeOutput(path, content)
+		if err != nil {
 			return fmt.Errorf("writing %s: %w", path, err)
 		}
+		g.recordStats(outputPath, content)
+		g.recordOutputFile(outputPath, path)
 
+		if !written {
+			if !g.quiet {
+				log.Printf("  Unchanged: %s\n", path)
+			}
+			continue
+		}
 		if !g.quiet {
-			fmt.Printf("  Generated: %s (%d bytes)\n", path, len(content))
+			log.Printf("  Generated: %s (%d bytes)\n", path, len(content))
+		}
+		if err := g.afterFileWrite(ctx, log, path); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+// recordStats adds a written file to the stats of its target when --stats
+// is set
+func (g *Generator) recordStats(outputPath string, content []byte) {
+	if g.stats != nil {
+		g.stats.Record(outputPath, content)
+	}
+}
+
+// writeOutput writes a generated file and reports whether it was written.
+// Files on disk that already hold the content are left alone unless force
+// is set, so their modification times only change with their content.
+func (g *Generator) writeOutput(path string, content []byte) (bool, error) {
+	if g.changes != nil {
+		g.changes.Compare(path, content)
+	}
+	if g.force || g.writer != nil {
+		return true, g.fileWriter().Write(path, content)
+	}
+	return codegen.WriteIfChanged(g.fileWriter(), path, content)
+}
+
+// runsHooks reports whether the config hooks run: only when the files are
+// written to disk, not for --dry-run or check
+func (g *Generator) runsHooks() bool {
+	return g.writer == nil
+}
+
+// afterFileWrite records a written output file and runs the
+// afterOneFileWrite hooks on it, logging their output with the target
+func (g *Generator) afterFileWrite(ctx context.Context, log *codegen.TargetLog, path string) error {
+	if !g.runsHooks() {
+		return nil
+	}
+
+	g.writtenMu.Lock()
+	g.written = append(g.written, path)
+	g.writtenMu.Unlock()
+
+	return runHooks(ctx, "afterOneFileWrite", g.config.Hooks.AfterOneFileWrite, []string{path}, log)
+}
+
+// fileWriter returns the writer for generated files
+func (g *Generator) fileWriter() codegen.FileWriter {
+	if g.writer == nil {
+		return &codegen.DefaultFileWriter{}
+	}
+	return g.writer
+}
+
 // generateWithPreset generates code using a preset
-func (g *Generator) generateWithPreset(ctx context.Context, outputPath string, target config.OutputTarget) error {
+func (g *Generator) generateWithPreset(ctx context.Context, log *codegen.TargetLog, outputPath string, target config.OutputTarget) error {
 	// Get the preset
 	preset, err := presets.Get(target.Preset)
 	if err != nil {
@@ -409,17 +1102,18 @@ func (g *Generator) generateWithPreset(ctx context.Context, outputPath string, t
 	}
 
 	if !g.quiet {
-		fmt.Printf("  Using preset: %s (generating %d files)\n", target.Preset, len(generates))
+		log.Printf("  Using preset: %s (generating %d files)\n", target.Preset, len(generates))
 	}
 
 	// Generate each target file
 	for _, gen := range generates {
 		if !g.quiet {
-			fmt.Printf("  Generating: %s\n", gen.Filename)
+			log.Printf("  Generating: %s\n", gen.Filename)
 		}
 
 		// Run plugins for this specific generation
 		combinedFiles := make(map[string][]byte)
+		sourceMappings := make(map[string][]plugin.SourceMapping)
 		for _, pluginName := range gen.Plugins {
 			p, ok := g.registry.Get(pluginName)
 			if !ok {
@@ -446,16 +1140,34 @@ func (g *Generator) generateWithPreset(ctx context.Context, outputPath string, t
 				return fmt.Errorf("plugin %q: %w", pluginName, err)
 			}
 
-			mergeGenerateResponse(combinedFiles, gen.Filename, resp)
+			mergeGenerateResponse(combinedFiles, sourceMappings, gen.Filename, resp)
+		}
+		addModuleMarkers(combinedFiles, target.Config)
+		if err := g.transformOutputs(combinedFiles, sourceMappings); err != nil {
+			return err
+		}
+		if err := addSourceMaps(combinedFiles, sourceMappings); err != nil {
+			return err
 		}
 
-		writer := &codegen.DefaultFileWriter{}
 		for path, data := range combinedFiles {
-			if err := writer.Write(path, data); err != nil {
+			written, err := g.writeOutput(path, data)
+			if err != nil {
 				return fmt.Errorf("writing %s: %w", path, err)
 			}
+			g.recordStats(outputPath, data)
+			g.recordOutputFile(outputPath, path)
+			if !written {
+				if !g.quiet {
+					log.Printf("    Unchanged: %s\n", path)
+				}
+				continue
+			}
 			if !g.quiet {
-				fmt.Printf("    Written: %s (%d bytes)\n", path, len(data))
+				log.Printf("    Written: %s (%d bytes)\n", path, len(data))
+			}
+			if err := g.afterFileWrite(ctx, log, path); err != nil {
+				return err
 			}
 		}
 	}
@@ -465,14 +1177,17 @@ func (g *Generator) generateWithPreset(ctx context.Context, outputPath string, t
 
 // mergeConfig merges two config maps
 func mergeConfig(base map[string]interface{}, overlay interface{}) map[string]interface{} {
-	if base == nil {
-		base = make(map[string]interface{})
+	// Merge into a copy: the base config is shared by the plugins of a file
+	// and may be read by other targets generating concurrently
+	merged := make(map[string]interface{}, len(base))
+	for key, value := range base {
+		merged[key] = value
 	}
 
 	switch v := overlay.(type) {
 	case map[string]interface{}:
 		for key, value := range v {
-			base[key] = value
+			merged[key] = value
 		}
 	default:
 		// If overlay is not a map, use it as the entire config
@@ -481,7 +1196,7 @@ func mergeConfig(base map[string]interface{}, overlay interface{}) map[string]in
 		}
 	}
 
-	return base
+	return merged
 }
 
 // getBool safely gets a boolean value from a map
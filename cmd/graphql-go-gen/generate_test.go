@@ -0,0 +1,1153 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jzeiders/graphql-go-gen/internal/codegen"
+	"github.com/jzeiders/graphql-go-gen/pkg/config"
+	"github.com/jzeiders/graphql-go-gen/pkg/documents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerator_DryRunDoesNotWrite(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	writeFile("schema.graphql", `type Query { user: User } type User { id: ID! name: String! }`)
+	writeFile("user.graphql", `query GetUser { user { id } }`)
+	writeFile("graphql-go-gen.yaml", `
+schema:
+  - path: schema.graphql
+documents:
+  include:
+    - "*.graphql"
+generates:
+  types.ts:
+    plugins:
+      - typescript-operations
+`)
+
+	cfg, err := loadConfig(filepath.Join(dir, "graphql-go-gen.yaml"))
+	require.NoError(t, err)
+
+	gen, err := newGenerator(cfg)
+	require.NoError(t, err)
+	writer := codegen.NewMemoryFileWriter()
+	gen.writer = writer
+	gen.quiet = true
+	require.NoError(t, gen.Generate(context.Background()))
+
+	output := filepath.Join(dir, "types.ts")
+	_, err = os.Stat(output)
+	assert.True(t, os.IsNotExist(err), "dry run wrote %s", output)
+
+	content := writer.Files()[output]
+	require.NotEmpty(t, content)
+	assert.Contains(t, string(content), "GetUserQuery")
+
+	var out bytes.Buffer
+	writeDryRun(&out, writer)
+	assert.Equal(t, fmt.Sprintf("  %s (%d bytes)\nDry run: 1 file(s), %d bytes would be written\n", output, len(content), len(content)), out.String())
+}
+
+func TestGenerator_ConcurrentTargets(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	writeFile("schema.graphql", `type Query { user: User } type User { id: ID! name: String! }`)
+	writeFile("user.graphql", `query GetUser { user { id name } }`)
+
+	load := func(generates string) *config.Config {
+		t.Helper()
+		writeFile("graphql-go-gen.yaml", `
+schema:
+  - path: schema.graphql
+documents:
+  include:
+    - "*.graphql"
+generates:
+`+generates)
+		cfg, err := loadConfig(filepath.Join(dir, "graphql-go-gen.yaml"))
+		require.NoError(t, err)
+		return cfg
+	}
+
+	generate := func(cfg *config.Config, concurrency int) (map[string][]byte, error) {
+		gen, err := newGenerator(cfg)
+		require.NoError(t, err)
+		writer := codegen.NewMemoryFileWriter()
+		gen.writer = writer
+		gen.quiet = true
+		gen.concurrency = concurrency
+		err = gen.Generate(context.Background())
+		return writer.Files(), err
+	}
+
+	t.Run("matches sequential output", func(t *testing.T) {
+		cfg := load(`
+  types.ts:
+    plugins: [typescript]
+  operations.ts:
+    plugins: [typescript-operations]
+  documents.ts:
+    plugins: [typescript-operations, typed-document-node]
+  gql/:
+    preset: client
+`)
+		sequential, err := generate(cfg, 1)
+		require.NoError(t, err)
+		concurrent, err := generate(cfg, 4)
+		require.NoError(t, err)
+
+		assert.Contains(t, concurrent, filepath.Join(dir, "types.ts"))
+		assert.Contains(t, concurrent, filepath.Join(dir, "gql", "graphql.ts"))
+		assert.Equal(t, sequential, concurrent)
+	})
+
+	t.Run("returns the first failed target", func(t *testing.T) {
+		cfg := load(`
+  a.ts:
+    plugins: [typescript]
+  b.ts:
+    plugins: [typed-document-node]
+    config: { persistedDocuments: "b" }
+  c.ts:
+    plugins: [typed-document-node]
+    config: { persistedDocuments: "c" }
+`)
+		_, err := generate(cfg, 3)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "generating "+filepath.Join(dir, "b.ts"))
+		assert.Contains(t, err.Error(), `plugin "typed-document-node": persistedDocuments must be a boolean or an object`)
+	})
+}
+
+func TestGenerator_StatsCountTypesPerTarget(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	writeFile("schema.graphql", `
+type Query { user(id: ID!): User viewer: User }
+type Mutation { rename(name: String!): User }
+type User { id: ID! name: String! }
+`)
+	writeFile("user.graphql", `
+query GetUser($id: ID!) { user(id: $id) { ...UserFields } }
+query GetViewer { viewer { id } }
+mutation Rename($name: String!) { rename(name: $name) { id name } }
+fragment UserFields on User { id name }
+`)
+	writeFile("graphql-go-gen.yaml", `
+schema:
+  - path: schema.graphql
+documents:
+  include:
+    - "*.graphql"
+generates:
+  operations.ts:
+    plugins:
+      - typescript-operations
+  schema.graphql.ts:
+    plugins:
+      - typescript
+`)
+
+	cfg, err := loadConfig(filepath.Join(dir, "graphql-go-gen.yaml"))
+	require.NoError(t, err)
+
+	gen, err := newGenerator(cfg)
+	require.NoError(t, err)
+	gen.writer = codegen.NewMemoryFileWriter()
+	gen.quiet = true
+	gen.stats = codegen.NewGenerationStats()
+	require.NoError(t, gen.Generate(context.Background()))
+
+	operations := gen.stats.Target(filepath.Join(dir, "operations.ts"))
+	assert.Equal(t, 1, operations.Files)
+	assert.Equal(t, codegen.TypeCounts{Operations: 3, Variables: 3, Fragments: 1}, operations.Types)
+
+	// Schema types are not operation, variables or fragment types
+	types := gen.stats.Target(filepath.Join(dir, "schema.graphql.ts"))
+	assert.Equal(t, 1, types.Files)
+	assert.Equal(t, 0, types.Types.Total())
+}
+
+func TestGenerator_ChangeSummary(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	writeFile("schema.graphql", `type Query { user: User } type User { id: ID! name: String! }`)
+	writeFile("user.graphql", "query GetUser { user { id } }\nquery GetLegacyUser { user { id } }\n")
+	writeFile("graphql-go-gen.yaml", `
+schema:
+  - path: schema.graphql
+documents:
+  include:
+    - "*.graphql"
+generates:
+  operations.ts:
+    plugins:
+      - typescript-operations
+`)
+	cfg, err := loadConfig(filepath.Join(dir, "graphql-go-gen.yaml"))
+	require.NoError(t, err)
+
+	generate := func() *codegen.ChangeSummary {
+		gen, err := newGenerator(cfg)
+		require.NoError(t, err)
+		gen.quiet = true
+		gen.changes = codegen.NewChangeSummary()
+		require.NoError(t, gen.Generate(context.Background()))
+		return gen.changes
+	}
+	output := filepath.Join(dir, "operations.ts")
+
+	assert.Equal(t, codegen.TypeChanges{
+		Added: []string{"GetLegacyUserQuery", "GetLegacyUserQueryVariables", "GetUserQuery", "GetUserQueryVariables"},
+	}, generate().File(output), "a new file adds every type")
+
+	writeFile("user.graphql", "query GetUser { user { id name } }\nquery GetProfile { user { name } }\n")
+	assert.Equal(t, codegen.TypeChanges{
+		Added:   []string{"GetProfileQuery", "GetProfileQueryVariables"},
+		Removed: []string{"GetLegacyUserQuery", "GetLegacyUserQueryVariables"},
+		Changed: []string{"GetUserQuery"},
+	}, generate().File(output))
+
+	assert.True(t, generate().File(output).Empty(), "regenerating unchanged documents changes nothing")
+}
+
+func TestGenerator_ScalarUsageReport(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	writeFile("schema.graphql", `
+scalar DateTime
+type Query { user: User }
+type User { id: ID! createdAt: DateTime! updatedAt: DateTime }
+`)
+	writeFile("user.graphql", `query GetUser { user { id createdAt } }`)
+	writeFile("graphql-go-gen.yaml", `
+schema:
+  - path: schema.graphql
+documents:
+  include:
+    - "*.graphql"
+generates:
+  types.ts:
+    plugins:
+      - typescript-operations
+`)
+
+	cfg, err := loadConfig(filepath.Join(dir, "graphql-go-gen.yaml"))
+	require.NoError(t, err)
+
+	gen, err := newGenerator(cfg)
+	require.NoError(t, err)
+	gen.writer = codegen.NewMemoryFileWriter()
+	gen.quiet = true
+	require.NoError(t, gen.Generate(context.Background()))
+
+	var out bytes.Buffer
+	writeScalarUsage(&out, documents.CollectScalarUsage(gen.schema.Raw(), gen.docs))
+	assert.Equal(t, "Scalar usage:\n"+
+		"  DateTime: 2 schema, 1 operation usage(s)\n"+
+		"    schema: User.createdAt, User.updatedAt\n"+
+		"    operations: GetUser: user.createdAt\n", out.String())
+}
+
+func TestGenerator_DependencyGraph(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	writeFile("schema.graphql", `
+type Query { user: User }
+type User { id: ID! name: String friends: [User!]! }
+`)
+	writeFile("user.graphql", `
+query GetUser { user { ...UserFields } }
+fragment UserFields on User { name friends { ...FriendFields } }
+fragment FriendFields on User { id }
+`)
+	writeFile("graphql-go-gen.yaml", `
+schema:
+  - path: schema.graphql
+documents:
+  include:
+    - "*.graphql"
+generates:
+  types.ts:
+    plugins:
+      - typescript-operations
+`)
+
+	cfg, err := loadConfig(filepath.Join(dir, "graphql-go-gen.yaml"))
+	require.NoError(t, err)
+
+	gen, err := newGenerator(cfg)
+	require.NoError(t, err)
+	writer := codegen.NewMemoryFileWriter()
+	gen.writer = writer
+	gen.quiet = true
+	gen.dependencyGraph = filepath.Join(dir, "deps.json")
+	require.NoError(t, gen.Generate(context.Background()))
+
+	data, ok := writer.Files()[gen.dependencyGraph]
+	require.True(t, ok, "dependency graph not written")
+	assert.JSONEq(t, `{
+		"operations": {"GetUser": ["UserFields"]},
+		"fragments": {"UserFields": ["FriendFields"], "FriendFields": []}
+	}`, string(data))
+}
+
+func TestLoadConfig_StrictEnv(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "graphql-go-gen.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+schema:
+  - path: ${UNSET_SCHEMA_DIR}/schema.graphql
+documents:
+  include:
+    - "*.graphql"
+generates:
+  types.ts:
+    plugins:
+      - typescript-operations
+`), 0644))
+
+	defer func() { strictEnv, quiet = false, false }()
+	quiet = true
+
+	_, err := loadConfig(configPath)
+	require.NoError(t, err)
+
+	strictEnv = true
+	_, err = loadConfig(configPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unset environment variables: UNSET_SCHEMA_DIR")
+}
+
+func TestGenerator_Hooks(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	writeFile("schema.graphql", `type Query { user: User } type User { id: ID! name: String! }`)
+	writeFile("user.graphql", `query GetUser { user { id } }`)
+	hookLog := filepath.Join(dir, "hooks.log")
+	writeConfig := func(hooks string) *config.Config {
+		t.Helper()
+		writeFile("graphql-go-gen.yaml", fmt.Sprintf(`
+schema:
+  - path: schema.graphql
+documents:
+  include:
+    - "*.graphql"
+generates:
+  types.ts:
+    plugins:
+      - typescript-operations
+  schema.ts:
+    plugins:
+      - typescript
+hooks:
+%s
+`, hooks))
+		cfg, err := loadConfig(filepath.Join(dir, "graphql-go-gen.yaml"))
+		require.NoError(t, err)
+		return cfg
+	}
+	generate := func(cfg *config.Config) error {
+		gen, err := newGenerator(cfg)
+		require.NoError(t, err)
+		gen.quiet = true
+		return gen.Generate(context.Background())
+	}
+
+	t.Run("runs in order with the written paths", func(t *testing.T) {
+		require.NoError(t, os.RemoveAll(hookLog))
+		cfg := writeConfig(fmt.Sprintf(`
+  beforeAllFileWrite:
+    - echo before >> %[1]s
+  afterOneFileWrite:
+    - echo one >> %[1]s.one
+  afterAllFileWrite:
+    - echo all >> %[1]s`, hookLog))
+		require.NoError(t, generate(cfg))
+
+		data, err := os.ReadFile(hookLog)
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("before\nall %s %s\n", filepath.Join(dir, "schema.ts"), filepath.Join(dir, "types.ts")), string(data))
+
+		// Targets are generated concurrently, so per file lines come in any order
+		data, err = os.ReadFile(hookLog + ".one")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{
+			"one " + filepath.Join(dir, "schema.ts"),
+			"one " + filepath.Join(dir, "types.ts"),
+		}, strings.Split(strings.TrimSpace(string(data)), "\n"))
+	})
+
+	t.Run("failing hook fails the run", func(t *testing.T) {
+		cfg := writeConfig(`
+  afterAllFileWrite:
+    - exit 3`)
+		err := generate(cfg)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `afterAllFileWrite hook "exit 3"`)
+	})
+
+	t.Run("non-fatal hook failure is a warning", func(t *testing.T) {
+		cfg := writeConfig(`
+  afterOneFileWrite:
+    - command: exit 3
+      nonFatal: true`)
+		require.NoError(t, generate(cfg))
+	})
+
+	t.Run("not run on dry run", func(t *testing.T) {
+		require.NoError(t, os.RemoveAll(hookLog))
+		cfg := writeConfig(fmt.Sprintf(`
+  afterAllFileWrite:
+    - echo all >> %s`, hookLog))
+		gen, err := newGenerator(cfg)
+		require.NoError(t, err)
+		gen.quiet = true
+		gen.writer = codegen.NewMemoryFileWriter()
+		require.NoError(t, gen.Generate(context.Background()))
+
+		_, err = os.Stat(hookLog)
+		assert.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestGenerator_SkipsUnchangedOutputs(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	writeFile("schema.graphql", `type Query { user: User } type User { id: ID! name: String! }`)
+	writeFile("user.graphql", `query GetUser { user { id } }`)
+	writeFile("graphql-go-gen.yaml", `
+schema:
+  - path: schema.graphql
+documents:
+  include:
+    - "*.graphql"
+generates:
+  types.ts:
+    plugins:
+      - typescript-operations
+`)
+	cfg, err := loadConfig(filepath.Join(dir, "graphql-go-gen.yaml"))
+	require.NoError(t, err)
+	output := filepath.Join(dir, "types.ts")
+
+	generate := func(force bool) time.Time {
+		t.Helper()
+		gen, err := newGenerator(cfg)
+		require.NoError(t, err)
+		gen.quiet = true
+		gen.force = force
+		require.NoError(t, gen.Generate(context.Background()))
+		info, err := os.Stat(output)
+		require.NoError(t, err)
+		return info.ModTime()
+	}
+
+	generate(false)
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	require.NoError(t, os.Chtimes(output, old, old))
+
+	assert.True(t, generate(false).Equal(old), "unchanged output was rewritten")
+	assert.False(t, generate(true).Equal(old), "--force did not rewrite the output")
+
+	require.NoError(t, os.Chtimes(output, old, old))
+	writeFile("user.graphql", `query GetUser { user { id name } }`)
+	assert.False(t, generate(false).Equal(old), "changed output was not rewritten")
+}
+
+func TestGenerator_Cache(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	writeFile("schema.graphql", `type Query { user: User } type User { id: ID! name: String! }`)
+	writeFile("user.graphql", `query GetUser { user { id } }`)
+	writeFile("graphql-go-gen.yaml", `
+schema:
+  - path: schema.graphql
+documents:
+  include:
+    - "*.graphql"
+generates:
+  types.ts:
+    plugins:
+      - typescript-operations
+`)
+	cfg, err := loadConfig(filepath.Join(dir, "graphql-go-gen.yaml"))
+	require.NoError(t, err)
+	output := filepath.Join(dir, "types.ts")
+	cacheFile := filepath.Join(dir, codegen.CacheFileName)
+
+	// generate reports how many files the run generated, counted by an
+	// output transform, which only runs for targets that are not cached
+	generate := func(force bool) int {
+		t.Helper()
+		gen, err := newGenerator(cfg)
+		require.NoError(t, err)
+		gen.quiet = true
+		gen.force = force
+		gen.cacheFile = cacheFile
+		generated := 0
+		gen.AddOutputTransform(func(path string, content []byte) ([]byte, error) {
+			generated++
+			return content, nil
+		})
+		require.NoError(t, gen.Generate(context.Background()))
+		return generated
+	}
+
+	assert.Equal(t, 1, generate(false), "first run should generate")
+	assert.FileExists(t, cacheFile)
+	assert.Equal(t, 0, generate(false), "unchanged inputs should be cached")
+	assert.Equal(t, 1, generate(true), "--force should generate")
+	assert.Equal(t, 0, generate(false))
+
+	writeFile("user.graphql", `query GetUser { user { id name } }`)
+	assert.Equal(t, 1, generate(false), "changed document should invalidate the cache")
+	assert.Equal(t, 0, generate(false))
+
+	require.NoError(t, os.Remove(output))
+	assert.Equal(t, 1, generate(false), "missing output should invalidate the cache")
+	assert.FileExists(t, output)
+
+	target := cfg.Generates[output]
+	target.Config = map[string]interface{}{"avoidOptionals": true}
+	cfg.Generates[output] = target
+	assert.Equal(t, 1, generate(false), "changed config should invalidate the cache")
+}
+
+func TestGenerator_Fragments(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	writeFile("schema.graphql", `type Query { user: User } type User { id: ID! name: String! email: String! }`)
+	writeFile("user.graphql", `
+query GetUser { user { ...UserName } }
+query GetEmail { user { ...UserEmail } }
+fragment UserName on User { id ...UserId name }
+fragment UserId on User { id }
+fragment UserEmail on User { email }
+`)
+	writeFile("graphql-go-gen.yaml", `
+schema:
+  - path: schema.graphql
+documents:
+  include:
+    - "*.graphql"
+generates:
+  types.ts:
+    plugins:
+      - typescript-operations
+`)
+	cfg, err := loadConfig(filepath.Join(dir, "graphql-go-gen.yaml"))
+	require.NoError(t, err)
+	outputPath := filepath.Join(dir, "types.ts")
+
+	generate := func(fragments []string, withOperations bool) (string, error) {
+		gen, err := newGenerator(cfg)
+		require.NoError(t, err)
+		writer := codegen.NewMemoryFileWriter()
+		gen.writer = writer
+		gen.quiet = true
+		gen.fragments = fragments
+		gen.fragmentOperations = withOperations
+		err = gen.Generate(context.Background())
+		return string(writer.Files()[outputPath]), err
+	}
+
+	t.Run("only selected fragments", func(t *testing.T) {
+		output, err := generate([]string{"UserName"}, false)
+		require.NoError(t, err)
+		assert.Contains(t, output, "export type UserNameFragment")
+		assert.Contains(t, output, "export type UserIdFragment", "spread fragments are kept")
+		assert.NotContains(t, output, "UserEmailFragment")
+		assert.NotContains(t, output, "GetUserQuery")
+		assert.NotContains(t, output, "GetEmailQuery")
+	})
+
+	t.Run("with dependent operations", func(t *testing.T) {
+		output, err := generate([]string{"UserId"}, true)
+		require.NoError(t, err)
+		assert.Contains(t, output, "export type UserIdFragment")
+		assert.Contains(t, output, "export type UserNameFragment")
+		assert.Contains(t, output, "export type GetUserQuery")
+		assert.NotContains(t, output, "UserEmailFragment")
+		assert.NotContains(t, output, "GetEmailQuery")
+	})
+
+	t.Run("unknown fragment", func(t *testing.T) {
+		_, err := generate([]string{"UserPosts"}, false)
+		assert.EqualError(t, err, "--fragment: unknown fragments: UserPosts")
+	})
+}
+
+func TestGenerator_ModuleMarker(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "schema.graphql"), []byte(`type Query { user: User } type User { id: ID! }`), 0644))
+
+	generate := func(targetConfig string) string {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "graphql-go-gen.yaml"), []byte(`
+schema:
+  - path: schema.graphql
+generates:
+  types.ts:
+    plugins:
+      - typescript
+    config:
+`+targetConfig), 0644))
+		cfg, err := loadConfig(filepath.Join(dir, "graphql-go-gen.yaml"))
+		require.NoError(t, err)
+
+		gen, err := newGenerator(cfg)
+		require.NoError(t, err)
+		writer := codegen.NewMemoryFileWriter()
+		gen.writer = writer
+		gen.quiet = true
+		require.NoError(t, gen.Generate(context.Background()))
+		return string(writer.Files()[filepath.Join(dir, "types.ts")])
+	}
+
+	t.Run("added without exports", func(t *testing.T) {
+		output := generate("      noExport: true\n      emitModuleMarker: true\n")
+		assert.NotContains(t, output, "export type")
+		assert.True(t, strings.HasSuffix(output, "\nexport {};\n"), output)
+	})
+
+	t.Run("not added with exports", func(t *testing.T) {
+		output := generate("      emitModuleMarker: true\n")
+		assert.Contains(t, output, "export type User")
+		assert.NotContains(t, output, "export {};")
+	})
+
+	t.Run("off by default", func(t *testing.T) {
+		output := generate("      noExport: true\n")
+		assert.NotContains(t, output, "export {};")
+	})
+}
+
+func TestGenerator_Target(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	writeFile("schema.graphql", `type Query { user: User } type User { id: ID! name: String! }`)
+	writeFile("user.graphql", `query GetUser { user { id } }`)
+	writeFile("graphql-go-gen.yaml", `
+schema:
+  - path: schema.graphql
+documents:
+  include:
+    - "*.graphql"
+generates:
+  operations.ts:
+    plugins:
+      - typescript-operations
+  schema.ts:
+    plugins:
+      - typescript
+`)
+	cfg, err := loadConfig(filepath.Join(dir, "graphql-go-gen.yaml"))
+	require.NoError(t, err)
+
+	generate := func(target string) (*codegen.MemoryFileWriter, error) {
+		gen, err := newGenerator(cfg)
+		require.NoError(t, err)
+		writer := codegen.NewMemoryFileWriter()
+		gen.writer = writer
+		gen.quiet = true
+		gen.target = target
+		return writer, gen.Generate(context.Background())
+	}
+
+	t.Run("runs only the selected target", func(t *testing.T) {
+		writer, err := generate(filepath.Join(dir, "schema.ts"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{filepath.Join(dir, "schema.ts")}, writer.Paths())
+	})
+
+	t.Run("matches relative paths", func(t *testing.T) {
+		wd, err := os.Getwd()
+		require.NoError(t, err)
+		rel, err := filepath.Rel(wd, filepath.Join(dir, "operations.ts"))
+		require.NoError(t, err)
+
+		writer, err := generate(rel)
+		require.NoError(t, err)
+		assert.Equal(t, []string{filepath.Join(dir, "operations.ts")}, writer.Paths())
+	})
+
+	t.Run("unknown target", func(t *testing.T) {
+		writer, err := generate("missing.ts")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `no output target "missing.ts"`)
+		assert.Contains(t, err.Error(), filepath.Join(dir, "schema.ts"))
+		assert.Empty(t, writer.Paths())
+	})
+}
+
+func TestGenerator_SourceMap(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	writeFile("schema.graphql", `type Query { user: User users: [User!]! } type User { id: ID! name: String! }`)
+	writeFile("user.graphql", "query GetUser {\n  user { id }\n}\n")
+	writeFile("users.graphql", "# All users\n\nquery ListUsers {\n  users { id name }\n}\n")
+	writeFile("graphql-go-gen.yaml", `
+schema:
+  - path: schema.graphql
+documents:
+  include:
+    - "*.graphql"
+generates:
+  gen/operations.ts:
+    plugins:
+      - typescript-operations
+      - add
+    config:
+      emitSourceMap: true
+      content: "/* eslint-disable */"
+`)
+	cfg, err := loadConfig(filepath.Join(dir, "graphql-go-gen.yaml"))
+	require.NoError(t, err)
+
+	gen, err := newGenerator(cfg)
+	require.NoError(t, err)
+	writer := codegen.NewMemoryFileWriter()
+	gen.writer = writer
+	gen.quiet = true
+	require.NoError(t, gen.Generate(context.Background()))
+
+	outputPath := filepath.Join(dir, "gen", "operations.ts")
+	files := writer.Files()
+	content := string(files[outputPath])
+	require.True(t, strings.HasPrefix(content, "/* eslint-disable */"), content)
+	data, ok := files[outputPath+".map.json"]
+	require.True(t, ok, "source map should be written next to the output: %v", writer.Paths())
+
+	var sourceMap codegen.SourceMap
+	require.NoError(t, json.Unmarshal(data, &sourceMap))
+	assert.Equal(t, 1, sourceMap.Version)
+	assert.Equal(t, "operations.ts", sourceMap.File)
+	require.Len(t, sourceMap.Mappings, 2)
+
+	expected := []struct {
+		name, source string
+		line         int
+		types        []string
+	}{
+		{"GetUser", "../user.graphql", 1, []string{"export type GetUserQueryVariables", "export type GetUserQuery ="}},
+		{"ListUsers", "../users.graphql", 3, []string{"export type ListUsersQueryVariables", "export type ListUsersQuery ="}},
+	}
+	for i, want := range expected {
+		m := sourceMap.Mappings[i]
+		assert.Equal(t, want.name, m.Name)
+		assert.Equal(t, "query", m.Kind)
+		assert.Equal(t, want.source, m.Source)
+		assert.Equal(t, want.line, m.Line)
+		assert.Equal(t, 1, m.Column)
+
+		generated := content[m.Generated.Start.Offset:m.Generated.End.Offset]
+		for _, typ := range want.types {
+			assert.Contains(t, generated, typ)
+		}
+		lines := strings.Split(content, "\n")
+		assert.True(t, strings.HasPrefix(lines[m.Generated.Start.Line-1][m.Generated.Start.Column-1:], "export type "+want.name),
+			"start of %s should point at its first type", want.name)
+	}
+}
+
+func TestGenerator_ChecksOutputDirs(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	writeFile("schema.graphql", `type Query { hello: String }`)
+	writeFile("blocker", "a file where the output directory should be")
+	writeFile("graphql-go-gen.yaml", `
+schema:
+  - path: missing.graphql
+documents:
+  include:
+    - "*.graphql"
+generates:
+  out/types.ts:
+    plugins:
+      - typescript
+  blocker/gql/types.ts:
+    plugins:
+      - typescript
+`)
+	cfg, err := loadConfig(filepath.Join(dir, "graphql-go-gen.yaml"))
+	require.NoError(t, err)
+
+	gen, err := newGenerator(cfg)
+	require.NoError(t, err)
+	gen.quiet = true
+
+	// The missing schema is never loaded: the output directory fails first
+	err = gen.Generate(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "output target "+filepath.Join(dir, "blocker", "gql", "types.ts"))
+	assert.Contains(t, err.Error(), "is not a directory")
+	_, statErr := os.Stat(filepath.Join(dir, "out"))
+	assert.True(t, os.IsNotExist(statErr), "no output directory should be created")
+}
+
+func TestGenerator_OutputTransforms(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	writeFile("schema.graphql", `type Query { user: User } type User { id: ID! }`)
+	writeFile("user.graphql", `query GetUser { user { id } }`)
+	writeFile("graphql-go-gen.yaml", `
+schema:
+  - path: schema.graphql
+documents:
+  include:
+    - "*.graphql"
+generates:
+  operations.ts:
+    plugins:
+      - add
+      - typescript-operations
+    config:
+      content: "/* @generated marker */"
+      emitSourceMap: true
+`)
+	cfg, err := loadConfig(filepath.Join(dir, "graphql-go-gen.yaml"))
+	require.NoError(t, err)
+	outputPath := filepath.Join(dir, "operations.ts")
+
+	generate := func(transforms ...OutputTransform) (*codegen.MemoryFileWriter, error) {
+		gen, err := newGenerator(cfg)
+		require.NoError(t, err)
+		writer := codegen.NewMemoryFileWriter()
+		gen.writer = writer
+		gen.quiet = true
+		for _, transform := range transforms {
+			gen.AddOutputTransform(transform)
+		}
+		return writer, gen.Generate(context.Background())
+	}
+
+	t.Run("applied in order before writing", func(t *testing.T) {
+		var seen []string
+		uppercase := func(path string, content []byte) ([]byte, error) {
+			seen = append(seen, path)
+			return bytes.Replace(content, []byte("@generated marker"), []byte("@GENERATED MARKER"), 1), nil
+		}
+		header := func(path string, content []byte) ([]byte, error) {
+			assert.Contains(t, string(content), "@GENERATED MARKER", "transforms should see the previous result")
+			return append([]byte("// License: MIT\n"), content...), nil
+		}
+
+		writer, err := generate(uppercase, header)
+		require.NoError(t, err)
+		content := string(writer.Files()[outputPath])
+		assert.True(t, strings.HasPrefix(content, "// License: MIT\n/* @GENERATED MARKER */"), content)
+		assert.NotContains(t, content, "@generated marker")
+		assert.Contains(t, content, "export type GetUserQuery")
+		assert.Equal(t, []string{outputPath}, seen)
+		assert.NotContains(t, writer.Files(), outputPath+".map.json", "source maps of changed files are dropped")
+	})
+
+	t.Run("unchanged files keep their source maps", func(t *testing.T) {
+		identity := func(path string, content []byte) ([]byte, error) {
+			return content, nil
+		}
+		writer, err := generate(identity)
+		require.NoError(t, err)
+		assert.Contains(t, writer.Files(), outputPath+".map.json")
+	})
+
+	t.Run("errors stop the run", func(t *testing.T) {
+		failing := func(path string, content []byte) ([]byte, error) {
+			return nil, fmt.Errorf("rejected")
+		}
+		writer, err := generate(failing)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "transforming "+outputPath+": rejected")
+		assert.Empty(t, writer.Paths())
+	})
+}
+
+func TestGenerator_Projects(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	writeFile("web/schema.graphql", `type Query { viewer: User } type User { id: ID! name: String! }`)
+	writeFile("web/viewer.graphql", `query GetViewer { viewer { ...UserFields } } fragment UserFields on User { id name }`)
+	writeFile("admin/schema.graphql", `type Query { user: User } type User { id: ID! role: String! }`)
+	writeFile("admin/user.graphql", `query GetUser { user { ...UserFields } } fragment UserFields on User { id role }`)
+	writeFile("graphql-go-gen.yaml", `
+projects:
+  web:
+    schema:
+      - path: web/schema.graphql
+    documents:
+      include:
+        - "web/*.graphql"
+      strict: true
+    generates:
+      web/types.ts:
+        plugins:
+          - typescript-operations
+  admin:
+    schema:
+      - path: admin/schema.graphql
+    documents:
+      include:
+        - "admin/*.graphql"
+      strict: true
+    generates:
+      admin/types.ts:
+        plugins:
+          - typescript-operations
+`)
+
+	quiet = true
+	defer func() { quiet = false }()
+
+	load := func() *config.Config {
+		cfg, err := loadConfig(filepath.Join(dir, "graphql-go-gen.yaml"))
+		require.NoError(t, err)
+		return cfg
+	}
+
+	t.Run("generates each project from its own documents", func(t *testing.T) {
+		require.NoError(t, runGenerate(load()))
+
+		web, err := os.ReadFile(filepath.Join(dir, "web/types.ts"))
+		require.NoError(t, err)
+		assert.Contains(t, string(web), "export type UserFieldsFragment = { __typename?: 'User', id: string, name: string };")
+		assert.NotContains(t, string(web), "GetUserQuery")
+
+		admin, err := os.ReadFile(filepath.Join(dir, "admin/types.ts"))
+		require.NoError(t, err)
+		assert.Contains(t, string(admin), "export type UserFieldsFragment = { __typename?: 'User', id: string, role: string };")
+		assert.NotContains(t, string(admin), "GetViewerQuery")
+	})
+
+	t.Run("fragments do not resolve across projects", func(t *testing.T) {
+		writeFile("admin/viewer.graphql", `query GetAdmin { user { ...ViewerFields } }`)
+		writeFile("web/fields.graphql", `fragment ViewerFields on User { id }`)
+		err := runGenerate(load())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "project admin:")
+		assert.Contains(t, err.Error(), "ViewerFields")
+	})
+}
+
+func TestGenerator_UnknownPlugins(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "graphql-go-gen.yaml"), []byte(`
+schema:
+  - path: missing.graphql
+generates:
+  types.ts:
+    plugins:
+      - typescript
+      - typescript-operation
+  api.ts:
+    plugins:
+      - typed-document-nod
+`), 0644))
+	cfg, err := loadConfig(filepath.Join(dir, "graphql-go-gen.yaml"))
+	require.NoError(t, err)
+
+	gen, err := newGenerator(cfg)
+	require.NoError(t, err)
+	gen.quiet = true
+	err = gen.Generate(context.Background())
+	require.Error(t, err)
+
+	// Reported together, before the missing schema is loaded
+	assert.Contains(t, err.Error(), `"typed-document-nod" in `+filepath.Join(dir, "api.ts")+` (did you mean "typed-document-node"?)`)
+	assert.Contains(t, err.Error(), `"typescript-operation" in `+filepath.Join(dir, "types.ts")+` (did you mean "typescript-operations"?)`)
+	assert.NotContains(t, err.Error(), "missing.graphql")
+}
+
+func TestGenerator_DocumentGlobs(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	writeFile("schema.graphql", `type Query { user: User } type User { id: ID! name: String! }`)
+	writeFile("src/app.ts", "const q = gql`query GetUser { user { id } }`;\n")
+	writeFile("src/pages/profile/Profile.tsx", "const q = gql`query GetProfile { user { name } }`;\n")
+	writeFile("src/pages/profile/Profile.test.tsx", "const q = gql`query GetTestUser { user { id } }`;\n")
+	writeFile("graphql-go-gen.yaml", `
+schema:
+  - path: schema.graphql
+documents:
+  include:
+    - "./src/**/*.{ts,tsx}"
+    - "./src/pages/**/*.tsx"
+  exclude:
+    - "**/*.test.tsx"
+generates:
+  types.ts:
+    plugins:
+      - typescript-operations
+`)
+	cfg, err := loadConfig(filepath.Join(dir, "graphql-go-gen.yaml"))
+	require.NoError(t, err)
+
+	gen, err := newGenerator(cfg)
+	require.NoError(t, err)
+	gen.writer = codegen.NewMemoryFileWriter()
+	gen.quiet = true
+	require.NoError(t, gen.Generate(context.Background()))
+
+	var names []string
+	for _, op := range documents.CollectAllOperations(gen.docs) {
+		names = append(names, op.Name)
+	}
+	assert.ElementsMatch(t, []string{"GetUser", "GetProfile"}, names, "nested files are found once and excluded files skipped")
+}
+
+func TestGenerator_DuplicateNames(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	writeFile("schema.graphql", `type Query { user: User } type User { id: ID! name: String! }`)
+	writeFile("user.graphql", "query GetUser { user { ...UserFields } }\nfragment UserFields on User { id }\n")
+	writeFile("profile.graphql", "query GetUser { user { ...UserFields } }\nfragment UserFields on User { name }\n")
+	writeFile("graphql-go-gen.yaml", `
+schema:
+  - path: schema.graphql
+documents:
+  include:
+    - "*.graphql"
+generates:
+  types.ts:
+    plugins:
+      - typescript-operations
+`)
+	cfg, err := loadConfig(filepath.Join(dir, "graphql-go-gen.yaml"))
+	require.NoError(t, err)
+
+	newGen := func() *Generator {
+		gen, err := newGenerator(cfg)
+		require.NoError(t, err)
+		gen.writer = codegen.NewMemoryFileWriter()
+		gen.quiet = true
+		return gen
+	}
+
+	err = newGen().Generate(context.Background())
+	var errs documents.SourceErrors
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs, 2)
+	// Files load in path order, so profile.graphql defines the names first
+	profileFile := filepath.Join(dir, "profile.graphql")
+	assert.Equal(t, filepath.Join(dir, "user.graphql")+`:1:1: in query GetUser: duplicate name "GetUser", first defined at `+profileFile+":1:1", errs[0].Error())
+	assert.Equal(t, filepath.Join(dir, "user.graphql")+`:2:1: in fragment UserFields: duplicate name "UserFields", first defined at `+profileFile+":2:1", errs[1].Error())
+
+	gen := newGen()
+	gen.allowDuplicates = true
+	assert.NoError(t, gen.Generate(context.Background()), "--allow-duplicate-names keeps generating")
+}
+
+func TestGenerator_RequireGenerateDirective(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	writeFile("schema.graphql", `type Query { user: User } type User { id: ID! name: String! }`)
+	writeFile("user.graphql", `
+query GetUser @generate { user { ...UserName } }
+query GetLegacyUser { user { id } }
+fragment UserName on User { name }
+`)
+	writeFile("app.ts", "const q = gql`query GetProfile @generate { user { id } }`;\n")
+	writeFile("graphql-go-gen.yaml", `
+schema:
+  - path: schema.graphql
+documents:
+  include:
+    - "*.graphql"
+    - "*.ts"
+  requireGenerateDirective: generate
+generates:
+  types.ts:
+    plugins:
+      - typescript-operations
+`)
+	cfg, err := loadConfig(filepath.Join(dir, "graphql-go-gen.yaml"))
+	require.NoError(t, err)
+
+	gen, err := newGenerator(cfg)
+	require.NoError(t, err)
+	writer := codegen.NewMemoryFileWriter()
+	gen.writer = writer
+	gen.quiet = true
+	require.NoError(t, gen.Generate(context.Background()))
+
+	var names []string
+	for _, op := range documents.CollectAllOperations(gen.docs) {
+		names = append(names, op.Name)
+	}
+	assert.ElementsMatch(t, []string{"GetUser", "GetProfile"}, names, "untagged operations are not generated")
+
+	content, ok := writer.Files()[filepath.Join(dir, "types.ts")]
+	require.True(t, ok)
+	assert.Contains(t, string(content), "GetUserQuery")
+	assert.Contains(t, string(content), "UserNameFragment", "fragments of tagged operations are generated")
+	assert.NotContains(t, string(content), "GetLegacyUser")
+	assert.NotContains(t, string(content), "@generate")
+}
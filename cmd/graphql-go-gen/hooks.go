@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+
+	"github.com/jzeiders/graphql-go-gen/pkg/config"
+)
+
+// runHooks runs the hooks of a stage in order with paths as their
+// arguments. The first failing hook not marked nonFatal stops the stage and
+// fails the run; nonFatal failures are reported to out.
+func runHooks(ctx context.Context, stage string, hooks []config.Hook, paths []string, out io.Writer) error {
+	for _, hook := range hooks {
+		if err := runHook(ctx, hook, paths, out); err != nil {
+			err = fmt.Errorf("%s hook %q: %w", stage, hook.Command, err)
+			if !hook.NonFatal {
+				return err
+			}
+			fmt.Fprintf(out, "Warning: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// runHook runs the command with sh, appending paths as quoted arguments so
+// file names with spaces reach the command intact. The command inherits the
+// process environment and working directory.
+func runHook(ctx context.Context, hook config.Hook, paths []string, out io.Writer) error {
+	args := append([]string{"-c", hook.Command + ` "$@"`, "sh"}, paths...)
+	cmd := exec.CommandContext(ctx, "sh", args...)
+	cmd.Stdout = out
+	cmd.Stderr = out
+	return cmd.Run()
+}
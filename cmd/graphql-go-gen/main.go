@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/jzeiders/graphql-go-gen/pkg/config"
 	"github.com/spf13/cobra"
@@ -14,6 +15,23 @@ var (
 	cfgFile string
 	verbose bool
 	quiet   bool
+
+	strictEnv bool
+
+	strictDocuments     bool
+	allowDuplicateNames bool
+	dryRun              bool
+	concurrency         int
+	showStats           bool
+	showChanges         bool
+	showScalarUsage     bool
+	dependencyGraph     string
+	force               bool
+	target              string
+	useCache            bool
+
+	fragmentNames      []string
+	fragmentOperations bool
 )
 
 var rootCmd = &cobra.Command{
@@ -29,32 +47,18 @@ var generateCmd = &cobra.Command{
 	Long: `Generate type-safe code from GraphQL schemas and operations.
 Extracts operations from TypeScript/JavaScript and .gql/.graphql files.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var configPath string
-		var cfg *config.Config
-		var err error
-
-		if cfgFile != "" {
-			configPath = cfgFile
-		} else {
-			configPath, err = config.DiscoverConfig("")
-			if err != nil {
-				return fmt.Errorf("discovering config: %w", err)
-			}
+		configPath, err := resolveConfigPath()
+		if err != nil {
+			return err
 		}
 
 		if !quiet {
 			fmt.Printf("Loading config from: %s\n", configPath)
 		}
 
-		// Check if it's a package.json file
-		if filepath.Base(configPath) == "package.json" {
-			cfg, err = config.LoadFromPackageJSON(configPath)
-		} else {
-			cfg, err = config.LoadFile(configPath)
-		}
-
+		cfg, err := loadConfig(configPath)
 		if err != nil {
-			return fmt.Errorf("loading config: %w", err)
+			return err
 		}
 
 		// Use the generator with gqlparser
@@ -62,10 +66,70 @@ Extracts operations from TypeScript/JavaScript and .gql/.graphql files.`,
 	},
 }
 
+// resolveConfigPath returns the --config file or the discovered one
+func resolveConfigPath() (string, error) {
+	if cfgFile != "" {
+		return cfgFile, nil
+	}
+
+	configPath, err := config.DiscoverConfig("")
+	if err != nil {
+		return "", fmt.Errorf("discovering config: %w", err)
+	}
+	return configPath, nil
+}
+
+// loadConfig loads a config file, including the graphql-go-gen key of a
+// package.json
+func loadConfig(configPath string) (*config.Config, error) {
+	var cfg *config.Config
+	var err error
+
+	// Check if it's a package.json file
+	if filepath.Base(configPath) == "package.json" {
+		cfg, err = config.LoadFromPackageJSON(configPath)
+	} else {
+		cfg, err = config.LoadFile(configPath)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+
+	if missing := cfg.MissingEnv(); len(missing) > 0 {
+		if strictEnv {
+			return nil, fmt.Errorf("config references unset environment variables: %s", strings.Join(missing, ", "))
+		}
+		if !quiet {
+			fmt.Printf("Warning: config references unset environment variables, left unexpanded: %s\n", strings.Join(missing, ", "))
+		}
+	}
+	return cfg, nil
+}
+
 func init() {
 	rootCmd.PersistentFlags().StringVarP(&cfgFile, "config", "c", "", "config file (default: auto-discover graphql-go-gen.{ts,js,yaml,yml})")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
 	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "quiet output")
+	rootCmd.PersistentFlags().BoolVar(&strictEnv, "strict-env", false, "fail when the config references an unset ${VAR} environment variable")
+	rootCmd.Flags().BoolVar(&versionJSON, "json", false, "print --version output as JSON")
+
+	cobra.AddTemplateFunc("versionOutput", versionOutput)
+	rootCmd.SetVersionTemplate(`{{versionOutput}}`)
+
+	generateCmd.Flags().BoolVar(&strictDocuments, "strict-documents", false, "fail when any document is invalid instead of skipping it")
+	generateCmd.Flags().BoolVar(&allowDuplicateNames, "allow-duplicate-names", false, "allow operations and fragments sharing a name across documents; the last fragment defined wins")
+	generateCmd.Flags().IntVar(&concurrency, "concurrency", 0, "number of output targets generated in parallel (default: number of CPUs)")
+	generateCmd.Flags().BoolVar(&showStats, "stats", false, "print files, bytes and exported operation, variables and fragment types per output")
+	generateCmd.Flags().BoolVar(&showChanges, "changes", false, "print the operation, variables and fragment types each output file adds, removes or changes compared to the file on disk")
+	generateCmd.Flags().BoolVar(&showScalarUsage, "scalar-usage", false, "print the schema fields and operation selections using each custom scalar")
+	generateCmd.Flags().StringVar(&target, "target", "", "generate only the output with this path from the config's generates section")
+	generateCmd.Flags().BoolVar(&force, "force", false, "rewrite output files even when their content is unchanged")
+	generateCmd.Flags().BoolVar(&useCache, "cache", false, "skip output targets whose schema, documents and config are unchanged since the last run, tracked in .graphql-go-gen-cache.json")
+	generateCmd.Flags().StringSliceVar(&fragmentNames, "fragment", nil, "generate only these fragments and the fragments they spread, e.g. --fragment UserFields,PostFields")
+	generateCmd.Flags().BoolVar(&fragmentOperations, "fragment-operations", false, "with --fragment, also generate the operations that spread the selected fragments")
+	generateCmd.Flags().StringVar(&dependencyGraph, "dependency-graph", "", "write a JSON graph of the fragments each operation and fragment spreads to this file")
+	generateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the files that would be written without touching disk")
 
 	rootCmd.AddCommand(generateCmd)
 }
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+)
+
+var (
+	// commit is set at build time:
+	//   go build -ldflags "-X main.commit=$(git rev-parse --short HEAD)"
+	commit = "unknown"
+
+	versionJSON bool
+)
+
+// versionInfo is the machine-readable output of --version --json
+type versionInfo struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+	Go      string `json:"go"`
+}
+
+func currentVersionInfo() versionInfo {
+	return versionInfo{
+		Version: version,
+		Commit:  commit,
+		Go:      runtime.Version(),
+	}
+}
+
+// formatVersion renders the --version output, as JSON when asJSON is set
+func formatVersion(info versionInfo, asJSON bool) (string, error) {
+	if !asJSON {
+		return fmt.Sprintf("graphql-go-gen version %s\n", info.Version), nil
+	}
+
+	data, err := json.Marshal(info)
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+// versionOutput is used by the root command's version template
+func versionOutput() string {
+	out, err := formatVersion(currentVersionInfo(), versionJSON)
+	if err != nil {
+		return fmt.Sprintf("graphql-go-gen version %s\n", version)
+	}
+	return out
+}
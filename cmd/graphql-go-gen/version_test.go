@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionJSON(t *testing.T) {
+	defer func() { versionJSON = false }()
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"--version", "--json"})
+	defer rootCmd.SetOut(nil)
+	defer rootCmd.SetArgs(nil)
+
+	require.NoError(t, rootCmd.Execute())
+
+	var info map[string]string
+	require.NoError(t, json.Unmarshal(out.Bytes(), &info), "output: %s", out.String())
+	assert.Equal(t, map[string]string{
+		"version": version,
+		"commit":  commit,
+		"go":      runtime.Version(),
+	}, info)
+}
+
+func TestFormatVersion(t *testing.T) {
+	info := versionInfo{Version: "1.2.3", Commit: "abc1234", Go: "go1.22.0"}
+
+	plain, err := formatVersion(info, false)
+	require.NoError(t, err)
+	assert.Equal(t, "graphql-go-gen version 1.2.3\n", plain)
+
+	asJSON, err := formatVersion(info, true)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"version": "1.2.3", "commit": "abc1234", "go": "go1.22.0"}`, asJSON)
+}
@@ -0,0 +1,357 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/jzeiders/graphql-go-gen/internal/loader"
+	"github.com/jzeiders/graphql-go-gen/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// watchDebounce groups the events of a save (editors often write a file in
+// several steps) into a single regeneration
+const watchDebounce = 200 * time.Millisecond
+
+var watchPollInterval time.Duration
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Regenerate code when the schema or documents change",
+	Long: `Generate once, then watch the config file, local schema files and document
+globs and regenerate whenever they change. Remote schemas are re-fetched every
+--poll-interval and regenerate the output when they change. Stop with Ctrl-C.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configPath, err := resolveConfigPath()
+		if err != nil {
+			return err
+		}
+
+		cfg, err := loadConfig(configPath)
+		if err != nil {
+			return err
+		}
+
+		// Setup context with signal handling
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-sigChan
+			fmt.Println("\nStopping watch...")
+			cancel()
+		}()
+
+		session, err := newWatchSession(configPath, cfg, cmd.OutOrStdout())
+		if err != nil {
+			return err
+		}
+		defer session.close()
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Watching %s (Ctrl-C to stop)\n", configPath)
+		return session.run(ctx, watchPollInterval)
+	},
+}
+
+func init() {
+	watchCmd.Flags().DurationVar(&watchPollInterval, "poll-interval", 0, "re-fetch remote schemas at this interval and regenerate when they change (0 disables)")
+	watchCmd.Flags().BoolVar(&strictDocuments, "strict-documents", false, "fail a run when any document is invalid instead of skipping it")
+	watchCmd.Flags().BoolVar(&allowDuplicateNames, "allow-duplicate-names", false, "allow operations and fragments sharing a name across documents; the last fragment defined wins")
+	watchCmd.Flags().BoolVar(&force, "force", false, "rewrite output files even when their content is unchanged")
+	rootCmd.AddCommand(watchCmd)
+}
+
+// watchSession regenerates the outputs of one config as its inputs change
+type watchSession struct {
+	configPath string
+	config     *config.Config
+	watcher    *fsnotify.Watcher
+	out        io.Writer
+
+	// watched holds the directories added to the watcher
+	watched map[string]bool
+	// schemaHash is the schema of the last successful run, compared against
+	// when polling remote schemas
+	schemaHash string
+}
+
+func newWatchSession(configPath string, cfg *config.Config, out io.Writer) (*watchSession, error) {
+	// A session watches the inputs of a single schema
+	if len(cfg.Projects) > 0 {
+		return nil, fmt.Errorf("watch does not support configs with projects; run generate instead")
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("creating file watcher: %w", err)
+	}
+
+	return &watchSession{
+		configPath: absPath(configPath),
+		config:     cfg,
+		watcher:    watcher,
+		out:        out,
+		watched:    make(map[string]bool),
+	}, nil
+}
+
+func (w *watchSession) close() {
+	w.watcher.Close()
+}
+
+// run generates once and then on every change until ctx is cancelled
+func (w *watchSession) run(ctx context.Context, pollInterval time.Duration) error {
+	// Watch first so changes made during the initial run are not missed
+	w.watchInputs()
+	w.regenerate(ctx, nil)
+
+	var poll <-chan time.Time
+	if pollInterval > 0 && w.hasRemoteSchema() {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+
+	pending := make(map[string]bool)
+	var debounce <-chan time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					w.watchTree(event.Name)
+				}
+			}
+			if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+				delete(w.watched, event.Name)
+			}
+			if w.isInput(event.Name) {
+				pending[event.Name] = true
+				debounce = time.After(watchDebounce)
+			}
+
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(w.out, "Warning: watching files: %v\n", err)
+
+		case <-debounce:
+			debounce = nil
+			changed := make([]string, 0, len(pending))
+			for path := range pending {
+				changed = append(changed, path)
+			}
+			pending = make(map[string]bool)
+
+			if containsPath(changed, w.configPath) {
+				cfg, err := loadConfig(w.configPath)
+				if err != nil {
+					fmt.Fprintf(w.out, "[%s] %v\n", time.Now().Format("15:04:05"), err)
+					continue
+				}
+				w.config = cfg
+				w.watchInputs()
+			}
+			w.regenerate(ctx, changed)
+
+		case <-poll:
+			w.pollRemoteSchema(ctx)
+		}
+	}
+}
+
+// regenerate runs the generator and prints a one line summary. Failures are
+// reported and the session keeps watching.
+func (w *watchSession) regenerate(ctx context.Context, changed []string) {
+	start := time.Now()
+	trigger := "initial run"
+	if len(changed) > 0 {
+		trigger = w.describe(changed)
+	}
+
+	gen, err := newGenerator(w.config)
+	if err == nil {
+		gen.quiet = !verbose
+		err = gen.Generate(ctx)
+	}
+	if err != nil {
+		if ctx.Err() == nil {
+			fmt.Fprintf(w.out, "[%s] %s: generation failed: %v\n", start.Format("15:04:05"), trigger, err)
+		}
+		return
+	}
+
+	w.schemaHash = gen.schema.Hash()
+	fmt.Fprintf(w.out, "[%s] %s: generated %d output(s) from %d document(s) in %s\n",
+		start.Format("15:04:05"), trigger, len(w.config.Generates), len(gen.docs),
+		time.Since(start).Round(time.Millisecond))
+}
+
+// pollRemoteSchema reloads the schema and regenerates when its hash changed
+func (w *watchSession) pollRemoteSchema(ctx context.Context) {
+	gen, err := newGenerator(w.config)
+	if err != nil {
+		return
+	}
+	sources, err := gen.schemaSources()
+	if err != nil {
+		return
+	}
+
+	loaded, err := loader.NewUniversalSchemaLoader().Load(ctx, sources)
+	if err != nil {
+		if ctx.Err() == nil {
+			fmt.Fprintf(w.out, "[%s] polling schema: %v\n", time.Now().Format("15:04:05"), err)
+		}
+		return
+	}
+	if loaded.Hash() != w.schemaHash {
+		w.regenerate(ctx, []string{"remote schema"})
+	}
+}
+
+func (w *watchSession) hasRemoteSchema() bool {
+	for _, src := range w.config.Schema {
+		if src.URL != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// watchInputs adds the directories holding the config, the local schema
+// files and the documents to the watcher
+func (w *watchSession) watchInputs() {
+	w.watchDir(filepath.Dir(w.configPath))
+	for _, src := range w.config.Schema {
+		if src.Path != "" {
+			w.watchTree(loader.GlobBase(absPath(src.Path)))
+		}
+	}
+	for _, pattern := range w.config.Documents.Include {
+		w.watchTree(loader.GlobBase(absPath(pattern)))
+	}
+}
+
+// watchTree watches dir and its subdirectories, skipping dependencies and
+// hidden directories
+func (w *watchSession) watchTree(dir string) {
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		if path != dir && (name == "node_modules" || strings.HasPrefix(name, ".")) {
+			return filepath.SkipDir
+		}
+		w.watchDir(path)
+		return nil
+	})
+}
+
+func (w *watchSession) watchDir(dir string) {
+	if w.watched[dir] {
+		return
+	}
+	if err := w.watcher.Add(dir); err != nil {
+		fmt.Fprintf(w.out, "Warning: cannot watch %s: %v\n", dir, err)
+		return
+	}
+	w.watched[dir] = true
+}
+
+// isInput reports whether a change to path affects the generated output.
+// Outputs are never inputs, even when a document glob matches them, so
+// writing them does not trigger another run.
+func (w *watchSession) isInput(path string) bool {
+	path = absPath(path)
+	if path == w.configPath {
+		return true
+	}
+
+	for output := range w.config.Generates {
+		output = absPath(output)
+		if path == output || strings.HasPrefix(path, output+string(filepath.Separator)) {
+			return false
+		}
+	}
+
+	for _, src := range w.config.Schema {
+		if src.Path != "" && matchPath(absPath(src.Path), path) {
+			return true
+		}
+	}
+
+	for _, pattern := range w.config.Documents.Exclude {
+		if matchPath(absPath(pattern), path) {
+			return false
+		}
+	}
+	for _, pattern := range w.config.Documents.Include {
+		if matchPath(absPath(pattern), path) {
+			return true
+		}
+	}
+	return false
+}
+
+// describe lists the changed files relative to the config directory
+func (w *watchSession) describe(changed []string) string {
+	baseDir := filepath.Dir(w.configPath)
+	names := make([]string, 0, len(changed))
+	for _, path := range changed {
+		if rel, err := filepath.Rel(baseDir, path); err == nil && filepath.IsAbs(path) {
+			path = rel
+		}
+		names = append(names, path)
+	}
+	sort.Strings(names)
+
+	const maxNames = 3
+	if len(names) > maxNames {
+		return fmt.Sprintf("%s and %d more changed", strings.Join(names[:maxNames], ", "), len(names)-maxNames)
+	}
+	return strings.Join(names, ", ") + " changed"
+}
+
+// matchPath matches path against a glob pattern the way the document loaders
+// expand it
+func matchPath(pattern, path string) bool {
+	return loader.MatchGlob(pattern, path)
+}
+
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+func containsPath(paths []string, target string) bool {
+	for _, path := range paths {
+		if absPath(path) == target {
+			return true
+		}
+	}
+	return false
+}
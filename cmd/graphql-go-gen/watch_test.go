@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jzeiders/graphql-go-gen/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer lets the test read the summary while the session writes it
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestWatchSession_IsInput(t *testing.T) {
+	dir := t.TempDir()
+	session := &watchSession{
+		configPath: filepath.Join(dir, "graphql-go-gen.yaml"),
+		config: &config.Config{
+			Schema: []config.SchemaSource{{Path: filepath.Join(dir, "schema.graphql")}},
+			Documents: config.Documents{
+				Include: []string{filepath.Join(dir, "src", "*.ts")},
+				Exclude: []string{filepath.Join(dir, "src", "*.test.ts")},
+			},
+			Generates: map[string]config.OutputTarget{
+				filepath.Join(dir, "src", "generated.ts"): {},
+				filepath.Join(dir, "src", "gql") + "/":    {},
+			},
+		},
+	}
+
+	assert.True(t, session.isInput(filepath.Join(dir, "graphql-go-gen.yaml")))
+	assert.True(t, session.isInput(filepath.Join(dir, "schema.graphql")))
+	assert.True(t, session.isInput(filepath.Join(dir, "src", "user.ts")))
+	assert.False(t, session.isInput(filepath.Join(dir, "src", "user.test.ts")))
+	assert.False(t, session.isInput(filepath.Join(dir, "src", "generated.ts")))
+	assert.False(t, session.isInput(filepath.Join(dir, "src", "gql", "graphql.ts")))
+	assert.False(t, session.isInput(filepath.Join(dir, "README.md")))
+}
+
+func TestWatchSession_RegeneratesOnChange(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	writeFile("schema.graphql", `type Query { user: User } type User { id: ID! name: String! }`)
+	writeFile("user.graphql", `query GetUser { user { id } }`)
+	writeFile("graphql-go-gen.yaml", `
+schema:
+  - path: schema.graphql
+documents:
+  include:
+    - "*.graphql"
+generates:
+  types.ts:
+    plugins:
+      - typescript-operations
+`)
+
+	configPath := filepath.Join(dir, "graphql-go-gen.yaml")
+	cfg, err := loadConfig(configPath)
+	require.NoError(t, err)
+
+	var out syncBuffer
+	session, err := newWatchSession(configPath, cfg, &out)
+	require.NoError(t, err)
+	defer session.close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- session.run(ctx, 0) }()
+	defer func() {
+		cancel()
+		require.NoError(t, <-done)
+	}()
+
+	// waitForRun waits for a run summary and returns the generated output
+	waitForRun := func(summary string) string {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			if strings.Contains(out.String(), summary) {
+				content, err := os.ReadFile(filepath.Join(dir, "types.ts"))
+				require.NoError(t, err)
+				return string(content)
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		t.Fatalf("no %q run, log:\n%s", summary, out.String())
+		return ""
+	}
+
+	content := waitForRun("initial run: generated 1 output(s) from 1 document(s)")
+	assert.Contains(t, content, "GetUserQuery")
+
+	writeFile("viewer.graphql", `query GetViewer { user { name } }`)
+	content = waitForRun("viewer.graphql changed: generated 1 output(s) from 2 document(s)")
+	assert.Contains(t, content, "GetViewerQuery")
+
+	// Deleting a document drops its operation
+	require.NoError(t, os.Remove(filepath.Join(dir, "user.graphql")))
+	content = waitForRun("user.graphql changed: generated 1 output(s) from 1 document(s)")
+	assert.NotContains(t, content, "GetUserQuery")
+	assert.Contains(t, content, "GetViewerQuery")
+}
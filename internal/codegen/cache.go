@@ -0,0 +1,167 @@
+package codegen
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/jzeiders/graphql-go-gen/pkg/documents"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// CacheFileName is the default name of the generation cache manifest
+const CacheFileName = ".graphql-go-gen-cache.json"
+
+// cacheVersion changes when the manifest format or the key derivation does,
+// so manifests of older versions are ignored
+const cacheVersion = 2
+
+// CacheManifest records, for each output target, the key of the inputs it
+// was last generated from and the files it wrote. A target whose key is
+// unchanged and whose files all exist does not need to be generated again.
+// Its methods are safe for concurrent use.
+type CacheManifest struct {
+	mu      sync.Mutex
+	Version int                   `json:"version"`
+	Targets map[string]CacheEntry `json:"targets"`
+}
+
+// CacheEntry is the cached state of one output target
+type CacheEntry struct {
+	Key   string   `json:"key"`
+	Files []string `json:"files"`
+}
+
+// LoadCacheManifest reads the manifest at path. A missing, unreadable or
+// outdated manifest yields an empty one, so the first run generates
+// everything.
+func LoadCacheManifest(path string) *CacheManifest {
+	manifest := &CacheManifest{Version: cacheVersion, Targets: make(map[string]CacheEntry)}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return manifest
+	}
+
+	var loaded CacheManifest
+	if json.Unmarshal(data, &loaded) != nil || loaded.Version != cacheVersion || loaded.Targets == nil {
+		return manifest
+	}
+	manifest.Targets = loaded.Targets
+	return manifest
+}
+
+// Fresh reports whether target was generated with key and every file it
+// wrote still exists
+func (m *CacheManifest) Fresh(target, key string) bool {
+	m.mu.Lock()
+	entry, ok := m.Targets[target]
+	m.mu.Unlock()
+	if !ok || entry.Key != key || len(entry.Files) == 0 {
+		return false
+	}
+	for _, file := range entry.Files {
+		if _, err := os.Stat(file); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Record stores the key and written files of target
+func (m *CacheManifest) Record(target, key string, files []string) {
+	files = append([]string(nil), files...)
+	sort.Strings(files)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Targets[target] = CacheEntry{Key: key, Files: files}
+}
+
+// Forget removes target, e.g. after it failed to generate
+func (m *CacheManifest) Forget(target string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.Targets, target)
+}
+
+// Save writes the manifest to path
+func (m *CacheManifest) Save(path string) error {
+	m.mu.Lock()
+	data, err := json.MarshalIndent(m, "", "  ")
+	m.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// CacheKey hashes the inputs of an output target: the schema hash, the
+// documents sorted by path with their content, position and the sources
+// their fragments were imported from with #import, and settings,
+// which is encoded as JSON and should hold everything else that shapes the
+// output, e.g. the target's config and the generator version
+func CacheKey(schemaHash string, docs []*documents.Document, settings interface{}) (string, error) {
+	encoded, err := json.Marshal(settings)
+	if err != nil {
+		return "", err
+	}
+
+	sorted := make([]*documents.Document, len(docs))
+	copy(sorted, docs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].FilePath != sorted[j].FilePath {
+			return sorted[i].FilePath < sorted[j].FilePath
+		}
+		return sorted[i].Content < sorted[j].Content
+	})
+
+	h := sha256.New()
+	writeCacheField(h, []byte(schemaHash))
+	for _, doc := range sorted {
+		writeCacheField(h, []byte(doc.FilePath))
+		writeCacheField(h, []byte(doc.Content))
+		writeCacheField(h, []byte(fmt.Sprintf("%d:%d", doc.Line, doc.Column)))
+		for _, src := range importedSources(doc) {
+			writeCacheField(h, []byte(src.Name))
+			writeCacheField(h, []byte(src.Input))
+		}
+	}
+	writeCacheField(h, encoded)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// importedSources returns the sources, other than the document's own file,
+// that fragments in its AST were parsed from, sorted by name. Imported files
+// need not match the document globs, so their content is not otherwise part
+// of the key.
+func importedSources(doc *documents.Document) []*ast.Source {
+	if doc.AST == nil {
+		return nil
+	}
+	seen := make(map[string]*ast.Source)
+	for _, frag := range doc.AST.Fragments {
+		if frag.Position == nil || frag.Position.Src == nil || frag.Position.Src.Name == doc.FilePath {
+			continue
+		}
+		seen[frag.Position.Src.Name] = frag.Position.Src
+	}
+	sources := make([]*ast.Source, 0, len(seen))
+	for _, src := range seen {
+		sources = append(sources, src)
+	}
+	sort.Slice(sources, func(i, j int) bool { return sources[i].Name < sources[j].Name })
+	return sources
+}
+
+// writeCacheField writes data prefixed with its length, so adjacent fields
+// cannot run into each other
+func writeCacheField(w io.Writer, data []byte) {
+	binary.Write(w, binary.LittleEndian, uint64(len(data)))
+	w.Write(data)
+}
@@ -0,0 +1,96 @@
+package codegen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jzeiders/graphql-go-gen/pkg/documents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestCacheKey(t *testing.T) {
+	docs := []*documents.Document{
+		{FilePath: "b.graphql", Content: "query B { b }"},
+		{FilePath: "a.graphql", Content: "query A { a }"},
+	}
+	settings := map[string]interface{}{"plugins": []string{"typescript"}}
+	key, err := CacheKey("schema", docs, settings)
+	require.NoError(t, err)
+
+	reordered, err := CacheKey("schema", []*documents.Document{docs[1], docs[0]}, settings)
+	require.NoError(t, err)
+	assert.Equal(t, key, reordered, "document order should not matter")
+
+	changes := map[string]func() (string, error){
+		"schema hash": func() (string, error) {
+			return CacheKey("other", docs, settings)
+		},
+		"document content": func() (string, error) {
+			return CacheKey("schema", []*documents.Document{docs[0], {FilePath: "a.graphql", Content: "query A { a b }"}}, settings)
+		},
+		"document path": func() (string, error) {
+			return CacheKey("schema", []*documents.Document{docs[0], {FilePath: "c.graphql", Content: "query A { a }"}}, settings)
+		},
+		"settings": func() (string, error) {
+			return CacheKey("schema", docs, map[string]interface{}{"plugins": []string{"typescript-operations"}})
+		},
+	}
+	for name, change := range changes {
+		t.Run("changes with "+name, func(t *testing.T) {
+			changed, err := change()
+			require.NoError(t, err)
+			assert.NotEqual(t, key, changed)
+		})
+	}
+
+	// Fragments pulled in with #import come from files outside the
+	// documents, so editing one must change the key too
+	withImport := func(input string) string {
+		src := &ast.Source{Name: "shared.graphql", Input: input}
+		doc := &documents.Document{
+			FilePath: "a.graphql",
+			Content:  "#import \"./shared.graphql\"\nquery A { ...F }",
+			AST: &ast.QueryDocument{Fragments: ast.FragmentDefinitionList{
+				{Name: "F", Position: &ast.Position{Src: src}},
+			}},
+		}
+		key, err := CacheKey("schema", []*documents.Document{doc}, settings)
+		require.NoError(t, err)
+		return key
+	}
+	assert.NotEqual(t, withImport("fragment F on Query { a }"), withImport("fragment F on Query { a b }"))
+
+	_, err = CacheKey("schema", docs, func() {})
+	assert.Error(t, err, "settings that cannot be encoded")
+}
+
+func TestCacheManifest(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, CacheFileName)
+	output := filepath.Join(dir, "types.ts")
+	require.NoError(t, os.WriteFile(output, []byte("export {};\n"), 0644))
+
+	manifest := LoadCacheManifest(path)
+	assert.False(t, manifest.Fresh(output, "k1"), "empty manifest")
+
+	manifest.Record(output, "k1", []string{output})
+	require.NoError(t, manifest.Save(path))
+
+	loaded := LoadCacheManifest(path)
+	assert.True(t, loaded.Fresh(output, "k1"))
+	assert.False(t, loaded.Fresh(output, "k2"), "different key")
+
+	require.NoError(t, os.Remove(output))
+	assert.False(t, loaded.Fresh(output, "k1"), "missing output file")
+
+	loaded.Forget(output)
+	assert.Empty(t, loaded.Targets)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": 0, "targets": {}}`), 0644))
+	assert.Empty(t, LoadCacheManifest(path).Targets, "outdated manifest")
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0644))
+	assert.Empty(t, LoadCacheManifest(path).Targets, "corrupt manifest")
+}
@@ -0,0 +1,138 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// nextExportRegexp matches the start of a top-level export, which ends the
+// declaration before it
+var nextExportRegexp = regexp.MustCompile(`(?m)^export `)
+
+// TypeChanges lists the exported operation, variables and fragment types a
+// generated file adds, removes or declares differently, by name
+type TypeChanges struct {
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+// Empty reports whether no type changed
+func (c TypeChanges) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+// ExportedTypes returns the declarations of the operation, variables and
+// fragment types exported by generated TypeScript, by type name. Each
+// declaration runs up to the next top-level export.
+func ExportedTypes(content []byte) map[string]string {
+	types := make(map[string]string)
+	for _, match := range exportedTypeRegexp.FindAllSubmatchIndex(content, -1) {
+		rest := content[match[1]:]
+		end := len(rest)
+		if next := nextExportRegexp.FindIndex(rest); next != nil {
+			end = next[0]
+		}
+		name := string(content[match[2]:match[3]])
+		types[name] = string(bytes.TrimSpace(content[match[0] : match[1]+end]))
+	}
+	return types
+}
+
+// CompareExportedTypes compares the exported types of a file's previous and
+// current content. Names are sorted.
+func CompareExportedTypes(previous, current []byte) TypeChanges {
+	before, after := ExportedTypes(previous), ExportedTypes(current)
+
+	var changes TypeChanges
+	for name, declaration := range after {
+		old, ok := before[name]
+		switch {
+		case !ok:
+			changes.Added = append(changes.Added, name)
+		case old != declaration:
+			changes.Changed = append(changes.Changed, name)
+		}
+	}
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			changes.Removed = append(changes.Removed, name)
+		}
+	}
+	sort.Strings(changes.Added)
+	sort.Strings(changes.Removed)
+	sort.Strings(changes.Changed)
+	return changes
+}
+
+// ChangeSummary collects the TypeChanges of generated files against the
+// files on disk before they are written. It is safe for concurrent use.
+type ChangeSummary struct {
+	mu    sync.Mutex
+	files map[string]TypeChanges
+}
+
+// NewChangeSummary creates an empty change summary
+func NewChangeSummary() *ChangeSummary {
+	return &ChangeSummary{files: make(map[string]TypeChanges)}
+}
+
+// Compare records how content changes the types of the file at path. A
+// missing file adds every type.
+func (s *ChangeSummary) Compare(path string, content []byte) {
+	previous, _ := os.ReadFile(path)
+	changes := CompareExportedTypes(previous, content)
+	if changes.Empty() {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[path] = changes
+}
+
+// File returns the changes recorded for the file at path
+func (s *ChangeSummary) File(path string) TypeChanges {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.files[path]
+}
+
+// Write prints the changed types of each file in path order, marking them
+// + added, - removed and ~ changed
+func (s *ChangeSummary) Write(out io.Writer, displayName func(string) string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.files) == 0 {
+		fmt.Fprintln(out, "Changes: none")
+		return
+	}
+
+	paths := make([]string, 0, len(s.files))
+	for path := range s.files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	fmt.Fprintln(out, "Changes:")
+	for _, path := range paths {
+		changes := s.files[path]
+		fmt.Fprintf(out, "  %s: %d added, %d removed, %d changed\n",
+			displayName(path), len(changes.Added), len(changes.Removed), len(changes.Changed))
+		for _, name := range changes.Added {
+			fmt.Fprintf(out, "    + %s\n", name)
+		}
+		for _, name := range changes.Removed {
+			fmt.Fprintf(out, "    - %s\n", name)
+		}
+		for _, name := range changes.Changed {
+			fmt.Fprintf(out, "    ~ %s\n", name)
+		}
+	}
+}
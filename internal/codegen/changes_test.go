@@ -0,0 +1,72 @@
+package codegen
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareExportedTypes(t *testing.T) {
+	previous := []byte(`export type Maybe<T> = T | null;
+export type GetUserQueryVariables = Exact<{ id: Scalars['ID']['input']; }>;
+
+export type GetUserQuery = { __typename?: 'Query', user?: { __typename?: 'User', id: string } | null };
+
+export type GetLegacyUserQuery = { __typename?: 'Query', legacyUser?: { id: string } | null };
+
+export type UserFieldsFragment = {
+  __typename?: 'User',
+  id: string
+};
+`)
+	current := []byte(`export type Maybe<T> = T | null;
+export type GetUserQueryVariables = Exact<{ id: Scalars['ID']['input']; }>;
+
+export type GetUserQuery = { __typename?: 'Query', user?: { __typename?: 'User', id: string, name: string } | null };
+
+export type UpdateUserMutation = { __typename?: 'Mutation', updateUser: boolean };
+
+export type UserFieldsFragment = {
+  __typename?: 'User',
+  id: string
+};
+`)
+
+	assert.Equal(t, TypeChanges{
+		Added:   []string{"UpdateUserMutation"},
+		Removed: []string{"GetLegacyUserQuery"},
+		Changed: []string{"GetUserQuery"},
+	}, CompareExportedTypes(previous, current))
+	assert.True(t, CompareExportedTypes(current, current).Empty())
+}
+
+func TestChangeSummary(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "types.ts")
+	require.NoError(t, os.WriteFile(existing, []byte("export type GetUserQuery = { id: string };\n"), 0644))
+
+	summary := NewChangeSummary()
+	summary.Compare(existing, []byte("export type GetUserQuery = { id: string, name: string };\n"))
+	summary.Compare(filepath.Join(dir, "new.ts"), []byte("export type UserFieldsFragment = { id: string };\n"))
+	summary.Compare(filepath.Join(dir, "index.ts"), []byte("export * from './types';\n"))
+
+	assert.Equal(t, TypeChanges{Changed: []string{"GetUserQuery"}}, summary.File(existing))
+
+	var out bytes.Buffer
+	summary.Write(&out, func(path string) string { return strings.TrimPrefix(path, dir+string(filepath.Separator)) })
+	assert.Equal(t, `Changes:
+  new.ts: 1 added, 0 removed, 0 changed
+    + UserFieldsFragment
+  types.ts: 0 added, 0 removed, 1 changed
+    ~ GetUserQuery
+`, out.String())
+
+	out.Reset()
+	NewChangeSummary().Write(&out, strings.ToUpper)
+	assert.Equal(t, "Changes: none\n", out.String())
+}
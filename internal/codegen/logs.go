@@ -0,0 +1,107 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// TargetLogs buffers log output per output target so that targets generated
+// concurrently never interleave their lines. A target's logs are written in
+// one piece once the target is closed.
+type TargetLogs struct {
+	mu  sync.Mutex
+	out io.Writer
+
+	// order holds the target names when logs are emitted in config order;
+	// it is empty when they are emitted in completion order
+	order []string
+	next  int
+	done  map[string]*bytes.Buffer
+}
+
+// NewTargetLogs creates a log collector writing to out. When order is
+// non-empty, targets are emitted in that order, each as soon as it and every
+// target before it have finished; otherwise targets are emitted as they finish.
+func NewTargetLogs(out io.Writer, order []string) *TargetLogs {
+	return &TargetLogs{
+		out:   out,
+		order: order,
+		done:  make(map[string]*bytes.Buffer),
+	}
+}
+
+// Target returns the log for a single output target
+func (l *TargetLogs) Target(name string) *TargetLog {
+	return &TargetLog{logs: l, name: name}
+}
+
+// Flush writes the logs of all closed targets that are still waiting for an
+// earlier target, e.g. after generation stopped on an error
+func (l *TargetLogs) Flush() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for ; l.next < len(l.order); l.next++ {
+		if buf, ok := l.done[l.order[l.next]]; ok {
+			l.out.Write(buf.Bytes())
+			delete(l.done, l.order[l.next])
+		}
+	}
+}
+
+func (l *TargetLogs) finish(name string, buf *bytes.Buffer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.order) == 0 {
+		l.out.Write(buf.Bytes())
+		return
+	}
+
+	l.done[name] = buf
+	for l.next < len(l.order) {
+		ready, ok := l.done[l.order[l.next]]
+		if !ok {
+			return
+		}
+		l.out.Write(ready.Bytes())
+		delete(l.done, l.order[l.next])
+		l.next++
+	}
+}
+
+// TargetLog collects the log lines of one output target. It is not safe for
+// concurrent use; each target is generated by a single goroutine.
+type TargetLog struct {
+	logs   *TargetLogs
+	name   string
+	buf    bytes.Buffer
+	closed bool
+}
+
+// Printf appends a formatted message to the target's log
+func (t *TargetLog) Printf(format string, args ...interface{}) {
+	fmt.Fprintf(&t.buf, format, args...)
+}
+
+// Println appends a line to the target's log
+func (t *TargetLog) Println(args ...interface{}) {
+	fmt.Fprintln(&t.buf, args...)
+}
+
+// Write appends p to the target's log, e.g. the output of a command run
+// for the target
+func (t *TargetLog) Write(p []byte) (int, error) {
+	return t.buf.Write(p)
+}
+
+// Close hands the target's log to the collector for writing
+func (t *TargetLog) Close() {
+	if t.closed {
+		return
+	}
+	t.closed = true
+	t.logs.finish(t.name, &t.buf)
+}
@@ -0,0 +1,75 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTargetLogs_ConcurrentTargetsDoNotInterleave(t *testing.T) {
+	var out bytes.Buffer
+	logs := NewTargetLogs(&out, nil)
+
+	targets := []string{"a.ts", "b.ts", "c.ts", "d.ts"}
+	var wg sync.WaitGroup
+	for _, name := range targets {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			log := logs.Target(name)
+			for i := 0; i < 50; i++ {
+				log.Printf("%s line %d\n", name, i)
+			}
+			log.Close()
+		}(name)
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	assert.Len(t, lines, 200)
+
+	// Each target's lines form one contiguous block
+	for block := 0; block < len(targets); block++ {
+		name := strings.Fields(lines[block*50])[0]
+		for i := 0; i < 50; i++ {
+			assert.Equal(t, fmt.Sprintf("%s line %d", name, i), lines[block*50+i])
+		}
+	}
+}
+
+func TestTargetLogs_ConfigOrder(t *testing.T) {
+	var out bytes.Buffer
+	logs := NewTargetLogs(&out, []string{"a.ts", "b.ts", "c.ts"})
+
+	c := logs.Target("c.ts")
+	c.Println("c done")
+	c.Close()
+	assert.Empty(t, out.String(), "c.ts must wait for a.ts and b.ts")
+
+	a := logs.Target("a.ts")
+	a.Println("a done")
+	a.Close()
+	assert.Equal(t, "a done\n", out.String())
+
+	b := logs.Target("b.ts")
+	b.Println("b done")
+	b.Close()
+	assert.Equal(t, "a done\nb done\nc done\n", out.String())
+}
+
+func TestTargetLogs_FlushSkipsUnfinishedTargets(t *testing.T) {
+	var out bytes.Buffer
+	logs := NewTargetLogs(&out, []string{"a.ts", "b.ts", "c.ts"})
+
+	logs.Target("a.ts")
+	c := logs.Target("c.ts")
+	c.Println("c done")
+	c.Close()
+
+	logs.Flush()
+	assert.Equal(t, "c done\n", out.String())
+}
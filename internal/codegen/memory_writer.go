@@ -0,0 +1,59 @@
+package codegen
+
+import (
+	"sort"
+	"sync"
+)
+
+// MemoryFileWriter collects generated files in memory instead of writing
+// them to disk. It is safe for concurrent use.
+type MemoryFileWriter struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+// NewMemoryFileWriter creates an empty in-memory writer
+func NewMemoryFileWriter() *MemoryFileWriter {
+	return &MemoryFileWriter{files: make(map[string][]byte)}
+}
+
+// Write records a single file, replacing earlier content for the same path
+func (w *MemoryFileWriter) Write(path string, content []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.files[path] = append([]byte(nil), content...)
+	return nil
+}
+
+// WriteMultiple records multiple files
+func (w *MemoryFileWriter) WriteMultiple(files map[string][]byte) error {
+	for path, content := range files {
+		if err := w.Write(path, content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Files returns a copy of the written files keyed by path
+func (w *MemoryFileWriter) Files() map[string][]byte {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	files := make(map[string][]byte, len(w.files))
+	for path, content := range w.files {
+		files[path] = content
+	}
+	return files
+}
+
+// Paths returns the written paths in sorted order
+func (w *MemoryFileWriter) Paths() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	paths := make([]string, 0, len(w.files))
+	for path := range w.files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,22 @@
+package codegen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryFileWriter(t *testing.T) {
+	w := NewMemoryFileWriter()
+	content := []byte("export type A = string;\n")
+
+	require.NoError(t, w.Write("b.ts", content))
+	require.NoError(t, w.WriteMultiple(map[string][]byte{"a.ts": []byte("a")}))
+
+	// The writer keeps its own copy of the content
+	content[0] = 'X'
+
+	assert.Equal(t, []string{"a.ts", "b.ts"}, w.Paths())
+	assert.Equal(t, "export type A = string;\n", string(w.Files()["b.ts"]))
+}
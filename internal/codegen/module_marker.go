@@ -0,0 +1,44 @@
+package codegen
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ModuleMarker is appended to TypeScript files that would otherwise be
+// scripts, so their declarations stay in module scope
+const ModuleMarker = "export {};\n"
+
+// moduleStatement matches a top-level import or export, which makes a
+// TypeScript file a module. Generated files start top-level statements in
+// the first column.
+var moduleStatement = regexp.MustCompile(`(?m)^(import|export)\b`)
+
+// NeedsModuleMarker reports whether the file at path is a TypeScript source
+// file without imports or exports. Declaration files are left alone: a
+// marker would turn their ambient module declarations into augmentations.
+func NeedsModuleMarker(path string, content []byte) bool {
+	if strings.HasSuffix(path, ".d.ts") {
+		return false
+	}
+	switch filepath.Ext(path) {
+	case ".ts", ".tsx":
+	default:
+		return false
+	}
+	return !moduleStatement.Match(content)
+}
+
+// AddModuleMarker appends ModuleMarker to content when NeedsModuleMarker
+func AddModuleMarker(path string, content []byte) []byte {
+	if !NeedsModuleMarker(path, content) {
+		return content
+	}
+	marked := make([]byte, 0, len(content)+len(ModuleMarker)+1)
+	marked = append(marked, content...)
+	if len(marked) > 0 && marked[len(marked)-1] != '\n' {
+		marked = append(marked, '\n')
+	}
+	return append(marked, ModuleMarker...)
+}
@@ -0,0 +1,65 @@
+package codegen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddModuleMarker(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		want    string
+	}{
+		{
+			name:    "types without exports",
+			path:    "types.ts",
+			content: "type Maybe<T> = T | null;\ntype User = { id: string };",
+			want:    "type Maybe<T> = T | null;\ntype User = { id: string };\nexport {};\n",
+		},
+		{
+			name:    "empty file",
+			path:    "types.tsx",
+			content: "",
+			want:    "export {};\n",
+		},
+		{
+			name:    "type exports",
+			path:    "types.ts",
+			content: "export type User = { id: string };\n",
+			want:    "export type User = { id: string };\n",
+		},
+		{
+			name:    "imports",
+			path:    "types.ts",
+			content: "import type { User } from './base';\ntype Users = User[];\n",
+			want:    "import type { User } from './base';\ntype Users = User[];\n",
+		},
+		{
+			name:    "nested exports do not count",
+			path:    "hooks.ts",
+			content: "namespace Hooks {\n  export type User = { id: string };\n}\n",
+			want:    "namespace Hooks {\n  export type User = { id: string };\n}\nexport {};\n",
+		},
+		{
+			name:    "declaration files",
+			path:    "gql.d.ts",
+			content: "declare module 'graphql-tag' {\n  import type { User } from './types';\n}\n",
+			want:    "declare module 'graphql-tag' {\n  import type { User } from './types';\n}\n",
+		},
+		{
+			name:    "other files",
+			path:    "schema.graphql",
+			content: "type Query { ping: String }\n",
+			want:    "type Query { ping: String }\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, string(AddModuleMarker(tt.path, []byte(tt.content))))
+		})
+	}
+}
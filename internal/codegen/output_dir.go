@@ -0,0 +1,43 @@
+package codegen
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// CheckOutputDir reports whether dir exists as a writable directory or can
+// be created. Nothing is created: the nearest existing ancestor must be a
+// directory, and a probe file is created in it and removed to check write
+// permission.
+func CheckOutputDir(dir string) error {
+	existing := filepath.Clean(dir)
+	for {
+		info, err := os.Stat(existing)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("%s is not a directory", existing)
+			}
+			break
+		}
+		// A file in the path fails with ENOTDIR; walking up reports it
+		if errors.Is(err, fs.ErrPermission) {
+			return err
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return err
+		}
+		existing = parent
+	}
+
+	probe, err := os.CreateTemp(existing, ".graphql-go-gen-*")
+	if err != nil {
+		return fmt.Errorf("directory %s is not writable: %w", existing, err)
+	}
+	name := probe.Name()
+	probe.Close()
+	return os.Remove(name)
+}
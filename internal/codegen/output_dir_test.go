@@ -0,0 +1,40 @@
+package codegen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckOutputDir(t *testing.T) {
+	root := t.TempDir()
+	blocker := filepath.Join(root, "blocker")
+	require.NoError(t, os.WriteFile(blocker, []byte("not a directory"), 0644))
+
+	t.Run("existing directory", func(t *testing.T) {
+		assert.NoError(t, CheckOutputDir(root))
+	})
+
+	t.Run("missing directories are creatable", func(t *testing.T) {
+		dir := filepath.Join(root, "src", "gql")
+		assert.NoError(t, CheckOutputDir(dir))
+		_, err := os.Stat(filepath.Join(root, "src"))
+		assert.True(t, os.IsNotExist(err), "the check should not create directories")
+	})
+
+	t.Run("file in the way", func(t *testing.T) {
+		err := CheckOutputDir(filepath.Join(blocker, "gql"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), blocker+" is not a directory")
+	})
+
+	t.Run("leaves no probe behind", func(t *testing.T) {
+		entries, err := os.ReadDir(root)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "blocker", entries[0].Name())
+	})
+}
@@ -0,0 +1,104 @@
+package codegen
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sort"
+
+	"github.com/jzeiders/graphql-go-gen/pkg/plugin"
+)
+
+// SourceMapSuffix is appended to the path of a generated file to name its
+// source map sidecar
+const SourceMapSuffix = ".map.json"
+
+// SourceMap links ranges of a generated file to the GraphQL operations and
+// fragments they were generated from
+type SourceMap struct {
+	Version  int                `json:"version"`
+	File     string             `json:"file"`
+	Mappings []SourceMapMapping `json:"mappings"`
+}
+
+// SourceMapMapping is one generated range and its GraphQL definition.
+// Sources are relative to the directory of the source map.
+type SourceMapMapping struct {
+	Name      string             `json:"name"`
+	Kind      string             `json:"kind"`
+	Source    string             `json:"source"`
+	Line      int                `json:"line"`
+	Column    int                `json:"column"`
+	Generated SourceMapGenerated `json:"generated"`
+}
+
+// SourceMapGenerated is a range of the generated file, as byte offsets and
+// as 1-based lines and columns
+type SourceMapGenerated struct {
+	Start SourceMapPosition `json:"start"`
+	End   SourceMapPosition `json:"end"`
+}
+
+// SourceMapPosition is a position in the generated file
+type SourceMapPosition struct {
+	Offset int `json:"offset"`
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// BuildSourceMap builds the source map of the generated file at path with
+// content, ordered by position in the file
+func BuildSourceMap(path string, content []byte, mappings []plugin.SourceMapping) *SourceMap {
+	dir := filepath.Dir(path)
+	sourceMap := &SourceMap{
+		Version:  1,
+		File:     filepath.Base(path),
+		Mappings: make([]SourceMapMapping, 0, len(mappings)),
+	}
+	for _, m := range mappings {
+		source := m.Source
+		if rel, err := filepath.Rel(dir, source); err == nil && filepath.IsAbs(source) {
+			source = filepath.ToSlash(rel)
+		}
+		sourceMap.Mappings = append(sourceMap.Mappings, SourceMapMapping{
+			Name:   m.Name,
+			Kind:   m.Kind,
+			Source: source,
+			Line:   m.Line,
+			Column: m.Column,
+			Generated: SourceMapGenerated{
+				Start: offsetPosition(content, m.Start),
+				End:   offsetPosition(content, m.End),
+			},
+		})
+	}
+	sort.SliceStable(sourceMap.Mappings, func(i, j int) bool {
+		return sourceMap.Mappings[i].Generated.Start.Offset < sourceMap.Mappings[j].Generated.Start.Offset
+	})
+	return sourceMap
+}
+
+// JSON returns the source map as indented JSON
+func (m *SourceMap) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// offsetPosition returns the line and column of a byte offset in content
+func offsetPosition(content []byte, offset int) SourceMapPosition {
+	if offset > len(content) {
+		offset = len(content)
+	}
+	pos := SourceMapPosition{Offset: offset, Line: 1, Column: 1}
+	for _, b := range content[:offset] {
+		if b == '\n' {
+			pos.Line++
+			pos.Column = 1
+		} else {
+			pos.Column++
+		}
+	}
+	return pos
+}
@@ -0,0 +1,42 @@
+package codegen
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jzeiders/graphql-go-gen/pkg/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildSourceMap(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "src", "gql", "graphql.ts")
+	content := []byte("// header\n\nexport type A = string;\n\nexport type B = number;\n")
+
+	sourceMap := BuildSourceMap(path, content, []plugin.SourceMapping{
+		{Start: 36, End: 59, Name: "B", Kind: "fragment", Source: filepath.Join(root, "src", "b.graphql"), Line: 2, Column: 1},
+		{Start: 11, End: 34, Name: "A", Kind: "query", Source: filepath.Join(root, "a.graphql"), Line: 1, Column: 5},
+	})
+
+	assert.Equal(t, 1, sourceMap.Version)
+	assert.Equal(t, "graphql.ts", sourceMap.File)
+	require.Len(t, sourceMap.Mappings, 2)
+
+	a := sourceMap.Mappings[0]
+	assert.Equal(t, "A", a.Name)
+	assert.Equal(t, "../../a.graphql", a.Source)
+	assert.Equal(t, SourceMapPosition{Offset: 11, Line: 3, Column: 1}, a.Generated.Start)
+	assert.Equal(t, SourceMapPosition{Offset: 34, Line: 3, Column: 24}, a.Generated.End)
+	assert.Equal(t, "export type A = string;", string(content[a.Generated.Start.Offset:a.Generated.End.Offset]))
+
+	b := sourceMap.Mappings[1]
+	assert.Equal(t, "B", b.Name)
+	assert.Equal(t, "../b.graphql", b.Source)
+	assert.Equal(t, SourceMapPosition{Offset: 36, Line: 5, Column: 1}, b.Generated.Start)
+
+	data, err := sourceMap.JSON()
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"file": "graphql.ts"`)
+	assert.Equal(t, byte('\n'), data[len(data)-1])
+}
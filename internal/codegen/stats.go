@@ -0,0 +1,104 @@
+package codegen
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sync"
+)
+
+// exportedTypeRegexp matches exported operation, variables and fragment
+// types by the suffix typescript-operations gives them, capturing the type
+// name and the suffix
+var exportedTypeRegexp = regexp.MustCompile(`(?m)^export (?:type|interface) (\w+?(Query|Mutation|Subscription|Variables|Fragment))\b`)
+
+// TypeCounts counts the exported types of generated code
+type TypeCounts struct {
+	Operations int
+	Variables  int
+	Fragments  int
+}
+
+// Total returns the number of counted types
+func (c TypeCounts) Total() int {
+	return c.Operations + c.Variables + c.Fragments
+}
+
+func (c *TypeCounts) add(other TypeCounts) {
+	c.Operations += other.Operations
+	c.Variables += other.Variables
+	c.Fragments += other.Fragments
+}
+
+// CountExportedTypes counts the operation result, variables and fragment
+// types exported by generated TypeScript
+func CountExportedTypes(content []byte) TypeCounts {
+	var counts TypeCounts
+	for _, match := range exportedTypeRegexp.FindAllSubmatch(content, -1) {
+		switch string(match[2]) {
+		case "Variables":
+			counts.Variables++
+		case "Fragment":
+			counts.Fragments++
+		default:
+			counts.Operations++
+		}
+	}
+	return counts
+}
+
+// TargetStats summarizes the files generated for one output target
+type TargetStats struct {
+	Files int
+	Bytes int
+	Types TypeCounts
+}
+
+// GenerationStats collects TargetStats while targets are generated. It is
+// safe for concurrent use.
+type GenerationStats struct {
+	mu      sync.Mutex
+	targets map[string]*TargetStats
+}
+
+// NewGenerationStats creates an empty stats collector
+func NewGenerationStats() *GenerationStats {
+	return &GenerationStats{targets: make(map[string]*TargetStats)}
+}
+
+// Record adds a generated file to the stats of its target
+func (s *GenerationStats) Record(target string, content []byte) {
+	counts := CountExportedTypes(content)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats, ok := s.targets[target]
+	if !ok {
+		stats = &TargetStats{}
+		s.targets[target] = stats
+	}
+	stats.Files++
+	stats.Bytes += len(content)
+	stats.Types.add(counts)
+}
+
+// Target returns the stats of a target
+func (s *GenerationStats) Target(target string) TargetStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if stats, ok := s.targets[target]; ok {
+		return *stats
+	}
+	return TargetStats{}
+}
+
+// Write prints one line per target in the given order
+func (s *GenerationStats) Write(out io.Writer, order []string, displayName func(string) string) {
+	fmt.Fprintln(out, "Stats:")
+	for _, target := range order {
+		stats := s.Target(target)
+		fmt.Fprintf(out, "  %s: %d file(s), %d bytes, %d types (%d operations, %d variables, %d fragments)\n",
+			displayName(target), stats.Files, stats.Bytes, stats.Types.Total(),
+			stats.Types.Operations, stats.Types.Variables, stats.Types.Fragments)
+	}
+}
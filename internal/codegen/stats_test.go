@@ -0,0 +1,41 @@
+package codegen
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountExportedTypes(t *testing.T) {
+	content := []byte(`export type Maybe<T> = T | null;
+export type Scalars = { ID: { input: string; output: string } };
+export type UserFieldsFragment = { __typename?: 'User', id: string };
+export type GetUserQueryVariables = Exact<{ id: Scalars['ID']['input']; }>;
+export type GetUserQuery = { __typename?: 'Query', user?: { __typename?: 'User', id: string } | null };
+export interface UpdateUserMutation { updateUser: boolean }
+export type UpdateUserMutationVariables = Exact<{ [key: string]: never; }>;
+export type OnUserSubscription = { userChanged: boolean };
+type LocalQuery = { hidden: boolean };
+`)
+
+	assert.Equal(t, TypeCounts{Operations: 3, Variables: 2, Fragments: 1}, CountExportedTypes(content))
+}
+
+func TestGenerationStats(t *testing.T) {
+	stats := NewGenerationStats()
+	stats.Record("gql/", []byte("export type GetUserQuery = {};\nexport type GetUserQueryVariables = {};\n"))
+	stats.Record("gql/", []byte("export * from './gql';\n"))
+	stats.Record("types.ts", []byte("export type UserFieldsFragment = {};\n"))
+
+	assert.Equal(t, TargetStats{Files: 2, Bytes: 94, Types: TypeCounts{Operations: 1, Variables: 1}}, stats.Target("gql/"))
+
+	var out bytes.Buffer
+	stats.Write(&out, []string{"gql/", "types.ts", "empty.ts"}, strings.ToUpper)
+	assert.Equal(t, `Stats:
+  GQL/: 2 file(s), 94 bytes, 2 types (1 operations, 1 variables, 0 fragments)
+  TYPES.TS: 1 file(s), 37 bytes, 1 types (0 operations, 0 variables, 1 fragments)
+  EMPTY.TS: 0 file(s), 0 bytes, 0 types (0 operations, 0 variables, 0 fragments)
+`, out.String())
+}
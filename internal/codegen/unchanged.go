@@ -0,0 +1,36 @@
+package codegen
+
+import (
+	"crypto/sha256"
+	"io"
+	"os"
+)
+
+// Unchanged reports whether the file at path already holds content, by
+// comparing SHA-256 hashes. A missing or unreadable file counts as changed.
+func Unchanged(path string, content []byte) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return false
+	}
+	return [sha256.Size]byte(h.Sum(nil)) == sha256.Sum256(content)
+}
+
+// WriteIfChanged writes content to path with w unless the file already
+// holds it, so unchanged outputs keep their modification time and do not
+// trigger watchers downstream. It reports whether the file was written.
+func WriteIfChanged(w FileWriter, path string, content []byte) (bool, error) {
+	if Unchanged(path, content) {
+		return false, nil
+	}
+	if err := w.Write(path, content); err != nil {
+		return false, err
+	}
+	return true, nil
+}
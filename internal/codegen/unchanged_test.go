@@ -0,0 +1,38 @@
+package codegen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteIfChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gen", "types.ts")
+	content := []byte("export type A = string;\n")
+	writer := &DefaultFileWriter{}
+
+	written, err := WriteIfChanged(writer, path, content)
+	require.NoError(t, err)
+	assert.True(t, written, "missing file should be written")
+
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	require.NoError(t, os.Chtimes(path, old, old))
+
+	written, err = WriteIfChanged(writer, path, content)
+	require.NoError(t, err)
+	assert.False(t, written, "identical content should not be written")
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.True(t, info.ModTime().Equal(old), "modification time changed to %v", info.ModTime())
+
+	written, err = WriteIfChanged(writer, path, []byte("export type A = number;\n"))
+	require.NoError(t, err)
+	assert.True(t, written, "changed content should be written")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "export type A = number;\n", string(data))
+}
@@ -6,18 +6,33 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/jzeiders/graphql-go-gen/pkg/documents"
 	"github.com/jzeiders/graphql-go-gen/pkg/schema"
 	"github.com/vektah/gqlparser/v2"
 	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
 )
 
+// importPattern matches `#import "./fragment.graphql"` include lines
+var importPattern = regexp.MustCompile(`^\s*#\s*import\s+["']([^"']+)["']`)
+
 // GraphQLDocumentLoader loads GraphQL documents from .graphql and .gql files using gqlparser
 type GraphQLDocumentLoader struct {
 	// Cache for loaded documents
 	cache map[string]*documents.Document
+
+	// resolveImports inlines fragments from #import includes before validation
+	resolveImports bool
+
+	// allowedDirectives may be used without the schema declaring them
+	allowedDirectives []string
+
+	// errors records why files matched by Load were skipped
+	errors documents.SourceErrors
 }
 
 // NewGraphQLDocumentLoader creates a new GraphQL document loader
@@ -27,6 +42,19 @@ func NewGraphQLDocumentLoader() *GraphQLDocumentLoader {
 	}
 }
 
+// SetResolveImports enables or disables resolution of #import includes.
+// Imported files are resolved relative to the importing file and their
+// fragments are added to the document before it is validated.
+func (l *GraphQLDocumentLoader) SetResolveImports(enabled bool) {
+	l.resolveImports = enabled
+}
+
+// SetAllowedDirectives lets documents use the named directives without the
+// schema declaring them, for directives that only codegen reads
+func (l *GraphQLDocumentLoader) SetAllowedDirectives(names ...string) {
+	l.allowedDirectives = names
+}
+
 // Load loads documents matching the given glob patterns
 func (l *GraphQLDocumentLoader) Load(ctx context.Context, s schema.Schema, includes []string, excludes []string) ([]*documents.Document, error) {
 	if s == nil || s.Raw() == nil {
@@ -37,7 +65,7 @@ func (l *GraphQLDocumentLoader) Load(ctx context.Context, s schema.Schema, inclu
 	seenFiles := make(map[string]bool)
 
 	for _, pattern := range includes {
-		matches, err := filepath.Glob(pattern)
+		matches, err := Glob(pattern)
 		if err != nil {
 			return nil, fmt.Errorf("invalid glob pattern %q: %w", pattern, err)
 		}
@@ -61,7 +89,16 @@ func (l *GraphQLDocumentLoader) Load(ctx context.Context, s schema.Schema, inclu
 
 			doc, err := l.LoadFile(ctx, s, path)
 			if err != nil {
-				// Skip files with errors (might be non-GraphQL files)
+				// Skip files with errors, keeping the reason for Errors.
+				// Schema files matched by the same globs are not documents.
+				content, _ := os.ReadFile(path)
+				if isSchemaDocument(path, string(content)) {
+					continue
+				}
+				l.errors = append(l.errors, documents.LocateErrors(&documents.Document{
+					FilePath: path,
+					Content:  string(content),
+				}, err)...)
 				continue
 			}
 
@@ -70,9 +107,27 @@ func (l *GraphQLDocumentLoader) Load(ctx context.Context, s schema.Schema, inclu
 		}
 	}
 
+	if l.resolveImports {
+		docs = dedupeImportedFragments(docs)
+	}
+
 	return docs, nil
 }
 
+// isSchemaDocument reports whether content holds only type system definitions
+func isSchemaDocument(path string, content string) bool {
+	doc, err := parser.ParseSchema(&ast.Source{Name: path, Input: content})
+	if err != nil {
+		return false
+	}
+	return len(doc.Definitions)+len(doc.Extensions)+len(doc.Directives)+len(doc.Schema)+len(doc.SchemaExtension) > 0
+}
+
+// Errors returns the parse and validation errors of the files Load skipped
+func (l *GraphQLDocumentLoader) Errors() documents.SourceErrors {
+	return l.errors
+}
+
 // LoadFile loads a single document from a file
 func (l *GraphQLDocumentLoader) LoadFile(ctx context.Context, s schema.Schema, path string) (*documents.Document, error) {
 	if s == nil || s.Raw() == nil {
@@ -95,7 +150,15 @@ func (l *GraphQLDocumentLoader) LoadFile(ctx context.Context, s schema.Schema, p
 		return nil, fmt.Errorf("reading file: %w", err)
 	}
 
-	doc, err := l.LoadString(ctx, s, string(content), path)
+	var imports []*ast.Source
+	if l.resolveImports {
+		imports, err = collectImports(path, string(content), make(map[string]bool))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	doc, err := l.loadSources(s, string(content), path, imports)
 	if err != nil {
 		return nil, err
 	}
@@ -112,18 +175,39 @@ func (l *GraphQLDocumentLoader) LoadString(ctx context.Context, s schema.Schema,
 		return nil, fmt.Errorf("schema is required for document validation")
 	}
 
-	// Parse and validate the document using gqlparser
+	return l.loadSources(s, content, sourcePath, nil)
+}
+
+// loadSources parses a document together with the fragments of its imported
+// sources and validates the result against the schema
+func (l *GraphQLDocumentLoader) loadSources(s schema.Schema, content string, sourcePath string, imports []*ast.Source) (*documents.Document, error) {
 	source := &ast.Source{
 		Name:  sourcePath,
 		Input: content,
 	}
 
-	// Parse the query document and validate against schema
-	queryDoc, err := gqlparser.LoadQuery(s.Raw(), content)
+	queryDoc, err := parser.ParseQuery(source)
 	if err != nil {
 		return nil, fmt.Errorf("parsing/validating GraphQL document: %w", err)
 	}
 
+	for _, imported := range imports {
+		importedDoc, err := parser.ParseQuery(imported)
+		if err != nil {
+			return nil, fmt.Errorf("parsing imported document %s: %w", imported.Name, err)
+		}
+		queryDoc.Fragments = append(queryDoc.Fragments, importedDoc.Fragments...)
+	}
+
+	// Validate the combined document against the schema
+	errs := documents.AllowFragmentArguments(queryDoc, validator.Validate(s.Raw(), queryDoc))
+	for _, name := range l.allowedDirectives {
+		errs = documents.AllowDirective(name, errs)
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("parsing/validating GraphQL document: %w", errs)
+	}
+
 	// Create document
 	doc := &documents.Document{
 		FilePath: sourcePath,
@@ -152,6 +236,92 @@ func (l *GraphQLDocumentLoader) LoadString(ctx context.Context, s schema.Schema,
 	return doc, nil
 }
 
+// collectImports resolves the #import includes of a document, depth first.
+// Each file is returned once; visited guards against import cycles.
+func collectImports(path string, content string, visited map[string]bool) ([]*ast.Source, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("resolving %s: %w", path, err)
+	}
+	visited[absPath] = true
+
+	var sources []*ast.Source
+	for _, line := range strings.Split(content, "\n") {
+		match := importPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		importPath := match[1]
+		if !filepath.IsAbs(importPath) {
+			importPath = filepath.Join(filepath.Dir(absPath), importPath)
+		}
+		if visited[importPath] {
+			continue
+		}
+
+		imported, err := os.ReadFile(importPath)
+		if err != nil {
+			return nil, fmt.Errorf("resolving import %q in %s: %w", match[1], path, err)
+		}
+
+		nested, err := collectImports(importPath, string(imported), visited)
+		if err != nil {
+			return nil, err
+		}
+
+		sources = append(sources, &ast.Source{Name: importPath, Input: string(imported)})
+		sources = append(sources, nested...)
+	}
+
+	return sources, nil
+}
+
+// dedupeImportedFragments drops imported fragments that are already defined
+// by another loaded document, so each fragment is generated only once
+func dedupeImportedFragments(docs []*documents.Document) []*documents.Document {
+	defined := make(map[string]bool)
+	for _, doc := range docs {
+		for _, frag := range doc.AST.Fragments {
+			if !isImported(doc, frag) {
+				defined[frag.Name] = true
+			}
+		}
+	}
+
+	result := make([]*documents.Document, 0, len(docs))
+	for _, doc := range docs {
+		fragments := make(ast.FragmentDefinitionList, 0, len(doc.AST.Fragments))
+		for _, frag := range doc.AST.Fragments {
+			if isImported(doc, frag) {
+				if defined[frag.Name] {
+					continue
+				}
+				defined[frag.Name] = true
+			}
+			fragments = append(fragments, frag)
+		}
+
+		if len(fragments) == len(doc.AST.Fragments) {
+			result = append(result, doc)
+			continue
+		}
+
+		queryDoc := *doc.AST
+		queryDoc.Fragments = fragments
+		deduped := *doc
+		deduped.AST = &queryDoc
+		result = append(result, &deduped)
+	}
+
+	return result
+}
+
+// isImported reports whether a fragment was inlined from an #import include
+func isImported(doc *documents.Document, frag *ast.FragmentDefinition) bool {
+	return frag.Position != nil && frag.Position.Src != nil && frag.Position.Src.Name != doc.FilePath
+}
+
 // LoadDocumentsFromGlob loads documents from files matching glob patterns
 func LoadDocumentsFromGlob(ctx context.Context, s schema.Schema, patterns []string) ([]*documents.Document, error) {
 	loader := NewGraphQLDocumentLoader()
@@ -246,8 +416,7 @@ func ExtractFragmentString(doc *documents.Document, fragmentName string) (string
 // shouldExclude checks if a path matches any exclude pattern
 func shouldExclude(path string, excludes []string) bool {
 	for _, pattern := range excludes {
-		matched, err := filepath.Match(pattern, path)
-		if err == nil && matched {
+		if MatchGlob(pattern, path) {
 			return true
 		}
 
@@ -0,0 +1,243 @@
+package loader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jzeiders/graphql-go-gen/pkg/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func loadTestSchema(t *testing.T) schema.Schema {
+	t.Helper()
+	raw, err := gqlparser.LoadSchema(&ast.Source{
+		Name: "schema.graphql",
+		Input: `
+			type Query {
+				user(id: ID!): User
+			}
+
+			type User {
+				id: ID!
+				name: String!
+				friends: [User!]!
+			}
+		`,
+	})
+	require.NoError(t, err)
+	return schema.NewSchema(raw, "schema.graphql")
+}
+
+func writeTestFile(t *testing.T, path string, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestGraphQLDocumentLoader_ResolveImports(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFile(t, filepath.Join(tmpDir, "fragments", "user.graphql"), `
+#import "./friend.graphql"
+
+fragment UserFields on User {
+  id
+  name
+  friends {
+    ...FriendFields
+  }
+}
+`)
+	writeTestFile(t, filepath.Join(tmpDir, "fragments", "friend.graphql"), `
+fragment FriendFields on User {
+  id
+}
+`)
+	writeTestFile(t, filepath.Join(tmpDir, "queries", "user.graphql"), `
+#import "../fragments/user.graphql"
+
+query GetUser($id: ID!) {
+  user(id: $id) {
+    ...UserFields
+  }
+}
+`)
+
+	s := loadTestSchema(t)
+	ctx := context.Background()
+	queryPath := filepath.Join(tmpDir, "queries", "user.graphql")
+
+	t.Run("imports are ignored by default", func(t *testing.T) {
+		loader := NewGraphQLDocumentLoader()
+		_, err := loader.LoadFile(ctx, s, queryPath)
+		assert.Error(t, err)
+	})
+
+	t.Run("imports are inlined before validation", func(t *testing.T) {
+		loader := NewGraphQLDocumentLoader()
+		loader.SetResolveImports(true)
+
+		doc, err := loader.LoadFile(ctx, s, queryPath)
+		require.NoError(t, err)
+		require.Len(t, doc.AST.Operations, 1)
+		assert.Equal(t, "GetUser", doc.AST.Operations[0].Name)
+		assert.NotNil(t, doc.AST.Fragments.ForName("UserFields"))
+		assert.NotNil(t, doc.AST.Fragments.ForName("FriendFields"))
+	})
+
+	t.Run("imported fragments are not duplicated across loaded files", func(t *testing.T) {
+		loader := NewGraphQLDocumentLoader()
+		loader.SetResolveImports(true)
+
+		writeTestFile(t, filepath.Join(tmpDir, "queries", "friends.graphql"), `
+#import "../fragments/friend.graphql"
+
+query GetFriends($id: ID!) {
+  user(id: $id) {
+    friends {
+      ...FriendFields
+    }
+  }
+}
+`)
+
+		docs, err := loader.Load(ctx, s, []string{
+			filepath.Join(tmpDir, "queries", "*.graphql"),
+		}, nil)
+		require.NoError(t, err)
+		require.Len(t, docs, 2)
+
+		counts := make(map[string]int)
+		for _, doc := range docs {
+			for _, frag := range doc.AST.Fragments {
+				counts[frag.Name]++
+			}
+		}
+		assert.Equal(t, map[string]int{"UserFields": 1, "FriendFields": 1}, counts)
+	})
+
+	t.Run("missing import is reported", func(t *testing.T) {
+		brokenPath := filepath.Join(tmpDir, "broken", "query.graphql")
+		writeTestFile(t, brokenPath, `
+#import "./missing.graphql"
+
+query Broken {
+  user(id: "1") {
+    id
+  }
+}
+`)
+		loader := NewGraphQLDocumentLoader()
+		loader.SetResolveImports(true)
+
+		_, err := loader.LoadFile(ctx, s, brokenPath)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing.graphql")
+	})
+
+	t.Run("import cycles terminate", func(t *testing.T) {
+		writeTestFile(t, filepath.Join(tmpDir, "cycle", "a.graphql"), `
+#import "./b.graphql"
+
+fragment A on User {
+  id
+  ...B
+}
+`)
+		writeTestFile(t, filepath.Join(tmpDir, "cycle", "b.graphql"), `
+#import "./a.graphql"
+
+fragment B on User {
+  name
+}
+`)
+		writeTestFile(t, filepath.Join(tmpDir, "cycle", "query.graphql"), `
+#import "./a.graphql"
+
+query Cycle {
+  user(id: "1") {
+    ...A
+  }
+}
+`)
+		loader := NewGraphQLDocumentLoader()
+		loader.SetResolveImports(true)
+
+		doc, err := loader.LoadFile(ctx, s, filepath.Join(tmpDir, "cycle", "query.graphql"))
+		require.NoError(t, err)
+		assert.Len(t, doc.AST.Fragments, 2)
+	})
+}
+
+func TestGraphQLDocumentLoader_Errors(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFile(t, filepath.Join(tmpDir, "valid.graphql"), `
+query GetUser($id: ID!) {
+  user(id: $id) {
+    id
+  }
+}
+`)
+	writeTestFile(t, filepath.Join(tmpDir, "schema.graphql"), `
+type Query {
+  ping: String
+}
+`)
+	writeTestFile(t, filepath.Join(tmpDir, "invalid.graphql"), `
+query GetUser2($id: ID!) {
+  user(id: $id) {
+    nope
+  }
+}
+`)
+
+	loader := NewGraphQLDocumentLoader()
+	docs, err := loader.Load(context.Background(), loadTestSchema(t), []string{
+		filepath.Join(tmpDir, "*.graphql"),
+	}, nil)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+
+	errs := loader.Errors()
+	require.Len(t, errs, 1)
+	assert.Equal(t, filepath.Join(tmpDir, "invalid.graphql"), errs[0].FilePath)
+	assert.Equal(t, 4, errs[0].Line)
+	assert.Equal(t, 5, errs[0].Column)
+	assert.Equal(t, "query GetUser2", errs[0].Definition)
+}
+
+func TestGraphQLDocumentLoader_FragmentArguments(t *testing.T) {
+	s := loadTestSchema(t)
+	loader := NewGraphQLDocumentLoader()
+
+	doc, err := loader.LoadString(context.Background(), s, `
+query GetUser($id: ID!) {
+  user(id: $id) {
+    ...UserName @arguments(withName: true)
+  }
+}
+
+fragment UserName on User @arguments(withName: {type: "Boolean!", defaultValue: false}) {
+  id
+  name @include(if: $withName)
+}
+`, "user.graphql")
+	require.NoError(t, err)
+	assert.Len(t, doc.AST.Fragments, 1)
+
+	_, err = loader.LoadString(context.Background(), s, `
+query GetUser($id: ID!) {
+  user(id: $id) { ...UserName }
+}
+
+fragment UserName on User @arguments(withName: Boolean) {
+  name @include(if: $withId)
+}
+`, "user.graphql")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"$withId"`, "variables that are not fragment arguments must be defined")
+}
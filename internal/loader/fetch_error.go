@@ -0,0 +1,89 @@
+package loader
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// FetchErrorKind classifies why a remote schema could not be fetched
+type FetchErrorKind string
+
+const (
+	// FetchErrorNetwork means the server could not be reached
+	FetchErrorNetwork FetchErrorKind = "network"
+	// FetchErrorAuth means the server answered 401 or 403
+	FetchErrorAuth FetchErrorKind = "auth"
+	// FetchErrorNotFound means the server answered 404 or 410
+	FetchErrorNotFound FetchErrorKind = "not_found"
+	// FetchErrorClient means the server answered any other 4xx status
+	FetchErrorClient FetchErrorKind = "client"
+	// FetchErrorServer means the server answered a 5xx status
+	FetchErrorServer FetchErrorKind = "server"
+	// FetchErrorGraphQL means introspection returned GraphQL errors
+	FetchErrorGraphQL FetchErrorKind = "graphql"
+)
+
+// FetchError is returned when a remote schema cannot be fetched. Its message
+// names the kind of failure and suggests a fix.
+type FetchError struct {
+	Kind FetchErrorKind
+	// StatusCode is the HTTP status for auth, client and server errors
+	StatusCode int
+	// Status is the HTTP status line, e.g. "401 Unauthorized"
+	Status string
+	// Messages holds the GraphQL error messages for graphql errors
+	Messages []string
+	// Err is the underlying error for network errors
+	Err error
+	// RetryAfter is the delay the server asked for with Retry-After
+	RetryAfter time.Duration
+}
+
+func (e *FetchError) Error() string {
+	switch e.Kind {
+	case FetchErrorNetwork:
+		return fmt.Sprintf("network error: %v; check that the URL is correct and the server is running and reachable", e.Err)
+	case FetchErrorAuth:
+		return fmt.Sprintf("authentication failed (HTTP %s); check the token in the source headers and that any environment variables it references are set", e.Status)
+	case FetchErrorNotFound:
+		return fmt.Sprintf("endpoint not found (HTTP %s); check that the URL points at the GraphQL endpoint", e.Status)
+	case FetchErrorClient:
+		return fmt.Sprintf("request rejected (HTTP %s); check that the URL points at the GraphQL endpoint", e.Status)
+	case FetchErrorServer:
+		return fmt.Sprintf("server error (HTTP %s); the server may be down or overloaded, try again later", e.Status)
+	case FetchErrorGraphQL:
+		return fmt.Sprintf("introspection returned GraphQL errors: %s; check that introspection is enabled and your credentials allow it", strings.Join(e.Messages, "; "))
+	}
+	return fmt.Sprintf("fetching schema failed: %v", e.Err)
+}
+
+// Unwrap returns the underlying network error, if any
+func (e *FetchError) Unwrap() error {
+	return e.Err
+}
+
+// networkError wraps an error returned by the HTTP client
+func networkError(err error) *FetchError {
+	return &FetchError{Kind: FetchErrorNetwork, Err: err}
+}
+
+// statusError classifies a non-200 response by its status class
+func statusError(resp *http.Response) *FetchError {
+	err := &FetchError{
+		Kind:       FetchErrorClient,
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		err.Kind = FetchErrorAuth
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		err.Kind = FetchErrorNotFound
+	case resp.StatusCode >= 500:
+		err.Kind = FetchErrorServer
+	}
+	return err
+}
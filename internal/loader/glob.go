@@ -0,0 +1,177 @@
+package loader
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Glob returns the files matching pattern, sorted. Besides the wildcards of
+// filepath.Match, patterns support ** for any number of directories and
+// brace sets such as *.{ts,tsx}. node_modules directories are only searched
+// when the pattern names them.
+func Glob(pattern string) ([]string, error) {
+	seen := make(map[string]bool)
+	for _, expanded := range expandBraces(pattern) {
+		if err := validateGlob(expanded); err != nil {
+			return nil, err
+		}
+
+		if !strings.ContainsAny(expanded, "*?[") {
+			// No wildcards: the pattern is a path
+			if info, err := os.Stat(expanded); err == nil && !info.IsDir() {
+				seen[expanded] = true
+			}
+			continue
+		}
+		base := GlobBase(expanded)
+
+		searchModules := strings.Contains(expanded, "node_modules")
+		_ = filepath.WalkDir(base, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				// Unreadable entries are skipped like a missing base
+				return nil
+			}
+			if d.IsDir() {
+				if d.Name() == "node_modules" && !searchModules && p != base {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if matchGlob(expanded, p) {
+				seen[p] = true
+			}
+			return nil
+		})
+	}
+
+	matches := make([]string, 0, len(seen))
+	for p := range seen {
+		matches = append(matches, p)
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// MatchGlob reports whether name matches pattern, with the ** and brace set
+// syntax of Glob. Malformed patterns match nothing.
+func MatchGlob(pattern, name string) bool {
+	for _, expanded := range expandBraces(pattern) {
+		if matchGlob(expanded, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchGlob(pattern, name string) bool {
+	return matchSegments(
+		strings.Split(filepath.ToSlash(filepath.Clean(pattern)), "/"),
+		strings.Split(filepath.ToSlash(filepath.Clean(name)), "/"),
+	)
+}
+
+// matchSegments matches path segments, where a ** segment matches any
+// number of them
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if matched, err := path.Match(pattern[0], name[0]); err != nil || !matched {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// validateGlob reports a malformed segment of pattern, as filepath.Glob does
+func validateGlob(pattern string) error {
+	for _, segment := range strings.Split(filepath.ToSlash(pattern), "/") {
+		if _, err := path.Match(segment, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GlobBase returns the directory before the first segment of pattern with a
+// wildcard or brace set, where the files it matches are searched for, or the
+// directory of pattern when it is a plain path
+func GlobBase(pattern string) string {
+	segments := strings.Split(pattern, string(filepath.Separator))
+	for i, segment := range segments {
+		if strings.ContainsAny(segment, "*?[{") {
+			base := strings.Join(segments[:i], string(filepath.Separator))
+			if base == "" {
+				if filepath.IsAbs(pattern) {
+					return string(filepath.Separator)
+				}
+				return "."
+			}
+			return base
+		}
+	}
+	return filepath.Dir(pattern)
+}
+
+// expandBraces expands the brace sets of pattern, e.g. *.{ts,tsx} into *.ts
+// and *.tsx. Sets may nest; an unclosed brace is kept as written.
+func expandBraces(pattern string) []string {
+	depth, start := 0, -1
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '{':
+			if depth == 0 {
+				start = i
+			}
+			depth++
+		case '}':
+			if depth == 0 {
+				continue
+			}
+			depth--
+			if depth > 0 {
+				continue
+			}
+			var expanded []string
+			for _, alternative := range splitAlternatives(pattern[start+1 : i]) {
+				expanded = append(expanded, expandBraces(pattern[:start]+alternative+pattern[i+1:])...)
+			}
+			return expanded
+		}
+	}
+	return []string{pattern}
+}
+
+// splitAlternatives splits the inside of a brace set at its top-level commas
+func splitAlternatives(set string) []string {
+	var alternatives []string
+	depth, start := 0, 0
+	for i := 0; i < len(set); i++ {
+		switch set[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				alternatives = append(alternatives, set[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(alternatives, set[start:])
+}
@@ -0,0 +1,105 @@
+package loader
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGlob(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"src/app.ts",
+		"src/pages/home.tsx",
+		"src/pages/home.test.tsx",
+		"src/pages/deep/profile.ts",
+		"src/styles.css",
+		"src/node_modules/lib/index.ts",
+		"node_modules/pkg/index.ts",
+	} {
+		writeTestFile(t, filepath.Join(dir, name), "")
+	}
+	paths := func(names ...string) []string {
+		result := make([]string, len(names))
+		for i, name := range names {
+			result[i] = filepath.Join(dir, name)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		want    []string
+	}{
+		{
+			name:    "recursive with brace set",
+			pattern: "src/**/*.{ts,tsx}",
+			want:    paths("src/app.ts", "src/pages/deep/profile.ts", "src/pages/home.test.tsx", "src/pages/home.tsx"),
+		},
+		{
+			name:    "double star matches no directories",
+			pattern: "src/**/app.ts",
+			want:    paths("src/app.ts"),
+		},
+		{
+			name:    "single star stays in one directory",
+			pattern: "src/pages/*.tsx",
+			want:    paths("src/pages/home.test.tsx", "src/pages/home.tsx"),
+		},
+		{
+			name:    "nested brace sets",
+			pattern: "src/{app.ts,pages/{home,deep/profile}.*}",
+			want:    paths("src/app.ts", "src/pages/deep/profile.ts", "src/pages/home.test.tsx", "src/pages/home.tsx"),
+		},
+		{
+			name:    "node_modules when named",
+			pattern: "node_modules/**/*.ts",
+			want:    paths("node_modules/pkg/index.ts"),
+		},
+		{
+			name:    "plain path",
+			pattern: "src/styles.css",
+			want:    paths("src/styles.css"),
+		},
+		{
+			name:    "no matches",
+			pattern: "lib/**/*.ts",
+			want:    []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, err := Glob(filepath.Join(dir, tt.pattern))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, matches)
+		})
+	}
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		_, err := Glob(filepath.Join(dir, "src/[.ts"))
+		assert.Error(t, err)
+	})
+}
+
+func TestMatchGlob(t *testing.T) {
+	assert.True(t, MatchGlob("src/**/*.{ts,tsx}", "src/a/b/c.tsx"))
+	assert.True(t, MatchGlob("src/**/*.{ts,tsx}", "src/c.ts"))
+	assert.False(t, MatchGlob("src/**/*.{ts,tsx}", "src/c.js"))
+	assert.True(t, MatchGlob("**/*.test.tsx", "src/pages/home.test.tsx"))
+	assert.True(t, MatchGlob("src/legacy/**", "src/legacy/old/query.ts"))
+	assert.False(t, MatchGlob("src/*.ts", "src/pages/home.ts"))
+	assert.False(t, MatchGlob("src/[.ts", "src/[.ts"), "malformed patterns match nothing")
+}
+
+func TestGlobBase(t *testing.T) {
+	assert.Equal(t, "src", GlobBase("src/**/*.ts"))
+	assert.Equal(t, "src/gql", GlobBase("src/gql/*.graphql"))
+	assert.Equal(t, "src", GlobBase("src/{app,lib}/*.ts"))
+	assert.Equal(t, "schema", GlobBase("schema/schema.graphql"))
+	assert.Equal(t, ".", GlobBase("*.graphql"))
+	assert.Equal(t, "/", GlobBase("/*.graphql"))
+}
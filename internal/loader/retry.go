@@ -0,0 +1,121 @@
+package loader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// defaultRetryableStatusCodes are the HTTP statuses retried unless a source
+// configures its own: timeouts, rate limiting and transient server errors.
+// Other statuses, e.g. 400 or 401, fail on the first attempt.
+var defaultRetryableStatusCodes = []int{
+	http.StatusRequestTimeout,
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// defaultMaxRetryAfter caps how long a Retry-After header can delay a retry
+const defaultMaxRetryAfter = 30 * time.Second
+
+// retryable reports whether a failed fetch is worth another attempt. HTTP
+// errors are retried only for the statuses in codes, or the loader's
+// defaults when codes is empty; other failures, e.g. network errors, are
+// always retried.
+func (l *UniversalSchemaLoader) retryable(err error, codes []int) bool {
+	var fetchErr *FetchError
+	if !errors.As(err, &fetchErr) || fetchErr.StatusCode == 0 {
+		return true
+	}
+	if len(codes) == 0 {
+		codes = l.retryableStatusCodes
+	}
+	for _, code := range codes {
+		if code == fetchErr.StatusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// exhaustedError wraps the last error once every attempt has failed. When
+// the server was unreachable or answered 5xx throughout, the message says
+// it was unavailable rather than that the request itself was wrong.
+func (l *UniversalSchemaLoader) exhaustedError(action string, err error) error {
+	var fetchErr *FetchError
+	if errors.As(err, &fetchErr) && (fetchErr.Kind == FetchErrorNetwork || fetchErr.Kind == FetchErrorServer) {
+		return fmt.Errorf("server unavailable after %d attempts: %w", l.defaultRetries, err)
+	}
+	return fmt.Errorf("%s after %d attempts: %w", action, l.defaultRetries, err)
+}
+
+// retryDelay returns how long to wait before retry attempt, which counts
+// from 1, after err. A Retry-After sent with 429 or 503 is honored up to
+// the loader's cap; otherwise the exponential backoff gets up to half again
+// as much random jitter, so clients failing together do not retry in step.
+func (l *UniversalSchemaLoader) retryDelay(attempt int, err error) time.Duration {
+	var fetchErr *FetchError
+	if errors.As(err, &fetchErr) && fetchErr.RetryAfter > 0 &&
+		(fetchErr.StatusCode == http.StatusTooManyRequests || fetchErr.StatusCode == http.StatusServiceUnavailable) {
+		if fetchErr.RetryAfter > l.maxRetryAfter {
+			return l.maxRetryAfter
+		}
+		return fetchErr.RetryAfter
+	}
+
+	backoff := time.Duration(1<<uint(attempt-1)) * time.Second
+	return backoff + l.jitter(backoff/2)
+}
+
+// waitForRetry sleeps before retry attempt, returning early with the
+// context's error when it is canceled
+func (l *UniversalSchemaLoader) waitForRetry(ctx context.Context, attempt int, err error) error {
+	return l.sleep(ctx, l.retryDelay(attempt, err))
+}
+
+// randomJitter returns a random duration in [0, max)
+func randomJitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(max)))
+}
+
+// sleepContext sleeps for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// parseRetryAfter reads a Retry-After header, given in seconds or as an
+// HTTP date; zero means none or unparseable
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := date.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,185 @@
+package loader
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jzeiders/graphql-go-gen/pkg/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordDelays makes the loader record its retry delays instead of sleeping
+func recordDelays(l *UniversalSchemaLoader) *[]time.Duration {
+	var delays []time.Duration
+	l.sleep = func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+	return &delays
+}
+
+func TestUniversalSchemaLoader_RetryBackoff(t *testing.T) {
+	ctx := context.Background()
+
+	// failing answers with the statuses in order, then serves the schema
+	failing := func(t *testing.T, header http.Header, statuses ...int) (*httptest.Server, *int) {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests <= len(statuses) {
+				for key, values := range header {
+					w.Header()[key] = values
+				}
+				w.WriteHeader(statuses[requests-1])
+				return
+			}
+			w.Write([]byte(`type Query { hello: String }`))
+		}))
+		t.Cleanup(server.Close)
+		return server, &requests
+	}
+
+	t.Run("exponential backoff with jitter", func(t *testing.T) {
+		server, requests := failing(t, nil, http.StatusBadGateway, http.StatusInternalServerError)
+		loader := NewUniversalSchemaLoader()
+		delays := recordDelays(loader)
+		var jitterMax []time.Duration
+		loader.jitter = func(max time.Duration) time.Duration {
+			jitterMax = append(jitterMax, max)
+			return max / 4
+		}
+
+		_, err := loader.Load(ctx, []schema.Source{{ID: "api", Kind: "url", URL: server.URL}})
+		require.NoError(t, err)
+		assert.Equal(t, 3, *requests)
+		assert.Equal(t, []time.Duration{1125 * time.Millisecond, 2250 * time.Millisecond}, *delays)
+		assert.Equal(t, []time.Duration{500 * time.Millisecond, time.Second}, jitterMax)
+	})
+
+	t.Run("random jitter stays below half the backoff", func(t *testing.T) {
+		loader := NewUniversalSchemaLoader()
+		for i := 0; i < 100; i++ {
+			delay := loader.retryDelay(2, &FetchError{Kind: FetchErrorServer, StatusCode: http.StatusBadGateway})
+			assert.GreaterOrEqual(t, delay, 2*time.Second)
+			assert.Less(t, delay, 3*time.Second)
+		}
+	})
+
+	t.Run("Retry-After on 429", func(t *testing.T) {
+		server, requests := failing(t, http.Header{"Retry-After": {"7"}}, http.StatusTooManyRequests)
+		loader := NewUniversalSchemaLoader()
+		delays := recordDelays(loader)
+
+		_, err := loader.Load(ctx, []schema.Source{{ID: "api", Kind: "introspection", URL: server.URL}})
+		require.Error(t, err, "the schema is SDL, not an introspection result")
+		assert.Equal(t, 7*time.Second, (*delays)[0])
+		assert.Equal(t, 3, *requests)
+	})
+
+	t.Run("Retry-After is capped", func(t *testing.T) {
+		server, _ := failing(t, http.Header{"Retry-After": {"3600"}}, http.StatusServiceUnavailable)
+		loader := NewUniversalSchemaLoader()
+		loader.SetMaxRetryAfter(10 * time.Second)
+		delays := recordDelays(loader)
+
+		_, err := loader.Load(ctx, []schema.Source{{ID: "api", Kind: "url", URL: server.URL}})
+		require.NoError(t, err)
+		assert.Equal(t, []time.Duration{10 * time.Second}, *delays)
+	})
+
+	t.Run("Retry-After as a date", func(t *testing.T) {
+		now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		assert.Equal(t, 90*time.Second, parseRetryAfter(now.Add(90*time.Second).Format(http.TimeFormat), now))
+		assert.Zero(t, parseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now))
+		assert.Zero(t, parseRetryAfter("soon", now))
+	})
+
+	t.Run("non-retryable status fails fast", func(t *testing.T) {
+		for _, status := range []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound} {
+			server, requests := failing(t, nil, status, status, status)
+			loader := NewUniversalSchemaLoader()
+			delays := recordDelays(loader)
+
+			_, err := loader.Load(ctx, []schema.Source{{ID: "api", Kind: "url", URL: server.URL}})
+			require.Error(t, err)
+			assert.Equal(t, 1, *requests, "status %d should not be retried", status)
+			assert.Empty(t, *delays)
+			assert.NotContains(t, err.Error(), "attempts")
+		}
+	})
+
+	t.Run("answered introspection fails fast", func(t *testing.T) {
+		for name, body := range map[string]string{
+			"graphql errors": `{"errors": [{"message": "introspection is disabled"}]}`,
+			"no schema":      `{"data": {}}`,
+		} {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				w.Write([]byte(body))
+			}))
+			t.Cleanup(server.Close)
+			loader := NewUniversalSchemaLoader()
+			delays := recordDelays(loader)
+
+			_, err := loader.Load(ctx, []schema.Source{{ID: "api", Kind: "introspection", URL: server.URL}})
+			require.Error(t, err)
+			assert.Equal(t, 1, requests, "%s should not be retried", name)
+			assert.Empty(t, *delays)
+			assert.NotContains(t, err.Error(), "attempts")
+		}
+	})
+
+	t.Run("final error distinguishes unavailable servers", func(t *testing.T) {
+		server, requests := failing(t, nil, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+		loader := NewUniversalSchemaLoader()
+		recordDelays(loader)
+
+		_, err := loader.Load(ctx, []schema.Source{{ID: "api", Kind: "url", URL: server.URL}})
+		require.Error(t, err)
+		assert.Equal(t, 3, *requests)
+		assert.Contains(t, err.Error(), "server unavailable after 3 attempts")
+
+		server, _ = failing(t, nil, http.StatusNotFound)
+		_, err = loader.Load(ctx, []schema.Source{{ID: "api", Kind: "introspection", URL: server.URL}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "endpoint not found (HTTP 404 Not Found)")
+		assert.NotContains(t, err.Error(), "unavailable")
+
+		server, _ = failing(t, nil, http.StatusForbidden)
+		_, err = loader.Load(ctx, []schema.Source{{ID: "api", Kind: "url", URL: server.URL}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "authentication failed (HTTP 403 Forbidden)")
+	})
+
+	t.Run("configurable retryable statuses", func(t *testing.T) {
+		server, requests := failing(t, nil, http.StatusConflict)
+		loader := NewUniversalSchemaLoader()
+		recordDelays(loader)
+
+		_, err := loader.Load(ctx, []schema.Source{{ID: "api", Kind: "url", URL: server.URL, RetryableStatusCodes: []int{http.StatusConflict}}})
+		require.NoError(t, err)
+		assert.Equal(t, 2, *requests)
+
+		server, requests = failing(t, nil, http.StatusBadGateway)
+		loader.SetRetryableStatusCodes(http.StatusServiceUnavailable)
+		_, err = loader.Load(ctx, []schema.Source{{ID: "api", Kind: "url", URL: server.URL}})
+		require.Error(t, err)
+		assert.Equal(t, 1, *requests)
+	})
+
+	t.Run("canceled context stops waiting", func(t *testing.T) {
+		server, requests := failing(t, nil, http.StatusBadGateway, http.StatusBadGateway)
+		loader := NewUniversalSchemaLoader()
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := loader.loadFromURL(ctx, schema.Source{URL: server.URL})
+		require.ErrorIs(t, err, context.Canceled)
+		assert.LessOrEqual(t, *requests, 1)
+	})
+}
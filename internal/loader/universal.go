@@ -3,6 +3,8 @@ package loader
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,6 +12,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -37,6 +40,23 @@ type UniversalSchemaLoader struct {
 	defaultTimeout time.Duration
 	defaultRetries int
 	defaultCacheTTL time.Duration
+
+	// Retries: the HTTP statuses worth retrying and the longest delay a
+	// Retry-After header may ask for. sleep and jitter are replaced in tests.
+	retryableStatusCodes []int
+	maxRetryAfter        time.Duration
+	sleep                func(context.Context, time.Duration) error
+	jitter               func(time.Duration) time.Duration
+
+	// warnings collected while loading, e.g. fallbacks to cached schemas
+	warnings []string
+
+	// sdlCache maps the SHA-256 of an introspection result to its SDL, so
+	// identical introspections are converted once. convertIntrospection is
+	// replaced in tests.
+	sdlCache             map[string]string
+	sdlCacheMu           sync.Mutex
+	convertIntrospection func(json.RawMessage) (string, error)
 }
 
 // NewUniversalSchemaLoader creates a new universal schema loader
@@ -49,9 +69,26 @@ func NewUniversalSchemaLoader() *UniversalSchemaLoader {
 		defaultTimeout:  30 * time.Second,
 		defaultRetries:  3,
 		defaultCacheTTL: 5 * time.Minute,
+
+		retryableStatusCodes: defaultRetryableStatusCodes,
+		maxRetryAfter:        defaultMaxRetryAfter,
+		sleep:                sleepContext,
+		jitter:               randomJitter,
+
+		sdlCache:             make(map[string]string),
+		convertIntrospection: introspectionToSDL,
 	}
 }
 
+// NewUniversalSchemaLoaderWithClient creates a universal schema loader that
+// fetches URL and introspection schemas with client, e.g. one whose
+// transport uses a proxy or presents a client certificate
+func NewUniversalSchemaLoaderWithClient(client *http.Client) *UniversalSchemaLoader {
+	l := NewUniversalSchemaLoader()
+	l.SetHTTPClient(client)
+	return l
+}
+
 // Load loads schema from multiple sources
 func (l *UniversalSchemaLoader) Load(ctx context.Context, sources []schema.Source) (schema.Schema, error) {
 	var astSources []*ast.Source
@@ -68,13 +105,17 @@ func (l *UniversalSchemaLoader) Load(ctx context.Context, sources []schema.Sourc
 			}
 
 		case "url":
-			content, err = l.loadFromURL(ctx, source.URL, source.Headers)
+			content, err = l.loadWithCacheFile(source, func() (string, error) {
+				return l.loadFromURL(ctx, source)
+			})
 			if err != nil {
 				return nil, fmt.Errorf("loading URL schema %s: %w", source.URL, err)
 			}
 
 		case "introspection":
-			content, err = l.loadFromIntrospection(ctx, source.URL, source.Headers)
+			content, err = l.loadWithCacheFile(source, func() (string, error) {
+				return l.loadFromIntrospection(ctx, source)
+			})
 			if err != nil {
 				return nil, fmt.Errorf("loading introspection schema %s: %w", source.URL, err)
 			}
@@ -149,7 +190,7 @@ func (l *UniversalSchemaLoader) LoadFromFile(ctx context.Context, path string) (
 
 // LoadFromURL loads schema from a URL with retry logic
 func (l *UniversalSchemaLoader) LoadFromURL(ctx context.Context, url string, headers map[string]string) (schema.Schema, error) {
-	content, err := l.loadFromURL(ctx, url, headers)
+	content, err := l.loadFromURL(ctx, schema.Source{Kind: "url", URL: url, Headers: headers})
 	if err != nil {
 		return nil, err
 	}
@@ -215,8 +256,11 @@ func (l *UniversalSchemaLoader) loadFromFile(ctx context.Context, path string) (
 	return string(content), nil
 }
 
-// loadFromURL fetches schema content from a URL with retry logic
-func (l *UniversalSchemaLoader) loadFromURL(ctx context.Context, urlStr string, headers map[string]string) (string, error) {
+// loadFromURL fetches schema content from the source's URL with retry logic.
+// A non-zero source timeout overrides the loader's HTTP timeout.
+func (l *UniversalSchemaLoader) loadFromURL(ctx context.Context, source schema.Source) (string, error) {
+	urlStr, headers := source.URL, source.Headers
+
 	// No cache checking here - just fetch the content
 	// Cache is handled at the Schema level, not content level
 
@@ -230,12 +274,16 @@ func (l *UniversalSchemaLoader) loadFromURL(ctx context.Context, urlStr string,
 		return "", fmt.Errorf("URL must use http or https scheme")
 	}
 
+	client := l.clientWithTimeout(source.Timeout)
+
 	// Fetch with retry logic
 	var lastErr error
 	for attempt := 0; attempt < l.defaultRetries; attempt++ {
 		if attempt > 0 {
-			// Exponential backoff
-			time.Sleep(time.Duration(1<<uint(attempt-1)) * time.Second)
+			// Exponential backoff with jitter, or the server's Retry-After
+			if err := l.waitForRetry(ctx, attempt, lastErr); err != nil {
+				return "", err
+			}
 		}
 
 		req, err := http.NewRequestWithContext(ctx, "GET", urlStr, nil)
@@ -250,32 +298,39 @@ func (l *UniversalSchemaLoader) loadFromURL(ctx context.Context, urlStr string,
 			req.Header.Set(key, expandedValue)
 		}
 
-		resp, err := l.httpClient.Do(req)
+		resp, err := client.Do(req)
 		if err != nil {
-			lastErr = err
+			lastErr = networkError(err)
 			continue
 		}
 		defer resp.Body.Close()
 
 		if resp.StatusCode != http.StatusOK {
-			lastErr = fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+			lastErr = statusError(resp)
+			if !l.retryable(lastErr, source.RetryableStatusCodes) {
+				return "", lastErr
+			}
 			continue
 		}
 
 		body, err := io.ReadAll(resp.Body)
 		if err != nil {
-			lastErr = err
+			lastErr = networkError(err)
 			continue
 		}
 
 		return string(body), nil
 	}
 
-	return "", fmt.Errorf("failed after %d attempts: %w", l.defaultRetries, lastErr)
+	return "", l.exhaustedError("failed", lastErr)
 }
 
-// loadFromIntrospection executes an introspection query and converts the result to SDL
-func (l *UniversalSchemaLoader) loadFromIntrospection(ctx context.Context, urlStr string, headers map[string]string) (string, error) {
+// loadFromIntrospection executes an introspection query against the source's
+// URL and converts the result to SDL. A non-zero source timeout overrides the
+// loader's HTTP timeout.
+func (l *UniversalSchemaLoader) loadFromIntrospection(ctx context.Context, source schema.Source) (string, error) {
+	urlStr, headers, options := source.URL, source.Headers, source.Introspection
+
 	// No cache checking here - just fetch the content
 	// Cache is handled at the Schema level, not content level
 
@@ -290,86 +345,49 @@ func (l *UniversalSchemaLoader) loadFromIntrospection(ctx context.Context, urlSt
 	}
 
 	// Prepare introspection query
-	introspectionQuery := getIntrospectionQuery()
+	request := newIntrospectionRequest(options)
+
+	client := l.clientWithTimeout(source.Timeout)
 
 	// Execute introspection with retry logic
 	var lastErr error
 	for attempt := 0; attempt < l.defaultRetries; attempt++ {
 		if attempt > 0 {
-			time.Sleep(time.Duration(1<<uint(attempt-1)) * time.Second)
-		}
-
-		// Create GraphQL request
-		requestBody := map[string]interface{}{
-			"query": introspectionQuery,
-		}
-		jsonBody, err := json.Marshal(requestBody)
-		if err != nil {
-			return "", fmt.Errorf("marshaling request: %w", err)
-		}
-
-		req, err := http.NewRequestWithContext(ctx, "POST", urlStr, bytes.NewReader(jsonBody))
-		if err != nil {
-			return "", fmt.Errorf("creating request: %w", err)
-		}
-
-		req.Header.Set("Content-Type", "application/json")
-
-		// Add custom headers
-		for key, value := range headers {
-			expandedValue := os.ExpandEnv(value)
-			req.Header.Set(key, expandedValue)
-		}
-
-		resp, err := l.httpClient.Do(req)
-		if err != nil {
-			lastErr = err
-			continue
+			if err := l.waitForRetry(ctx, attempt, lastErr); err != nil {
+				return "", err
+			}
 		}
-		defer resp.Body.Close()
 
-		if resp.StatusCode != http.StatusOK {
-			lastErr = fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
-			continue
+		// With persisted queries the hash goes first; the full query is only
+		// sent when the server has not stored it yet
+		result, err := l.sendIntrospection(ctx, client, urlStr, headers, options.Method, request.persisted())
+		if err == nil && result.persistedQueryNotFound() {
+			result, err = l.sendIntrospection(ctx, client, urlStr, headers, options.Method, request)
 		}
-
-		body, err := io.ReadAll(resp.Body)
 		if err != nil {
 			lastErr = err
+			if !l.retryable(err, source.RetryableStatusCodes) {
+				return "", err
+			}
 			continue
 		}
 
-		// Parse introspection response
-		var result struct {
-			Data struct {
-				Schema json.RawMessage `json:"__schema"`
-			} `json:"data"`
-			Errors []struct {
-				Message string `json:"message"`
-			} `json:"errors"`
-		}
-
-		if err := json.Unmarshal(body, &result); err != nil {
-			lastErr = fmt.Errorf("parsing introspection response: %w", err)
-			continue
-		}
-
+		// The server answered, so GraphQL errors and a missing schema, e.g.
+		// with introspection disabled, would only repeat on a retry
 		if len(result.Errors) > 0 {
 			var errMsgs []string
 			for _, e := range result.Errors {
 				errMsgs = append(errMsgs, e.Message)
 			}
-			lastErr = fmt.Errorf("GraphQL errors: %s", strings.Join(errMsgs, "; "))
-			continue
+			return "", &FetchError{Kind: FetchErrorGraphQL, Messages: errMsgs}
 		}
 
 		if len(result.Data.Schema) == 0 {
-			lastErr = fmt.Errorf("no schema data in introspection response")
-			continue
+			return "", fmt.Errorf("no schema data in introspection response")
 		}
 
 		// Convert introspection result to SDL
-		sdl, err := introspectionToSDL(result.Data.Schema)
+		sdl, err := l.introspectionSDL(result.Data.Schema)
 		if err != nil {
 			return "", fmt.Errorf("converting introspection to SDL: %w", err)
 		}
@@ -377,7 +395,281 @@ func (l *UniversalSchemaLoader) loadFromIntrospection(ctx context.Context, urlSt
 		return sdl, nil
 	}
 
-	return "", fmt.Errorf("introspection failed after %d attempts: %w", l.defaultRetries, lastErr)
+	return "", l.exhaustedError("introspection failed", lastErr)
+}
+
+// introspectionRequest is the GraphQL request sent for introspection
+type introspectionRequest struct {
+	Query         string
+	OperationName string
+	Variables     map[string]interface{}
+	Extensions    *introspectionExtensions
+	// Body holds extra top-level fields; the fields above take precedence
+	Body map[string]interface{}
+}
+
+// fields returns the request's top-level fields, leaving out empty ones
+func (r introspectionRequest) fields() map[string]interface{} {
+	fields := make(map[string]interface{}, len(r.Body)+4)
+	for key, value := range r.Body {
+		fields[key] = value
+	}
+	if r.Query != "" {
+		fields["query"] = r.Query
+	}
+	if r.OperationName != "" {
+		fields["operationName"] = r.OperationName
+	}
+	if len(r.Variables) > 0 {
+		fields["variables"] = r.Variables
+	}
+	if r.Extensions != nil {
+		fields["extensions"] = r.Extensions
+	}
+	return fields
+}
+
+// MarshalJSON encodes the request as a GraphQL over HTTP body
+func (r introspectionRequest) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.fields())
+}
+
+// introspectionExtensions carries the automatic persisted query hash
+type introspectionExtensions struct {
+	PersistedQuery struct {
+		Version    int    `json:"version"`
+		Sha256Hash string `json:"sha256Hash"`
+	} `json:"persistedQuery"`
+}
+
+// introspectionResponse is the part of an introspection response the loader
+// reads
+type introspectionResponse struct {
+	Data struct {
+		Schema json.RawMessage `json:"__schema"`
+	} `json:"data"`
+	Errors []struct {
+		Message    string `json:"message"`
+		Extensions struct {
+			Code string `json:"code"`
+		} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// newIntrospectionRequest builds the introspection request for options. A
+// custom operation name renames the query's operation to match it.
+func newIntrospectionRequest(options schema.IntrospectionOptions) introspectionRequest {
+	query := getIntrospectionQuery()
+	if options.OperationName != "" {
+		query = strings.Replace(query, "query IntrospectionQuery", "query "+options.OperationName, 1)
+	}
+
+	request := introspectionRequest{
+		Query:         query,
+		OperationName: options.OperationName,
+		Variables:     options.Variables,
+		Body:          options.Body,
+	}
+	if options.PersistedQuery {
+		hash := sha256.Sum256([]byte(query))
+		request.Extensions = &introspectionExtensions{}
+		request.Extensions.PersistedQuery.Version = 1
+		request.Extensions.PersistedQuery.Sha256Hash = hex.EncodeToString(hash[:])
+	}
+	return request
+}
+
+// persisted returns the request to send first: without the query text when
+// it is sent as a persisted query
+func (r introspectionRequest) persisted() introspectionRequest {
+	if r.Extensions != nil {
+		r.Query = ""
+	}
+	return r
+}
+
+// persistedQueryNotFound reports whether the server does not know the hash
+// of a persisted query
+func (r *introspectionResponse) persistedQueryNotFound() bool {
+	for _, e := range r.Errors {
+		if e.Message == "PersistedQueryNotFound" || e.Extensions.Code == "PERSISTED_QUERY_NOT_FOUND" {
+			return true
+		}
+	}
+	return false
+}
+
+// sendIntrospection sends one introspection request, as a JSON body for POST
+// or as query string parameters for GET, and parses the response
+func (l *UniversalSchemaLoader) sendIntrospection(ctx context.Context, client *http.Client, urlStr string, headers map[string]string, method string, request introspectionRequest) (*introspectionResponse, error) {
+	var req *http.Request
+	var err error
+	if method == http.MethodGet {
+		req, err = newIntrospectionGet(ctx, urlStr, request)
+	} else {
+		req, err = newIntrospectionPost(ctx, urlStr, request)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Add custom headers
+	for key, value := range headers {
+		expandedValue := os.ExpandEnv(value)
+		req.Header.Set(key, expandedValue)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, networkError(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse introspection response
+	var result introspectionResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("parsing introspection response: %w", err)
+	}
+	return &result, nil
+}
+
+// newIntrospectionPost creates a POST request with the request as JSON body
+func newIntrospectionPost(ctx context.Context, urlStr string, request introspectionRequest) (*http.Request, error) {
+	jsonBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, urlStr, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// newIntrospectionGet creates a GET request with the request's fields as
+// query string parameters, as GraphQL over HTTP specifies. Fields other than
+// strings, e.g. variables and extensions, are sent JSON-encoded.
+func newIntrospectionGet(ctx context.Context, urlStr string, request introspectionRequest) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlStr, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	params := req.URL.Query()
+	for key, value := range request.fields() {
+		if s, ok := value.(string); ok {
+			params.Set(key, s)
+			continue
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling %s: %w", key, err)
+		}
+		params.Set(key, string(encoded))
+	}
+	req.URL.RawQuery = params.Encode()
+	req.Header.Set("Accept", "application/json")
+	return req, nil
+}
+
+// loadWithCacheFile fetches a remote schema and keeps source.CacheFile up to
+// date. When the fetch fails and a cache file exists, the cached SDL is used
+// and a warning with its age is recorded.
+func (l *UniversalSchemaLoader) loadWithCacheFile(source schema.Source, fetch func() (string, error)) (string, error) {
+	content, err := fetch()
+	if source.CacheFile == "" {
+		return content, err
+	}
+
+	if err == nil {
+		if writeErr := writeCacheFile(source.CacheFile, content); writeErr != nil {
+			l.warnings = append(l.warnings, fmt.Sprintf("could not update schema cache %s: %v", source.CacheFile, writeErr))
+		}
+		return content, nil
+	}
+
+	cached, readErr := os.ReadFile(source.CacheFile)
+	if readErr != nil {
+		return "", fmt.Errorf("%w (no cached schema at %s to fall back to)", err, source.CacheFile)
+	}
+	info, statErr := os.Stat(source.CacheFile)
+	if statErr != nil {
+		return "", err
+	}
+
+	age := time.Since(info.ModTime()).Round(time.Second)
+	l.warnings = append(l.warnings, fmt.Sprintf(
+		"%s is unreachable (%v); using cached schema from %s, last updated %s ago (%s)",
+		source.URL, err, source.CacheFile, age, info.ModTime().Format(time.RFC3339)))
+
+	return string(cached), nil
+}
+
+// writeCacheFile atomically replaces the cache file with content
+func writeCacheFile(path string, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Warnings returns the warnings recorded while loading schemas
+func (l *UniversalSchemaLoader) Warnings() []string {
+	return l.warnings
+}
+
+// clientWithTimeout returns the loader's HTTP client, or a copy of it with a
+// different timeout for sources that override it
+func (l *UniversalSchemaLoader) clientWithTimeout(timeout time.Duration) *http.Client {
+	if timeout <= 0 || timeout == l.httpClient.Timeout {
+		return l.httpClient
+	}
+	client := *l.httpClient
+	client.Timeout = timeout
+	return &client
+}
+
+// SetHTTPClient makes the loader fetch schemas with client. The loader keeps
+// a copy, so SetHTTPTimeout and per-source timeouts do not change the
+// caller's client; its transport, cookie jar and redirect policy are shared.
+// A client without a timeout gets the loader's current one. A nil client
+// restores the default.
+func (l *UniversalSchemaLoader) SetHTTPClient(client *http.Client) {
+	if client == nil {
+		l.httpClient = &http.Client{Timeout: l.defaultTimeout}
+		return
+	}
+	copied := *client
+	if copied.Timeout <= 0 {
+		copied.Timeout = l.defaultTimeout
+	}
+	l.httpClient = &copied
+	l.defaultTimeout = copied.Timeout
 }
 
 // SetHTTPTimeout sets the HTTP client timeout
@@ -386,6 +678,17 @@ func (l *UniversalSchemaLoader) SetHTTPTimeout(timeout time.Duration) {
 	l.defaultTimeout = timeout
 }
 
+// SetRetryableStatusCodes sets the HTTP statuses that are retried for
+// sources without their own list; any other status fails immediately
+func (l *UniversalSchemaLoader) SetRetryableStatusCodes(codes ...int) {
+	l.retryableStatusCodes = codes
+}
+
+// SetMaxRetryAfter caps how long a Retry-After header can delay a retry
+func (l *UniversalSchemaLoader) SetMaxRetryAfter(max time.Duration) {
+	l.maxRetryAfter = max
+}
+
 // SetRetries sets the number of retry attempts
 func (l *UniversalSchemaLoader) SetRetries(retries int) {
 	l.defaultRetries = retries
@@ -401,6 +704,33 @@ func (l *UniversalSchemaLoader) ClearCache() {
 	l.cacheMu.Lock()
 	l.cache = make(map[string]*CacheEntry)
 	l.cacheMu.Unlock()
+
+	l.sdlCacheMu.Lock()
+	l.sdlCache = make(map[string]string)
+	l.sdlCacheMu.Unlock()
+}
+
+// introspectionSDL converts an introspection result to SDL, reusing the SDL
+// of an earlier result with the same bytes
+func (l *UniversalSchemaLoader) introspectionSDL(schemaJSON json.RawMessage) (string, error) {
+	sum := sha256.Sum256(schemaJSON)
+	key := hex.EncodeToString(sum[:])
+
+	l.sdlCacheMu.Lock()
+	sdl, ok := l.sdlCache[key]
+	l.sdlCacheMu.Unlock()
+	if ok {
+		return sdl, nil
+	}
+
+	sdl, err := l.convertIntrospection(schemaJSON)
+	if err != nil {
+		return "", err
+	}
+	l.sdlCacheMu.Lock()
+	l.sdlCache[key] = sdl
+	l.sdlCacheMu.Unlock()
+	return sdl, nil
 }
 
 
@@ -659,6 +989,13 @@ func introspectionToSDL(schemaJSON json.RawMessage) (string, error) {
 		sb.WriteString("}\n\n")
 	}
 
+	// Emit types in the same order as sorted schema-ast output, so the SDL
+	// does not depend on the order the server lists them in
+	sort.SliceStable(introspection.Types, func(i, j int) bool {
+		a, b := introspection.Types[i], introspection.Types[j]
+		return schema.LessDefinition(ast.DefinitionKind(a.Kind), a.Name, ast.DefinitionKind(b.Kind), b.Name)
+	})
+
 	// Process each type
 	for _, typ := range introspection.Types {
 		// Skip introspection types
@@ -2,7 +2,10 @@ package loader
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -234,7 +237,7 @@ func TestUniversalSchemaLoader_LoadFromIntrospection(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("Load from introspection", func(t *testing.T) {
-		s, err := loader.loadFromIntrospection(ctx, server.URL, nil)
+		s, err := loader.loadFromIntrospection(ctx, schema.Source{URL: server.URL})
 		require.NoError(t, err)
 		assert.NotEmpty(t, s)
 		// The SDL should contain the Query type
@@ -245,7 +248,7 @@ func TestUniversalSchemaLoader_LoadFromIntrospection(t *testing.T) {
 		headers := map[string]string{
 			"X-Custom-Header": "test",
 		}
-		s, err := loader.loadFromIntrospection(ctx, server.URL, headers)
+		s, err := loader.loadFromIntrospection(ctx, schema.Source{URL: server.URL, Headers: headers})
 		require.NoError(t, err)
 		assert.NotEmpty(t, s)
 	})
@@ -254,17 +257,180 @@ func TestUniversalSchemaLoader_LoadFromIntrospection(t *testing.T) {
 		loader.SetCacheTTL(5 * time.Minute)
 
 		// Load once
-		s1, err := loader.loadFromIntrospection(ctx, server.URL, nil)
+		s1, err := loader.loadFromIntrospection(ctx, schema.Source{URL: server.URL})
 		require.NoError(t, err)
 
 		// Load again - should use cache
-		s2, err := loader.loadFromIntrospection(ctx, server.URL, nil)
+		s2, err := loader.loadFromIntrospection(ctx, schema.Source{URL: server.URL})
 		require.NoError(t, err)
 
 		assert.Equal(t, s1, s2)
 	})
 }
 
+func TestUniversalSchemaLoader_IntrospectionTransport(t *testing.T) {
+	introspectionResult := `{"data": {"__schema": {
+		"queryType": {"name": "Query"},
+		"types": [
+			{"kind": "OBJECT", "name": "Query", "fields": [
+				{"name": "hello", "args": [], "type": {"kind": "SCALAR", "name": "String"}}
+			]},
+			{"kind": "SCALAR", "name": "String"}
+		]
+	}}}`
+
+	// request is one request as the server saw it
+	type request struct {
+		method        string
+		query         string
+		operationName string
+		hash          string
+	}
+
+	// newServer answers introspection, remembering the persisted queries it
+	// has seen the full text of when persisted is set
+	newServer := func(t *testing.T, persisted bool) (*httptest.Server, *[]request) {
+		var requests []request
+		stored := make(map[string]bool)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body struct {
+				Query         string `json:"query"`
+				OperationName string `json:"operationName"`
+				Extensions    struct {
+					PersistedQuery struct {
+						Sha256Hash string `json:"sha256Hash"`
+					} `json:"persistedQuery"`
+				} `json:"extensions"`
+			}
+			if r.Method == http.MethodGet {
+				params := r.URL.Query()
+				body.Query = params.Get("query")
+				body.OperationName = params.Get("operationName")
+				if extensions := params.Get("extensions"); extensions != "" {
+					require.NoError(t, json.Unmarshal([]byte(extensions), &body.Extensions))
+				}
+			} else {
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			}
+			hash := body.Extensions.PersistedQuery.Sha256Hash
+			requests = append(requests, request{r.Method, body.Query, body.OperationName, hash})
+
+			w.Header().Set("Content-Type", "application/json")
+			if persisted && hash != "" && body.Query == "" && !stored[hash] {
+				w.Write([]byte(`{"errors": [{"message": "PersistedQueryNotFound", "extensions": {"code": "PERSISTED_QUERY_NOT_FOUND"}}]}`))
+				return
+			}
+			if hash != "" && body.Query != "" {
+				sum := sha256.Sum256([]byte(body.Query))
+				assert.Equal(t, hex.EncodeToString(sum[:]), hash)
+				stored[hash] = true
+			}
+			w.Write([]byte(introspectionResult))
+		}))
+		t.Cleanup(server.Close)
+		return server, &requests
+	}
+	ctx := context.Background()
+
+	t.Run("GET with operation name", func(t *testing.T) {
+		server, requests := newServer(t, false)
+		loader := NewUniversalSchemaLoader()
+		s, err := loader.loadFromIntrospection(ctx, schema.Source{URL: server.URL, Introspection: schema.IntrospectionOptions{
+			Method:        http.MethodGet,
+			OperationName: "IntrospectionQuery",
+		}})
+		require.NoError(t, err)
+		assert.Contains(t, s, "type Query")
+
+		require.Len(t, *requests, 1)
+		got := (*requests)[0]
+		assert.Equal(t, http.MethodGet, got.method)
+		assert.Equal(t, "IntrospectionQuery", got.operationName)
+		assert.Contains(t, got.query, "query IntrospectionQuery")
+	})
+
+	t.Run("custom operation name renames the query", func(t *testing.T) {
+		server, requests := newServer(t, false)
+		loader := NewUniversalSchemaLoader()
+		_, err := loader.loadFromIntrospection(ctx, schema.Source{URL: server.URL, Introspection: schema.IntrospectionOptions{OperationName: "SchemaDownload"}})
+		require.NoError(t, err)
+
+		got := (*requests)[0]
+		assert.Equal(t, http.MethodPost, got.method)
+		assert.Equal(t, "SchemaDownload", got.operationName)
+		assert.Contains(t, got.query, "query SchemaDownload {")
+	})
+
+	t.Run("custom body fields", func(t *testing.T) {
+		var bodies []map[string]interface{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body := make(map[string]interface{})
+			if r.Method == http.MethodGet {
+				for key := range r.URL.Query() {
+					body[key] = r.URL.Query().Get(key)
+				}
+			} else {
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			}
+			assert.Equal(t, "codegen", r.Header.Get("X-Client-Name"))
+			bodies = append(bodies, body)
+			w.Write([]byte(introspectionResult))
+		}))
+		defer server.Close()
+
+		options := schema.IntrospectionOptions{
+			OperationName: "IntrospectionQuery",
+			Variables:     map[string]interface{}{"includeDeprecated": true},
+			Body:          map[string]interface{}{"clientName": "codegen", "query": "ignored"},
+		}
+		source := schema.Source{URL: server.URL, Headers: map[string]string{"X-Client-Name": "codegen"}, Introspection: options}
+		loader := NewUniversalSchemaLoader()
+		_, err := loader.loadFromIntrospection(ctx, source)
+		require.NoError(t, err)
+
+		source.Introspection.Method = http.MethodGet
+		_, err = loader.loadFromIntrospection(ctx, source)
+		require.NoError(t, err)
+
+		require.Len(t, bodies, 2)
+		post, get := bodies[0], bodies[1]
+		assert.Equal(t, "IntrospectionQuery", post["operationName"])
+		assert.Equal(t, map[string]interface{}{"includeDeprecated": true}, post["variables"])
+		assert.Equal(t, "codegen", post["clientName"])
+		assert.Contains(t, post["query"], "query IntrospectionQuery", "body fields cannot replace the query")
+
+		assert.Equal(t, "IntrospectionQuery", get["operationName"])
+		assert.Equal(t, `{"includeDeprecated":true}`, get["variables"])
+		assert.Equal(t, "codegen", get["clientName"])
+		assert.Contains(t, get["query"], "query IntrospectionQuery")
+	})
+
+	for _, method := range []string{http.MethodPost, http.MethodGet} {
+		t.Run("persisted query over "+method, func(t *testing.T) {
+			server, requests := newServer(t, true)
+			loader := NewUniversalSchemaLoader()
+			source := schema.Source{URL: server.URL, Introspection: schema.IntrospectionOptions{Method: method, PersistedQuery: true}}
+
+			_, err := loader.loadFromIntrospection(ctx, source)
+			require.NoError(t, err)
+			require.Len(t, *requests, 2, "unknown hash should be followed by the full query")
+			first, second := (*requests)[0], (*requests)[1]
+			assert.Empty(t, first.query)
+			assert.NotEmpty(t, first.hash)
+			assert.Equal(t, first.hash, second.hash)
+			assert.Contains(t, second.query, "IntrospectionQuery")
+
+			_, err = loader.loadFromIntrospection(ctx, source)
+			require.NoError(t, err)
+			require.Len(t, *requests, 3, "a stored hash is enough")
+			assert.Empty(t, (*requests)[2].query)
+			for _, r := range *requests {
+				assert.Equal(t, method, r.method)
+			}
+		})
+	}
+}
+
 func TestUniversalSchemaLoader_LoadMultipleSources(t *testing.T) {
 	// Create temporary files
 	tmpDir := t.TempDir()
@@ -418,4 +584,335 @@ func TestHelperFunctions(t *testing.T) {
 		assert.False(t, isBuiltInScalar("DateTime"))
 		assert.False(t, isBuiltInScalar("CustomScalar"))
 	})
-}
\ No newline at end of file
+}
+func TestUniversalSchemaLoader_CacheFileFallback(t *testing.T) {
+	schemaContent := `
+		type Query {
+			user(id: ID!): User
+		}
+
+		type User {
+			id: ID!
+			email: String!
+		}
+	`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(schemaContent))
+	}))
+
+	cacheFile := filepath.Join(t.TempDir(), "cache", "schema.graphql")
+	sources := []schema.Source{
+		{ID: "remote", Kind: "url", URL: server.URL, CacheFile: cacheFile},
+	}
+	ctx := context.Background()
+
+	// A successful load writes the cache file
+	loader := NewUniversalSchemaLoader()
+	loader.SetRetries(1)
+	_, err := loader.Load(ctx, sources)
+	require.NoError(t, err)
+	assert.Empty(t, loader.Warnings())
+
+	cached, err := os.ReadFile(cacheFile)
+	require.NoError(t, err)
+	assert.Equal(t, schemaContent, string(cached))
+
+	// Simulate an unreachable endpoint
+	server.Close()
+
+	t.Run("falls back to cached SDL", func(t *testing.T) {
+		loader := NewUniversalSchemaLoader()
+		loader.SetRetries(1)
+
+		s, err := loader.Load(ctx, sources)
+		require.NoError(t, err)
+		assert.NotNil(t, s.GetType("User"))
+
+		warnings := loader.Warnings()
+		require.Len(t, warnings, 1)
+		assert.Contains(t, warnings[0], "using cached schema from "+cacheFile)
+		assert.Contains(t, warnings[0], "last updated")
+	})
+
+	t.Run("fails without a cache file", func(t *testing.T) {
+		loader := NewUniversalSchemaLoader()
+		loader.SetRetries(1)
+
+		_, err := loader.Load(ctx, []schema.Source{
+			{ID: "remote", Kind: "url", URL: server.URL, CacheFile: filepath.Join(t.TempDir(), "missing.graphql")},
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestUniversalSchemaLoader_PerSourceTimeout(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte(`type Query { slow: String }`))
+	}))
+	defer slow.Close()
+
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`type Query { fast: String }`))
+	}))
+	defer fast.Close()
+
+	ctx := context.Background()
+
+	t.Run("short timeout fails only its source", func(t *testing.T) {
+		loader := NewUniversalSchemaLoader()
+		loader.SetRetries(1)
+
+		_, err := loader.Load(ctx, []schema.Source{
+			{ID: "fast", Kind: "url", URL: fast.URL},
+			{ID: "slow", Kind: "url", URL: slow.URL, Timeout: 20 * time.Millisecond},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), slow.URL)
+
+		s, err := loader.Load(ctx, []schema.Source{
+			{ID: "fast", Kind: "url", URL: fast.URL, Timeout: 20 * time.Millisecond},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, s.GetQueryType())
+	})
+
+	t.Run("long timeout overrides the loader default", func(t *testing.T) {
+		loader := NewUniversalSchemaLoader()
+		loader.SetRetries(1)
+		loader.SetHTTPTimeout(20 * time.Millisecond)
+
+		_, err := loader.Load(ctx, []schema.Source{
+			{ID: "slow", Kind: "url", URL: slow.URL},
+		})
+		require.Error(t, err)
+
+		s, err := loader.Load(ctx, []schema.Source{
+			{ID: "slow", Kind: "url", URL: slow.URL, Timeout: 5 * time.Second},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, s.GetQueryType())
+	})
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestUniversalSchemaLoader_CustomHTTPClient(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`type Query { hello: String }`))
+	}))
+	defer server.Close()
+	ctx := context.Background()
+	source := []schema.Source{{ID: "tls", Kind: "url", URL: server.URL}}
+
+	t.Run("default client rejects the test certificate", func(t *testing.T) {
+		loader := NewUniversalSchemaLoader()
+		loader.SetRetries(1)
+		_, err := loader.Load(ctx, source)
+		require.Error(t, err)
+	})
+
+	t.Run("injected client trusts it", func(t *testing.T) {
+		client := server.Client()
+		var requests int
+		transport := client.Transport
+		client.Transport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			requests++
+			return transport.RoundTrip(req)
+		})
+
+		loader := NewUniversalSchemaLoaderWithClient(client)
+		s, err := loader.Load(ctx, source)
+		require.NoError(t, err)
+		assert.NotNil(t, s.GetQueryType())
+		assert.Equal(t, 1, requests)
+	})
+
+	t.Run("timeouts do not change the caller's client", func(t *testing.T) {
+		client := &http.Client{Transport: server.Client().Transport}
+		loader := NewUniversalSchemaLoaderWithClient(client)
+		assert.Equal(t, 30*time.Second, loader.httpClient.Timeout, "a client without a timeout gets the default")
+
+		loader.SetHTTPTimeout(5 * time.Second)
+		assert.Equal(t, 5*time.Second, loader.httpClient.Timeout)
+		assert.Zero(t, client.Timeout)
+
+		_, err := loader.Load(ctx, source)
+		require.NoError(t, err)
+	})
+
+	t.Run("keeps the client's own timeout", func(t *testing.T) {
+		loader := NewUniversalSchemaLoader()
+		loader.SetHTTPClient(&http.Client{Timeout: 2 * time.Second})
+		assert.Equal(t, 2*time.Second, loader.defaultTimeout)
+	})
+}
+
+func TestIntrospectionToSDL_SortsTypes(t *testing.T) {
+	schemaJSON := json.RawMessage(`{
+		"queryType": {"name": "Query"},
+		"types": [
+			{"kind": "OBJECT", "name": "User", "fields": [
+				{"name": "name", "args": [], "type": {"kind": "SCALAR", "name": "String"}},
+				{"name": "id", "args": [], "type": {"kind": "SCALAR", "name": "ID"}}
+			]},
+			{"kind": "OBJECT", "name": "Query", "fields": [
+				{"name": "user", "args": [], "type": {"kind": "OBJECT", "name": "User"}}
+			]},
+			{"kind": "INPUT_OBJECT", "name": "UserFilter", "inputFields": [
+				{"name": "role", "type": {"kind": "ENUM", "name": "Role"}}
+			]},
+			{"kind": "ENUM", "name": "Role", "enumValues": [{"name": "ADMIN"}]},
+			{"kind": "SCALAR", "name": "DateTime"}
+		]
+	}`)
+
+	sdl, err := introspectionToSDL(schemaJSON)
+	require.NoError(t, err)
+
+	order := []string{"scalar DateTime", "enum Role", "input UserFilter", "type Query", "type User"}
+	last := -1
+	for _, want := range order {
+		idx := strings.Index(sdl, want)
+		require.NotEqual(t, -1, idx, "missing %q in:\n%s", want, sdl)
+		assert.Greater(t, idx, last, "%q out of order in:\n%s", want, sdl)
+		last = idx
+	}
+	// Fields keep their declaration order
+	assert.Less(t, strings.Index(sdl, "  name: String"), strings.Index(sdl, "  id: ID"))
+}
+
+func TestUniversalSchemaLoader_IntrospectionSDLCache(t *testing.T) {
+	queryType := "String"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"data": {"__schema": {"queryType": {"name": "Query"}, "types": [
+			{"kind": "OBJECT", "name": "Query", "fields": [
+				{"name": "hello", "args": [], "type": {"kind": "SCALAR", "name": %q}}
+			]}
+		]}}}`, queryType)
+	}))
+	defer server.Close()
+
+	loader := NewUniversalSchemaLoader()
+	conversions := 0
+	loader.convertIntrospection = func(schemaJSON json.RawMessage) (string, error) {
+		conversions++
+		return introspectionToSDL(schemaJSON)
+	}
+	ctx := context.Background()
+
+	first, err := loader.loadFromIntrospection(ctx, schema.Source{URL: server.URL})
+	require.NoError(t, err)
+	second, err := loader.loadFromIntrospection(ctx, schema.Source{URL: server.URL})
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, conversions, "identical introspections should be converted once")
+
+	queryType = "ID"
+	changed, err := loader.loadFromIntrospection(ctx, schema.Source{URL: server.URL})
+	require.NoError(t, err)
+	assert.Contains(t, changed, "hello: ID")
+	assert.Equal(t, 2, conversions, "a different introspection should be converted")
+
+	loader.ClearCache()
+	_, err = loader.loadFromIntrospection(ctx, schema.Source{URL: server.URL})
+	require.NoError(t, err)
+	assert.Equal(t, 3, conversions, "ClearCache should drop converted SDL")
+}
+
+func TestUniversalSchemaLoader_FetchErrors(t *testing.T) {
+	ctx := context.Background()
+	respond := func(status int, body string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+			w.Write([]byte(body))
+		}))
+	}
+
+	unauthorized := respond(http.StatusUnauthorized, "")
+	defer unauthorized.Close()
+	notFound := respond(http.StatusNotFound, "")
+	defer notFound.Close()
+	unavailable := respond(http.StatusServiceUnavailable, "")
+	defer unavailable.Close()
+	graphqlErrors := respond(http.StatusOK, `{"errors": [{"message": "introspection is disabled"}]}`)
+	defer graphqlErrors.Close()
+
+	// A closed server refuses connections
+	refused := respond(http.StatusOK, "")
+	refusedURL := refused.URL
+	refused.Close()
+
+	tests := []struct {
+		name     string
+		source   schema.Source
+		kind     FetchErrorKind
+		contains []string
+	}{
+		{
+			name:     "unauthorized",
+			source:   schema.Source{ID: "api", Kind: "url", URL: unauthorized.URL},
+			kind:     FetchErrorAuth,
+			contains: []string{"authentication failed (HTTP 401 Unauthorized)", "check the token"},
+		},
+		{
+			name:     "connection refused",
+			source:   schema.Source{ID: "api", Kind: "url", URL: refusedURL},
+			kind:     FetchErrorNetwork,
+			contains: []string{"network error", "connection refused", "check that the URL is correct"},
+		},
+		{
+			name:     "not found",
+			source:   schema.Source{ID: "api", Kind: "introspection", URL: notFound.URL},
+			kind:     FetchErrorNotFound,
+			contains: []string{"endpoint not found (HTTP 404 Not Found)", "GraphQL endpoint"},
+		},
+		{
+			name:     "server error",
+			source:   schema.Source{ID: "api", Kind: "introspection", URL: unavailable.URL},
+			kind:     FetchErrorServer,
+			contains: []string{"server error (HTTP 503 Service Unavailable)"},
+		},
+		{
+			name:     "graphql errors",
+			source:   schema.Source{ID: "api", Kind: "introspection", URL: graphqlErrors.URL},
+			kind:     FetchErrorGraphQL,
+			contains: []string{"introspection returned GraphQL errors: introspection is disabled", "check that introspection is enabled"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loader := NewUniversalSchemaLoader()
+			loader.SetRetries(1)
+
+			_, err := loader.Load(ctx, []schema.Source{tt.source})
+			require.Error(t, err)
+			for _, want := range tt.contains {
+				assert.Contains(t, err.Error(), want)
+			}
+
+			var fetchErr *FetchError
+			require.ErrorAs(t, err, &fetchErr)
+			assert.Equal(t, tt.kind, fetchErr.Kind)
+		})
+	}
+
+	t.Run("missing cache file is reported", func(t *testing.T) {
+		loader := NewUniversalSchemaLoader()
+		loader.SetRetries(1)
+
+		cacheFile := filepath.Join(t.TempDir(), "schema.graphql")
+		_, err := loader.Load(ctx, []schema.Source{{ID: "api", Kind: "url", URL: unauthorized.URL, CacheFile: cacheFile}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "authentication failed")
+		assert.Contains(t, err.Error(), "no cached schema at "+cacheFile)
+	})
+}
@@ -0,0 +1,59 @@
+package pluck
+
+import (
+	"path/filepath"
+	"regexp"
+)
+
+var (
+	scriptOpenRegexp  = regexp.MustCompile(`(?i)<script\b[^>]*>`)
+	scriptCloseRegexp = regexp.MustCompile(`(?i)</script\s*>`)
+)
+
+// isSingleFileComponent reports whether the file is a Vue or Svelte component
+// whose GraphQL lives in <script> blocks
+func isSingleFileComponent(filePath string) bool {
+	switch filepath.Ext(filePath) {
+	case ".vue", ".svelte":
+		return true
+	default:
+		return false
+	}
+}
+
+// isolateScriptBlocks keeps only the content of the <script> and
+// <script setup> blocks of a component. Everything else is blanked out with
+// spaces while line breaks are kept, so the remaining code stays at the same
+// line and column as in the original file.
+func isolateScriptBlocks(content string) string {
+	masked := []byte(content)
+	keep := make([]bool, len(masked))
+
+	pos := 0
+	for pos < len(content) {
+		open := scriptOpenRegexp.FindStringIndex(content[pos:])
+		if open == nil {
+			break
+		}
+		start := pos + open[1]
+
+		end := len(content)
+		if closeTag := scriptCloseRegexp.FindStringIndex(content[start:]); closeTag != nil {
+			end = start + closeTag[0]
+			pos = start + closeTag[1]
+		} else {
+			pos = end
+		}
+
+		for i := start; i < end; i++ {
+			keep[i] = true
+		}
+	}
+
+	for i, ch := range masked {
+		if !keep[i] && ch != '\n' && ch != '\r' {
+			masked[i] = ' '
+		}
+	}
+	return string(masked)
+}
@@ -0,0 +1,67 @@
+package pluck
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypeScriptExtractor_SingleFileComponents(t *testing.T) {
+	extractor := NewTypeScriptExtractor()
+
+	t.Run("can extract from vue and svelte files", func(t *testing.T) {
+		assert.True(t, extractor.CanExtract("Component.vue"))
+		assert.True(t, extractor.CanExtract("Component.svelte"))
+	})
+
+	t.Run("extracts from vue script blocks", func(t *testing.T) {
+		content := `<template>
+  <div>{{ gql` + "`query FromTemplate { ignored }`" + ` }}</div>
+</template>
+
+<script lang="ts">
+export default { name: 'User' }
+</script>
+
+<script setup lang="ts">
+import { gql } from '@apollo/client'
+
+const query = gql` + "`" + `
+  query GetUser {
+    user { id }
+  }
+` + "`" + `
+</script>
+`
+
+		docs, err := extractor.ExtractFromString(content, "User.vue")
+		require.NoError(t, err)
+		require.Len(t, docs, 1)
+		assert.Contains(t, docs[0].Content, "query GetUser")
+
+		extracted := extractor.scan(content, "User.vue")
+		require.Len(t, extracted, 1)
+		assert.Equal(t, location{line: 12, column: 18}, extracted[0].location)
+	})
+
+	t.Run("extracts from svelte module and instance scripts", func(t *testing.T) {
+		content := `<script context="module">
+  const fragment = graphql` + "`fragment UserName on User { name }`" + `
+</script>
+
+<script>
+  const query = graphql` + "`query GetUser { user { ...UserName } }`" + `
+</script>
+
+<h1>{name}</h1>
+`
+
+		extracted := extractor.scan(content, "User.svelte")
+		require.Len(t, extracted, 2)
+		assert.Equal(t, "fragment UserName on User { name }", extracted[0].content)
+		assert.Equal(t, 2, extracted[0].location.line)
+		assert.Equal(t, "query GetUser { user { ...UserName } }", extracted[1].content)
+		assert.Equal(t, 6, extracted[1].location.line)
+	})
+}
@@ -37,6 +37,19 @@ func NewTypeScriptExtractor() *TypeScriptExtractor {
 	}
 }
 
+// NewTypeScriptExtractorWithOptions creates a TypeScript extractor that looks
+// for the given tag names and comment patterns. Empty options keep the defaults.
+func NewTypeScriptExtractorWithOptions(opts ExtractorOptions) *TypeScriptExtractor {
+	e := NewTypeScriptExtractor()
+	if len(opts.Tags) > 0 {
+		e.SetTaggedTemplates(opts.Tags)
+	}
+	if len(opts.CommentPatterns) > 0 {
+		e.SetCommentPatterns(opts.CommentPatterns)
+	}
+	return e
+}
+
 // SetTaggedTemplates sets the template tag names to look for
 func (e *TypeScriptExtractor) SetTaggedTemplates(tags []string) {
 	e.taggedTemplates = tags
@@ -55,11 +68,12 @@ func (e *TypeScriptExtractor) EnableFragmentImports(enable bool) {
 	e.fragmentImports = enable
 }
 
-// CanExtract checks if this extractor can handle the given file
+// CanExtract checks if this extractor can handle the given file. Vue and
+// Svelte components are read from their <script> blocks.
 func (e *TypeScriptExtractor) CanExtract(filePath string) bool {
 	ext := filepath.Ext(filePath)
 	switch ext {
-	case ".ts", ".tsx", ".js", ".jsx":
+	case ".ts", ".tsx", ".js", ".jsx", ".vue", ".svelte":
 		return true
 	default:
 		return false
@@ -77,6 +91,38 @@ func (e *TypeScriptExtractor) Extract(ctx context.Context, filePath string, cont
 
 // ExtractFromString extracts GraphQL documents from a string
 func (e *TypeScriptExtractor) ExtractFromString(content string, sourcePath string) ([]*documents.Document, error) {
+	graphqlStrings := e.scan(content, sourcePath)
+
+	// Convert extracted strings to documents
+	var docs []*documents.Document
+	for _, extracted := range graphqlStrings {
+		doc := &documents.Document{
+			FilePath: sourcePath,
+			Content:  extracted.content,
+			Hash:     documents.ComputeDocumentHash([]byte(extracted.content)),
+			AST:      nil, // Will be parsed and validated later
+			Line:     extracted.start.line,
+			Column:   extracted.start.column,
+		}
+
+		// Parse the GraphQL content
+		// Note: In a real implementation, we would use the GraphQL parser
+		// For now, we'll just store the raw content
+
+		docs = append(docs, doc)
+	}
+
+	return docs, nil
+}
+
+// scan finds the GraphQL strings in a file. Locations refer to the original
+// file, including for components where only the <script> blocks are scanned.
+func (e *TypeScriptExtractor) scan(content string, sourcePath string) []extractedGraphQL {
+	if isSingleFileComponent(sourcePath) {
+		content = isolateScriptBlocks(content)
+	}
+
+	tags := e.resolveTagAliases(content)
 	scanner := newScanner(content)
 	var graphqlStrings []extractedGraphQL
 
@@ -89,7 +135,7 @@ func (e *TypeScriptExtractor) ExtractFromString(content string, sourcePath strin
 		}
 
 		// Look for tagged templates
-		if graphql := e.scanForTaggedTemplate(scanner); graphql != nil {
+		if graphql := e.scanForTaggedTemplate(scanner, tags); graphql != nil {
 			graphqlStrings = append(graphqlStrings, *graphql)
 			continue
 		}
@@ -98,30 +144,15 @@ func (e *TypeScriptExtractor) ExtractFromString(content string, sourcePath strin
 		scanner.advance()
 	}
 
-	// Convert extracted strings to documents
-	var docs []*documents.Document
-	for _, extracted := range graphqlStrings {
-		doc := &documents.Document{
-			FilePath: sourcePath,
-			Content:  extracted.content,
-			Hash:     documents.ComputeDocumentHash([]byte(extracted.content)),
-			AST:      nil, // Will be parsed and validated later
-		}
-
-		// Parse the GraphQL content
-		// Note: In a real implementation, we would use the GraphQL parser
-		// For now, we'll just store the raw content
-
-		docs = append(docs, doc)
-	}
-
-	return docs, nil
+	return graphqlStrings
 }
 
 // extractedGraphQL represents an extracted GraphQL string
 type extractedGraphQL struct {
-	content  string
+	content string
+	// location is the opening backtick, start the first byte of content
 	location location
+	start    location
 }
 
 // location represents a position in the source
@@ -130,6 +161,19 @@ type location struct {
 	column int
 }
 
+// after returns the location reached by reading text from l
+func (l location) after(text string) location {
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			l.line++
+			l.column = 1
+		} else {
+			l.column++
+		}
+	}
+	return l
+}
+
 // scanner provides a simple scanner for TypeScript/JavaScript code
 type scanner struct {
 	content []byte
@@ -246,6 +290,7 @@ func (e *TypeScriptExtractor) scanForComment(s *scanner) *extractedGraphQL {
 				return &extractedGraphQL{
 					content:  content.String(),
 					location: location,
+					start:    location.after("`"),
 				}
 			}
 		}
@@ -255,12 +300,12 @@ func (e *TypeScriptExtractor) scanForComment(s *scanner) *extractedGraphQL {
 }
 
 // scanForTaggedTemplate looks for tagged template literals
-func (e *TypeScriptExtractor) scanForTaggedTemplate(s *scanner) *extractedGraphQL {
+func (e *TypeScriptExtractor) scanForTaggedTemplate(s *scanner, tags []string) *extractedGraphQL {
 	// Skip whitespace
 	s.skipWhitespace()
 
 	// Check if we're at a potential tagged template
-	for _, tag := range e.taggedTemplates {
+	for _, tag := range tags {
 		if e.matchesTag(s, tag) {
 			// Move past the tag
 			for i := 0; i < len(tag); i++ {
@@ -338,9 +383,13 @@ func (e *TypeScriptExtractor) scanForTaggedTemplate(s *scanner) *extractedGraphQ
 					}
 				}
 
+				raw := content.String()
+				trimmed := strings.TrimSpace(raw)
+				leading := raw[:strings.Index(raw, trimmed)]
 				return &extractedGraphQL{
-					content:  strings.TrimSpace(content.String()),
+					content:  trimmed,
 					location: location,
+					start:    location.after("`" + leading),
 				}
 			}
 		}
@@ -349,6 +398,34 @@ func (e *TypeScriptExtractor) scanForTaggedTemplate(s *scanner) *extractedGraphQ
 	return nil
 }
 
+var (
+	namedImportsRegexp    = regexp.MustCompile(`import\s+(?:type\s+)?(?:[A-Za-z_$][\w$]*\s*,\s*)?\{([^}]*)\}\s*from\b`)
+	importSpecifierRegexp = regexp.MustCompile(`^(?:type\s+)?([A-Za-z_$][\w$]*)\s+as\s+([A-Za-z_$][\w$]*)$`)
+)
+
+// resolveTagAliases returns the tag names to look for in a file: the
+// configured tags plus the local names they are imported under, e.g. g for
+// import { graphql as g } from '...'
+func (e *TypeScriptExtractor) resolveTagAliases(content string) []string {
+	known := make(map[string]bool, len(e.taggedTemplates))
+	for _, tag := range e.taggedTemplates {
+		known[tag] = true
+	}
+
+	tags := append([]string(nil), e.taggedTemplates...)
+	for _, match := range namedImportsRegexp.FindAllStringSubmatch(content, -1) {
+		for _, specifier := range strings.Split(match[1], ",") {
+			parts := importSpecifierRegexp.FindStringSubmatch(strings.TrimSpace(specifier))
+			if parts == nil || !known[parts[1]] || known[parts[2]] {
+				continue
+			}
+			known[parts[2]] = true
+			tags = append(tags, parts[2])
+		}
+	}
+	return tags
+}
+
 // matchesTag checks if the current position matches a tag
 func (e *TypeScriptExtractor) matchesTag(s *scanner, tag string) bool {
 	// Check if we're at a word boundary before the tag
@@ -180,4 +180,68 @@ const q2 = /* GraphQL */ ` + "`query Q2 { field }`" + `; // Should not match
 		extractor.EnableFragmentImports(true)
 		assert.True(t, extractor.fragmentImports)
 	})
-}
\ No newline at end of file
+}
+func TestTypeScriptExtractor_CustomTags(t *testing.T) {
+	t.Run("extracts configured function tags", func(t *testing.T) {
+		extractor := NewTypeScriptExtractorWithOptions(ExtractorOptions{
+			Tags: []string{"graphqlDocument"},
+		})
+
+		content := "const q = graphqlDocument(`query Q1 { field1 }`);\nconst q2 = gql`query Q2 { field2 }`;"
+
+		docs, err := extractor.ExtractFromString(content, "test.ts")
+		require.NoError(t, err)
+		require.Len(t, docs, 1)
+		assert.Equal(t, "query Q1 { field1 }", docs[0].Content)
+	})
+
+	t.Run("empty options keep the default tags", func(t *testing.T) {
+		extractor := NewTypeScriptExtractorWithOptions(ExtractorOptions{})
+
+		docs, err := extractor.ExtractFromString("const q = gql`query Q { field }`;", "test.ts")
+		require.NoError(t, err)
+		assert.Len(t, docs, 1)
+	})
+
+	t.Run("resolves import aliases of known tags", func(t *testing.T) {
+		extractor := NewTypeScriptExtractor()
+
+		content := `import { graphql as g, useQuery } from './gql';
+import type { Other as o } from './other';
+
+const q1 = g` + "`query Q1 { field1 }`" + `;
+const q2 = o` + "`query Q2 { field2 }`" + `;
+`
+
+		docs, err := extractor.ExtractFromString(content, "test.ts")
+		require.NoError(t, err)
+		require.Len(t, docs, 1)
+		assert.Equal(t, "query Q1 { field1 }", docs[0].Content)
+	})
+
+	t.Run("aliases are scoped to the importing file", func(t *testing.T) {
+		extractor := NewTypeScriptExtractor()
+
+		_, err := extractor.ExtractFromString("import { gql as g } from 'graphql-tag';", "a.ts")
+		require.NoError(t, err)
+
+		docs, err := extractor.ExtractFromString("const q = g`query Q { field }`;", "b.ts")
+		require.NoError(t, err)
+		assert.Empty(t, docs)
+	})
+}
+
+func TestTypeScriptExtractor_DocumentLocations(t *testing.T) {
+	content := "import { gql } from '@apollo/client';\n\n" +
+		"const A = gql`query A { a }`;\n" +
+		"const B = gql`\n  query B {\n    b\n  }\n`;\n" +
+		"const C = /* GraphQL */ `query C { c }`;\n"
+
+	docs, err := NewTypeScriptExtractor().ExtractFromString(content, "queries.ts")
+	require.NoError(t, err)
+	require.Len(t, docs, 3)
+
+	assert.Equal(t, []int{3, 15}, []int{docs[0].Line, docs[0].Column})
+	assert.Equal(t, []int{5, 3}, []int{docs[1].Line, docs[1].Column})
+	assert.Equal(t, []int{9, 26}, []int{docs[2].Line, docs[2].Column})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/url"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 )
@@ -17,12 +18,99 @@ type SchemaSource struct {
 	Timeout  string            `yaml:"timeout,omitempty"`   // HTTP timeout (e.g., "30s")
 	Retries  int               `yaml:"retries,omitempty"`   // Number of retry attempts
 	CacheTTL string            `yaml:"cache_ttl,omitempty"` // Cache TTL (e.g., "5m")
+
+	// CacheFile persists the last successful remote schema as SDL and is used
+	// as a fallback when the endpoint is unreachable
+	CacheFile string `yaml:"cache_file,omitempty"`
+
+	// Introspection configures the request of introspection sources
+	Introspection *IntrospectionConfig `yaml:"introspection,omitempty"`
+
+	// RetryableStatusCodes are the HTTP statuses retried for remote sources
+	// (default: 408, 429, 500, 502, 503 and 504)
+	RetryableStatusCodes []int `yaml:"retryable_status_codes,omitempty"`
+}
+
+// IntrospectionConfig configures how the introspection query is sent, for
+// gateways that only accept GET, require an operation name or only run
+// persisted queries
+type IntrospectionConfig struct {
+	// Method is GET or POST (default: POST)
+	Method string `yaml:"method,omitempty"`
+
+	// OperationName is sent in the request, e.g. "IntrospectionQuery"
+	OperationName string `yaml:"operationName,omitempty"`
+
+	// PersistedQuery sends the query's hash first and the full query only
+	// when the server answers PersistedQueryNotFound
+	PersistedQuery bool `yaml:"persistedQuery,omitempty"`
+
+	// Variables are sent as the variables of the introspection request
+	Variables map[string]interface{} `yaml:"variables,omitempty"`
+
+	// Body adds fields to the request body next to query and operationName
+	Body map[string]interface{} `yaml:"body,omitempty"`
+}
+
+// introspectionBodyReserved are the request fields the loader sets itself
+var introspectionBodyReserved = []string{"query", "operationName", "variables", "extensions"}
+
+// graphqlName matches a GraphQL name, e.g. of a directive
+var graphqlName = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// validate reports an unsupported method and body fields the loader sets
+func (c *IntrospectionConfig) validate() error {
+	if c == nil {
+		return nil
+	}
+	switch strings.ToUpper(c.Method) {
+	case "", "GET", "POST":
+	default:
+		return fmt.Errorf("introspection.method must be GET or POST, got %q", c.Method)
+	}
+	for _, key := range introspectionBodyReserved {
+		if _, ok := c.Body[key]; ok {
+			return fmt.Errorf("introspection.body cannot set %q; use the introspection options instead", key)
+		}
+	}
+	return nil
 }
 
 // Documents defines where to find GraphQL operations
 type Documents struct {
 	Include []string `yaml:"include"` // Glob patterns for files to include
 	Exclude []string `yaml:"exclude"` // Glob patterns for files to exclude
+
+	// ResolveImports inlines `#import "./fragment.graphql"` includes in .graphql files
+	ResolveImports bool `yaml:"resolveImports,omitempty"`
+
+	// RequireIdSelection warns about selections that omit `id` on types that have one
+	RequireIdSelection bool `yaml:"requireIdSelection,omitempty"`
+
+	// Strict fails generation when any document does not parse or validate
+	// instead of skipping it
+	Strict bool `yaml:"strict,omitempty"`
+
+	// AllowedOperationTypes restricts operations to the listed types, e.g.
+	// [query, subscription] for clients that must not send mutations. Empty
+	// allows every type.
+	AllowedOperationTypes []string `yaml:"allowedOperationTypes,omitempty"`
+
+	// RequireGenerateDirective, when set, names a directive such as
+	// "generate" that operations and fragments must carry to be generated.
+	// The directive is stripped from the output.
+	RequireGenerateDirective string `yaml:"requireGenerateDirective,omitempty"`
+
+	// PluckConfig controls how GraphQL is extracted from TypeScript/JavaScript
+	PluckConfig PluckConfig `yaml:"pluckConfig,omitempty"`
+}
+
+// PluckConfig configures GraphQL extraction from source files
+type PluckConfig struct {
+	// Tags are the template tags and functions holding GraphQL, e.g. gql`...`
+	// or graphqlDocument(`...`). Defaults to gql and graphql. Local aliases
+	// such as import { graphql as g } are recognized automatically.
+	Tags []string `yaml:"tags,omitempty"`
 }
 
 // OutputTarget defines a code generation target
@@ -42,7 +130,25 @@ type Config struct {
 	Watch          bool                    `yaml:"watch"`           // Enable watch mode
 	Verbose        bool                    `yaml:"verbose"`         // Verbose output
 	Scalars        map[string]string       `yaml:"scalars"`         // Custom scalar mappings
-	OnTypeConflict string                  `yaml:"onTypeConflict"`  // Conflict resolution strategy: "error" (default), "useFirst", "useLast"
+	OnTypeConflict string                  `yaml:"onTypeConflict"`  // Conflict resolution strategy: "error" (default), "useFirst", "useLast", "union"
+
+	// StrictOrder makes enums and unions that list the same values in a
+	// different order across schema sources conflict
+	StrictOrder bool `yaml:"strictOrder,omitempty"`
+
+	// DisableEnvExpansion keeps $VAR and ${VAR} references in the config as
+	// written instead of replacing them with environment variable values
+	DisableEnvExpansion bool `yaml:"disableEnvExpansion,omitempty"`
+
+	// Hooks are shell commands run before and after the output files are written
+	Hooks Hooks `yaml:"hooks,omitempty"`
+
+	// Projects replace Schema, Documents and Generates with several
+	// independent sets of them, keyed by project name
+	Projects map[string]Project `yaml:"projects,omitempty"`
+
+	// missingEnv lists the unset variables referenced as ${NAME}
+	missingEnv []string
 }
 
 // LoadFile loads configuration from a file (YAML, TypeScript, or JavaScript)
@@ -53,20 +159,46 @@ func LoadFile(path string) (*Config, error) {
 
 // setDefaults sets default values for the configuration
 func (c *Config) setDefaults() error {
+	if len(c.Projects) == 0 {
+		setSourceDefaults(c.Schema, &c.Documents)
+	}
+	for name, project := range c.Projects {
+		setSourceDefaults(project.Schema, &project.Documents)
+		c.Projects[name] = project
+	}
+
+	// Set default scalar mappings if not provided
+	if c.Scalars == nil {
+		c.Scalars = make(map[string]string)
+	}
+
+	// Common scalar defaults
+	for name, tsType := range defaultScalars {
+		if _, ok := c.Scalars[name]; !ok {
+			c.Scalars[name] = tsType
+		}
+	}
+
+	return nil
+}
+
+// setSourceDefaults sets the schema source types and document includes left
+// out of a config or project
+func setSourceDefaults(schema []SchemaSource, docs *Documents) {
 	// Set default schema type if not specified
-	for i := range c.Schema {
-		if c.Schema[i].Type == "" {
-			if c.Schema[i].Path != "" {
-				c.Schema[i].Type = "file"
-			} else if c.Schema[i].URL != "" {
-				c.Schema[i].Type = "url"
+	for i := range schema {
+		if schema[i].Type == "" {
+			if schema[i].Path != "" {
+				schema[i].Type = "file"
+			} else if schema[i].URL != "" {
+				schema[i].Type = "url"
 			}
 		}
 	}
 
 	// Set default document includes if empty
-	if len(c.Documents.Include) == 0 {
-		c.Documents.Include = []string{
+	if len(docs.Include) == 0 {
+		docs.Include = []string{
 			"**/*.graphql",
 			"**/*.gql",
 			"**/*.ts",
@@ -75,28 +207,14 @@ func (c *Config) setDefaults() error {
 			"**/*.jsx",
 		}
 	}
-
-	// Set default scalar mappings if not provided
-	if c.Scalars == nil {
-		c.Scalars = make(map[string]string)
-	}
-
-	// Common scalar defaults
-	if _, ok := c.Scalars["DateTime"]; !ok {
-		c.Scalars["DateTime"] = "string"
-	}
-	if _, ok := c.Scalars["UUID"]; !ok {
-		c.Scalars["UUID"] = "string"
-	}
-	if _, ok := c.Scalars["JSON"]; !ok {
-		c.Scalars["JSON"] = "any"
-	}
-
-	return nil
 }
 
 // Validate checks if the configuration is valid
 func (c *Config) Validate() error {
+	if len(c.Projects) > 0 {
+		return c.validateProjects()
+	}
+
 	if len(c.Schema) == 0 {
 		return fmt.Errorf("at least one schema source is required")
 	}
@@ -150,6 +268,9 @@ func (c *Config) Validate() error {
 					return fmt.Errorf("schema[%d]: invalid cache_ttl: %w", i, err)
 				}
 			}
+			if err := source.Introspection.validate(); err != nil {
+				return fmt.Errorf("schema[%d]: %w", i, err)
+			}
 		default:
 			return fmt.Errorf("schema[%d]: invalid type %q", i, source.Type)
 		}
@@ -159,10 +280,26 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("documents.include cannot be empty")
 	}
 
+	for _, operationType := range c.Documents.AllowedOperationTypes {
+		switch operationType {
+		case "query", "mutation", "subscription":
+		default:
+			return fmt.Errorf("documents.allowedOperationTypes: invalid operation type %q (expected query, mutation or subscription)", operationType)
+		}
+	}
+
+	if directive := c.Documents.RequireGenerateDirective; directive != "" && !graphqlName.MatchString(directive) {
+		return fmt.Errorf("documents.requireGenerateDirective: %q is not a directive name", directive)
+	}
+
 	if len(c.Generates) == 0 {
 		return fmt.Errorf("at least one generation target is required")
 	}
 
+	if err := c.Hooks.validate(); err != nil {
+		return err
+	}
+
 	for path, target := range c.Generates {
 		if path == "" {
 			return fmt.Errorf("output path cannot be empty")
@@ -205,28 +342,41 @@ func validateDuration(duration string) error {
 func (c *Config) ResolveRelativePaths(configPath string) {
 	baseDir := filepath.Dir(configPath)
 
+	c.Generates = resolveSourcePaths(baseDir, c.Schema, &c.Documents, c.Generates)
+	for name, project := range c.Projects {
+		project.Generates = resolveSourcePaths(baseDir, project.Schema, &project.Documents, project.Generates)
+		c.Projects[name] = project
+	}
+}
+
+// resolveSourcePaths resolves the schema paths and document patterns in
+// place and returns the outputs keyed by their resolved paths
+func resolveSourcePaths(baseDir string, schema []SchemaSource, docs *Documents, generates map[string]OutputTarget) map[string]OutputTarget {
 	// Resolve schema paths
-	for i := range c.Schema {
-		if c.Schema[i].Path != "" && !filepath.IsAbs(c.Schema[i].Path) {
-			c.Schema[i].Path = filepath.Join(baseDir, c.Schema[i].Path)
+	for i := range schema {
+		if schema[i].Path != "" && !filepath.IsAbs(schema[i].Path) {
+			schema[i].Path = filepath.Join(baseDir, schema[i].Path)
+		}
+		if schema[i].CacheFile != "" && !filepath.IsAbs(schema[i].CacheFile) {
+			schema[i].CacheFile = filepath.Join(baseDir, schema[i].CacheFile)
 		}
 	}
 
 	// Resolve document patterns
-	for i := range c.Documents.Include {
-		if !filepath.IsAbs(c.Documents.Include[i]) {
-			c.Documents.Include[i] = filepath.Join(baseDir, c.Documents.Include[i])
+	for i := range docs.Include {
+		if !filepath.IsAbs(docs.Include[i]) {
+			docs.Include[i] = filepath.Join(baseDir, docs.Include[i])
 		}
 	}
-	for i := range c.Documents.Exclude {
-		if !filepath.IsAbs(c.Documents.Exclude[i]) {
-			c.Documents.Exclude[i] = filepath.Join(baseDir, c.Documents.Exclude[i])
+	for i := range docs.Exclude {
+		if !filepath.IsAbs(docs.Exclude[i]) {
+			docs.Exclude[i] = filepath.Join(baseDir, docs.Exclude[i])
 		}
 	}
 
 	// Resolve output paths
 	newGenerates := make(map[string]OutputTarget)
-	for path, target := range c.Generates {
+	for path, target := range generates {
 		// Preserve trailing slash for directory outputs (needed for presets)
 		hasTrailingSlash := strings.HasSuffix(path, "/")
 
@@ -240,5 +390,5 @@ func (c *Config) ResolveRelativePaths(configPath string) {
 		target.Path = path
 		newGenerates[path] = target
 	}
-	c.Generates = newGenerates
+	return newGenerates
 }
\ No newline at end of file
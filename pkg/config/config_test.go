@@ -38,6 +38,27 @@ generates:
 				assert.Len(t, cfg.Generates, 1)
 			},
 		},
+		{
+			name: "pluck config tags",
+			yaml: `
+schema:
+  - path: schema.graphql
+documents:
+  include:
+    - "src/**/*.ts"
+  pluckConfig:
+    tags:
+      - graphql
+      - graphqlDocument
+generates:
+  output.ts:
+    plugins:
+      - typescript
+`,
+			validate: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, []string{"graphql", "graphqlDocument"}, cfg.Documents.PluckConfig.Tags)
+			},
+		},
 		{
 			name: "environment variable expansion",
 			yaml: `
@@ -274,6 +295,24 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			wantErr: "url is required for url type",
 		},
+		{
+			name: "unsupported introspection method",
+			config: Config{
+				Schema: []SchemaSource{
+					{Type: "introspection", URL: "https://api.example.com/graphql", Introspection: &IntrospectionConfig{Method: "PUT"}},
+				},
+			},
+			wantErr: "introspection.method must be GET or POST",
+		},
+		{
+			name: "introspection body replacing the query",
+			config: Config{
+				Schema: []SchemaSource{
+					{Type: "introspection", URL: "https://api.example.com/graphql", Introspection: &IntrospectionConfig{Body: map[string]interface{}{"query": "{ __typename }"}}},
+				},
+			},
+			wantErr: `introspection.body cannot set "query"`,
+		},
 		{
 			name: "empty documents",
 			config: Config{
@@ -299,6 +338,32 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			wantErr: "at least one generation target is required",
 		},
+		{
+			name: "invalid allowed operation type",
+			config: Config{
+				Schema: []SchemaSource{
+					{Type: "file", Path: "schema.graphql"},
+				},
+				Documents: Documents{
+					Include:               []string{"**/*.graphql"},
+					AllowedOperationTypes: []string{"query", "mutations"},
+				},
+			},
+			wantErr: `invalid operation type "mutations"`,
+		},
+		{
+			name: "invalid generate directive",
+			config: Config{
+				Schema: []SchemaSource{
+					{Type: "file", Path: "schema.graphql"},
+				},
+				Documents: Documents{
+					Include:                  []string{"**/*.graphql"},
+					RequireGenerateDirective: "@generate",
+				},
+			},
+			wantErr: `documents.requireGenerateDirective: "@generate" is not a directive name`,
+		},
 		{
 			name: "generate without plugins",
 			config: Config{
@@ -393,68 +458,3 @@ func TestConfig_ResolveRelativePaths(t *testing.T) {
 	assert.Contains(t, cfg.Generates, "/project/output.ts")
 	assert.Contains(t, cfg.Generates, "/absolute/output.ts")
 }
-
-func TestExpandEnvVars(t *testing.T) {
-	tests := []struct {
-		name    string
-		input   string
-		envVars map[string]string
-		want    string
-	}{
-		{
-			name:  "no env vars",
-			input: "hello world",
-			want:  "hello world",
-		},
-		{
-			name:  "single env var with braces",
-			input: "url: ${API_URL}",
-			envVars: map[string]string{
-				"API_URL": "https://api.example.com",
-			},
-			want: "url: https://api.example.com",
-		},
-		{
-			name:  "single env var without braces",
-			input: "token: $TOKEN",
-			envVars: map[string]string{
-				"TOKEN": "secret",
-			},
-			want: "token: secret",
-		},
-		{
-			name:  "multiple env vars",
-			input: "Bearer ${TOKEN} for ${USER}",
-			envVars: map[string]string{
-				"TOKEN": "abc123",
-				"USER":  "john",
-			},
-			want: "Bearer abc123 for john",
-		},
-		{
-			name:  "undefined env var",
-			input: "value: ${UNDEFINED_VAR}",
-			want:  "value: ${UNDEFINED_VAR}",
-		},
-		{
-			name:  "mixed defined and undefined",
-			input: "${DEFINED} and ${UNDEFINED}",
-			envVars: map[string]string{
-				"DEFINED": "value",
-			},
-			want: "value and ${UNDEFINED}",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			// Set environment variables
-			for k, v := range tt.envVars {
-				t.Setenv(k, v)
-			}
-
-			got := expandEnvVars(tt.input)
-			assert.Equal(t, tt.want, got)
-		})
-	}
-}
\ No newline at end of file
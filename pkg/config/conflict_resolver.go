@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"github.com/jzeiders/graphql-go-gen/pkg/schema"
-	"github.com/vektah/gqlparser/v2/ast"
 )
 
 // GetConflictResolver returns a ConflictResolver based on the config strategy
@@ -15,16 +14,16 @@ func GetConflictResolver(strategy string) schema.ConflictResolver {
 		return nil
 
 	case "useFirst":
-		return func(left *ast.Definition, right *ast.Definition, conflictType string) (*ast.Definition, error) {
-			// Always use the first (left) type
-			return left, nil
-		}
+		// Always use the first (left) type
+		return schema.ResolvePreferLeft
 
 	case "useLast":
-		return func(left *ast.Definition, right *ast.Definition, conflictType string) (*ast.Definition, error) {
-			// Always use the last (right) type
-			return right, nil
-		}
+		// Always use the last (right) type
+		return schema.ResolvePreferRight
+
+	case "union":
+		// Combine compatible fields, enum values and union members
+		return schema.ResolveUnionFields
 
 	default:
 		// Unknown strategy, treat as error
@@ -37,6 +36,7 @@ func GetMergeOptions(c *Config) schema.MergeOptions {
 	return schema.MergeOptions{
 		OnTypeConflict:   GetConflictResolver(c.OnTypeConflict),
 		TrackSources:     true,
+		StrictOrder:      c.StrictOrder,
 		AllowEmptySchema: false,
 	}
 }
@@ -44,9 +44,9 @@ func GetMergeOptions(c *Config) schema.MergeOptions {
 // ValidateConflictStrategy validates the conflict resolution strategy
 func ValidateConflictStrategy(strategy string) error {
 	switch strategy {
-	case "", "error", "useFirst", "useLast":
+	case "", "error", "useFirst", "useLast", "union":
 		return nil
 	default:
-		return fmt.Errorf("invalid onTypeConflict strategy: %s (must be 'error', 'useFirst', or 'useLast')", strategy)
+		return fmt.Errorf("invalid onTypeConflict strategy: %s (must be 'error', 'useFirst', 'useLast', or 'union')", strategy)
 	}
-}
\ No newline at end of file
+}
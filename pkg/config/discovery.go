@@ -104,6 +104,7 @@ func LoadFromPackageJSON(path string) (*Config, error) {
 		return nil, fmt.Errorf("parsing config: %w", err)
 	}
 
+	config.expandEnv()
 	config.ResolveRelativePaths(path)
 
 	if err := config.setDefaults(); err != nil {
@@ -111,7 +112,7 @@ func LoadFromPackageJSON(path string) (*Config, error) {
 	}
 
 	if err := config.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid configuration: %w", err)
+		return nil, fmt.Errorf("invalid configuration: %w%s", err, config.missingEnvHint())
 	}
 
 	return &config, nil
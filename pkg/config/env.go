@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// expandEnv replaces $VAR and ${VAR} references to set environment
+// variables in the string fields of the config: schema sources, document
+// globs, output paths, scalar mappings and plugin and preset config.
+// References to unset variables are left as written and reported by
+// MissingEnv. Does nothing when DisableEnvExpansion is set.
+func (c *Config) expandEnv() {
+	if c.DisableEnvExpansion {
+		return
+	}
+
+	missing := make(map[string]bool)
+	expand := func(s string) string {
+		return envReferenceRegexp.ReplaceAllStringFunc(s, func(match string) string {
+			groups := envReferenceRegexp.FindStringSubmatch(match)
+			if value, ok := os.LookupEnv(groups[1] + groups[2]); ok && value != "" {
+				return value
+			}
+			// Bare $name is also how TypeScript code in plugin config such
+			// as ' $fragmentName' reads, so only ${NAME} counts as missing
+			if groups[1] != "" {
+				missing[groups[1]] = true
+			}
+			return match
+		})
+	}
+
+	c.Generates = expandEnvSources(c.Schema, &c.Documents, c.Generates, expand)
+	for name, project := range c.Projects {
+		project.Generates = expandEnvSources(project.Schema, &project.Documents, project.Generates, expand)
+		c.Projects[name] = project
+	}
+
+	for name, tsType := range c.Scalars {
+		c.Scalars[name] = expand(tsType)
+	}
+
+	c.missingEnv = make([]string, 0, len(missing))
+	for name := range missing {
+		c.missingEnv = append(c.missingEnv, name)
+	}
+	sort.Strings(c.missingEnv)
+}
+
+// expandEnvSources expands the schema sources and document globs in place
+// and returns the outputs with expanded paths and config
+func expandEnvSources(schema []SchemaSource, docs *Documents, generates map[string]OutputTarget, expand func(string) string) map[string]OutputTarget {
+	for i := range schema {
+		source := &schema[i]
+		source.Path = expand(source.Path)
+		source.URL = expand(source.URL)
+		source.CacheFile = expand(source.CacheFile)
+		for name, value := range source.Headers {
+			source.Headers[name] = expand(value)
+		}
+	}
+
+	for i := range docs.Include {
+		docs.Include[i] = expand(docs.Include[i])
+	}
+	for i := range docs.Exclude {
+		docs.Exclude[i] = expand(docs.Exclude[i])
+	}
+
+	if generates == nil {
+		return nil
+	}
+	expanded := make(map[string]OutputTarget, len(generates))
+	for path, target := range generates {
+		target.Path = expand(target.Path)
+		target.Config = expandEnvValue(target.Config, expand).(map[string]interface{})
+		target.PresetConfig = expandEnvValue(target.PresetConfig, expand).(map[string]interface{})
+		expanded[expand(path)] = target
+	}
+	return expanded
+}
+
+// expandEnvValue expands the strings nested in a plugin or preset config value
+func expandEnvValue(value interface{}, expand func(string) string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return expand(v)
+	case map[string]interface{}:
+		if v == nil {
+			return v
+		}
+		for key, item := range v {
+			v[key] = expandEnvValue(item, expand)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = expandEnvValue(item, expand)
+		}
+		return v
+	}
+	return value
+}
+
+// MissingEnv returns the names of the unset environment variables the
+// config references as ${NAME}, sorted. The references are left unexpanded,
+// which usually surfaces later as an invalid URL or path.
+func (c *Config) MissingEnv() []string {
+	return c.missingEnv
+}
+
+// missingEnvHint names the unset variables in a validation error, since an
+// unexpanded ${NAME} otherwise shows up as an unexplained invalid URL or path
+func (c *Config) missingEnvHint() string {
+	if len(c.missingEnv) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (the config references unset environment variables: %s)", strings.Join(c.missingEnv, ", "))
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_ExpandEnv(t *testing.T) {
+	t.Setenv("SCHEMA_DIR", "schemas")
+	t.Setenv("API_URL", "https://api.example.com/graphql")
+	t.Setenv("SRC", "app")
+	t.Setenv("OUT", "generated")
+	t.Setenv("DATE_TYPE", "Date")
+
+	writeConfig := func(t *testing.T, content string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "graphql-go-gen.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	t.Run("expands string fields", func(t *testing.T) {
+		path := writeConfig(t, `
+schema:
+  - path: ${SCHEMA_DIR}/schema.graphql
+  - url: ${API_URL}
+documents:
+  include:
+    - "${SRC}/**/*.graphql"
+  exclude:
+    - "${SRC}/legacy/**"
+scalars:
+  DateTime: ${DATE_TYPE}
+generates:
+  ${OUT}/types.ts:
+    plugins:
+      - typescript
+    config:
+      fragmentSuffix: $fragmentName
+`)
+		cfg, err := LoadFile(path)
+		require.NoError(t, err)
+		dir := filepath.Dir(path)
+
+		assert.Equal(t, filepath.Join(dir, "schemas", "schema.graphql"), cfg.Schema[0].Path)
+		assert.Equal(t, "https://api.example.com/graphql", cfg.Schema[1].URL)
+		assert.Equal(t, "url", cfg.Schema[1].Type)
+		assert.Equal(t, []string{filepath.Join(dir, "app/**/*.graphql")}, cfg.Documents.Include)
+		assert.Equal(t, []string{filepath.Join(dir, "app/legacy/**")}, cfg.Documents.Exclude)
+		assert.Equal(t, "Date", cfg.Scalars["DateTime"])
+
+		target, ok := cfg.Generates[filepath.Join(dir, "generated", "types.ts")]
+		require.True(t, ok, "output path should be expanded: %v", cfg.Generates)
+		assert.Equal(t, "$fragmentName", target.Config["fragmentSuffix"])
+		assert.Empty(t, cfg.MissingEnv())
+	})
+
+	t.Run("reports unset variables", func(t *testing.T) {
+		path := writeConfig(t, `
+schema:
+  - path: ${UNSET_SCHEMA_DIR}/schema.graphql
+  - url: https://api.example.com/graphql
+    headers:
+      Authorization: "Bearer ${UNSET_TOKEN}"
+documents:
+  include:
+    - "**/*.graphql"
+generates:
+  types.ts:
+    plugins:
+      - typescript
+`)
+		cfg, err := LoadFile(path)
+		require.NoError(t, err)
+
+		assert.Equal(t, filepath.Join(filepath.Dir(path), "${UNSET_SCHEMA_DIR}", "schema.graphql"), cfg.Schema[0].Path)
+		assert.Equal(t, "Bearer ${UNSET_TOKEN}", cfg.Schema[1].Headers["Authorization"])
+		assert.Equal(t, []string{"UNSET_SCHEMA_DIR", "UNSET_TOKEN"}, cfg.MissingEnv())
+	})
+
+	t.Run("names unset variables in validation errors", func(t *testing.T) {
+		path := writeConfig(t, `
+schema:
+  - url: ${UNSET_API_URL}
+documents:
+  include:
+    - "**/*.graphql"
+generates:
+  types.ts:
+    plugins:
+      - typescript
+`)
+		_, err := LoadFile(path)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid URL")
+		assert.Contains(t, err.Error(), "unset environment variables: UNSET_API_URL")
+	})
+
+	t.Run("opt out", func(t *testing.T) {
+		path := writeConfig(t, `
+disableEnvExpansion: true
+schema:
+  - path: ${SCHEMA_DIR}/schema.graphql
+documents:
+  include:
+    - "${SRC}/**/*.graphql"
+generates:
+  types.ts:
+    plugins:
+      - typescript
+`)
+		cfg, err := LoadFile(path)
+		require.NoError(t, err)
+
+		assert.Equal(t, filepath.Join(filepath.Dir(path), "${SCHEMA_DIR}", "schema.graphql"), cfg.Schema[0].Path)
+		assert.Equal(t, []string{filepath.Join(filepath.Dir(path), "${SRC}/**/*.graphql")}, cfg.Documents.Include)
+		assert.Empty(t, cfg.MissingEnv())
+	})
+}
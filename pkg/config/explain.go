@@ -0,0 +1,308 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Resolution stages reported by Explain, in the order they are applied
+const (
+	SourceDefault  = "default"
+	SourceFile     = "file"
+	SourceEnv      = "env"
+	SourceResolved = "resolved"
+)
+
+// ResolutionStep is one stage that set or changed a config value
+type ResolutionStep struct {
+	Source string
+	// Detail names what the stage used, e.g. the config file or env variables
+	Detail string
+	Value  interface{}
+}
+
+// Explanation describes how a config value was resolved
+type Explanation struct {
+	Key   string
+	Value interface{}
+	Found bool
+	Steps []ResolutionStep
+}
+
+var envReferenceRegexp = regexp.MustCompile(`\$\{([^}]+)\}|\$(\w+)`)
+
+// Explain loads the config file at path and traces how the value at key (a
+// dot-separated path such as "schema.0.url" or "documents.include") was
+// resolved: built-in default, config file, environment variable expansion and
+// relative path resolution.
+func Explain(path string, key string) (*Explanation, error) {
+	final, err := loadConfigForExplain(path)
+	if err != nil {
+		return nil, err
+	}
+	aliasOutputKeys(final, filepath.Dir(path))
+
+	raw, expanded, err := rawConfigValues(path)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := strings.Split(key, ".")
+	explanation := &Explanation{Key: key}
+
+	defaults := &Config{}
+	if err := defaults.setDefaults(); err != nil {
+		return nil, err
+	}
+	defaultsValue, err := toGeneric(defaults)
+	if err != nil {
+		return nil, err
+	}
+	if value, ok := lookupPath(defaultsValue, segments); ok && !isEmptyValue(value) {
+		explanation.Steps = append(explanation.Steps, ResolutionStep{Source: SourceDefault, Value: value})
+	}
+
+	rawValue, inFile := lookupPath(raw, segments)
+	if inFile {
+		explanation.Steps = append(explanation.Steps, ResolutionStep{Source: SourceFile, Detail: path, Value: rawValue})
+
+		// Only values referencing variables are expanded; other differences
+		// come from how the config is parsed, e.g. a shorthand schema source
+		references := envReferences(rawValue)
+		if expandedValue, ok := lookupPath(expanded, segments); ok && len(references) > 0 && !reflect.DeepEqual(normalize(rawValue), normalize(expandedValue)) {
+			explanation.Steps = append(explanation.Steps, ResolutionStep{
+				Source: SourceEnv,
+				Detail: strings.Join(references, ", "),
+				Value:  expandedValue,
+			})
+		}
+	}
+
+	finalValue, found := lookupPath(final, segments)
+	explanation.Found = found
+	if !found {
+		return explanation, nil
+	}
+	explanation.Value = finalValue
+
+	if len(explanation.Steps) > 0 && reflect.DeepEqual(normalize(explanation.Steps[len(explanation.Steps)-1].Value), normalize(finalValue)) {
+		return explanation, nil
+	}
+
+	if inFile {
+		// Paths in the file are made relative to the config file
+		explanation.Steps = append(explanation.Steps, ResolutionStep{
+			Source: SourceResolved,
+			Detail: "relative to " + filepath.Dir(path),
+			Value:  finalValue,
+		})
+	} else {
+		// Values the file leaves out may be derived from the rest of the
+		// config, e.g. a schema source's type from its path or url
+		explanation.Steps = append(explanation.Steps, ResolutionStep{Source: SourceDefault, Detail: "derived", Value: finalValue})
+	}
+
+	return explanation, nil
+}
+
+// loadConfigForExplain loads the final config the same way generate does
+func loadConfigForExplain(path string) (interface{}, error) {
+	var cfg *Config
+	var err error
+	if filepath.Base(path) == "package.json" {
+		cfg, err = LoadFromPackageJSON(path)
+	} else {
+		cfg, err = LoadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toGeneric(cfg)
+}
+
+// aliasOutputKeys makes resolved generates entries reachable by the relative
+// key written in the config file
+func aliasOutputKeys(final interface{}, baseDir string) {
+	root, ok := final.(map[string]interface{})
+	if !ok {
+		return
+	}
+	generates, ok := root["generates"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for key, target := range generates {
+		rel, err := filepath.Rel(baseDir, key)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		if strings.HasSuffix(key, "/") {
+			rel += "/"
+		}
+		if _, exists := generates[rel]; !exists {
+			generates[rel] = target
+		}
+	}
+}
+
+// rawConfigValues returns the config as written in the file and after
+// environment variable expansion. Every loader expands the string fields of
+// the parsed config with expandEnv, so the expanded values are built the same
+// way rather than by expanding the file's text.
+func rawConfigValues(path string) (interface{}, interface{}, error) {
+	var raw interface{}
+	var cfg *Config
+	yamlLoader := &YAMLLoader{}
+	switch {
+	case filepath.Base(path) == "package.json":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading package.json: %w", err)
+		}
+		var pkg map[string]interface{}
+		if err := json.Unmarshal(data, &pkg); err != nil {
+			return nil, nil, fmt.Errorf("parsing package.json: %w", err)
+		}
+		raw = pkg["graphql-go-gen"]
+		configJSON, err := json.Marshal(raw)
+		if err != nil {
+			return nil, nil, fmt.Errorf("marshaling config data: %w", err)
+		}
+		cfg = &Config{}
+		if err := json.Unmarshal(configJSON, cfg); err != nil {
+			return nil, nil, fmt.Errorf("parsing config: %w", err)
+		}
+	case yamlLoader.CanLoad(path):
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading config file: %w", err)
+		}
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, nil, fmt.Errorf("parsing YAML config file: %w", err)
+		}
+		if cfg, err = yamlLoader.Load(path); err != nil {
+			return nil, nil, err
+		}
+	default:
+		for _, loader := range NewLoaderRegistry().loaders {
+			if !loader.CanLoad(path) {
+				continue
+			}
+			loaded, err := loader.Load(path)
+			if err != nil {
+				return nil, nil, fmt.Errorf("loading config with %T: %w", loader, err)
+			}
+			if raw, err = toGeneric(loaded); err != nil {
+				return nil, nil, err
+			}
+			cfg = loaded
+			break
+		}
+		if cfg == nil {
+			return nil, nil, fmt.Errorf("no loader found for file: %s", path)
+		}
+	}
+
+	cfg.expandEnv()
+	expanded, err := toGeneric(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return raw, expanded, nil
+}
+
+// toGeneric converts a config into maps and slices keyed by its YAML names
+func toGeneric(cfg *Config) (interface{}, error) {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// lookupPath walks a dot-separated key through maps and lists. Map keys may
+// themselves contain dots, e.g. generates.src/graphql.ts.plugins, so the
+// longest matching key wins.
+func lookupPath(value interface{}, segments []string) (interface{}, bool) {
+	if len(segments) == 0 {
+		return value, true
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for n := len(segments); n > 0; n-- {
+			if child, ok := v[strings.Join(segments[:n], ".")]; ok {
+				if result, ok := lookupPath(child, segments[n:]); ok {
+					return result, true
+				}
+			}
+		}
+	case []interface{}:
+		index, err := strconv.Atoi(segments[0])
+		if err != nil || index < 0 || index >= len(v) {
+			return nil, false
+		}
+		return lookupPath(v[index], segments[1:])
+	}
+
+	return nil, false
+}
+
+// normalize makes YAML and JSON decoded values comparable
+func normalize(value interface{}) interface{} {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var result interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return value
+	}
+	return result
+}
+
+func isEmptyValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	}
+	return false
+}
+
+// envReferences lists the environment variables referenced in a raw value
+func envReferences(value interface{}) []string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, match := range envReferenceRegexp.FindAllStringSubmatch(string(data), -1) {
+		name := match[1]
+		if name == "" {
+			name = match[2]
+		}
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const explainConfig = `
+schema:
+  - url: ${EXPLAIN_ENDPOINT}
+    headers:
+      Authorization: "Bearer ${EXPLAIN_TOKEN}"
+  - path: schema.graphql
+documents:
+  include:
+    - "src/**/*.ts"
+scalars:
+  Money: ${EXPLAIN_MONEY}
+generates:
+  src/gql/graphql.ts:
+    plugins:
+      - typescript
+`
+
+func writeExplainConfig(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "graphql-go-gen.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(explainConfig), 0644))
+	return path
+}
+
+func TestExplain(t *testing.T) {
+	t.Setenv("EXPLAIN_ENDPOINT", "https://api.example.com/graphql")
+	t.Setenv("EXPLAIN_TOKEN", "secret")
+	// Looks like a YAML mapping, but is expanded as a plain string
+	t.Setenv("EXPLAIN_MONEY", "{ amount: number }")
+	path := writeExplainConfig(t)
+
+	t.Run("env overrides the file value", func(t *testing.T) {
+		explanation, err := Explain(path, "schema.0.url")
+		require.NoError(t, err)
+
+		assert.True(t, explanation.Found)
+		assert.Equal(t, "https://api.example.com/graphql", explanation.Value)
+		assert.Equal(t, []ResolutionStep{
+			{Source: SourceFile, Detail: path, Value: "${EXPLAIN_ENDPOINT}"},
+			{Source: SourceEnv, Detail: "EXPLAIN_ENDPOINT", Value: "https://api.example.com/graphql"},
+		}, explanation.Steps)
+	})
+
+	t.Run("env values are not parsed as YAML", func(t *testing.T) {
+		explanation, err := Explain(path, "scalars.Money")
+		require.NoError(t, err)
+
+		assert.Equal(t, "{ amount: number }", explanation.Value)
+		assert.Equal(t, []ResolutionStep{
+			{Source: SourceFile, Detail: path, Value: "${EXPLAIN_MONEY}"},
+			{Source: SourceEnv, Detail: "EXPLAIN_MONEY", Value: "{ amount: number }"},
+		}, explanation.Steps)
+	})
+
+	t.Run("file overrides the default", func(t *testing.T) {
+		explanation, err := Explain(path, "documents.include")
+		require.NoError(t, err)
+
+		require.Len(t, explanation.Steps, 3)
+		assert.Equal(t, SourceDefault, explanation.Steps[0].Source)
+		assert.Contains(t, explanation.Steps[0].Value, "**/*.graphql")
+		assert.Equal(t, SourceFile, explanation.Steps[1].Source)
+		assert.Equal(t, []interface{}{"src/**/*.ts"}, explanation.Steps[1].Value)
+		assert.Equal(t, SourceResolved, explanation.Steps[2].Source)
+		assert.Equal(t, []interface{}{filepath.Join(filepath.Dir(path), "src/**/*.ts")}, explanation.Value)
+	})
+
+	t.Run("relative paths are resolved", func(t *testing.T) {
+		explanation, err := Explain(path, "schema.1.path")
+		require.NoError(t, err)
+
+		require.Len(t, explanation.Steps, 2)
+		assert.Equal(t, "schema.graphql", explanation.Steps[0].Value)
+		assert.Equal(t, SourceResolved, explanation.Steps[1].Source)
+		assert.Equal(t, filepath.Join(filepath.Dir(path), "schema.graphql"), explanation.Value)
+	})
+
+	t.Run("derived values", func(t *testing.T) {
+		explanation, err := Explain(path, "schema.0.type")
+		require.NoError(t, err)
+
+		assert.Equal(t, "url", explanation.Value)
+		assert.Equal(t, []ResolutionStep{{Source: SourceDefault, Detail: "derived", Value: "url"}}, explanation.Steps)
+	})
+
+	t.Run("keys containing dots", func(t *testing.T) {
+		explanation, err := Explain(path, "generates.src/gql/graphql.ts.plugins")
+		require.NoError(t, err)
+
+		assert.True(t, explanation.Found)
+		assert.Equal(t, []interface{}{"typescript"}, explanation.Value)
+	})
+
+	t.Run("unknown keys", func(t *testing.T) {
+		explanation, err := Explain(path, "documents.missing")
+		require.NoError(t, err)
+		assert.False(t, explanation.Found)
+	})
+}
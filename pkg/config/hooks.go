@@ -0,0 +1,74 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Hooks are shell commands run around writing the generated files, e.g.
+// `prettier --write` to format the output
+type Hooks struct {
+	// BeforeAllFileWrite runs once before any output file is written
+	BeforeAllFileWrite []Hook `yaml:"beforeAllFileWrite,omitempty"`
+
+	// AfterOneFileWrite runs after each output file is written, with the
+	// file's path as its argument
+	AfterOneFileWrite []Hook `yaml:"afterOneFileWrite,omitempty"`
+
+	// AfterAllFileWrite runs once after every output file is written, with
+	// all their paths as arguments
+	AfterAllFileWrite []Hook `yaml:"afterAllFileWrite,omitempty"`
+}
+
+// Hook is a shell command run by sh with the process environment. A failing
+// command fails the run unless NonFatal is set. In the config a hook is
+// either the command string or a {command, nonFatal} mapping.
+type Hook struct {
+	Command  string `yaml:"command"`
+	NonFatal bool   `yaml:"nonFatal,omitempty"`
+}
+
+// hookFields has the fields of Hook without its unmarshal methods
+type hookFields Hook
+
+// UnmarshalYAML accepts a command string as well as a mapping
+func (h *Hook) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*h = Hook{}
+		return value.Decode(&h.Command)
+	}
+	return value.Decode((*hookFields)(h))
+}
+
+// UnmarshalJSON accepts a command string as well as an object, for
+// package.json configs
+func (h *Hook) UnmarshalJSON(data []byte) error {
+	var command string
+	if err := json.Unmarshal(data, &command); err == nil {
+		*h = Hook{Command: command}
+		return nil
+	}
+	return json.Unmarshal(data, (*hookFields)(h))
+}
+
+// validate reports hooks without a command
+func (h Hooks) validate() error {
+	stages := []struct {
+		name  string
+		hooks []Hook
+	}{
+		{"beforeAllFileWrite", h.BeforeAllFileWrite},
+		{"afterOneFileWrite", h.AfterOneFileWrite},
+		{"afterAllFileWrite", h.AfterAllFileWrite},
+	}
+	for _, stage := range stages {
+		for i, hook := range stage.hooks {
+			if hook.Command == "" {
+				return fmt.Errorf("hooks.%s[%d]: command is required", stage.name, i)
+			}
+		}
+	}
+	return nil
+}
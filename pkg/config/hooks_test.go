@@ -0,0 +1,45 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestHooks_Unmarshal(t *testing.T) {
+	want := Hooks{
+		AfterOneFileWrite: []Hook{
+			{Command: "prettier --write"},
+			{Command: "eslint --fix", NonFatal: true},
+		},
+	}
+
+	t.Run("yaml", func(t *testing.T) {
+		var hooks Hooks
+		require.NoError(t, yaml.Unmarshal([]byte(`
+afterOneFileWrite:
+  - prettier --write
+  - command: eslint --fix
+    nonFatal: true
+`), &hooks))
+		assert.Equal(t, want, hooks)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var hooks Hooks
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"afterOneFileWrite": ["prettier --write", {"command": "eslint --fix", "nonFatal": true}]
+		}`), &hooks))
+		assert.Equal(t, want, hooks)
+	})
+}
+
+func TestHooks_Validate(t *testing.T) {
+	hooks := Hooks{AfterAllFileWrite: []Hook{{Command: "prettier --write"}, {NonFatal: true}}}
+	err := hooks.validate()
+	require.Error(t, err)
+	assert.Equal(t, "hooks.afterAllFileWrite[1]: command is required", err.Error())
+}
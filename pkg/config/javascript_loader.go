@@ -1,12 +1,10 @@
 package config
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
-	"path/filepath"
+
+	"github.com/evanw/esbuild/pkg/api"
 )
 
 type JavaScriptLoader struct{}
@@ -17,62 +15,17 @@ func (l *JavaScriptLoader) CanLoad(path string) bool {
 }
 
 func (l *JavaScriptLoader) Load(path string) (*Config, error) {
-	jsCode, err := os.ReadFile(path)
+	jsCode, err := l.transformJavaScript(path)
 	if err != nil {
-		return nil, fmt.Errorf("reading JavaScript file: %w", err)
+		return nil, err
 	}
 
-	config, err := l.executeJavaScript(string(jsCode), path)
+	rawConfig, err := evaluateConfig(jsCode, path)
 	if err != nil {
 		return nil, fmt.Errorf("executing JavaScript: %w", err)
 	}
 
-	return config, nil
-}
-
-func (l *JavaScriptLoader) executeJavaScript(jsCode string, originalPath string) (*Config, error) {
-	if !l.hasNode() {
-		return nil, fmt.Errorf("node not found. Please install Node.js")
-	}
-
-	wrapper := `
-%s
-
-const exportedConfig = module.exports.default || module.exports;
-console.log(JSON.stringify(exportedConfig));
-`
-
-	scriptContent := fmt.Sprintf(wrapper, jsCode)
-
-	tempFile, err := os.CreateTemp("", "graphql-go-gen-*.js")
-	if err != nil {
-		return nil, fmt.Errorf("creating temp file: %w", err)
-	}
-	defer os.Remove(tempFile.Name())
-
-	if _, err := tempFile.WriteString(scriptContent); err != nil {
-		return nil, fmt.Errorf("writing temp file: %w", err)
-	}
-	tempFile.Close()
-
-	cmd := exec.Command("node", tempFile.Name())
-	cmd.Dir = filepath.Dir(originalPath)
-
-	var out bytes.Buffer
-	var stderr bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("node execution error: %s\n%s", err, stderr.String())
-	}
-
-	var rawConfig map[string]interface{}
-	if err := json.Unmarshal(out.Bytes(), &rawConfig); err != nil {
-		return nil, fmt.Errorf("parsing config JSON: %w", err)
-	}
-
-	config, err := l.mapToConfig(rawConfig)
+	config, err := mapScriptConfig(rawConfig)
 	if err != nil {
 		return nil, fmt.Errorf("mapping config: %w", err)
 	}
@@ -80,33 +33,28 @@ console.log(JSON.stringify(exportedConfig));
 	return config, nil
 }
 
-func (l *JavaScriptLoader) mapToConfig(raw map[string]interface{}) (*Config, error) {
-	// Handle onTypeConflict specially if it's a function
-	if conflictVal, ok := raw["onTypeConflict"]; ok {
-		switch conflictVal.(type) {
-		case string:
-			// Keep as string ("error", "useFirst", "useLast")
-		default:
-			// If it's a function or other type, convert to "error" and log warning
-			// JavaScript functions can't be transferred via JSON
-			raw["onTypeConflict"] = "error"
-		}
-	}
-
-	jsonBytes, err := json.Marshal(raw)
+// transformJavaScript converts ES module syntax such as export default to
+// CommonJS, leaving CommonJS configs unchanged
+func (l *JavaScriptLoader) transformJavaScript(path string) (string, error) {
+	contents, err := os.ReadFile(path)
 	if err != nil {
-		return nil, err
+		return "", fmt.Errorf("reading JavaScript file: %w", err)
 	}
 
-	var config Config
-	if err := json.Unmarshal(jsonBytes, &config); err != nil {
-		return nil, err
+	result := api.Transform(string(contents), api.TransformOptions{
+		Loader:     api.LoaderJS,
+		Format:     api.FormatCommonJS,
+		Target:     api.ES2020,
+		Sourcefile: path,
+	})
+
+	if len(result.Errors) > 0 {
+		var errMsg string
+		for _, err := range result.Errors {
+			errMsg += fmt.Sprintf("%v: %s\n", err.Location, err.Text)
+		}
+		return "", fmt.Errorf("JavaScript syntax errors:\n%s", errMsg)
 	}
 
-	return &config, nil
+	return string(result.Code), nil
 }
-
-func (l *JavaScriptLoader) hasNode() bool {
-	cmd := exec.Command("node", "--version")
-	return cmd.Run() == nil
-}
\ No newline at end of file
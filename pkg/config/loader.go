@@ -33,6 +33,7 @@ func (r *LoaderRegistry) Load(path string) (*Config, error) {
 				return nil, fmt.Errorf("loading config with %T: %w", loader, err)
 			}
 
+			cfg.expandEnv()
 			cfg.ResolveRelativePaths(path)
 
 			if err := cfg.setDefaults(); err != nil {
@@ -40,7 +41,7 @@ func (r *LoaderRegistry) Load(path string) (*Config, error) {
 			}
 
 			if err := cfg.Validate(); err != nil {
-				return nil, fmt.Errorf("invalid configuration: %w", err)
+				return nil, fmt.Errorf("invalid configuration: %w%s", err, cfg.missingEnvHint())
 			}
 
 			return cfg, nil
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// configOutputMarker prefixes the JSON line the evaluated config is printed
+// on, so console output of the config itself is ignored
+const configOutputMarker = "__GRAPHQL_GO_GEN_CONFIG__"
+
+// evaluateScript compiles the CommonJS code as if it were the module at
+// GRAPHQL_GO_GEN_CONFIG, so __dirname and relative requires resolve next to
+// the config file. The export may be the config, a promise of it, or a
+// function returning either.
+const evaluateScript = `
+const Module = require('module');
+const path = require('path');
+const fs = require('fs');
+
+const file = process.env.GRAPHQL_GO_GEN_CONFIG;
+const configModule = new Module(file, module);
+configModule.filename = file;
+configModule.paths = Module._nodeModulePaths(path.dirname(file));
+configModule._compile(fs.readFileSync(0, 'utf8'), file);
+
+const exported = configModule.exports.default || configModule.exports;
+Promise.resolve(typeof exported === 'function' ? exported() : exported)
+  .then((config) => {
+    process.stdout.write('\n' + '` + configOutputMarker + `' + JSON.stringify(config) + '\n');
+  })
+  .catch((err) => {
+    console.error((err && err.stack) || err);
+    process.exit(1);
+  });
+`
+
+// hasNode reports whether node is on the PATH
+func hasNode() bool {
+	_, err := exec.LookPath("node")
+	return err == nil
+}
+
+// evaluateConfig runs the CommonJS code of the config at path with Node.js
+// and returns the exported config as decoded JSON
+func evaluateConfig(jsCode string, path string) (map[string]interface{}, error) {
+	if !hasNode() {
+		return nil, fmt.Errorf("%s needs Node.js to be evaluated, but node was not found on PATH; install Node.js (https://nodejs.org) or use a graphql-go-gen.yaml config instead", path)
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("resolving config path: %w", err)
+	}
+
+	cmd := exec.Command("node", "-e", evaluateScript)
+	cmd.Dir = filepath.Dir(absPath)
+	cmd.Env = append(os.Environ(), "GRAPHQL_GO_GEN_CONFIG="+absPath)
+	cmd.Stdin = strings.NewReader(jsCode)
+
+	var out bytes.Buffer
+	var stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("node execution error: %s\n%s", err, stderr.String())
+	}
+
+	idx := strings.LastIndex(out.String(), configOutputMarker)
+	if idx == -1 {
+		return nil, fmt.Errorf("config did not produce any output")
+	}
+	output, _, _ := strings.Cut(out.String()[idx+len(configOutputMarker):], "\n")
+
+	var rawConfig map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &rawConfig); err != nil {
+		return nil, fmt.Errorf("parsing config JSON: %w", err)
+	}
+	if rawConfig == nil {
+		return nil, fmt.Errorf("config must export an object")
+	}
+	return rawConfig, nil
+}
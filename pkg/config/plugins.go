@@ -0,0 +1,44 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CheckPlugins reports the plugins of every output that are not in known,
+// the names of the registered plugins, in one error that suggests close
+// matches. Outputs using a preset are left to the preset.
+func (c *Config) CheckPlugins(known []string) error {
+	candidates := append([]string(nil), known...)
+	sort.Strings(candidates)
+	registered := make(map[string]bool, len(candidates))
+	for _, name := range candidates {
+		registered[name] = true
+	}
+
+	outputPaths := make([]string, 0, len(c.Generates))
+	for outputPath := range c.Generates {
+		outputPaths = append(outputPaths, outputPath)
+	}
+	sort.Strings(outputPaths)
+
+	var unknown []string
+	for _, outputPath := range outputPaths {
+		for _, name := range c.Generates[outputPath].Plugins {
+			if registered[name] {
+				continue
+			}
+			problem := fmt.Sprintf("%q in %s", name, outputPath)
+			if suggestions := suggestNames(name, candidates); len(suggestions) > 0 {
+				problem += fmt.Sprintf(" (did you mean %s?)", strings.Join(quoteAll(suggestions), " or "))
+			}
+			unknown = append(unknown, problem)
+		}
+	}
+
+	if len(unknown) == 0 {
+		return nil
+	}
+	return fmt.Errorf("unknown plugins: %s; available plugins are: %s", strings.Join(unknown, ", "), strings.Join(candidates, ", "))
+}
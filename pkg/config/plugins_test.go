@@ -0,0 +1,32 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_CheckPlugins(t *testing.T) {
+	known := []string{"typescript-operations", "typescript", "add"}
+
+	t.Run("known plugins", func(t *testing.T) {
+		cfg := &Config{Generates: map[string]OutputTarget{
+			"types.ts": {Plugins: []string{"add", "typescript", "typescript-operations"}},
+			"client/":  {Preset: "client"},
+		}}
+		assert.NoError(t, cfg.CheckPlugins(known))
+	})
+
+	t.Run("reports every unknown plugin", func(t *testing.T) {
+		cfg := &Config{Generates: map[string]OutputTarget{
+			"types.ts": {Plugins: []string{"typescript", "typescript-operation"}},
+			"api.ts":   {Plugins: []string{"typescrpt", "graphql-request"}},
+		}}
+		err := cfg.CheckPlugins(known)
+		require.Error(t, err)
+		assert.Equal(t, `unknown plugins: "typescrpt" in api.ts (did you mean "typescript"?), "graphql-request" in api.ts, `+
+			`"typescript-operation" in types.ts (did you mean "typescript-operations"?); `+
+			`available plugins are: add, typescript, typescript-operations`, err.Error())
+	})
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Project is a schema with the documents validated against it and the
+// outputs generated from them. Configs with several projects, e.g. one per
+// app of a monorepo, generate each on its own, so a project's documents
+// never see the fragments or types of another.
+type Project struct {
+	Schema    []SchemaSource          `yaml:"schema"`    // Schema sources
+	Documents Documents               `yaml:"documents"` // Document sources
+	Generates map[string]OutputTarget `yaml:"generates"` // Output targets
+}
+
+// ProjectNames returns the names of the config's projects, sorted
+func (c *Config) ProjectNames() []string {
+	names := make([]string, 0, len(c.Projects))
+	for name := range c.Projects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ForProject returns the config of the named project: the project's schema,
+// documents and outputs with the settings shared by all projects, such as
+// scalars and hooks. It returns nil for an unknown project.
+func (c *Config) ForProject(name string) *Config {
+	project, ok := c.Projects[name]
+	if !ok {
+		return nil
+	}
+	projectConfig := *c
+	projectConfig.Schema = project.Schema
+	projectConfig.Documents = project.Documents
+	projectConfig.Generates = project.Generates
+	projectConfig.Projects = nil
+	return &projectConfig
+}
+
+// validateProjects validates each project, and that the schema, documents
+// and outputs are not also set outside of them
+func (c *Config) validateProjects() error {
+	if len(c.Schema) > 0 || len(c.Documents.Include) > 0 || len(c.Documents.Exclude) > 0 || len(c.Generates) > 0 {
+		return fmt.Errorf("schema, documents and generates must be set per project when projects are used")
+	}
+	for _, name := range c.ProjectNames() {
+		if err := c.ForProject(name).Validate(); err != nil {
+			return fmt.Errorf("project %q: %w", name, err)
+		}
+	}
+	return nil
+}
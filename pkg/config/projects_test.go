@@ -0,0 +1,97 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Projects(t *testing.T) {
+	writeConfig := func(t *testing.T, content string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "graphql-go-gen.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	t.Run("loads each project", func(t *testing.T) {
+		t.Setenv("WEB_OUT", "web/src/gql")
+		path := writeConfig(t, `
+scalars:
+  DateTime: Date
+projects:
+  web:
+    schema:
+      - path: web/schema.graphql
+    documents:
+      include:
+        - "web/src/**/*.graphql"
+    generates:
+      ${WEB_OUT}/types.ts:
+        plugins:
+          - typescript
+  admin:
+    schema:
+      - url: https://admin.example.com/graphql
+    generates:
+      admin/src/types.ts:
+        plugins:
+          - typescript
+`)
+		cfg, err := LoadFile(path)
+		require.NoError(t, err)
+		dir := filepath.Dir(path)
+
+		assert.Equal(t, []string{"admin", "web"}, cfg.ProjectNames())
+		assert.Empty(t, cfg.Schema)
+		assert.Empty(t, cfg.Generates)
+
+		web := cfg.ForProject("web")
+		require.NotNil(t, web)
+		assert.Nil(t, web.Projects)
+		require.Len(t, web.Schema, 1)
+		assert.Equal(t, "file", web.Schema[0].Type)
+		assert.Equal(t, filepath.Join(dir, "web/schema.graphql"), web.Schema[0].Path)
+		assert.Equal(t, []string{filepath.Join(dir, "web/src/**/*.graphql")}, web.Documents.Include)
+		assert.Contains(t, web.Generates, filepath.Join(dir, "web/src/gql/types.ts"))
+		assert.Equal(t, "Date", web.Scalars["DateTime"], "settings outside projects are shared")
+
+		admin := cfg.ForProject("admin")
+		require.NotNil(t, admin)
+		assert.Equal(t, "url", admin.Schema[0].Type)
+		assert.Contains(t, admin.Documents.Include, "**/*.graphql", "documents default per project")
+
+		assert.Nil(t, cfg.ForProject("mobile"))
+	})
+
+	t.Run("rejects top-level sources next to projects", func(t *testing.T) {
+		_, err := LoadFile(writeConfig(t, `
+schema:
+  - path: schema.graphql
+projects:
+  web:
+    schema:
+      - path: web/schema.graphql
+    generates:
+      web/types.ts:
+        plugins:
+          - typescript
+`))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must be set per project")
+	})
+
+	t.Run("validates each project", func(t *testing.T) {
+		_, err := LoadFile(writeConfig(t, `
+projects:
+  web:
+    schema:
+      - path: web/schema.graphql
+`))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `project "web": at least one generation target is required`)
+	})
+}
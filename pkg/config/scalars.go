@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/agnivade/levenshtein"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// defaultScalars are the scalar mappings added by setDefaults
+var defaultScalars = map[string]string{
+	"DateTime": "string",
+	"UUID":     "string",
+	"JSON":     "any",
+}
+
+// CheckScalarMappings returns a warning for every configured scalar mapping
+// that does not match a scalar type in the schema, suggesting close matches.
+// Built-in default mappings are only reported when they were overridden.
+func (c *Config) CheckScalarMappings(s *ast.Schema) []string {
+	if s == nil || len(c.Scalars) == 0 {
+		return nil
+	}
+
+	var schemaScalars []string
+	for name, def := range s.Types {
+		if def.Kind == ast.Scalar {
+			schemaScalars = append(schemaScalars, name)
+		}
+	}
+	sort.Strings(schemaScalars)
+
+	names := make([]string, 0, len(c.Scalars))
+	for name := range c.Scalars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var warnings []string
+	for _, name := range names {
+		if def, ok := s.Types[name]; ok && def.Kind == ast.Scalar {
+			continue
+		}
+		if tsType, ok := defaultScalars[name]; ok && c.Scalars[name] == tsType {
+			continue
+		}
+
+		warning := fmt.Sprintf("scalar %q is mapped in config but is not a scalar in the schema", name)
+		if suggestions := suggestNames(name, schemaScalars); len(suggestions) > 0 {
+			warning += fmt.Sprintf(" (did you mean %s?)", strings.Join(quoteAll(suggestions), " or "))
+		}
+		warnings = append(warnings, warning)
+	}
+
+	return warnings
+}
+
+// suggestNames returns the candidates that are close to name, closest first
+func suggestNames(name string, candidates []string) []string {
+	type match struct {
+		name     string
+		distance int
+	}
+
+	threshold := len(name) / 3
+	if threshold < 2 {
+		threshold = 2
+	}
+
+	var matches []match
+	for _, candidate := range candidates {
+		if strings.EqualFold(name, candidate) {
+			matches = append(matches, match{candidate, 0})
+			continue
+		}
+		if d := levenshtein.ComputeDistance(strings.ToLower(name), strings.ToLower(candidate)); d <= threshold {
+			matches = append(matches, match{candidate, d})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].distance < matches[j].distance
+	})
+
+	result := make([]string, len(matches))
+	for i, m := range matches {
+		result[i] = m.name
+	}
+	return result
+}
+
+func quoteAll(names []string) []string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = fmt.Sprintf("%q", name)
+	}
+	return quoted
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestConfig_CheckScalarMappings(t *testing.T) {
+	s, err := gqlparser.LoadSchema(&ast.Source{Input: `
+		scalar DateTime
+		scalar URL
+
+		type Query {
+			now: DateTime
+			home: URL
+		}
+	`})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		scalars  map[string]string
+		warnings []string
+	}{
+		{
+			name:    "known scalars",
+			scalars: map[string]string{"DateTime": "Date", "URL": "string"},
+		},
+		{
+			name:    "misspelled scalar",
+			scalars: map[string]string{"DateTiem": "string"},
+			warnings: []string{
+				`scalar "DateTiem" is mapped in config but is not a scalar in the schema (did you mean "DateTime"?)`,
+			},
+		},
+		{
+			name:    "wrong case",
+			scalars: map[string]string{"Url": "string"},
+			warnings: []string{
+				`scalar "Url" is mapped in config but is not a scalar in the schema (did you mean "URL"?)`,
+			},
+		},
+		{
+			name:    "unused scalar without close match",
+			scalars: map[string]string{"Money": "number"},
+			warnings: []string{
+				`scalar "Money" is mapped in config but is not a scalar in the schema`,
+			},
+		},
+		{
+			name:    "mapping for an object type",
+			scalars: map[string]string{"Query": "string"},
+			warnings: []string{
+				`scalar "Query" is mapped in config but is not a scalar in the schema`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Scalars: tt.scalars}
+			assert.Equal(t, tt.warnings, cfg.CheckScalarMappings(s))
+		})
+	}
+
+	t.Run("default mappings are not reported", func(t *testing.T) {
+		cfg := &Config{}
+		require.NoError(t, cfg.setDefaults())
+		assert.Empty(t, cfg.CheckScalarMappings(s))
+
+		cfg.Scalars["UUID"] = "UUIDString"
+		assert.Len(t, cfg.CheckScalarMappings(s), 1)
+	})
+}
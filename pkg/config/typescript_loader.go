@@ -1,14 +1,11 @@
 package config
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
-	"path/filepath"
 
 	"github.com/evanw/esbuild/pkg/api"
+	"gopkg.in/yaml.v3"
 )
 
 type TypeScriptLoader struct{}
@@ -24,11 +21,16 @@ func (l *TypeScriptLoader) Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("transpiling TypeScript: %w", err)
 	}
 
-	config, err := l.executeJavaScript(jsCode, path)
+	rawConfig, err := evaluateConfig(jsCode, path)
 	if err != nil {
 		return nil, fmt.Errorf("executing JavaScript: %w", err)
 	}
 
+	config, err := mapScriptConfig(rawConfig)
+	if err != nil {
+		return nil, fmt.Errorf("mapping config: %w", err)
+	}
+
 	return config, nil
 }
 
@@ -56,60 +58,9 @@ func (l *TypeScriptLoader) transpileTypeScript(path string) (string, error) {
 	return string(result.Code), nil
 }
 
-func (l *TypeScriptLoader) executeJavaScript(jsCode string, originalPath string) (*Config, error) {
-	if !l.hasNode() {
-		return nil, fmt.Errorf("node not found. Please install Node.js")
-	}
-
-	wrapper := `
-const path = require('path');
-
-%s
-
-// The transpiled code already has module.exports set up
-const exportedConfig = module.exports.default || module.exports;
-console.log(JSON.stringify(exportedConfig));
-`
-
-	scriptContent := fmt.Sprintf(wrapper, jsCode)
-
-	tempFile, err := os.CreateTemp("", "graphql-go-gen-*.js")
-	if err != nil {
-		return nil, fmt.Errorf("creating temp file: %w", err)
-	}
-	defer os.Remove(tempFile.Name())
-
-	if _, err := tempFile.WriteString(scriptContent); err != nil {
-		return nil, fmt.Errorf("writing temp file: %w", err)
-	}
-	tempFile.Close()
-
-	cmd := exec.Command("node", tempFile.Name())
-	cmd.Dir = filepath.Dir(originalPath)
-
-	var out bytes.Buffer
-	var stderr bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("node execution error: %s\n%s", err, stderr.String())
-	}
-
-	var rawConfig map[string]interface{}
-	if err := json.Unmarshal(out.Bytes(), &rawConfig); err != nil {
-		return nil, fmt.Errorf("parsing config JSON: %w", err)
-	}
-
-	config, err := l.mapToConfig(rawConfig)
-	if err != nil {
-		return nil, fmt.Errorf("mapping config: %w", err)
-	}
-
-	return config, nil
-}
-
-func (l *TypeScriptLoader) mapToConfig(raw map[string]interface{}) (*Config, error) {
+// mapScriptConfig converts a config evaluated from TypeScript or JavaScript,
+// which may use the shorthand graphql-codegen forms, to a Config
+func mapScriptConfig(raw map[string]interface{}) (*Config, error) {
 	// Handle onTypeConflict specially if it's a function
 	if conflictVal, ok := raw["onTypeConflict"]; ok {
 		switch conflictVal.(type) {
@@ -122,40 +73,57 @@ func (l *TypeScriptLoader) mapToConfig(raw map[string]interface{}) (*Config, err
 		}
 	}
 
+	mapScriptSources(raw)
+	if projects, ok := raw["projects"].(map[string]interface{}); ok {
+		for _, project := range projects {
+			if project, ok := project.(map[string]interface{}); ok {
+				mapScriptSources(project)
+			}
+		}
+	}
+
+	// Round trip through YAML so keys match the YAML config, e.g. cache_ttl
+	yamlBytes, err := yaml.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(yamlBytes, &config); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// mapScriptSources converts the shorthand schema and documents forms of a
+// config or project to the YAML structure
+func mapScriptSources(raw map[string]interface{}) {
 	// Handle schema field - it can be string, []string, or []object
 	if schemaVal, ok := raw["schema"]; ok && schemaVal != nil {
-		var schemas []SchemaSource
+		var items []interface{}
 
 		switch v := schemaVal.(type) {
-		case string:
-			// Single string: schema: './schema.graphql'
-			schemas = []SchemaSource{{Type: "file", Path: v}}
 		case []interface{}:
-			// Array of strings or objects
-			for _, item := range v {
-				switch s := item.(type) {
-				case string:
-					// String in array: ['./schema1.graphql', './schema2.graphql']
-					schemas = append(schemas, SchemaSource{Type: "file", Path: s})
-				case map[string]interface{}:
-					// Object in array: [{type: 'url', url: '...'}]
-					// This will be handled by the normal JSON unmarshaling
-					// Keep as-is for now, will be processed later
-					schemas = nil
-					goto skipSchemaProcessing
-				}
-			}
-		case map[string]interface{}:
-			// Single object (rare but possible)
-			// Keep as-is for normal processing
-			goto skipSchemaProcessing
+			items = v
+		default:
+			// Single string or object: schema: './schema.graphql'
+			items = []interface{}{v}
 		}
 
-		if schemas != nil {
-			raw["schema"] = schemas
+		schemas := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			switch s := item.(type) {
+			case string:
+				// String: './schema1.graphql'
+				schemas = append(schemas, SchemaSource{Type: "file", Path: s})
+			default:
+				// Object: {type: 'url', url: '...'}, decoded with the YAML keys below
+				schemas = append(schemas, s)
+			}
 		}
+		raw["schema"] = schemas
 	}
-skipSchemaProcessing:
 
 	// Handle documents field - it can be string, []string, or object with include/exclude
 	if docsVal, ok := raw["documents"]; ok && docsVal != nil {
@@ -206,26 +174,37 @@ skipSchemaProcessing:
 					}
 				}
 			}
+			if resolveImports, ok := v["resolveImports"].(bool); ok {
+				documents.ResolveImports = resolveImports
+			}
+			if requireID, ok := v["requireIdSelection"].(bool); ok {
+				documents.RequireIdSelection = requireID
+			}
+			if strict, ok := v["strict"].(bool); ok {
+				documents.Strict = strict
+			}
+			if directive, ok := v["requireGenerateDirective"].(string); ok {
+				documents.RequireGenerateDirective = directive
+			}
+			if allowed, ok := v["allowedOperationTypes"].([]interface{}); ok {
+				for _, item := range allowed {
+					if str, ok := item.(string); ok {
+						documents.AllowedOperationTypes = append(documents.AllowedOperationTypes, str)
+					}
+				}
+			}
+			if pluckConfig, ok := v["pluckConfig"].(map[string]interface{}); ok {
+				if tags, ok := pluckConfig["tags"].([]interface{}); ok {
+					for _, item := range tags {
+						if str, ok := item.(string); ok {
+							documents.PluckConfig.Tags = append(documents.PluckConfig.Tags, str)
+						}
+					}
+				}
+			}
 		}
 
 		// Replace the raw documents field with our structured version
 		raw["documents"] = documents
 	}
-
-	jsonBytes, err := json.Marshal(raw)
-	if err != nil {
-		return nil, err
-	}
-
-	var config Config
-	if err := json.Unmarshal(jsonBytes, &config); err != nil {
-		return nil, err
-	}
-
-	return &config, nil
-}
-
-func (l *TypeScriptLoader) hasNode() bool {
-	cmd := exec.Command("node", "--version")
-	return cmd.Run() == nil
 }
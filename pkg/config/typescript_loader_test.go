@@ -77,7 +77,7 @@ module.exports = config;
 
 	t.Run("Loads network schema configuration", func(t *testing.T) {
 		// Skip if node is not available
-		if !loader.hasNode() {
+		if !hasNode() {
 			t.Skip("Node.js is not available")
 		}
 
@@ -170,7 +170,7 @@ module.exports = config;
 
 	t.Run("Loads network schema configuration", func(t *testing.T) {
 		// Skip if node is not available
-		if !loader.hasNode() {
+		if !hasNode() {
 			t.Skip("Node.js is not available")
 		}
 
@@ -321,4 +321,78 @@ func TestConfigValidation_NetworkSchemas(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestScriptConfig_Evaluation(t *testing.T) {
+	if !hasNode() {
+		t.Skip("Node.js is not available")
+	}
+
+	dir := t.TempDir()
+	writeFile := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+	writeFile("shared.js", `module.exports = { documents: ['src/**/*.graphql'] };`)
+
+	t.Run("relative requires, __dirname and console output", func(t *testing.T) {
+		path := writeFile("graphql-go-gen.config.js", `
+const path = require('path');
+const shared = require('./shared');
+console.log('computing config');
+module.exports = {
+	schema: path.join(__dirname, 'schema.graphql'),
+	documents: shared.documents,
+	generates: { 'types.ts': { plugins: ['typescript'] } },
+};
+`)
+		cfg, err := (&JavaScriptLoader{}).Load(path)
+		require.NoError(t, err)
+		require.Len(t, cfg.Schema, 1)
+		assert.Equal(t, filepath.Join(dir, "schema.graphql"), cfg.Schema[0].Path)
+		assert.Equal(t, []string{"src/**/*.graphql"}, cfg.Documents.Include)
+	})
+
+	t.Run("export default of an async function", func(t *testing.T) {
+		t.Setenv("SCHEMA_TOKEN", "secret")
+		path := writeFile("graphql-go-gen.config.ts", `
+export default async () => ({
+	schema: [{ type: 'url', url: 'https://api.example.com/graphql', headers: { Authorization: 'Bearer ' + process.env.SCHEMA_TOKEN } }],
+	generates: { 'types.ts': { plugins: ['typescript'] } },
+});
+`)
+		cfg, err := (&TypeScriptLoader{}).Load(path)
+		require.NoError(t, err)
+		require.Len(t, cfg.Schema, 1)
+		assert.Equal(t, "Bearer secret", cfg.Schema[0].Headers["Authorization"])
+	})
+
+	t.Run("ES module syntax in a JavaScript config", func(t *testing.T) {
+		path := writeFile("graphql-go-gen.config.mjs", `
+export default { schema: './schema.graphql', generates: { 'types.ts': { plugins: ['typescript'] } } };
+`)
+		cfg, err := (&JavaScriptLoader{}).Load(path)
+		require.NoError(t, err)
+		require.Len(t, cfg.Schema, 1)
+		assert.Equal(t, "./schema.graphql", cfg.Schema[0].Path)
+	})
+
+	t.Run("thrown errors are reported", func(t *testing.T) {
+		path := writeFile("broken.config.js", `throw new Error('missing SCHEMA_URL');`)
+		_, err := (&JavaScriptLoader{}).Load(path)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing SCHEMA_URL")
+	})
+}
+
+func TestScriptConfig_NodeMissing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "graphql-go-gen.config.js")
+	require.NoError(t, os.WriteFile(path, []byte(`module.exports = {};`), 0644))
+	t.Setenv("PATH", t.TempDir())
+
+	_, err := (&JavaScriptLoader{}).Load(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "node was not found on PATH")
+	assert.Contains(t, err.Error(), "install Node.js")
+}
@@ -3,8 +3,6 @@ package config
 import (
 	"fmt"
 	"os"
-	"regexp"
-	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -22,8 +20,6 @@ func (l *YAMLLoader) Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("reading config file: %w", err)
 	}
 
-	data = []byte(expandEnvVars(string(data)))
-
 	var config Config
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("parsing YAML config file: %w", err)
@@ -31,17 +27,3 @@ func (l *YAMLLoader) Load(path string) (*Config, error) {
 
 	return &config, nil
 }
-
-func expandEnvVars(s string) string {
-	re := regexp.MustCompile(`\$\{([^}]+)\}|\$(\w+)`)
-	return re.ReplaceAllStringFunc(s, func(match string) string {
-		varName := strings.TrimPrefix(match, "${")
-		varName = strings.TrimPrefix(varName, "$")
-		varName = strings.TrimSuffix(varName, "}")
-
-		if value := os.Getenv(varName); value != "" {
-			return value
-		}
-		return match
-	})
-}
\ No newline at end of file
@@ -0,0 +1,90 @@
+package documents
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+)
+
+// DedupeDefinitions drops operations and fragments that repeat one seen
+// earlier in the same file with the same name and printed body, as when a
+// file is loaded twice. Definitions sharing a name with a different body
+// are kept and reported in the returned warnings. Documents left empty are
+// dropped; the documents returned are copies where anything was dropped,
+// and docs is not modified.
+func DedupeDefinitions(docs []*Document) ([]*Document, []string) {
+	type definitionKey struct {
+		filePath, kind, name, body string
+	}
+	type firstSeen struct {
+		filePath, body string
+	}
+
+	seen := make(map[definitionKey]bool)
+	named := make(map[string]firstSeen)
+	var warnings []string
+
+	// keep reports whether a definition is new, warning about a name that
+	// was seen with another body
+	keep := func(doc *Document, kind, name, body string) bool {
+		key := definitionKey{doc.FilePath, kind, name, body}
+		if seen[key] {
+			return false
+		}
+		seen[key] = true
+
+		if name == "" {
+			return true
+		}
+		if first, ok := named[kind+" "+name]; !ok {
+			named[kind+" "+name] = firstSeen{doc.FilePath, body}
+		} else if first.body != body {
+			warnings = append(warnings, fmt.Sprintf("%s %q in %s differs from the one in %s", kind, name, doc.FilePath, first.filePath))
+		}
+		return true
+	}
+
+	result := make([]*Document, 0, len(docs))
+	for _, doc := range docs {
+		if doc == nil || doc.AST == nil {
+			result = append(result, doc)
+			continue
+		}
+
+		filtered := *doc.AST
+		filtered.Operations = nil
+		filtered.Fragments = nil
+		for _, op := range doc.AST.Operations {
+			if keep(doc, "operation", op.Name, printDefinition(&ast.QueryDocument{Operations: ast.OperationList{op}})) {
+				filtered.Operations = append(filtered.Operations, op)
+			}
+		}
+		for _, frag := range doc.AST.Fragments {
+			if keep(doc, "fragment", frag.Name, printDefinition(&ast.QueryDocument{Fragments: ast.FragmentDefinitionList{frag}})) {
+				filtered.Fragments = append(filtered.Fragments, frag)
+			}
+		}
+
+		switch {
+		case len(filtered.Operations) == 0 && len(filtered.Fragments) == 0:
+			continue
+		case len(filtered.Operations) == len(doc.AST.Operations) && len(filtered.Fragments) == len(doc.AST.Fragments):
+			result = append(result, doc)
+		default:
+			copied := *doc
+			copied.AST = &filtered
+			result = append(result, &copied)
+		}
+	}
+	return result, warnings
+}
+
+// printDefinition prints a document holding a single definition, so bodies
+// that differ only in formatting compare equal
+func printDefinition(doc *ast.QueryDocument) string {
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf).FormatQueryDocument(doc)
+	return buf.String()
+}
@@ -0,0 +1,70 @@
+package documents
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+func TestDedupeDefinitions(t *testing.T) {
+	load := func(path, query string) *Document {
+		doc, err := parser.ParseQuery(&ast.Source{Name: path, Input: query})
+		require.NoError(t, err)
+		return &Document{FilePath: path, Content: query, AST: doc}
+	}
+	names := func(docs []*Document) (ops, frags []string) {
+		for _, op := range CollectAllOperations(docs) {
+			ops = append(ops, op.Name)
+		}
+		for _, frag := range CollectAllFragments(docs) {
+			frags = append(frags, frag.Name)
+		}
+		return ops, frags
+	}
+
+	t.Run("drops definitions loaded twice", func(t *testing.T) {
+		docs := []*Document{
+			load("user.ts", `query GetUser { user { ...UserFields } } fragment UserFields on User { id }`),
+			load("user.ts", `query GetUser { user { ...UserFields } }`),
+			load("user.ts", "fragment UserFields on User {\n  id\n}"),
+			load("viewer.ts", `query GetViewer { viewer { ...UserFields } }`),
+		}
+
+		deduped, warnings := DedupeDefinitions(docs)
+		assert.Empty(t, warnings)
+		ops, frags := names(deduped)
+		assert.Equal(t, []string{"GetUser", "GetViewer"}, ops)
+		assert.Equal(t, []string{"UserFields"}, frags)
+		assert.Len(t, deduped, 2, "documents left empty are dropped")
+		assert.Same(t, docs[0], deduped[0], "unchanged documents are kept as is")
+
+		ops, frags = names(docs)
+		assert.Len(t, ops, 3, "the input is not modified")
+		assert.Len(t, frags, 2)
+	})
+
+	t.Run("keeps definitions with the same name and another body", func(t *testing.T) {
+		docs := []*Document{
+			load("a.graphql", `fragment UserFields on User { id }`),
+			load("b.graphql", `fragment UserFields on User { id name }`),
+			load("b.graphql", `fragment UserFields on User { id name }`),
+		}
+
+		deduped, warnings := DedupeDefinitions(docs)
+		_, frags := names(deduped)
+		assert.Equal(t, []string{"UserFields", "UserFields"}, frags)
+		assert.Equal(t, []string{`fragment "UserFields" in b.graphql differs from the one in a.graphql`}, warnings)
+	})
+
+	t.Run("identical definitions in different files", func(t *testing.T) {
+		deduped, warnings := DedupeDefinitions([]*Document{
+			load("a.graphql", `query GetUser { user { id } }`),
+			load("b.graphql", `query GetUser { user { id } }`),
+		})
+		assert.Empty(t, warnings)
+		assert.Len(t, deduped, 2, "only repeats within a file are dropped")
+	})
+}
@@ -0,0 +1,84 @@
+package documents
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// DependencyGraph maps operations and fragments to the fragments they
+// spread directly. Transitive dependencies are found by following the
+// fragment edges.
+type DependencyGraph struct {
+	Operations map[string][]string `json:"operations"`
+	Fragments  map[string][]string `json:"fragments"`
+}
+
+// BuildDependencyGraph builds the fragment dependency graph of docs.
+// Anonymous operations are keyed by their operation type, e.g.
+// "anonymous query". Spreads are listed sorted and once each.
+func BuildDependencyGraph(docs []*Document) *DependencyGraph {
+	graph := &DependencyGraph{
+		Operations: make(map[string][]string),
+		Fragments:  make(map[string][]string),
+	}
+
+	for _, op := range CollectAllOperations(docs) {
+		name := op.Name
+		if name == "" {
+			name = "anonymous " + string(op.Operation)
+		}
+		graph.Operations[name] = sortedUnique(append(graph.Operations[name], GetUsedFragments(op.SelectionSet)...))
+	}
+
+	for _, frag := range CollectAllFragments(docs) {
+		graph.Fragments[frag.Name] = sortedUnique(append(graph.Fragments[frag.Name], GetUsedFragments(frag.SelectionSet)...))
+	}
+
+	for _, edges := range []map[string][]string{graph.Operations, graph.Fragments} {
+		for name, deps := range edges {
+			if deps == nil {
+				edges[name] = []string{}
+			}
+		}
+	}
+
+	return graph
+}
+
+// JSON returns the graph as indented JSON with sorted keys
+func (g *DependencyGraph) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// FragmentClosure returns every fragment name reaches, directly or through
+// other fragments, sorted
+func (g *DependencyGraph) FragmentClosure(name string) []string {
+	deps, ok := g.Operations[name]
+	if !ok {
+		deps = g.Fragments[name]
+	}
+
+	seen := make(map[string]bool)
+	var visit func(names []string)
+	visit = func(names []string) {
+		for _, dep := range names {
+			if seen[dep] {
+				continue
+			}
+			seen[dep] = true
+			visit(g.Fragments[dep])
+		}
+	}
+	visit(deps)
+
+	closure := make([]string, 0, len(seen))
+	for dep := range seen {
+		closure = append(closure, dep)
+	}
+	sort.Strings(closure)
+	return closure
+}
@@ -0,0 +1,51 @@
+package documents
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestBuildDependencyGraph(t *testing.T) {
+	s, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: scalarUsageSchema})
+	require.NoError(t, err)
+
+	doc, gqlErr := gqlparser.LoadQuery(s, `
+		query GetViewer {
+			viewer { ...UserFields posts { ...PostFields } }
+		}
+		query GetPosts {
+			posts { id }
+		}
+		fragment UserFields on User { id posts { ...PostFields } }
+		fragment PostFields on Post { ...PostId createdAt }
+		fragment PostId on Post { id }
+	`)
+	require.Nil(t, gqlErr)
+
+	graph := BuildDependencyGraph([]*Document{{FilePath: "queries.graphql", AST: doc}})
+
+	assert.Equal(t, map[string][]string{
+		"GetViewer": {"PostFields", "UserFields"},
+		"GetPosts":  {},
+	}, graph.Operations)
+	assert.Equal(t, map[string][]string{
+		"UserFields": {"PostFields"},
+		"PostFields": {"PostId"},
+		"PostId":     {},
+	}, graph.Fragments)
+
+	assert.Equal(t, []string{"PostFields", "PostId", "UserFields"}, graph.FragmentClosure("GetViewer"))
+	assert.Equal(t, []string{"PostFields", "PostId"}, graph.FragmentClosure("UserFields"))
+	assert.Empty(t, graph.FragmentClosure("GetPosts"))
+
+	data, err := graph.JSON()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"operations": {"GetPosts": [], "GetViewer": ["PostFields", "UserFields"]},
+		"fragments": {"PostFields": ["PostId"], "PostId": [], "UserFields": ["PostFields"]}
+	}`, string(data))
+}
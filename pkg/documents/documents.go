@@ -22,6 +22,12 @@ type Document struct {
 
 	// Hash of the document content
 	Hash string
+
+	// Line and Column locate Content within FilePath when the document was
+	// extracted from a larger file, e.g. a template literal. Zero means the
+	// document starts at the beginning of the file.
+	Line   int
+	Column int
 }
 
 // Loader loads GraphQL documents from various sources
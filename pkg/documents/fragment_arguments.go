@@ -0,0 +1,273 @@
+package documents
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// FragmentArgumentsDirective declares the arguments of a fragment and passes
+// values for them at a spread, in the experimental fragment arguments
+// syntax:
+//
+//	fragment UserPosts on User @arguments(count: Int, draft: {type: "Boolean!", defaultValue: false}) {
+//	  posts(first: $count) @skip(if: $draft) { id }
+//	}
+//	query GetUser { user { ...UserPosts @arguments(count: 5) } }
+//
+// The fragment's selections refer to its arguments as variables.
+const FragmentArgumentsDirective = "arguments"
+
+// FragmentArgument is an argument a fragment declares with @arguments
+type FragmentArgument struct {
+	Name string
+	// Type is the GraphQL type as written, e.g. Int or "Boolean!"
+	Type string
+	// DefaultValue is nil when the argument has no default
+	DefaultValue *ast.Value
+}
+
+// FragmentArguments returns the arguments frag declares, in order. Each is
+// declared with its type, as in count: Int, or with an object holding the
+// type and a default value, as in count: {type: "Int", defaultValue: 10}.
+func FragmentArguments(frag *ast.FragmentDefinition) []FragmentArgument {
+	directive := frag.Directives.ForName(FragmentArgumentsDirective)
+	if directive == nil {
+		return nil
+	}
+
+	args := make([]FragmentArgument, 0, len(directive.Arguments))
+	for _, arg := range directive.Arguments {
+		declared := FragmentArgument{Name: arg.Name}
+		if arg.Value != nil {
+			switch arg.Value.Kind {
+			case ast.ObjectValue:
+				if typ := arg.Value.Children.ForName("type"); typ != nil {
+					declared.Type = typ.Raw
+				}
+				declared.DefaultValue = arg.Value.Children.ForName("defaultValue")
+			default:
+				declared.Type = arg.Value.Raw
+			}
+		}
+		args = append(args, declared)
+	}
+	return args
+}
+
+// FragmentSelections returns the selections of frag with its arguments
+// replaced by the values spread passes with @arguments or, failing that,
+// their default values. A nil spread uses the default values, as for the
+// fragment's own type. Arguments without either stay variables. The
+// fragment's selection set is returned unchanged when it declares no
+// arguments.
+func FragmentSelections(frag *ast.FragmentDefinition, spread *ast.FragmentSpread) ast.SelectionSet {
+	declared := FragmentArguments(frag)
+	if len(declared) == 0 {
+		return frag.SelectionSet
+	}
+
+	var passed ast.ArgumentList
+	if spread != nil {
+		if directive := spread.Directives.ForName(FragmentArgumentsDirective); directive != nil {
+			passed = directive.Arguments
+		}
+	}
+
+	values := make(map[string]*ast.Value, len(declared))
+	for _, arg := range declared {
+		if value := passed.ForName(arg.Name); value != nil {
+			values[arg.Name] = value.Value
+		} else if arg.DefaultValue != nil {
+			values[arg.Name] = arg.DefaultValue
+		}
+	}
+	if len(values) == 0 {
+		return frag.SelectionSet
+	}
+	return substituteSelections(frag.SelectionSet, values)
+}
+
+// substituteSelections copies selections, replacing the variables named in
+// values in arguments and directives. Fragments spread by selections are
+// left for their own expansion.
+func substituteSelections(selections ast.SelectionSet, values map[string]*ast.Value) ast.SelectionSet {
+	if selections == nil {
+		return nil
+	}
+	result := make(ast.SelectionSet, 0, len(selections))
+	for _, sel := range selections {
+		switch s := sel.(type) {
+		case *ast.Field:
+			field := *s
+			field.Arguments = substituteArguments(s.Arguments, values)
+			field.Directives = substituteDirectives(s.Directives, values)
+			field.SelectionSet = substituteSelections(s.SelectionSet, values)
+			result = append(result, &field)
+		case *ast.InlineFragment:
+			inline := *s
+			inline.Directives = substituteDirectives(s.Directives, values)
+			inline.SelectionSet = substituteSelections(s.SelectionSet, values)
+			result = append(result, &inline)
+		case *ast.FragmentSpread:
+			spread := *s
+			spread.Directives = substituteDirectives(s.Directives, values)
+			result = append(result, &spread)
+		default:
+			result = append(result, sel)
+		}
+	}
+	return result
+}
+
+func substituteDirectives(directives ast.DirectiveList, values map[string]*ast.Value) ast.DirectiveList {
+	if directives == nil {
+		return nil
+	}
+	result := make(ast.DirectiveList, 0, len(directives))
+	for _, directive := range directives {
+		copied := *directive
+		copied.Arguments = substituteArguments(directive.Arguments, values)
+		result = append(result, &copied)
+	}
+	return result
+}
+
+func substituteArguments(args ast.ArgumentList, values map[string]*ast.Value) ast.ArgumentList {
+	if args == nil {
+		return nil
+	}
+	result := make(ast.ArgumentList, 0, len(args))
+	for _, arg := range args {
+		copied := *arg
+		copied.Value = substituteValue(arg.Value, values)
+		result = append(result, &copied)
+	}
+	return result
+}
+
+func substituteValue(value *ast.Value, values map[string]*ast.Value) *ast.Value {
+	if value == nil {
+		return nil
+	}
+	if value.Kind == ast.Variable {
+		if replacement, ok := values[value.Raw]; ok {
+			return replacement
+		}
+		return value
+	}
+	if len(value.Children) == 0 {
+		return value
+	}
+	copied := *value
+	copied.Children = make(ast.ChildValueList, 0, len(value.Children))
+	for _, child := range value.Children {
+		copied.Children = append(copied.Children, &ast.ChildValue{
+			Name:     child.Name,
+			Value:    substituteValue(child.Value, values),
+			Position: child.Position,
+			Comment:  child.Comment,
+		})
+	}
+	return &copied
+}
+
+// AllowFragmentArguments drops the validation errors the fragment arguments
+// syntax causes: the unknown @arguments directive and the arguments of doc's
+// fragments used as variables the operation does not define. An undefined
+// variable is only allowed where it is used inside a fragment declaring an
+// argument of that name; anywhere else it is still an error.
+func AllowFragmentArguments(doc *ast.QueryDocument, errs gqlerror.List) gqlerror.List {
+	argumentUses := make(map[string]bool)
+	for _, frag := range doc.Fragments {
+		declared := make(map[string]bool)
+		for _, arg := range FragmentArguments(frag) {
+			declared[arg.Name] = true
+		}
+		if len(declared) > 0 {
+			collectVariableUses(frag.SelectionSet, declared, argumentUses)
+		}
+	}
+
+	kept := errs[:0:0]
+	for _, err := range errs {
+		switch {
+		case err.Rule == "KnownDirectives" && err.Message == `Unknown directive "@`+FragmentArgumentsDirective+`".`:
+			continue
+		case err.Rule == "NoUndefinedVariables" && len(err.Locations) > 0:
+			file, _ := err.Extensions["file"].(string)
+			location := err.Locations[0]
+			if argumentUses[variableUseKey(undefinedVariable(err.Message), file, location.Line, location.Column)] {
+				continue
+			}
+		}
+		kept = append(kept, err)
+	}
+	return kept
+}
+
+// collectVariableUses records in uses where the variables named in declared
+// are used in selections, keyed by variableUseKey. Fragments spread by
+// selections are not followed, as their variables are their own arguments.
+func collectVariableUses(selections ast.SelectionSet, declared map[string]bool, uses map[string]bool) {
+	for _, sel := range selections {
+		switch s := sel.(type) {
+		case *ast.Field:
+			for _, arg := range s.Arguments {
+				collectValueVariableUses(arg.Value, declared, uses)
+			}
+			collectDirectiveVariableUses(s.Directives, declared, uses)
+			collectVariableUses(s.SelectionSet, declared, uses)
+		case *ast.InlineFragment:
+			collectDirectiveVariableUses(s.Directives, declared, uses)
+			collectVariableUses(s.SelectionSet, declared, uses)
+		case *ast.FragmentSpread:
+			collectDirectiveVariableUses(s.Directives, declared, uses)
+		}
+	}
+}
+
+func collectDirectiveVariableUses(directives ast.DirectiveList, declared map[string]bool, uses map[string]bool) {
+	for _, directive := range directives {
+		for _, arg := range directive.Arguments {
+			collectValueVariableUses(arg.Value, declared, uses)
+		}
+	}
+}
+
+func collectValueVariableUses(value *ast.Value, declared map[string]bool, uses map[string]bool) {
+	if value == nil {
+		return
+	}
+	if value.Kind == ast.Variable {
+		if declared[value.Raw] && value.Position != nil {
+			file := ""
+			if value.Position.Src != nil {
+				file = value.Position.Src.Name
+			}
+			uses[variableUseKey(value.Raw, file, value.Position.Line, value.Position.Column)] = true
+		}
+		return
+	}
+	for _, child := range value.Children {
+		collectValueVariableUses(child.Value, declared, uses)
+	}
+}
+
+// variableUseKey identifies the use of variable name at a position
+func variableUseKey(name string, file string, line int, column int) string {
+	return fmt.Sprintf("%s@%s:%d:%d", name, file, line, column)
+}
+
+// undefinedVariable returns the variable name of a NoUndefinedVariables
+// message, e.g. count in `Variable "$count" is not defined by operation "Q".`
+func undefinedVariable(message string) string {
+	rest, ok := strings.CutPrefix(message, `Variable "$`)
+	if !ok {
+		return ""
+	}
+	name, _, _ := strings.Cut(rest, `"`)
+	return name
+}
@@ -0,0 +1,115 @@
+package documents
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+const fragmentArgumentsDocument = `
+query GetViewer($withHistory: Boolean!) {
+	viewer {
+		...UserPosts @arguments(count: 5)
+		...UserPosts @arguments(count: 10, withHistory: $withHistory)
+	}
+}
+
+fragment UserPosts on User @arguments(count: Int, withHistory: {type: "Boolean!", defaultValue: false}) {
+	posts(since: $count) {
+		id
+		history @include(if: $withHistory)
+	}
+}
+`
+
+func TestFragmentArguments(t *testing.T) {
+	doc, err := parser.ParseQuery(&ast.Source{Name: "viewer.graphql", Input: fragmentArgumentsDocument})
+	require.NoError(t, err)
+	frag := doc.Fragments.ForName("UserPosts")
+
+	args := FragmentArguments(frag)
+	require.Len(t, args, 2)
+	assert.Equal(t, "count", args[0].Name)
+	assert.Equal(t, "Int", args[0].Type)
+	assert.Nil(t, args[0].DefaultValue)
+	assert.Equal(t, "withHistory", args[1].Name)
+	assert.Equal(t, "Boolean!", args[1].Type)
+	require.NotNil(t, args[1].DefaultValue)
+	assert.Equal(t, "false", args[1].DefaultValue.Raw)
+
+	assert.Empty(t, FragmentArguments(&ast.FragmentDefinition{Name: "Plain"}))
+}
+
+func TestFragmentSelections(t *testing.T) {
+	doc, err := parser.ParseQuery(&ast.Source{Name: "viewer.graphql", Input: fragmentArgumentsDocument})
+	require.NoError(t, err)
+	frag := doc.Fragments.ForName("UserPosts")
+	spreads := doc.Operations[0].SelectionSet[0].(*ast.Field).SelectionSet
+
+	// posts(since: ...) { history @include(if: ...) }
+	values := func(selections ast.SelectionSet) (since, include *ast.Value) {
+		posts := selections[0].(*ast.Field)
+		history := posts.SelectionSet[1].(*ast.Field)
+		return posts.Arguments.ForName("since").Value, history.Directives.ForName("include").Arguments.ForName("if").Value
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		since, include := values(FragmentSelections(frag, nil))
+		assert.Equal(t, "$count", since.String(), "arguments without a default stay variables")
+		assert.Equal(t, "false", include.String())
+	})
+
+	t.Run("spread values override defaults", func(t *testing.T) {
+		since, include := values(FragmentSelections(frag, spreads[0].(*ast.FragmentSpread)))
+		assert.Equal(t, "5", since.String())
+		assert.Equal(t, "false", include.String())
+
+		since, include = values(FragmentSelections(frag, spreads[1].(*ast.FragmentSpread)))
+		assert.Equal(t, "10", since.String())
+		assert.Equal(t, "$withHistory", include.String(), "operation variables are passed through")
+	})
+
+	t.Run("fragment is unchanged", func(t *testing.T) {
+		since, include := values(frag.SelectionSet)
+		assert.Equal(t, "$count", since.String())
+		assert.Equal(t, "$withHistory", include.String())
+	})
+}
+
+func TestAllowFragmentArguments(t *testing.T) {
+	s, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: scalarUsageSchema})
+	require.NoError(t, err)
+
+	doc, err := parser.ParseQuery(&ast.Source{Name: "viewer.graphql", Input: fragmentArgumentsDocument})
+	require.NoError(t, err)
+	assert.NotEmpty(t, validator.Validate(s, doc))
+	assert.Empty(t, AllowFragmentArguments(doc, validator.Validate(s, doc)))
+
+	undefined, err := parser.ParseQuery(&ast.Source{Name: "viewer.graphql", Input: `
+		query GetViewer { viewer { ...UserPosts } }
+		fragment UserPosts on User @arguments(count: Int) {
+			posts(since: $since) { id }
+		}
+	`})
+	require.NoError(t, err)
+	errs := AllowFragmentArguments(undefined, validator.Validate(s, undefined))
+	require.Len(t, errs, 1, "variables that are not fragment arguments are still undefined")
+	assert.Contains(t, errs[0].Message, `"$since"`)
+
+	direct, err := parser.ParseQuery(&ast.Source{Name: "viewer.graphql", Input: `
+		query GetViewer { viewer { posts(since: $count) { id } ...UserPosts } }
+		fragment UserPosts on User @arguments(count: Int) {
+			posts(since: $count) { id }
+		}
+	`})
+	require.NoError(t, err)
+	errs = AllowFragmentArguments(direct, validator.Validate(s, direct))
+	require.Len(t, errs, 1, "an operation using a fragment argument's name directly is still undefined")
+	assert.Contains(t, errs[0].Message, `"$count"`)
+	assert.Equal(t, 2, errs[0].Locations[0].Line)
+}
@@ -0,0 +1,80 @@
+package documents
+
+import (
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// SelectTagged restricts docs to the operations and fragments carrying the
+// directive, e.g. @generate, and the fragments they spread, which their
+// types need. The directive is stripped from the definitions returned.
+// Documents left empty are dropped. The documents returned are copies; docs
+// is not modified.
+func SelectTagged(docs []*Document, directive string) []*Document {
+	graph := BuildDependencyGraph(docs)
+
+	var roots []string
+	for _, op := range CollectAllOperations(docs) {
+		if op.Directives.ForName(directive) != nil {
+			roots = append(roots, GetUsedFragments(op.SelectionSet)...)
+		}
+	}
+	for _, frag := range CollectAllFragments(docs) {
+		if frag.Directives.ForName(directive) != nil {
+			roots = append(roots, frag.Name)
+		}
+	}
+	keepFragments := make(map[string]bool)
+	for _, name := range fragmentClosure(graph, roots) {
+		keepFragments[name] = true
+	}
+
+	result := make([]*Document, 0, len(docs))
+	for _, doc := range docs {
+		if doc == nil || doc.AST == nil {
+			continue
+		}
+		filtered := *doc.AST
+		filtered.Operations = nil
+		filtered.Fragments = nil
+		for _, op := range doc.AST.Operations {
+			if op.Directives.ForName(directive) == nil {
+				continue
+			}
+			stripped := *op
+			stripped.Directives = filterDirectives(op.Directives, map[string]bool{directive: true})
+			filtered.Operations = append(filtered.Operations, &stripped)
+		}
+		for _, frag := range doc.AST.Fragments {
+			if !keepFragments[frag.Name] {
+				continue
+			}
+			if frag.Directives.ForName(directive) != nil {
+				stripped := *frag
+				stripped.Directives = filterDirectives(frag.Directives, map[string]bool{directive: true})
+				frag = &stripped
+			}
+			filtered.Fragments = append(filtered.Fragments, frag)
+		}
+		if len(filtered.Operations) == 0 && len(filtered.Fragments) == 0 {
+			continue
+		}
+
+		copied := *doc
+		copied.AST = &filtered
+		result = append(result, &copied)
+	}
+	return result
+}
+
+// AllowDirective drops the validation errors for using the directive name
+// when the schema does not declare it, as for directives only codegen reads
+func AllowDirective(name string, errs gqlerror.List) gqlerror.List {
+	kept := errs[:0:0]
+	for _, err := range errs {
+		if err.Rule == "KnownDirectives" && err.Message == `Unknown directive "@`+name+`".` {
+			continue
+		}
+		kept = append(kept, err)
+	}
+	return kept
+}
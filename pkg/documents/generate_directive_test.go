@@ -0,0 +1,64 @@
+package documents
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+func TestSelectTagged(t *testing.T) {
+	load := func(path, query string) *Document {
+		doc, err := parser.ParseQuery(&ast.Source{Name: path, Input: query})
+		require.NoError(t, err)
+		return &Document{FilePath: path, Content: query, AST: doc}
+	}
+	docs := []*Document{
+		load("viewer.graphql", `
+			query GetViewer @generate { viewer { ...UserFields } }
+			query GetLegacyViewer { viewer { id } }
+			fragment UserFields on User { id posts { ...PostFields } }
+			fragment PostFields on Post { id }
+		`),
+		load("posts.graphql", `
+			query GetPosts { posts { ...PostTitle } }
+			fragment PostTitle on Post @generate @client { title }
+			fragment PostBody on Post { body }
+		`),
+		load("legacy.graphql", `query GetLegacyPosts { posts { id } }`),
+	}
+
+	selected := SelectTagged(docs, "generate")
+
+	var ops, frags []string
+	for _, op := range CollectAllOperations(selected) {
+		ops = append(ops, op.Name)
+		assert.Nil(t, op.Directives.ForName("generate"), "the directive is stripped")
+	}
+	for _, frag := range CollectAllFragments(selected) {
+		frags = append(frags, frag.Name)
+		assert.Nil(t, frag.Directives.ForName("generate"), "the directive is stripped")
+	}
+	assert.Equal(t, []string{"GetViewer"}, ops)
+	assert.Equal(t, []string{"UserFields", "PostFields", "PostTitle"}, frags, "fragments tagged operations spread are kept")
+	assert.Len(t, selected, 2, "documents left empty are dropped")
+
+	title := CollectAllFragments(selected)[2]
+	require.Len(t, title.Directives, 1, "other directives are kept")
+	assert.Equal(t, "client", title.Directives[0].Name)
+	assert.NotNil(t, docs[0].AST.Operations[0].Directives.ForName("generate"), "docs is not modified")
+}
+
+func TestAllowDirective(t *testing.T) {
+	s := gqlparser.MustLoadSchema(&ast.Source{Input: `type Query { ping: String }`})
+	doc, err := parser.ParseQuery(&ast.Source{Input: `query Ping @generate @unknown { ping }`})
+	require.NoError(t, err)
+
+	errs := AllowDirective("generate", validator.Validate(s, doc))
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Message, "@unknown")
+}
@@ -0,0 +1,262 @@
+package documents
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// CheckIdSelections returns a warning for every selection on a type with an
+// `id` field that does not select `id`. Normalized caches such as Apollo's and
+// Relay's identify entities by it, so leaving it out causes stale data.
+// Fragment spreads are expanded, so an `id` selected by a fragment counts.
+func CheckIdSelections(s *ast.Schema, docs []*Document) []string {
+	if s == nil {
+		return nil
+	}
+
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, frag := range CollectAllFragments(docs) {
+		fragments[frag.Name] = frag
+	}
+
+	var warnings []string
+	for _, doc := range docs {
+		for _, op := range GetOperations(doc) {
+			var root *ast.Definition
+			switch op.Operation {
+			case ast.Query:
+				root = s.Query
+			case ast.Mutation:
+				root = s.Mutation
+			case ast.Subscription:
+				root = s.Subscription
+			}
+			if root == nil {
+				continue
+			}
+
+			name := op.Name
+			if name == "" {
+				name = "anonymous " + string(op.Operation)
+			}
+			c := &idSelectionChecker{schema: s, fragments: fragments}
+			c.check(root, []ast.SelectionSet{op.SelectionSet}, "", false)
+			for _, missing := range c.missing {
+				warnings = append(warnings, fmt.Sprintf("%s: %s selects %s", doc.FilePath, name, missing))
+			}
+		}
+	}
+
+	return warnings
+}
+
+type idSelectionChecker struct {
+	schema    *ast.Schema
+	fragments map[string]*ast.FragmentDefinition
+	missing   []string
+}
+
+// narrowedSelection is a fragment on a more specific type than the one it
+// is spread into
+type narrowedSelection struct {
+	typeCondition string
+	selectionSet  ast.SelectionSet
+}
+
+// check verifies the selections made on typeDef at path, then the selections
+// of its fields and of fragments on narrower types. hasID is set when the
+// enclosing abstract type already selects id.
+func (c *idSelectionChecker) check(typeDef *ast.Definition, selectionSets []ast.SelectionSet, path string, hasID bool) {
+	var order []string
+	var narrowed []narrowedSelection
+	fields := make(map[string][]*ast.Field)
+	for _, selectionSet := range selectionSets {
+		c.collectFields(typeDef, selectionSet, make(map[string]bool), &narrowed, func(field *ast.Field) {
+			responseName := field.Alias
+			if responseName == "" {
+				responseName = field.Name
+			}
+			if _, seen := fields[responseName]; !seen {
+				order = append(order, responseName)
+			}
+			fields[responseName] = append(fields[responseName], field)
+		})
+	}
+
+	hasID = hasID || selectsID(fields["id"])
+	if typeDef.Fields.ForName("id") != nil && !hasID {
+		location := "the root selection"
+		if path != "" {
+			location = fmt.Sprintf("%q", path)
+		}
+		c.missing = append(c.missing, fmt.Sprintf("%s at %s without id", typeDef.Name, location))
+	}
+
+	for _, responseName := range order {
+		var childSets []ast.SelectionSet
+		var fieldDef *ast.FieldDefinition
+		for _, field := range fields[responseName] {
+			if fieldDef == nil {
+				fieldDef = typeDef.Fields.ForName(field.Name)
+			}
+			if len(field.SelectionSet) > 0 {
+				childSets = append(childSets, field.SelectionSet)
+			}
+		}
+		if fieldDef == nil || len(childSets) == 0 {
+			continue
+		}
+
+		childType := c.schema.Types[fieldDef.Type.Name()]
+		if childType == nil || (childType.Kind != ast.Object && childType.Kind != ast.Interface) {
+			continue
+		}
+		c.check(childType, childSets, joinPath(path, responseName), false)
+	}
+
+	for _, n := range narrowed {
+		if narrowedType := c.schema.Types[n.typeCondition]; narrowedType != nil {
+			c.check(narrowedType, []ast.SelectionSet{n.selectionSet}, path+" on "+n.typeCondition, hasID)
+		}
+	}
+}
+
+// collectFields calls add for each field selected on typeDef, including those
+// in fragments that apply to it. Fragments on narrower types are appended to
+// narrowed so they can be checked against their own type condition.
+func (c *idSelectionChecker) collectFields(typeDef *ast.Definition, selectionSet ast.SelectionSet, visited map[string]bool, narrowed *[]narrowedSelection, add func(*ast.Field)) {
+	for _, sel := range selectionSet {
+		switch s := sel.(type) {
+		case *ast.Field:
+			add(s)
+		case *ast.InlineFragment:
+			c.collectFragment(typeDef, s.TypeCondition, s.SelectionSet, visited, narrowed, add)
+		case *ast.FragmentSpread:
+			frag := c.fragments[s.Name]
+			if frag == nil || visited[frag.Name] {
+				continue
+			}
+			visited[frag.Name] = true
+			c.collectFragment(typeDef, frag.TypeCondition, frag.SelectionSet, visited, narrowed, add)
+			delete(visited, frag.Name)
+		}
+	}
+}
+
+func (c *idSelectionChecker) collectFragment(typeDef *ast.Definition, typeCondition string, selectionSet ast.SelectionSet, visited map[string]bool, narrowed *[]narrowedSelection, add func(*ast.Field)) {
+	if typeCondition == "" || typeCondition == typeDef.Name || implementsInterface(typeDef, typeCondition) {
+		c.collectFields(typeDef, selectionSet, visited, narrowed, add)
+		return
+	}
+	*narrowed = append(*narrowed, narrowedSelection{typeCondition: typeCondition, selectionSet: selectionSet})
+}
+
+// selectsID reports whether id is selected under its own response name
+func selectsID(fields []*ast.Field) bool {
+	for _, field := range fields {
+		if field.Name == "id" {
+			return true
+		}
+	}
+	return false
+}
+
+func implementsInterface(def *ast.Definition, name string) bool {
+	for _, iface := range def.Interfaces {
+		if iface == name {
+			return true
+		}
+	}
+	return false
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// CheckOperationTypes returns an error for every operation whose type is not
+// in allowed, located in the file the operation came from. An empty allowed
+// list permits every operation type.
+func CheckOperationTypes(docs []*Document, allowed []string) SourceErrors {
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	permitted := make(map[ast.Operation]bool, len(allowed))
+	for _, operationType := range allowed {
+		permitted[ast.Operation(operationType)] = true
+	}
+
+	var errs SourceErrors
+	for _, doc := range docs {
+		for _, op := range GetOperations(doc) {
+			if permitted[op.Operation] {
+				continue
+			}
+
+			definition := string(op.Operation) + " " + op.Name
+			if op.Name == "" {
+				definition = "anonymous " + string(op.Operation)
+			}
+			err := &SourceError{
+				FilePath:   doc.FilePath,
+				Definition: definition,
+				Message:    fmt.Sprintf("%s operations are not allowed (allowed: %s)", op.Operation, strings.Join(allowed, ", ")),
+			}
+			if op.Position != nil && op.Position.Line > 0 {
+				err.Line, err.Column = doc.position(op.Position.Line, op.Position.Column)
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// CheckDuplicateNames returns an error for every operation or fragment whose
+// name was already used by one defined earlier in docs, located in its file
+// and pointing at the first definition. Generated types are named after
+// definitions, so duplicates would declare a type twice or silently resolve
+// spreads to the last fragment defined.
+func CheckDuplicateNames(docs []*Document) SourceErrors {
+	type definitionKey struct {
+		fragment bool
+		name     string
+	}
+	first := make(map[definitionKey]string)
+
+	var errs SourceErrors
+	check := func(doc *Document, key definitionKey, definition string, pos *ast.Position) {
+		err := &SourceError{FilePath: doc.FilePath, Definition: definition}
+		if pos != nil && pos.Line > 0 {
+			err.Line, err.Column = doc.Locate(pos)
+		}
+		location := err.FilePath
+		if err.Line > 0 {
+			location = fmt.Sprintf("%s:%d:%d", err.FilePath, err.Line, err.Column)
+		}
+
+		if previous, ok := first[key]; ok {
+			err.Message = fmt.Sprintf("duplicate name %q, first defined at %s", key.name, previous)
+			errs = append(errs, err)
+			return
+		}
+		first[key] = location
+	}
+
+	for _, doc := range docs {
+		for _, op := range GetOperations(doc) {
+			if op.Name != "" {
+				check(doc, definitionKey{false, op.Name}, string(op.Operation)+" "+op.Name, op.Position)
+			}
+		}
+		for _, frag := range GetFragments(doc) {
+			check(doc, definitionKey{true, frag.Name}, "fragment "+frag.Name, frag.Position)
+		}
+	}
+	return errs
+}
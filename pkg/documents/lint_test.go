@@ -0,0 +1,148 @@
+package documents
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+const lintSchema = `
+interface Node {
+	id: ID!
+}
+
+type User implements Node {
+	id: ID!
+	name: String!
+	friends: [User!]!
+	settings: Settings!
+}
+
+type Settings {
+	theme: String!
+}
+
+type Query {
+	viewer: User
+	node(id: ID!): Node
+}
+`
+
+func TestCheckIdSelections(t *testing.T) {
+	s, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: lintSchema})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		query    string
+		warnings []string
+	}{
+		{
+			name:  "id selected",
+			query: `query Viewer { viewer { id name settings { theme } } }`,
+		},
+		{
+			name:  "missing id",
+			query: `query Viewer { viewer { name } }`,
+			warnings: []string{
+				`viewer.graphql: Viewer selects User at "viewer" without id`,
+			},
+		},
+		{
+			name:  "missing id in nested list",
+			query: `query Viewer { viewer { id friends { name } } }`,
+			warnings: []string{
+				`viewer.graphql: Viewer selects User at "viewer.friends" without id`,
+			},
+		},
+		{
+			name: "id selected by fragment",
+			query: `
+				query Viewer { viewer { ...UserName ...UserID } }
+				fragment UserName on User { name }
+				fragment UserID on Node { id }
+			`,
+		},
+		{
+			name:  "aliased id does not count",
+			query: `query Viewer { viewer { userId: id name } }`,
+			warnings: []string{
+				`viewer.graphql: Viewer selects User at "viewer" without id`,
+			},
+		},
+		{
+			name:  "narrowed fragment without id",
+			query: `query Viewer { node(id: "1") { id ... on User { friends { name } } } }`,
+			warnings: []string{
+				`viewer.graphql: Viewer selects User at "node on User.friends" without id`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queryDoc, gqlErr := gqlparser.LoadQuery(s, tt.query)
+			require.Nil(t, gqlErr)
+			docs := []*Document{{FilePath: "viewer.graphql", Content: tt.query, AST: queryDoc}}
+
+			assert.Equal(t, tt.warnings, CheckIdSelections(s, docs))
+		})
+	}
+}
+
+func TestCheckOperationTypes(t *testing.T) {
+	s, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: lintSchema + `
+type Mutation {
+	rename(name: String!): User
+}
+`})
+	require.NoError(t, err)
+
+	content := "query Viewer { viewer { id } }\n\nmutation Rename { rename(name: \"x\") { id } }"
+	queryDoc, gqlErr := gqlparser.LoadQuery(s, content)
+	require.Nil(t, gqlErr)
+	docs := []*Document{{FilePath: "src/user.ts", Content: content, AST: queryDoc, Line: 5, Column: 20}}
+
+	t.Run("mutation rejected when only queries are allowed", func(t *testing.T) {
+		errs := CheckOperationTypes(docs, []string{"query"})
+		require.Len(t, errs, 1)
+		assert.Equal(t, `src/user.ts:7:1: in mutation Rename: mutation operations are not allowed (allowed: query)`, errs[0].Error())
+	})
+
+	t.Run("all types allowed by default", func(t *testing.T) {
+		assert.Empty(t, CheckOperationTypes(docs, nil))
+		assert.Empty(t, CheckOperationTypes(docs, []string{"query", "mutation"}))
+	})
+}
+
+func TestCheckDuplicateNames(t *testing.T) {
+	load := func(path, query string, line, column int) *Document {
+		doc, err := parser.ParseQuery(&ast.Source{Name: path, Input: query})
+		require.NoError(t, err)
+		return &Document{FilePath: path, Content: query, AST: doc, Line: line, Column: column}
+	}
+
+	t.Run("names defined in several files", func(t *testing.T) {
+		docs := []*Document{
+			load("user.graphql", "query GetUser { viewer { ...UserFields } }\nfragment UserFields on User { id }", 0, 0),
+			load("src/profile.ts", "query GetUser { viewer { id } }", 3, 15),
+			load("src/fields.ts", "fragment UserFields on User { name }\nquery UserFields { viewer { id } }", 1, 20),
+		}
+
+		errs := CheckDuplicateNames(docs)
+		require.Len(t, errs, 2, "operations and fragments have their own names")
+		assert.Equal(t, `src/profile.ts:3:15: in query GetUser: duplicate name "GetUser", first defined at user.graphql:1:1`, errs[0].Error())
+		assert.Equal(t, `src/fields.ts:1:20: in fragment UserFields: duplicate name "UserFields", first defined at user.graphql:2:1`, errs[1].Error())
+	})
+
+	t.Run("unique and anonymous definitions", func(t *testing.T) {
+		assert.Empty(t, CheckDuplicateNames([]*Document{
+			load("a.graphql", "{ viewer { id } }\nquery GetUser { viewer { id } }", 0, 0),
+			load("b.graphql", "{ viewer { id } }\nfragment UserFields on User { id }", 0, 0),
+		}))
+	})
+}
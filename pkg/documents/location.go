@@ -0,0 +1,121 @@
+package documents
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// definitionHeaderRegexp matches the start of a named operation or fragment
+var definitionHeaderRegexp = regexp.MustCompile(`^\s*(query|mutation|subscription|fragment)\s+([_A-Za-z][_0-9A-Za-z]*)`)
+
+// SourceError is a GraphQL parse or validation error located in the file the
+// document came from
+type SourceError struct {
+	FilePath string
+	Line     int
+	Column   int
+	// Definition is the operation or fragment containing the error, e.g.
+	// "query GetUser", or empty when it can't be determined
+	Definition string
+	Message    string
+}
+
+func (e *SourceError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.FilePath)
+	if e.Line > 0 {
+		fmt.Fprintf(&b, ":%d:%d", e.Line, e.Column)
+	}
+	b.WriteString(": ")
+	if e.Definition != "" {
+		fmt.Fprintf(&b, "in %s: ", e.Definition)
+	}
+	b.WriteString(e.Message)
+	return b.String()
+}
+
+// SourceErrors reports every invalid document at once
+type SourceErrors []*SourceError
+
+func (errs SourceErrors) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid GraphQL documents (%d errors):", len(errs))
+	for _, err := range errs {
+		b.WriteString("\n  ")
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
+
+// LocateErrors translates the GraphQL errors in err, whose positions are
+// relative to doc.Content, into positions in doc.FilePath. Errors that carry
+// no GraphQL position are returned as a single SourceError for the file.
+func LocateErrors(doc *Document, err error) []*SourceError {
+	if err == nil {
+		return nil
+	}
+
+	var gqlErrs gqlerror.List
+	var gqlErr *gqlerror.Error
+	switch {
+	case errors.As(err, &gqlErrs):
+	case errors.As(err, &gqlErr):
+		gqlErrs = gqlerror.List{gqlErr}
+	default:
+		return []*SourceError{{FilePath: doc.FilePath, Message: err.Error()}}
+	}
+
+	located := make([]*SourceError, 0, len(gqlErrs))
+	for _, e := range gqlErrs {
+		sourceErr := &SourceError{FilePath: doc.FilePath, Message: e.Message}
+		if len(e.Locations) > 0 {
+			loc := e.Locations[0]
+			sourceErr.Line, sourceErr.Column = doc.position(loc.Line, loc.Column)
+			sourceErr.Definition = definitionAt(doc.Content, loc.Line)
+		}
+		located = append(located, sourceErr)
+	}
+	return located
+}
+
+// Locate returns the line and column in FilePath of a position in the
+// document's AST, e.g. of an operation defined in a template literal
+func (d *Document) Locate(pos *ast.Position) (int, int) {
+	if pos == nil {
+		return 0, 0
+	}
+	return d.position(pos.Line, pos.Column)
+}
+
+// position maps a line and column in Content to the containing file. Only
+// the first line is shifted by Column: later lines of an embedded document
+// begin at the start of a file line.
+func (d *Document) position(line, column int) (int, int) {
+	if d.Line == 0 {
+		return line, column
+	}
+	if line == 1 {
+		column += d.Column - 1
+	}
+	return d.Line + line - 1, column
+}
+
+// definitionAt returns the last operation or fragment header that starts at
+// or before line in content
+func definitionAt(content string, line int) string {
+	lines := strings.Split(content, "\n")
+	if line > len(lines) {
+		line = len(lines)
+	}
+	for i := line - 1; i >= 0; i-- {
+		if match := definitionHeaderRegexp.FindStringSubmatch(lines[i]); match != nil {
+			return match[1] + " " + match[2]
+		}
+	}
+	return ""
+}
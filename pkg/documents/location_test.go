@@ -0,0 +1,74 @@
+package documents
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+func TestLocateErrors(t *testing.T) {
+	t.Run("parse error in an extracted document", func(t *testing.T) {
+		doc := &Document{
+			FilePath: "src/user.ts",
+			Content:  "query GetUser {\n  user {\n    id(\n  }\n}",
+			Line:     10,
+			Column:   22,
+		}
+		_, err := parser.ParseQuery(&ast.Source{Name: doc.FilePath, Input: doc.Content})
+		require.Error(t, err)
+
+		located := LocateErrors(doc, err)
+		require.Len(t, located, 1)
+		assert.Equal(t, 13, located[0].Line)
+		assert.Equal(t, "query GetUser", located[0].Definition)
+		assert.Contains(t, located[0].Error(), "src/user.ts:13:")
+	})
+
+	t.Run("validation error on the first line", func(t *testing.T) {
+		schema := gqlparser.MustLoadSchema(&ast.Source{Input: `type Query { viewer: String }`})
+		doc := &Document{
+			FilePath: "src/viewer.tsx",
+			Content:  "query Viewer { missing }",
+			Line:     4,
+			Column:   30,
+		}
+		queryDoc, err := parser.ParseQuery(&ast.Source{Input: doc.Content})
+		require.NoError(t, err)
+
+		located := LocateErrors(doc, validator.Validate(schema, queryDoc))
+		require.Len(t, located, 1)
+		assert.Equal(t, 4, located[0].Line)
+		assert.Equal(t, 30+15, located[0].Column)
+		assert.Equal(t, "query Viewer", located[0].Definition)
+		assert.Equal(t, `src/viewer.tsx:4:45: in query Viewer: Cannot query field "missing" on type "Query".`, located[0].Error())
+	})
+
+	t.Run("documents read from their own file", func(t *testing.T) {
+		doc := &Document{
+			FilePath: "queries.graphql",
+			Content:  "fragment A on Query { viewer }\n\nquery B { viewer(x: 1) }",
+		}
+		schema := gqlparser.MustLoadSchema(&ast.Source{Input: `type Query { viewer: String }`})
+		queryDoc, err := parser.ParseQuery(&ast.Source{Input: doc.Content})
+		require.NoError(t, err)
+
+		located := LocateErrors(doc, validator.Validate(schema, queryDoc))
+		require.NotEmpty(t, located)
+		assert.Equal(t, 3, located[0].Line)
+		assert.Equal(t, "query B", located[0].Definition)
+	})
+}
+
+func TestSourceErrors(t *testing.T) {
+	errs := SourceErrors{
+		{FilePath: "a.graphql", Line: 2, Column: 3, Definition: "query A", Message: "boom"},
+		{FilePath: "b.ts", Message: "reading file"},
+	}
+
+	assert.Equal(t, "invalid GraphQL documents (2 errors):\n  a.graphql:2:3: in query A: boom\n  b.ts: reading file", errs.Error())
+}
@@ -0,0 +1,189 @@
+package documents
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+)
+
+// NormalizeOptions controls how NormalizeDocument orders a document. Sorting
+// makes documents that differ only in the order of their selections or
+// arguments print, and so hash, the same.
+type NormalizeOptions struct {
+	// SortSelections orders each selection set: fields by response key and
+	// name, then fragment spreads by name, then inline fragments by type
+	// condition
+	SortSelections bool
+	// SortArguments orders the arguments of fields and directives, the
+	// variable definitions of operations and the fields of object values by
+	// name
+	SortArguments bool
+}
+
+// CanonicalNormalization sorts everything NormalizeOptions can sort. It is
+// the normalization of persisted documents.
+var CanonicalNormalization = NormalizeOptions{
+	SortSelections: true,
+	SortArguments:  true,
+}
+
+// NormalizeDocument prints doc without client-only directives, ordered as
+// opts asks. doc itself is left unchanged.
+func NormalizeDocument(doc *ast.QueryDocument, opts NormalizeOptions) string {
+	if doc == nil {
+		return ""
+	}
+
+	// Clone the document to avoid modifying the original
+	cloned := cloneDocument(doc)
+	if cloned == doc {
+		// The document does not reparse; sorting would change the original
+		opts = NormalizeOptions{}
+	}
+
+	// Remove client-only directives
+	removeClientDirectives(cloned)
+	sortDocument(cloned, opts)
+
+	// Format the document consistently
+	var buf bytes.Buffer
+	f := formatter.NewFormatter(&buf)
+	f.FormatQueryDocument(cloned)
+
+	return buf.String()
+}
+
+// sortDocument orders the operations and fragments of doc in place
+func sortDocument(doc *ast.QueryDocument, opts NormalizeOptions) {
+	if !opts.SortSelections && !opts.SortArguments {
+		return
+	}
+	for _, op := range doc.Operations {
+		if opts.SortArguments {
+			sort.SliceStable(op.VariableDefinitions, func(i, j int) bool {
+				return op.VariableDefinitions[i].Variable < op.VariableDefinitions[j].Variable
+			})
+			for _, variable := range op.VariableDefinitions {
+				sortValue(variable.DefaultValue)
+				sortDirectiveArguments(variable.Directives)
+			}
+			sortDirectiveArguments(op.Directives)
+		}
+		sortSelections(op.SelectionSet, opts)
+	}
+	for _, frag := range doc.Fragments {
+		if opts.SortArguments {
+			sortDirectiveArguments(frag.Directives)
+		}
+		sortSelections(frag.SelectionSet, opts)
+	}
+}
+
+// sortSelections orders a selection set and the selection sets within it
+func sortSelections(selections ast.SelectionSet, opts NormalizeOptions) {
+	for _, sel := range selections {
+		switch s := sel.(type) {
+		case *ast.Field:
+			if opts.SortArguments {
+				sortArguments(s.Arguments)
+				sortDirectiveArguments(s.Directives)
+			}
+			sortSelections(s.SelectionSet, opts)
+		case *ast.InlineFragment:
+			if opts.SortArguments {
+				sortDirectiveArguments(s.Directives)
+			}
+			sortSelections(s.SelectionSet, opts)
+		case *ast.FragmentSpread:
+			if opts.SortArguments {
+				sortDirectiveArguments(s.Directives)
+			}
+		}
+	}
+
+	if opts.SortSelections {
+		sort.SliceStable(selections, func(i, j int) bool {
+			return selectionSortKey(selections[i]) < selectionSortKey(selections[j])
+		})
+	}
+}
+
+// SelectionSortKey returns the group and name selections are ordered by:
+// fields (0) by field name, then fragment spreads (1) by fragment name, then
+// inline fragments (2), which have no name
+func SelectionSortKey(sel ast.Selection) (int, string) {
+	switch s := sel.(type) {
+	case *ast.Field:
+		return 0, s.Name
+	case *ast.FragmentSpread:
+		return 1, s.Name
+	case *ast.InlineFragment:
+		return 2, ""
+	}
+	return 3, ""
+}
+
+// selectionSortKey refines SelectionSortKey for NormalizeDocument: fields
+// are ordered by response name first and inline fragments by type
+// condition, and directive names break ties between selections that differ
+// only in their directives
+func selectionSortKey(sel ast.Selection) string {
+	group, name := SelectionSortKey(sel)
+	key := strconv.Itoa(group)
+	switch s := sel.(type) {
+	case *ast.Field:
+		responseName := s.Alias
+		if responseName == "" {
+			responseName = name
+		}
+		return key + responseName + "\x00" + name + "\x00" + directiveNames(s.Directives)
+	case *ast.FragmentSpread:
+		return key + name + "\x00" + directiveNames(s.Directives)
+	case *ast.InlineFragment:
+		return key + s.TypeCondition + "\x00" + directiveNames(s.Directives)
+	}
+	return key
+}
+
+func directiveNames(directives ast.DirectiveList) string {
+	names := make([]string, len(directives))
+	for i, directive := range directives {
+		names[i] = directive.Name
+	}
+	return strings.Join(names, ",")
+}
+
+func sortDirectiveArguments(directives ast.DirectiveList) {
+	for _, directive := range directives {
+		sortArguments(directive.Arguments)
+	}
+}
+
+func sortArguments(args ast.ArgumentList) {
+	sort.SliceStable(args, func(i, j int) bool {
+		return args[i].Name < args[j].Name
+	})
+	for _, arg := range args {
+		sortValue(arg.Value)
+	}
+}
+
+// sortValue orders the fields of object values, including those nested in
+// lists and other objects
+func sortValue(value *ast.Value) {
+	if value == nil {
+		return
+	}
+	if value.Kind == ast.ObjectValue {
+		sort.SliceStable(value.Children, func(i, j int) bool {
+			return value.Children[i].Name < value.Children[j].Name
+		})
+	}
+	for _, child := range value.Children {
+		sortValue(child.Value)
+	}
+}
@@ -0,0 +1,85 @@
+package documents
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+func TestNormalizeDocument(t *testing.T) {
+	parse := func(query string) *ast.QueryDocument {
+		t.Helper()
+		doc, err := parser.ParseQuery(&ast.Source{Input: query})
+		require.NoError(t, err)
+		return doc
+	}
+
+	original := parse(`
+		query Search($term: String!, $first: Int = 10) {
+			search(term: $term, filter: {kind: USER, archived: false}, first: $first) {
+				... on User { name id }
+				...SearchResult
+				id
+				primary: name @include(if: true)
+			}
+		}
+		fragment SearchResult on SearchResult { score title }
+	`)
+	reordered := parse(`
+		query Search($first: Int = 10, $term: String!) {
+			search(first: $first, filter: {archived: false, kind: USER}, term: $term) {
+				primary: name @include(if: true)
+				id
+				...SearchResult
+				... on User { id name }
+			}
+		}
+		fragment SearchResult on SearchResult { title score }
+	`)
+
+	t.Run("canonical output for reordered input", func(t *testing.T) {
+		normalized := NormalizeDocument(original, CanonicalNormalization)
+		assert.Equal(t, normalized, NormalizeDocument(reordered, CanonicalNormalization))
+		assert.Equal(t, `query Search ($first: Int = 10, $term: String!) {
+	search(filter: {archived:false,kind:USER}, first: $first, term: $term) {
+		id
+		primary: name @include(if: true)
+		... SearchResult
+		... on User {
+			id
+			name
+		}
+	}
+}
+fragment SearchResult on SearchResult {
+	score
+	title
+}
+`, normalized)
+	})
+
+	t.Run("persisted documents are canonical", func(t *testing.T) {
+		assert.Equal(t, NormalizePersistedDocument(original), NormalizePersistedDocument(reordered))
+	})
+
+	t.Run("options sort independently", func(t *testing.T) {
+		selectionsOnly := NormalizeDocument(reordered, NormalizeOptions{SortSelections: true})
+		assert.Contains(t, selectionsOnly, "search(first: $first, filter: {archived:false,kind:USER}, term: $term)")
+		assert.Contains(t, selectionsOnly, "\t\tid\n\t\tprimary: name")
+
+		argumentsOnly := NormalizeDocument(reordered, NormalizeOptions{SortArguments: true})
+		assert.Contains(t, argumentsOnly, "search(filter: {archived:false,kind:USER}, first: $first, term: $term)")
+		assert.Contains(t, argumentsOnly, "\t\tprimary: name @include(if: true)\n\t\tid")
+	})
+
+	t.Run("leaves the document unchanged", func(t *testing.T) {
+		NormalizeDocument(reordered, CanonicalNormalization)
+		field := reordered.Operations[0].SelectionSet[0].(*ast.Field)
+		assert.Equal(t, "search", field.Name)
+		assert.Equal(t, "first", field.Arguments[0].Name)
+		assert.Equal(t, "primary", field.SelectionSet[0].(*ast.Field).Alias)
+	})
+}
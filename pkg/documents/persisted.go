@@ -0,0 +1,192 @@
+package documents
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash/fnv"
+	"sort"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// NormalizePersistedDocument normalizes a document for persisted operations.
+// It removes client-only directives, sorts selections and arguments with
+// CanonicalNormalization and formats consistently, so reordering a document
+// keeps its hash.
+func NormalizePersistedDocument(doc *ast.QueryDocument) string {
+	return NormalizeDocument(doc, CanonicalNormalization)
+}
+
+// HashPersistedDocument hashes a normalized document with the given algorithm
+// or a func(string) string. The string algorithms produce hex hashes of these
+// lengths:
+//
+//   - "sha1": 40 characters (the default, also used for unknown algorithms)
+//   - "sha256": 64 characters
+//   - "sha256-trunc": 16 characters, the first 64 bits of the sha256
+//   - "fnv": 16 characters, the 64-bit FNV-1a hash; fast but not
+//     collision-resistant, meant for local development
+func HashPersistedDocument(content string, algorithm interface{}) string {
+	switch alg := algorithm.(type) {
+	case string:
+		switch alg {
+		case "sha256":
+			hash := sha256.Sum256([]byte(content))
+			return hex.EncodeToString(hash[:])
+		case "sha256-trunc":
+			hash := sha256.Sum256([]byte(content))
+			return hex.EncodeToString(hash[:8])
+		case "fnv":
+			hash := fnv.New64a()
+			hash.Write([]byte(content))
+			return hex.EncodeToString(hash.Sum(nil))
+		case "sha1":
+			fallthrough
+		default:
+			hash := sha1.Sum([]byte(content))
+			return hex.EncodeToString(hash[:])
+		}
+	case func(string) string:
+		// Custom hash function
+		return alg(content)
+	default:
+		// Default to SHA1
+		hash := sha1.Sum([]byte(content))
+		return hex.EncodeToString(hash[:])
+	}
+}
+
+// cloneDocument creates a deep copy of a GraphQL document
+func cloneDocument(doc *ast.QueryDocument) *ast.QueryDocument {
+	if doc == nil {
+		return nil
+	}
+
+	// Serialize and reparse for a deep clone
+	var buf bytes.Buffer
+	f := formatter.NewFormatter(&buf)
+	f.FormatQueryDocument(doc)
+
+	cloned, err := parser.ParseQuery(&ast.Source{
+		Input: buf.String(),
+	})
+	if err != nil {
+		// Fallback to original if parsing fails
+		return doc
+	}
+
+	return cloned
+}
+
+// removeClientDirectives removes client-only directives from a document
+func removeClientDirectives(doc *ast.QueryDocument) {
+	if doc == nil {
+		return
+	}
+
+	clientDirectives := map[string]bool{
+		"client":     true,
+		"connection": true,
+		"defer":      true,
+		"stream":     true,
+	}
+
+	for _, op := range doc.Operations {
+		removeDirectivesFromOperation(op, clientDirectives)
+	}
+
+	for _, frag := range doc.Fragments {
+		removeDirectivesFromFragment(frag, clientDirectives)
+	}
+}
+
+// removeDirectivesFromOperation removes specific directives from an operation
+func removeDirectivesFromOperation(op *ast.OperationDefinition, directivesToRemove map[string]bool) {
+	if op == nil {
+		return
+	}
+	op.Directives = filterDirectives(op.Directives, directivesToRemove)
+	removeDirectivesFromSelectionSet(op.SelectionSet, directivesToRemove)
+}
+
+// removeDirectivesFromFragment removes specific directives from a fragment
+func removeDirectivesFromFragment(frag *ast.FragmentDefinition, directivesToRemove map[string]bool) {
+	if frag == nil {
+		return
+	}
+	frag.Directives = filterDirectives(frag.Directives, directivesToRemove)
+	removeDirectivesFromSelectionSet(frag.SelectionSet, directivesToRemove)
+}
+
+// removeDirectivesFromSelectionSet removes directives from a selection set
+func removeDirectivesFromSelectionSet(selSet ast.SelectionSet, directivesToRemove map[string]bool) {
+	if selSet == nil {
+		return
+	}
+
+	for _, sel := range selSet {
+		switch s := sel.(type) {
+		case *ast.Field:
+			s.Directives = filterDirectives(s.Directives, directivesToRemove)
+			removeDirectivesFromSelectionSet(s.SelectionSet, directivesToRemove)
+
+		case *ast.InlineFragment:
+			s.Directives = filterDirectives(s.Directives, directivesToRemove)
+			removeDirectivesFromSelectionSet(s.SelectionSet, directivesToRemove)
+
+		case *ast.FragmentSpread:
+			s.Directives = filterDirectives(s.Directives, directivesToRemove)
+		}
+	}
+}
+
+// filterDirectives filters out specific directives from a list
+func filterDirectives(directives ast.DirectiveList, toRemove map[string]bool) ast.DirectiveList {
+	if len(directives) == 0 {
+		return directives
+	}
+
+	var filtered ast.DirectiveList
+	for _, dir := range directives {
+		if !toRemove[dir.Name] {
+			filtered = append(filtered, dir)
+		}
+	}
+	return filtered
+}
+
+// OperationDocument builds the document sent to the server for an operation:
+// the operation followed by every fragment it uses, sorted by name
+func OperationDocument(op *ast.OperationDefinition, fragments map[string]*ast.FragmentDefinition) *ast.QueryDocument {
+	doc := &ast.QueryDocument{
+		Operations: ast.OperationList{op},
+	}
+
+	seen := make(map[string]bool)
+	var names []string
+	var collect func(ast.SelectionSet)
+	collect = func(selections ast.SelectionSet) {
+		for _, name := range GetUsedFragments(selections) {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			if frag, ok := fragments[name]; ok {
+				names = append(names, name)
+				collect(frag.SelectionSet)
+			}
+		}
+	}
+	collect(op.SelectionSet)
+
+	sort.Strings(names)
+	for _, name := range names {
+		doc.Fragments = append(doc.Fragments, fragments[name])
+	}
+
+	return doc
+}
@@ -0,0 +1,61 @@
+package documents
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+func TestOperationDocument(t *testing.T) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: `
+		query Viewer { viewer { ...UserFields } }
+		fragment UserFields on User { id ...Avatar settings { ...Settings } }
+		fragment Settings on Settings { theme }
+		fragment Avatar on User { avatar }
+		fragment Unused on User { id }
+	`})
+	require.NoError(t, err)
+
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, frag := range doc.Fragments {
+		fragments[frag.Name] = frag
+	}
+
+	opDoc := OperationDocument(doc.Operations[0], fragments)
+
+	require.Len(t, opDoc.Operations, 1)
+	var names []string
+	for _, frag := range opDoc.Fragments {
+		names = append(names, frag.Name)
+	}
+	assert.Equal(t, []string{"Avatar", "Settings", "UserFields"}, names)
+}
+
+func TestNormalizePersistedDocument_RemovesClientDirectives(t *testing.T) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: `query Viewer { viewer { id name @client ... @defer { email } } }`})
+	require.NoError(t, err)
+
+	normalized := NormalizePersistedDocument(doc)
+
+	assert.NotContains(t, normalized, "@client")
+	assert.NotContains(t, normalized, "@defer")
+	assert.Equal(t, NormalizePersistedDocument(doc), normalized)
+	assert.Len(t, HashPersistedDocument(normalized, "sha1"), 40)
+}
+
+func TestHashPersistedDocument_Algorithms(t *testing.T) {
+	content := "query GetUser { user { id } }"
+
+	lengths := map[string]int{"sha1": 40, "sha256": 64, "sha256-trunc": 16, "fnv": 16, "unknown": 40}
+	for algorithm, length := range lengths {
+		hash := HashPersistedDocument(content, algorithm)
+		assert.Len(t, hash, length, algorithm)
+		assert.Equal(t, hash, HashPersistedDocument(content, algorithm), algorithm)
+	}
+
+	assert.Equal(t, HashPersistedDocument(content, "sha256")[:16], HashPersistedDocument(content, "sha256-trunc"))
+	assert.NotEqual(t, HashPersistedDocument(content, "fnv"), HashPersistedDocument(content+" ", "fnv"))
+}
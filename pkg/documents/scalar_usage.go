@@ -0,0 +1,170 @@
+package documents
+
+import (
+	"sort"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// ScalarUsage lists where a custom scalar is used
+type ScalarUsage struct {
+	Scalar string
+	// Schema holds the fields, arguments and input fields of that type, e.g.
+	// "User.createdAt" or "Query.posts(since)"
+	Schema []string
+	// Operations holds the selected fields and variables of that type, e.g.
+	// "GetUser: user.createdAt" or "GetPosts: $since"
+	Operations []string
+}
+
+// CollectScalarUsage reports, for every custom scalar of the schema, the
+// schema fields and operation selections and variables of that type.
+// Fragment spreads are expanded, so a field selected by a fragment counts
+// for every operation spreading it. Scalars nothing uses are reported with
+// empty lists.
+func CollectScalarUsage(s *ast.Schema, docs []*Document) []ScalarUsage {
+	if s == nil {
+		return nil
+	}
+
+	usages := make(map[string]*ScalarUsage)
+	var names []string
+	for name, def := range s.Types {
+		if def.Kind == ast.Scalar && !def.BuiltIn {
+			usages[name] = &ScalarUsage{Scalar: name}
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return nil
+	}
+
+	for _, def := range s.Types {
+		if def.BuiltIn {
+			continue
+		}
+		for _, field := range def.Fields {
+			if usage := usages[field.Type.Name()]; usage != nil {
+				usage.Schema = append(usage.Schema, def.Name+"."+field.Name)
+			}
+			for _, arg := range field.Arguments {
+				if usage := usages[arg.Type.Name()]; usage != nil {
+					usage.Schema = append(usage.Schema, def.Name+"."+field.Name+"("+arg.Name+")")
+				}
+			}
+		}
+	}
+
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, frag := range CollectAllFragments(docs) {
+		fragments[frag.Name] = frag
+	}
+
+	for _, op := range CollectAllOperations(docs) {
+		name := op.Name
+		if name == "" {
+			name = "anonymous " + string(op.Operation)
+		}
+		for _, v := range op.VariableDefinitions {
+			if usage := usages[v.Type.Name()]; usage != nil {
+				usage.Operations = append(usage.Operations, name+": $"+v.Variable)
+			}
+		}
+
+		var root *ast.Definition
+		switch op.Operation {
+		case ast.Query:
+			root = s.Query
+		case ast.Mutation:
+			root = s.Mutation
+		case ast.Subscription:
+			root = s.Subscription
+		}
+		if root == nil {
+			continue
+		}
+
+		w := &scalarUsageWalker{schema: s, fragments: fragments, expanding: make(map[string]bool), add: func(path, scalar string) {
+			if usage := usages[scalar]; usage != nil {
+				usage.Operations = append(usage.Operations, name+": "+path)
+			}
+		}}
+		w.walk(root, op.SelectionSet, "")
+	}
+
+	result := make([]ScalarUsage, 0, len(names))
+	for _, name := range names {
+		usage := usages[name]
+		usage.Schema = sortedUnique(usage.Schema)
+		usage.Operations = sortedUnique(usage.Operations)
+		result = append(result, *usage)
+	}
+	return result
+}
+
+type scalarUsageWalker struct {
+	schema    *ast.Schema
+	fragments map[string]*ast.FragmentDefinition
+	// expanding holds the fragments being expanded, guarding against cycles
+	expanding map[string]bool
+	add       func(path, scalar string)
+}
+
+// walk reports the scalar fields selected on typeDef at path
+func (w *scalarUsageWalker) walk(typeDef *ast.Definition, selectionSet ast.SelectionSet, path string) {
+	for _, selection := range selectionSet {
+		switch sel := selection.(type) {
+		case *ast.Field:
+			fieldDef := typeDef.Fields.ForName(sel.Name)
+			if fieldDef == nil {
+				continue
+			}
+			responseName := sel.Alias
+			if responseName == "" {
+				responseName = sel.Name
+			}
+			fieldPath := joinPath(path, responseName)
+			fieldType := w.schema.Types[fieldDef.Type.Name()]
+			if fieldType == nil {
+				continue
+			}
+			if fieldType.Kind == ast.Scalar {
+				w.add(fieldPath, fieldType.Name)
+				continue
+			}
+			w.walk(fieldType, sel.SelectionSet, fieldPath)
+		case *ast.InlineFragment:
+			inner := typeDef
+			if sel.TypeCondition != "" {
+				if def := w.schema.Types[sel.TypeCondition]; def != nil {
+					inner = def
+				}
+			}
+			w.walk(inner, sel.SelectionSet, path)
+		case *ast.FragmentSpread:
+			frag := w.fragments[sel.Name]
+			if frag == nil || w.expanding[sel.Name] {
+				continue
+			}
+			inner := typeDef
+			if def := w.schema.Types[frag.TypeCondition]; def != nil {
+				inner = def
+			}
+			w.expanding[sel.Name] = true
+			w.walk(inner, frag.SelectionSet, path)
+			delete(w.expanding, sel.Name)
+		}
+	}
+}
+
+func sortedUnique(values []string) []string {
+	sort.Strings(values)
+	unique := values[:0]
+	for _, value := range values {
+		if len(unique) == 0 || value != unique[len(unique)-1] {
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,81 @@
+package documents
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const scalarUsageSchema = `
+scalar DateTime
+scalar JSON
+
+type Post {
+	id: ID!
+	createdAt: DateTime!
+	history: [DateTime!]
+}
+
+type User {
+	id: ID!
+	createdAt: DateTime
+	posts(since: DateTime): [Post!]!
+}
+
+input PostFilter {
+	after: DateTime
+}
+
+type Query {
+	viewer: User
+	posts(filter: PostFilter): [Post!]!
+}
+`
+
+func TestCollectScalarUsage(t *testing.T) {
+	s, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: scalarUsageSchema})
+	require.NoError(t, err)
+
+	doc, gqlErr := gqlparser.LoadQuery(s, `
+		query GetViewer($since: DateTime) {
+			viewer {
+				joined: createdAt
+				posts(since: $since) { ...PostFields }
+			}
+		}
+		query GetPosts {
+			posts { ...PostFields history }
+		}
+		fragment PostFields on Post { id createdAt }
+	`)
+	require.Nil(t, gqlErr)
+
+	usages := CollectScalarUsage(s, []*Document{{FilePath: "queries.graphql", AST: doc}})
+	require.Len(t, usages, 2)
+
+	dateTime := usages[0]
+	assert.Equal(t, "DateTime", dateTime.Scalar)
+	assert.Equal(t, []string{
+		"Post.createdAt",
+		"Post.history",
+		"PostFilter.after",
+		"User.createdAt",
+		"User.posts(since)",
+	}, dateTime.Schema)
+	// Fields selected through the fragment count for each operation
+	assert.Equal(t, []string{
+		"GetPosts: posts.createdAt",
+		"GetPosts: posts.history",
+		"GetViewer: $since",
+		"GetViewer: viewer.joined",
+		"GetViewer: viewer.posts.createdAt",
+	}, dateTime.Operations)
+
+	// Unused scalars are still reported
+	assert.Equal(t, "JSON", usages[1].Scalar)
+	assert.Empty(t, usages[1].Schema)
+	assert.Empty(t, usages[1].Operations)
+}
@@ -0,0 +1,109 @@
+package documents
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// SelectFragments restricts docs to the named fragments and the fragments
+// they spread, which their types need. With withOperations, operations that
+// spread a named fragment, directly or through other fragments, are kept
+// too, along with the fragments they spread; otherwise every operation is
+// dropped. Documents left empty are dropped. The documents returned are
+// copies; docs is not modified.
+func SelectFragments(docs []*Document, names []string, withOperations bool) ([]*Document, error) {
+	graph := BuildDependencyGraph(docs)
+
+	selected := make(map[string]bool, len(names))
+	var unknown []string
+	for _, name := range names {
+		if _, ok := graph.Fragments[name]; !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		selected[name] = true
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown fragments: %s", strings.Join(unknown, ", "))
+	}
+
+	keepFragments := make(map[string]bool)
+	for _, name := range fragmentClosure(graph, names) {
+		keepFragments[name] = true
+	}
+
+	keepOperation := func(op *ast.OperationDefinition) bool {
+		if !withOperations {
+			return false
+		}
+		deps := fragmentClosure(graph, GetUsedFragments(op.SelectionSet))
+		for _, dep := range deps {
+			if selected[dep] {
+				for _, dep := range deps {
+					keepFragments[dep] = true
+				}
+				return true
+			}
+		}
+		return false
+	}
+
+	keptOps := make(map[*ast.OperationDefinition]bool)
+	for _, op := range CollectAllOperations(docs) {
+		if keepOperation(op) {
+			keptOps[op] = true
+		}
+	}
+
+	result := make([]*Document, 0, len(docs))
+	for _, doc := range docs {
+		if doc == nil || doc.AST == nil {
+			continue
+		}
+		filtered := *doc.AST
+		filtered.Operations = nil
+		filtered.Fragments = nil
+		for _, op := range doc.AST.Operations {
+			if keptOps[op] {
+				filtered.Operations = append(filtered.Operations, op)
+			}
+		}
+		for _, frag := range doc.AST.Fragments {
+			if keepFragments[frag.Name] {
+				filtered.Fragments = append(filtered.Fragments, frag)
+			}
+		}
+		if len(filtered.Operations) == 0 && len(filtered.Fragments) == 0 {
+			continue
+		}
+
+		copied := *doc
+		copied.AST = &filtered
+		result = append(result, &copied)
+	}
+	return result, nil
+}
+
+// fragmentClosure returns the fragments names and every fragment they
+// spread, directly or through other fragments
+func fragmentClosure(graph *DependencyGraph, names []string) []string {
+	seen := make(map[string]bool)
+	var closure []string
+	var visit func(names []string)
+	visit = func(names []string) {
+		for _, name := range names {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			closure = append(closure, name)
+			visit(graph.Fragments[name])
+		}
+	}
+	visit(names)
+	return closure
+}
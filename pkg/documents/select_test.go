@@ -0,0 +1,79 @@
+package documents
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+func TestSelectFragments(t *testing.T) {
+	// Parsed without validation, as posts.graphql spreads a fragment of
+	// viewer.graphql
+	load := func(path, query string) *Document {
+		doc, err := parser.ParseQuery(&ast.Source{Name: path, Input: query})
+		require.NoError(t, err)
+		return &Document{FilePath: path, Content: query, AST: doc}
+	}
+	docs := []*Document{
+		load("viewer.graphql", `
+			query GetViewer { viewer { ...UserFields } }
+			fragment UserFields on User { id posts { ...PostFields } }
+			fragment PostFields on Post { ...PostId createdAt }
+			fragment PostId on Post { id }
+		`),
+		load("posts.graphql", `
+			query GetPosts { posts { ...PostId } }
+			fragment PostTitle on Post { id }
+		`),
+	}
+	names := func(docs []*Document) (ops, frags []string) {
+		for _, op := range CollectAllOperations(docs) {
+			ops = append(ops, op.Name)
+		}
+		for _, frag := range CollectAllFragments(docs) {
+			frags = append(frags, frag.Name)
+		}
+		return ops, frags
+	}
+
+	t.Run("fragments and their dependencies only", func(t *testing.T) {
+		selected, err := SelectFragments(docs, []string{"PostFields"}, false)
+		require.NoError(t, err)
+		ops, frags := names(selected)
+		assert.Empty(t, ops)
+		assert.Equal(t, []string{"PostFields", "PostId"}, frags)
+		assert.Len(t, selected, 1, "documents left empty are dropped")
+	})
+
+	t.Run("with dependent operations", func(t *testing.T) {
+		selected, err := SelectFragments(docs, []string{"PostId"}, true)
+		require.NoError(t, err)
+		ops, frags := names(selected)
+		assert.Equal(t, []string{"GetViewer", "GetPosts"}, ops)
+		assert.Equal(t, []string{"UserFields", "PostFields", "PostId"}, frags, "fragments the operations spread are kept")
+	})
+
+	t.Run("operations not spreading the fragments are dropped", func(t *testing.T) {
+		selected, err := SelectFragments(docs, []string{"UserFields", "PostTitle"}, true)
+		require.NoError(t, err)
+		ops, frags := names(selected)
+		assert.Equal(t, []string{"GetViewer"}, ops)
+		assert.Equal(t, []string{"UserFields", "PostFields", "PostId", "PostTitle"}, frags)
+	})
+
+	t.Run("leaves the input unchanged", func(t *testing.T) {
+		_, err := SelectFragments(docs, []string{"PostTitle"}, false)
+		require.NoError(t, err)
+		ops, frags := names(docs)
+		assert.Len(t, ops, 2)
+		assert.Len(t, frags, 4)
+	})
+
+	t.Run("unknown fragments", func(t *testing.T) {
+		_, err := SelectFragments(docs, []string{"Missing", "PostId", "GetViewer"}, false)
+		assert.EqualError(t, err, "unknown fragments: GetViewer, Missing")
+	})
+}
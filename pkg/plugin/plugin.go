@@ -84,6 +84,27 @@ type GenerateResponse struct {
 
 	// Warnings contains any warnings
 	Warnings []string
+
+	// SourceMappings links ranges of the Files, by path, to the GraphQL
+	// definitions they were generated from
+	SourceMappings map[string][]SourceMapping
+}
+
+// SourceMapping links a range of a generated file to the operation or
+// fragment it was generated from
+type SourceMapping struct {
+	// Start and End are the byte offsets of the generated range
+	Start int
+	End   int
+
+	// Name and Kind identify the definition, e.g. "GetUser" and "query"
+	Name string
+	Kind string
+
+	// Source is the file of the definition; Line and Column its position
+	Source string
+	Line   int
+	Column int
 }
 
 // GeneratedFile represents a single generated file along with placement hints
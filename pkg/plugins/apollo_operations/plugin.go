@@ -0,0 +1,280 @@
+package apollo_operations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jzeiders/graphql-go-gen/pkg/documents"
+	"github.com/jzeiders/graphql-go-gen/pkg/plugin"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// manifestVersion is the operation manifest version understood by
+// `apollo client:push`
+const manifestVersion = 2
+
+// Manifest is the operation manifest reported to Apollo Studio
+type Manifest struct {
+	Version    int                 `json:"version"`
+	Operations []ManifestOperation `json:"operations"`
+}
+
+// ManifestOperation describes a single registered operation
+type ManifestOperation struct {
+	// Signature is the SHA-256 hash of Document
+	Signature string `json:"signature"`
+	// Document is the normalized operation, including the fragments it uses
+	Document string                    `json:"document"`
+	Metadata ManifestOperationMetadata `json:"metadata"`
+}
+
+// ManifestOperationMetadata holds additional signatures for an operation
+type ManifestOperationMetadata struct {
+	// EngineSignature is the signature used for usage reporting, which also
+	// drops aliases and hides every literal
+	EngineSignature string `json:"engineSignature"`
+}
+
+// Plugin emits a JSON manifest of normalized, hashed operation signatures
+type Plugin struct{}
+
+// New creates a new Apollo operations plugin
+func New() plugin.Plugin {
+	return &Plugin{}
+}
+
+// Name returns the plugin name
+func (p *Plugin) Name() string {
+	return "apollo-operations"
+}
+
+// Description returns the plugin description
+func (p *Plugin) Description() string {
+	return "Generates an operation signature manifest for Apollo Studio"
+}
+
+// DefaultConfig returns the default configuration
+func (p *Plugin) DefaultConfig() map[string]interface{} {
+	return map[string]interface{}{}
+}
+
+// ValidateConfig validates the plugin configuration
+func (p *Plugin) ValidateConfig(config map[string]interface{}) error {
+	return nil
+}
+
+// Generate writes the operation manifest to the output path
+func (p *Plugin) Generate(ctx context.Context, req *plugin.GenerateRequest) (*plugin.GenerateResponse, error) {
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, frag := range documents.CollectAllFragments(req.Documents) {
+		fragments[frag.Name] = frag
+	}
+
+	resp := &plugin.GenerateResponse{
+		Files: make(map[string][]byte),
+	}
+	manifest := Manifest{
+		Version:    manifestVersion,
+		Operations: []ManifestOperation{},
+	}
+	seen := make(map[string]bool)
+
+	for _, op := range documents.CollectAllOperations(req.Documents) {
+		if op.Name == "" {
+			resp.Warnings = append(resp.Warnings, fmt.Sprintf("skipping anonymous %s operation", op.Operation))
+			continue
+		}
+		if seen[op.Name] {
+			return nil, fmt.Errorf("duplicate operation name %q", op.Name)
+		}
+		seen[op.Name] = true
+
+		document, err := Signature(op, fragments, false)
+		if err != nil {
+			return nil, fmt.Errorf("normalizing operation %s: %w", op.Name, err)
+		}
+		engineSignature, err := Signature(op, fragments, true)
+		if err != nil {
+			return nil, fmt.Errorf("normalizing operation %s: %w", op.Name, err)
+		}
+
+		manifest.Operations = append(manifest.Operations, ManifestOperation{
+			Signature: documents.ComputeDocumentHash([]byte(document)),
+			Document:  document,
+			Metadata:  ManifestOperationMetadata{EngineSignature: engineSignature},
+		})
+	}
+
+	sort.SliceStable(manifest.Operations, func(i, j int) bool {
+		return manifest.Operations[i].Document < manifest.Operations[j].Document
+	})
+
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding operation manifest: %w", err)
+	}
+	resp.Files[req.OutputPath] = append(content, '\n')
+
+	return resp, nil
+}
+
+// Signature returns the normalized form of an operation and the fragments it
+// uses: definitions, selections, arguments and directives are sorted, string
+// and numeric literals are hidden and whitespace is reduced. The engine
+// signature (engine=true) additionally drops aliases and hides list and
+// object literals.
+func Signature(op *ast.OperationDefinition, fragments map[string]*ast.FragmentDefinition, engine bool) (string, error) {
+	doc, err := usedDefinitions(op, fragments)
+	if err != nil {
+		return "", err
+	}
+
+	for _, frag := range doc.Fragments {
+		normalizeDirectives(frag.Directives, engine)
+		normalizeSelectionSet(frag.SelectionSet, engine)
+	}
+	for _, op := range doc.Operations {
+		for _, v := range op.VariableDefinitions {
+			hideLiterals(v.DefaultValue, engine)
+			normalizeDirectives(v.Directives, engine)
+		}
+		sort.SliceStable(op.VariableDefinitions, func(i, j int) bool {
+			return op.VariableDefinitions[i].Variable < op.VariableDefinitions[j].Variable
+		})
+		normalizeDirectives(op.Directives, engine)
+		normalizeSelectionSet(op.SelectionSet, engine)
+	}
+	sort.SliceStable(doc.Fragments, func(i, j int) bool {
+		return doc.Fragments[i].Name < doc.Fragments[j].Name
+	})
+
+	var buf bytes.Buffer
+	f := formatter.NewFormatter(&buf)
+	// Fragments sort before operations, as definitions are ordered by kind
+	for _, frag := range doc.Fragments {
+		f.FormatQueryDocument(&ast.QueryDocument{Fragments: ast.FragmentDefinitionList{frag}})
+	}
+	f.FormatQueryDocument(&ast.QueryDocument{Operations: doc.Operations})
+
+	return reduceWhitespace(buf.String()), nil
+}
+
+// usedDefinitions returns a copy of the operation together with every
+// fragment it uses, so normalizing it leaves the loaded documents untouched
+func usedDefinitions(op *ast.OperationDefinition, fragments map[string]*ast.FragmentDefinition) (*ast.QueryDocument, error) {
+	doc := &ast.QueryDocument{Operations: ast.OperationList{op}}
+
+	seen := make(map[string]bool)
+	var collect func(selections ast.SelectionSet)
+	collect = func(selections ast.SelectionSet) {
+		for _, name := range documents.GetUsedFragments(selections) {
+			frag, ok := fragments[name]
+			if !ok || seen[name] {
+				continue
+			}
+			seen[name] = true
+			doc.Fragments = append(doc.Fragments, frag)
+			collect(frag.SelectionSet)
+		}
+	}
+	collect(op.SelectionSet)
+
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf).FormatQueryDocument(doc)
+	return parser.ParseQuery(&ast.Source{Input: buf.String()})
+}
+
+func normalizeSelectionSet(selections ast.SelectionSet, engine bool) {
+	for _, sel := range selections {
+		switch s := sel.(type) {
+		case *ast.Field:
+			if engine {
+				s.Alias = s.Name
+			}
+			for _, arg := range s.Arguments {
+				hideLiterals(arg.Value, engine)
+			}
+			sort.SliceStable(s.Arguments, func(i, j int) bool {
+				return s.Arguments[i].Name < s.Arguments[j].Name
+			})
+			normalizeDirectives(s.Directives, engine)
+			normalizeSelectionSet(s.SelectionSet, engine)
+		case *ast.FragmentSpread:
+			normalizeDirectives(s.Directives, engine)
+		case *ast.InlineFragment:
+			normalizeDirectives(s.Directives, engine)
+			normalizeSelectionSet(s.SelectionSet, engine)
+		}
+	}
+
+	sort.SliceStable(selections, func(i, j int) bool {
+		// Inline fragments have no name, so they keep their relative order
+		ki, ni := documents.SelectionSortKey(selections[i])
+		kj, nj := documents.SelectionSortKey(selections[j])
+		if ki != kj {
+			return ki < kj
+		}
+		return ni < nj
+	})
+}
+
+func normalizeDirectives(directives ast.DirectiveList, engine bool) {
+	for _, dir := range directives {
+		for _, arg := range dir.Arguments {
+			hideLiterals(arg.Value, engine)
+		}
+		sort.SliceStable(dir.Arguments, func(i, j int) bool {
+			return dir.Arguments[i].Name < dir.Arguments[j].Name
+		})
+	}
+	sort.SliceStable(directives, func(i, j int) bool {
+		return directives[i].Name < directives[j].Name
+	})
+}
+
+// hideLiterals replaces numbers with 0 and strings with "". With all set,
+// lists and objects are emptied as well.
+func hideLiterals(value *ast.Value, all bool) {
+	if value == nil {
+		return
+	}
+
+	switch value.Kind {
+	case ast.IntValue, ast.FloatValue:
+		value.Kind = ast.IntValue
+		value.Raw = "0"
+	case ast.StringValue, ast.BlockValue:
+		value.Kind = ast.StringValue
+		value.Raw = ""
+	case ast.ListValue, ast.ObjectValue:
+		if all {
+			value.Children = nil
+			return
+		}
+		for _, child := range value.Children {
+			hideLiterals(child.Value, all)
+		}
+	}
+}
+
+var (
+	whitespacePattern  = regexp.MustCompile(`\s+`)
+	spaceBeforePattern = regexp.MustCompile(` ([^_a-zA-Z0-9])`)
+	spaceAfterPattern  = regexp.MustCompile(`([^_a-zA-Z0-9]) `)
+)
+
+// reduceWhitespace keeps only the spaces that separate two names. It is only
+// safe once string literals have been hidden.
+func reduceWhitespace(document string) string {
+	document = whitespacePattern.ReplaceAllString(document, " ")
+	document = spaceAfterPattern.ReplaceAllString(document, "$1")
+	document = spaceBeforePattern.ReplaceAllString(document, "$1")
+	return strings.TrimSpace(document)
+}
@@ -0,0 +1,115 @@
+package apollo_operations
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/jzeiders/graphql-go-gen/pkg/documents"
+	"github.com/jzeiders/graphql-go-gen/pkg/plugins/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+)
+
+func TestPlugin_Name(t *testing.T) {
+	p := New()
+	assert.Equal(t, "apollo-operations", p.Name())
+}
+
+func TestPlugin_GenerateManifest(t *testing.T) {
+	req := testutil.CreateTestRequest(t, nil)
+	query := `
+		query SearchUsers($first: Int = 10, $filter: String) {
+			users(first: $first, filter: $filter) {
+				edges { cursor }
+			}
+			admins: users(filter: "admin") {
+				...UserConnectionFields
+			}
+		}
+
+		fragment UserConnectionFields on UserConnection {
+			totalCount
+			pageInfo { hasNextPage }
+		}
+	`
+	queryDoc, gqlErr := gqlparser.LoadQuery(req.Schema.Raw(), query)
+	require.Nil(t, gqlErr)
+	req.Documents = []*documents.Document{{FilePath: "search.graphql", Content: query, AST: queryDoc}}
+
+	resp, err := New().Generate(context.Background(), req)
+	require.NoError(t, err)
+
+	var manifest map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Files[req.OutputPath], &manifest))
+	assert.Equal(t, float64(2), manifest["version"])
+
+	operations, ok := manifest["operations"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, operations, 1)
+
+	op := operations[0].(map[string]interface{})
+	assert.ElementsMatch(t, []string{"signature", "document", "metadata"}, mapKeys(op))
+
+	document := op["document"].(string)
+	assert.Equal(t,
+		`fragment UserConnectionFields on UserConnection{pageInfo{hasNextPage}totalCount}`+
+			`query SearchUsers($filter:String,$first:Int=0){users(filter:$filter,first:$first){edges{cursor}}admins:users(filter:""){...UserConnectionFields}}`,
+		document)
+	assert.Equal(t, documents.ComputeDocumentHash([]byte(document)), op["signature"])
+
+	metadata := op["metadata"].(map[string]interface{})
+	assert.Equal(t,
+		`fragment UserConnectionFields on UserConnection{pageInfo{hasNextPage}totalCount}`+
+			`query SearchUsers($filter:String,$first:Int=0){users(filter:$filter,first:$first){edges{cursor}}users(filter:""){...UserConnectionFields}}`,
+		metadata["engineSignature"])
+}
+
+func TestPlugin_GenerateIsStableAcrossFormatting(t *testing.T) {
+	req := testutil.CreateTestRequest(t, nil)
+
+	signature := func(query string) string {
+		queryDoc, gqlErr := gqlparser.LoadQuery(req.Schema.Raw(), query)
+		require.Nil(t, gqlErr)
+		req.Documents = []*documents.Document{{FilePath: "user.graphql", Content: query, AST: queryDoc}}
+
+		resp, err := New().Generate(context.Background(), req)
+		require.NoError(t, err)
+
+		var manifest Manifest
+		require.NoError(t, json.Unmarshal(resp.Files[req.OutputPath], &manifest))
+		require.Len(t, manifest.Operations, 1)
+		return manifest.Operations[0].Signature
+	}
+
+	assert.Equal(t,
+		signature(`query GetUser { user(id: "1") { id name } }`),
+		signature("query GetUser {\n  user(id: \"2\") {\n    name\n    id\n  }\n}"),
+	)
+	assert.NotEqual(t,
+		signature(`query GetUser { user(id: "1") { id name } }`),
+		signature(`query GetUser { user(id: "1") { id email } }`),
+	)
+}
+
+func TestPlugin_GenerateSkipsAnonymousOperations(t *testing.T) {
+	req := testutil.CreateTestRequest(t, nil)
+	query := `{ currentUser { id } }`
+	queryDoc, gqlErr := gqlparser.LoadQuery(req.Schema.Raw(), query)
+	require.Nil(t, gqlErr)
+	req.Documents = []*documents.Document{{FilePath: "anonymous.graphql", Content: query, AST: queryDoc}}
+
+	resp, err := New().Generate(context.Background(), req)
+	require.NoError(t, err)
+	assert.Len(t, resp.Warnings, 1)
+	assert.JSONEq(t, `{"version": 2, "operations": []}`, string(resp.Files[req.OutputPath]))
+}
+
+func mapKeys(m map[string]interface{}) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+	return result
+}
@@ -0,0 +1,35 @@
+package base
+
+// AvoidOptionals selects where optional `?` markers are replaced by required
+// members, following graphql-codegen's avoidOptionals
+type AvoidOptionals struct {
+	// Field covers the fields of operation results
+	Field bool
+	// InputValue covers operation variables and input object fields
+	InputValue bool
+	// Object covers the fields of object types
+	Object bool
+	// DefaultValue also covers input values that have a default value,
+	// which otherwise stay optional under InputValue
+	DefaultValue bool
+}
+
+// GetAvoidOptionals reads an avoidOptionals config: true or false for every
+// kind of member, or a `{ field, inputValue, object, defaultValue }` map
+// where missing keys are false
+func GetAvoidOptionals(m map[string]interface{}, key string) AvoidOptionals {
+	switch v := m[key].(type) {
+	case bool:
+		return AvoidOptionals{Field: v, InputValue: v, Object: v, DefaultValue: v}
+	case AvoidOptionals:
+		return v
+	case map[string]interface{}:
+		return AvoidOptionals{
+			Field:        GetBool(v, "field", false),
+			InputValue:   GetBool(v, "inputValue", false),
+			Object:       GetBool(v, "object", false),
+			DefaultValue: GetBool(v, "defaultValue", false),
+		}
+	}
+	return AvoidOptionals{}
+}
@@ -0,0 +1,42 @@
+package base
+
+import "fmt"
+
+// GetBrandedIDTypes reads a list of type names whose `id` field gets a
+// branded ID type
+func GetBrandedIDTypes(m map[string]interface{}, key string) map[string]bool {
+	result := make(map[string]bool)
+	switch configured := m[key].(type) {
+	case []string:
+		for _, name := range configured {
+			result[name] = true
+		}
+	case []interface{}:
+		for _, value := range configured {
+			if name, ok := value.(string); ok {
+				result[name] = true
+			}
+		}
+	}
+	return result
+}
+
+// BrandedIDName returns the name of the branded ID type of a type, e.g.
+// UserId for User
+func BrandedIDName(typeName string) string {
+	return typeName + "Id"
+}
+
+// BrandedIDType returns the branded ID type of a type on top of the ID type
+// idType, e.g. string & { readonly __brand: 'UserId' }. Brands are
+// structural, so the same brand written by different plugins is the same
+// type, while IDs of different types are not assignable to each other.
+func BrandedIDType(typeName string, idType string) string {
+	return fmt.Sprintf("%s & { readonly __brand: '%s' }", idType, BrandedIDName(typeName))
+}
+
+// IsBrandedIDField reports whether field is the `id: ID` field of a type
+// whose IDs are branded
+func IsBrandedIDField(branded map[string]bool, typeName string, fieldName string, fieldTypeName string) bool {
+	return branded[typeName] && fieldName == "id" && fieldTypeName == "ID"
+}
@@ -0,0 +1,63 @@
+package base
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// DefaultOperationFilenameTemplate is used when no filename template is configured
+const DefaultOperationFilenameTemplate = "{operationName}.graphql"
+
+var placeholderPattern = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// filenamePlaceholders lists the supported placeholders and how they are resolved
+var filenamePlaceholders = map[string]func(op *ast.OperationDefinition) string{
+	"operationType": func(op *ast.OperationDefinition) string { return string(op.Operation) },
+	"operationName": func(op *ast.OperationDefinition) string { return op.Name },
+}
+
+// FilenameTemplate builds per-operation output paths such as
+// "{operationType}/{operationName}.graphql"
+type FilenameTemplate struct {
+	template string
+}
+
+// ParseFilenameTemplate parses and validates a per-operation filename template
+func ParseFilenameTemplate(template string) (*FilenameTemplate, error) {
+	if strings.TrimSpace(template) == "" {
+		return nil, fmt.Errorf("filename template cannot be empty")
+	}
+	if filepath.IsAbs(template) {
+		return nil, fmt.Errorf("filename template %q must be relative", template)
+	}
+
+	for _, match := range placeholderPattern.FindAllStringSubmatch(template, -1) {
+		if _, ok := filenamePlaceholders[match[1]]; !ok {
+			return nil, fmt.Errorf("filename template %q: unknown placeholder {%s}", template, match[1])
+		}
+	}
+
+	// Any brace left after removing placeholders is unbalanced
+	if strings.ContainsAny(placeholderPattern.ReplaceAllString(template, ""), "{}") {
+		return nil, fmt.Errorf("filename template %q has unbalanced braces", template)
+	}
+
+	return &FilenameTemplate{template: template}, nil
+}
+
+// Execute returns the output path for an operation
+func (t *FilenameTemplate) Execute(op *ast.OperationDefinition) string {
+	path := placeholderPattern.ReplaceAllStringFunc(t.template, func(placeholder string) string {
+		return filenamePlaceholders[placeholder[1:len(placeholder)-1]](op)
+	})
+	return filepath.FromSlash(path)
+}
+
+// String returns the raw template
+func (t *FilenameTemplate) String() string {
+	return t.template
+}
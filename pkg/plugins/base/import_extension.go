@@ -0,0 +1,31 @@
+package base
+
+import "fmt"
+
+// GetImportExtension reads the extension appended to relative imports
+// between generated files: importExtension when it is set, one of ".js",
+// ".ts" or "" for bundler module resolution, and otherwise ".js" unless
+// emitLegacyCommonJSImports asks for extensionless CommonJS imports
+func GetImportExtension(m map[string]interface{}) string {
+	if ext, ok := m["importExtension"].(string); ok {
+		return ext
+	}
+	if GetBool(m, "emitLegacyCommonJSImports", false) {
+		return ""
+	}
+	return ".js"
+}
+
+// ValidateImportExtension rejects an importExtension other than ".js",
+// ".ts" or ""
+func ValidateImportExtension(m map[string]interface{}) error {
+	value, ok := m["importExtension"]
+	if !ok || value == nil {
+		return nil
+	}
+	switch value {
+	case ".js", ".ts", "":
+		return nil
+	}
+	return fmt.Errorf(`invalid importExtension: %v (must be ".js", ".ts" or "")`, value)
+}
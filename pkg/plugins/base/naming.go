@@ -0,0 +1,99 @@
+package base
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// NamingConvention controls how GraphQL operation and fragment names become
+// TypeScript type names. The zero value is the default pascal-case convention.
+type NamingConvention struct {
+	// Keep leaves names as written instead of upper-casing the first letter
+	Keep bool
+	// TransformUnderscore removes underscores and upper-cases the letter that
+	// follows each one, so get_user_v2 becomes GetUserV2
+	TransformUnderscore bool
+}
+
+// GetNamingConvention reads a naming convention from a config map. The value
+// is either a convention name ("pascal-case" or "keep") or an object such as
+// { typeNames: "keep", transformUnderscore: true }.
+func GetNamingConvention(m map[string]interface{}, key string) (NamingConvention, error) {
+	var convention NamingConvention
+
+	switch value := m[key].(type) {
+	case nil:
+		return convention, nil
+	case string:
+		keep, err := parseTypeNamesConvention(value)
+		if err != nil {
+			return convention, err
+		}
+		convention.Keep = keep
+	case map[string]interface{}:
+		if typeNames, ok := value["typeNames"].(string); ok {
+			keep, err := parseTypeNamesConvention(typeNames)
+			if err != nil {
+				return convention, err
+			}
+			convention.Keep = keep
+		}
+		convention.TransformUnderscore = GetBool(value, "transformUnderscore", false)
+	default:
+		return convention, fmt.Errorf("%s must be a string or an object, got %T", key, value)
+	}
+
+	return convention, nil
+}
+
+// parseTypeNamesConvention reports whether the named convention keeps names
+// unchanged. graphql-codegen's change-case-all names are accepted as well.
+func parseTypeNamesConvention(name string) (bool, error) {
+	switch name {
+	case "pascal-case", "pascalCase", "change-case-all#pascalCase":
+		return false, nil
+	case "keep":
+		return true, nil
+	default:
+		return false, fmt.Errorf("unsupported naming convention %q (expected \"pascal-case\" or \"keep\")", name)
+	}
+}
+
+// Convert applies the naming convention to a GraphQL name
+func (n NamingConvention) Convert(name string) string {
+	if n.TransformUnderscore {
+		parts := strings.Split(name, "_")
+		var sb strings.Builder
+		for _, part := range parts {
+			if part == "" {
+				continue
+			}
+			if sb.Len() > 0 {
+				part = ToPascalCase(part)
+			}
+			sb.WriteString(part)
+		}
+		if sb.Len() > 0 {
+			name = sb.String()
+		}
+	}
+
+	if n.Keep {
+		return name
+	}
+	return ToPascalCase(name)
+}
+
+// OperationTypeNames returns the result and variables type names
+// typescript-operations generates for op
+func OperationTypeNames(op *ast.OperationDefinition, naming NamingConvention, omitSuffix bool) (string, string) {
+	name := naming.Convert(op.Name)
+	if omitSuffix {
+		return name, name + "Variables"
+	}
+
+	suffix := ToPascalCase(string(op.Operation))
+	return name + suffix, name + suffix + "Variables"
+}
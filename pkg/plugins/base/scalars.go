@@ -0,0 +1,57 @@
+package base
+
+// ScalarMapping is the TypeScript type of a scalar as an argument (Input) and
+// as a result (Output)
+type ScalarMapping struct {
+	Input  string
+	Output string
+}
+
+// GetScalars reads a scalar mapping config. Each scalar maps either to a
+// single type used in both directions or to an `{ input, output }` object; a
+// side missing from the object falls back to the other one.
+func GetScalars(m map[string]interface{}, key string) map[string]ScalarMapping {
+	result := make(map[string]ScalarMapping)
+	switch configured := m[key].(type) {
+	case map[string]ScalarMapping:
+		for name, mapping := range configured {
+			result[name] = mapping
+		}
+	case map[string]string:
+		for name, tsType := range configured {
+			result[name] = ScalarMapping{Input: tsType, Output: tsType}
+		}
+	case map[string]interface{}:
+		for name, value := range configured {
+			if mapping, ok := ParseScalarMapping(value); ok {
+				result[name] = mapping
+			}
+		}
+	}
+	return result
+}
+
+// ParseScalarMapping reads a single scalar mapping: a type name or an
+// `{ input, output }` object
+func ParseScalarMapping(value interface{}) (ScalarMapping, bool) {
+	switch v := value.(type) {
+	case string:
+		return ScalarMapping{Input: v, Output: v}, true
+	case ScalarMapping:
+		return v, true
+	case map[string]interface{}:
+		input := GetString(v, "input", "")
+		output := GetString(v, "output", "")
+		if input == "" {
+			input = output
+		}
+		if output == "" {
+			output = input
+		}
+		if input == "" {
+			return ScalarMapping{}, false
+		}
+		return ScalarMapping{Input: input, Output: output}, true
+	}
+	return ScalarMapping{}, false
+}
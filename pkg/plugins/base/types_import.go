@@ -0,0 +1,22 @@
+package base
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RenderTypesImport imports the referenced types of the typescript plugin
+// from importTypesFrom, sorted by name. It returns "" when importTypesFrom
+// is unset or nothing is referenced.
+func RenderTypesImport(referenced map[string]bool, importTypesFrom string) string {
+	if importTypesFrom == "" || len(referenced) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(referenced))
+	for name := range referenced {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Sprintf("import type { %s } from '%s';", strings.Join(names, ", "), importTypesFrom)
+}
@@ -3,12 +3,16 @@ package fragment_masking
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/jzeiders/graphql-go-gen/pkg/plugin"
 	"github.com/jzeiders/graphql-go-gen/pkg/plugins/base"
 )
 
+// identifierRegexp matches the names usable for the generated helpers
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
 // Plugin generates fragment masking helper functions
 type Plugin struct{}
 
@@ -35,12 +39,26 @@ func (p *Plugin) DefaultConfig() map[string]interface{} {
 		"augmentedModuleName":       nil,
 		"emitLegacyCommonJSImports": false,
 		"isStringDocumentMode":      false,
+		"inline":                    false,
+		// fragmentArrayName, e.g. "FragmentArray", declares a named helper
+		// for arrays of fragment refs, used by the array overloads in place
+		// of Array<FragmentType<...>>
+		"fragmentArrayName": nil,
+		// importExtension, ".js", ".ts" or "", overrides the extension of
+		// the graphql.ts import implied by emitLegacyCommonJSImports
+		"importExtension": nil,
 	}
 }
 
 // ValidateConfig validates the plugin configuration
 func (p *Plugin) ValidateConfig(config map[string]interface{}) error {
 	// All config options are optional
+	if err := base.ValidateImportExtension(config); err != nil {
+		return err
+	}
+	if name := base.GetString(config, "fragmentArrayName", ""); name != "" && !identifierRegexp.MatchString(name) {
+		return fmt.Errorf("fragmentArrayName %q is not a valid TypeScript identifier", name)
+	}
 	return nil
 }
 
@@ -50,15 +68,20 @@ func (p *Plugin) Generate(ctx context.Context, req *plugin.GenerateRequest) (*pl
 	unmaskFunctionName := base.GetString(req.Config, "unmaskFunctionName", "useFragment")
 	useTypeImports := base.GetBool(req.Config, "useTypeImports", false)
 	augmentedModuleName := base.GetStringPtr(req.Config, "augmentedModuleName")
-	emitLegacyCommonJSImports := base.GetBool(req.Config, "emitLegacyCommonJSImports", false)
+	importExtension := base.GetImportExtension(req.Config)
 	isStringDocumentMode := base.GetBool(req.Config, "isStringDocumentMode", false)
+	inline := base.GetBool(req.Config, "inline", false)
+	fragmentArrayName := base.GetString(req.Config, "fragmentArrayName", "")
+	if err := p.ValidateConfig(req.Config); err != nil {
+		return nil, err
+	}
 
 	var sb strings.Builder
 
 	if augmentedModuleName != nil {
-		p.generateAugmentedMode(&sb, unmaskFunctionName, useTypeImports, *augmentedModuleName)
+		p.generateAugmentedMode(&sb, unmaskFunctionName, fragmentArrayName, useTypeImports, *augmentedModuleName)
 	} else {
-		p.generateStandardMode(&sb, unmaskFunctionName, useTypeImports, emitLegacyCommonJSImports, isStringDocumentMode)
+		p.generateStandardMode(&sb, unmaskFunctionName, fragmentArrayName, useTypeImports, importExtension, isStringDocumentMode, inline)
 	}
 
 	return &plugin.GenerateResponse{
@@ -68,8 +91,10 @@ func (p *Plugin) Generate(ctx context.Context, req *plugin.GenerateRequest) (*pl
 	}, nil
 }
 
-// generateStandardMode generates the standard fragment masking utilities
-func (p *Plugin) generateStandardMode(sb *strings.Builder, unmaskFunctionName string, useTypeImports bool, emitLegacyCommonJSImports bool, isStringDocumentMode bool) {
+// generateStandardMode generates the standard fragment masking utilities. When
+// inline, the helpers are appended to the operations file, which already
+// declares Incremental and imports TypedDocumentNode.
+func (p *Plugin) generateStandardMode(sb *strings.Builder, unmaskFunctionName string, fragmentArrayName string, useTypeImports bool, importExtension string, isStringDocumentMode bool, inline bool) {
 	// Imports
 	importType := "import"
 	if useTypeImports {
@@ -77,7 +102,7 @@ func (p *Plugin) generateStandardMode(sb *strings.Builder, unmaskFunctionName st
 	}
 
 	documentNodeImports := "ResultOf, DocumentTypeDecoration"
-	if !isStringDocumentMode {
+	if !isStringDocumentMode && !inline {
 		documentNodeImports += ", TypedDocumentNode"
 	}
 	sb.WriteString(fmt.Sprintf("%s { %s } from '@graphql-typed-document-node/core';\n", importType, documentNodeImports))
@@ -86,23 +111,23 @@ func (p *Plugin) generateStandardMode(sb *strings.Builder, unmaskFunctionName st
 		sb.WriteString(fmt.Sprintf("%s { FragmentDefinitionNode } from 'graphql';\n", importType))
 	}
 
-	jsExt := ""
-	if !emitLegacyCommonJSImports {
-		jsExt = ".js"
-	}
-
-	incrementalImports := "Incremental"
-	if isStringDocumentMode {
-		incrementalImports += ", TypedDocumentString"
+	if inline {
+		sb.WriteString("\n")
+	} else {
+		incrementalImports := "Incremental"
+		if isStringDocumentMode {
+			incrementalImports += ", TypedDocumentString"
+		}
+		sb.WriteString(fmt.Sprintf("%s { %s } from './graphql%s';\n\n", importType, incrementalImports, importExtension))
 	}
-	sb.WriteString(fmt.Sprintf("%s { %s } from './graphql%s';\n\n", importType, incrementalImports, jsExt))
 
 	// FragmentType helper
 	p.writeFragmentTypeHelper(sb)
 	sb.WriteString("\n")
+	p.writeFragmentArrayHelper(sb, fragmentArrayName)
 
 	// Unmask function with all overloads
-	p.writeUnmaskFunction(sb, unmaskFunctionName)
+	p.writeUnmaskFunction(sb, unmaskFunctionName, fragmentArrayName)
 	sb.WriteString("\n")
 
 	// makeFragmentData helper
@@ -114,7 +139,7 @@ func (p *Plugin) generateStandardMode(sb *strings.Builder, unmaskFunctionName st
 }
 
 // generateAugmentedMode generates module augmentation mode
-func (p *Plugin) generateAugmentedMode(sb *strings.Builder, unmaskFunctionName string, useTypeImports bool, augmentedModuleName string) {
+func (p *Plugin) generateAugmentedMode(sb *strings.Builder, unmaskFunctionName string, fragmentArrayName string, useTypeImports bool, augmentedModuleName string) {
 	importType := "import"
 	if useTypeImports {
 		importType = "import type"
@@ -129,9 +154,10 @@ func (p *Plugin) generateAugmentedMode(sb *strings.Builder, unmaskFunctionName s
 	// FragmentType helper (indented)
 	p.writeFragmentTypeHelper(&content)
 	content.WriteString("\n")
+	p.writeFragmentArrayHelper(&content, fragmentArrayName)
 
 	// Unmask function type definitions only (indented)
-	p.writeUnmaskFunctionTypeDefinitions(&content, unmaskFunctionName)
+	p.writeUnmaskFunctionTypeDefinitions(&content, unmaskFunctionName, fragmentArrayName)
 	content.WriteString("\n")
 
 	// makeFragmentData helper (indented)
@@ -164,8 +190,28 @@ func (p *Plugin) writeFragmentTypeHelper(sb *strings.Builder) {
 	sb.WriteString("  : never;")
 }
 
+// writeFragmentArrayHelper writes the named helper for arrays of fragment
+// refs, if one is configured
+func (p *Plugin) writeFragmentArrayHelper(sb *strings.Builder, fragmentArrayName string) {
+	if fragmentArrayName == "" {
+		return
+	}
+	sb.WriteString(fmt.Sprintf("export type %s<TDocumentType extends DocumentTypeDecoration<any, any>> = Array<FragmentType<TDocumentType>>;\n", fragmentArrayName))
+}
+
+// fragmentArrayType returns the type of an array of fragment refs in the
+// unmask function signatures
+func fragmentArrayType(fragmentArrayName string) string {
+	if fragmentArrayName == "" {
+		return "Array<FragmentType<DocumentTypeDecoration<TType, any>>>"
+	}
+	return fragmentArrayName + "<DocumentTypeDecoration<TType, any>>"
+}
+
 // writeUnmaskFunctionTypeDefinitions writes just the type definitions for the unmask function
-func (p *Plugin) writeUnmaskFunctionTypeDefinitions(sb *strings.Builder, unmaskFunctionName string) {
+func (p *Plugin) writeUnmaskFunctionTypeDefinitions(sb *strings.Builder, unmaskFunctionName string, fragmentArrayName string) {
+	arrayType := fragmentArrayType(fragmentArrayName)
+
 	// Non-nullable overload
 	sb.WriteString("// return non-nullable if `fragmentType` is non-nullable\n")
 	sb.WriteString(fmt.Sprintf("export function %s<TType>(\n", unmaskFunctionName))
@@ -198,14 +244,14 @@ func (p *Plugin) writeUnmaskFunctionTypeDefinitions(sb *strings.Builder, unmaskF
 	sb.WriteString("// return array of non-nullable if `fragmentType` is array of non-nullable\n")
 	sb.WriteString(fmt.Sprintf("export function %s<TType>(\n", unmaskFunctionName))
 	sb.WriteString("  _documentNode: DocumentTypeDecoration<TType, any>,\n")
-	sb.WriteString("  fragmentType: Array<FragmentType<DocumentTypeDecoration<TType, any>>>\n")
+	sb.WriteString(fmt.Sprintf("  fragmentType: %s\n", arrayType))
 	sb.WriteString("): Array<TType>;\n")
 
 	// Nullable array overload
 	sb.WriteString("// return array of nullable if `fragmentType` is array of nullable\n")
 	sb.WriteString(fmt.Sprintf("export function %s<TType>(\n", unmaskFunctionName))
 	sb.WriteString("  _documentNode: DocumentTypeDecoration<TType, any>,\n")
-	sb.WriteString("  fragmentType: Array<FragmentType<DocumentTypeDecoration<TType, any>>> | null | undefined\n")
+	sb.WriteString(fmt.Sprintf("  fragmentType: %s | null | undefined\n", arrayType))
 	sb.WriteString("): Array<TType> | null | undefined;\n")
 
 	// ReadonlyArray overload
@@ -224,16 +270,16 @@ func (p *Plugin) writeUnmaskFunctionTypeDefinitions(sb *strings.Builder, unmaskF
 }
 
 // writeUnmaskFunction writes the complete unmask function with implementation
-func (p *Plugin) writeUnmaskFunction(sb *strings.Builder, unmaskFunctionName string) {
+func (p *Plugin) writeUnmaskFunction(sb *strings.Builder, unmaskFunctionName string, fragmentArrayName string) {
 	// Write type definitions first
-	p.writeUnmaskFunctionTypeDefinitions(sb, unmaskFunctionName)
+	p.writeUnmaskFunctionTypeDefinitions(sb, unmaskFunctionName, fragmentArrayName)
 
 	sb.WriteString("\n")
 
 	// Implementation
 	sb.WriteString(fmt.Sprintf("export function %s<TType>(\n", unmaskFunctionName))
 	sb.WriteString("  _documentNode: DocumentTypeDecoration<TType, any>,\n")
-	sb.WriteString("  fragmentType: FragmentType<DocumentTypeDecoration<TType, any>> | Array<FragmentType<DocumentTypeDecoration<TType, any>>> | ReadonlyArray<FragmentType<DocumentTypeDecoration<TType, any>>> | null | undefined\n")
+	sb.WriteString("  fragmentType: FragmentType<DocumentTypeDecoration<TType, any>> | " + fragmentArrayType(fragmentArrayName) + " | ReadonlyArray<FragmentType<DocumentTypeDecoration<TType, any>>> | null | undefined\n")
 	sb.WriteString("): TType | Array<TType> | ReadonlyArray<TType> | null | undefined {\n")
 	sb.WriteString("  return fragmentType as any;\n")
 	sb.WriteString("}")
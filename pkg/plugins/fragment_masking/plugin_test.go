@@ -0,0 +1,89 @@
+package fragment_masking
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jzeiders/graphql-go-gen/pkg/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlugin_FragmentArrayName(t *testing.T) {
+	generate := func(t *testing.T, cfg map[string]interface{}) string {
+		t.Helper()
+		resp, err := New().Generate(context.Background(), &plugin.GenerateRequest{
+			Config:     cfg,
+			OutputPath: "fragment-masking.ts",
+		})
+		require.NoError(t, err)
+		return string(resp.Files["fragment-masking.ts"])
+	}
+
+	t.Run("inline arrays by default", func(t *testing.T) {
+		output := generate(t, map[string]interface{}{})
+		assert.Contains(t, output, "fragmentType: Array<FragmentType<DocumentTypeDecoration<TType, any>>>\n")
+		assert.NotContains(t, output, "FragmentArray")
+	})
+
+	t.Run("named wrapper", func(t *testing.T) {
+		output := generate(t, map[string]interface{}{"fragmentArrayName": "FragmentArray"})
+
+		helper := "export type FragmentArray<TDocumentType extends DocumentTypeDecoration<any, any>> = Array<FragmentType<TDocumentType>>;\n"
+		assert.Equal(t, 1, strings.Count(output, helper), "helper should be declared once:\n%s", output)
+		assert.Less(t, strings.Index(output, "export type FragmentType<"), strings.Index(output, helper))
+
+		assert.Contains(t, output, "  fragmentType: FragmentArray<DocumentTypeDecoration<TType, any>>\n): Array<TType>;")
+		assert.Contains(t, output, "  fragmentType: FragmentArray<DocumentTypeDecoration<TType, any>> | null | undefined\n): Array<TType> | null | undefined;")
+		assert.Contains(t, output, "FragmentType<DocumentTypeDecoration<TType, any>> | FragmentArray<DocumentTypeDecoration<TType, any>> | ReadonlyArray")
+		assert.NotContains(t, output, "fragmentType: Array<")
+	})
+
+	t.Run("named wrapper in augmented module", func(t *testing.T) {
+		output := generate(t, map[string]interface{}{
+			"fragmentArrayName":   "FragmentArray",
+			"augmentedModuleName": "@graphql-typed-document-node/core",
+		})
+		assert.Equal(t, 1, strings.Count(output, "  export type FragmentArray<"))
+		assert.Contains(t, output, "fragmentType: FragmentArray<DocumentTypeDecoration<TType, any>>\n")
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		_, err := New().Generate(context.Background(), &plugin.GenerateRequest{
+			Config:     map[string]interface{}{"fragmentArrayName": "Fragment Array"},
+			OutputPath: "fragment-masking.ts",
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fragmentArrayName")
+	})
+}
+
+func TestPlugin_ImportExtension(t *testing.T) {
+	generate := func(cfg map[string]interface{}) (string, error) {
+		resp, err := New().Generate(context.Background(), &plugin.GenerateRequest{
+			Config:     cfg,
+			OutputPath: "fragment-masking.ts",
+		})
+		if err != nil {
+			return "", err
+		}
+		return string(resp.Files["fragment-masking.ts"]), nil
+	}
+
+	output, err := generate(map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Contains(t, output, "import { Incremental } from './graphql.js';")
+
+	output, err = generate(map[string]interface{}{"importExtension": ""})
+	require.NoError(t, err)
+	assert.Contains(t, output, "import { Incremental } from './graphql';")
+
+	output, err = generate(map[string]interface{}{"importExtension": ".ts", "isStringDocumentMode": true})
+	require.NoError(t, err)
+	assert.Contains(t, output, "import { Incremental, TypedDocumentString } from './graphql.ts';")
+
+	_, err = generate(map[string]interface{}{"importExtension": ".mjs"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "importExtension")
+}
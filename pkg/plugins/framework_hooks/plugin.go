@@ -0,0 +1,257 @@
+package framework_hooks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jzeiders/graphql-go-gen/pkg/documents"
+	"github.com/jzeiders/graphql-go-gen/pkg/plugin"
+	"github.com/jzeiders/graphql-go-gen/pkg/plugins/base"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Frameworks lists the supported framework values
+var Frameworks = []string{"apollo", "urql", "react-query"}
+
+// Plugin generates typed hooks wrapping the typed-document-node documents
+// for a client framework
+type Plugin struct{}
+
+// New creates a new framework hooks plugin
+func New() plugin.Plugin {
+	return &Plugin{}
+}
+
+// Name returns the plugin name
+func (p *Plugin) Name() string {
+	return "framework-hooks"
+}
+
+// Description returns the plugin description
+func (p *Plugin) Description() string {
+	return "Generates typed apollo, urql or react-query hooks for operations"
+}
+
+// DefaultConfig returns the default configuration
+func (p *Plugin) DefaultConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"documentsImport": "./graphql",
+		"fetcher":         "./fetcher#fetcher",
+	}
+}
+
+// ValidateConfig validates the plugin configuration
+func (p *Plugin) ValidateConfig(config map[string]interface{}) error {
+	if err := ValidateFramework(base.GetString(config, "framework", "")); err != nil {
+		return err
+	}
+	if _, _, err := parseFetcher(base.GetString(config, "fetcher", "./fetcher#fetcher")); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ValidateFramework returns an error unless framework is supported
+func ValidateFramework(framework string) error {
+	for _, supported := range Frameworks {
+		if framework == supported {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported framework %q (expected one of %s)", framework, strings.Join(Frameworks, ", "))
+}
+
+// parseFetcher splits a "module#function" fetcher reference
+func parseFetcher(fetcher string) (string, string, error) {
+	module, name, ok := strings.Cut(fetcher, "#")
+	if !ok || module == "" || name == "" {
+		return "", "", fmt.Errorf("fetcher must be of the form module#function, got %q", fetcher)
+	}
+	return module, name, nil
+}
+
+// hookOperation holds the names a hook refers to
+type hookOperation struct {
+	op           *ast.OperationDefinition
+	hookName     string
+	documentName string
+	resultType   string
+	variables    string
+}
+
+// Generate writes a hook for each named operation
+func (p *Plugin) Generate(ctx context.Context, req *plugin.GenerateRequest) (*plugin.GenerateResponse, error) {
+	if err := p.ValidateConfig(req.Config); err != nil {
+		return nil, err
+	}
+	framework := base.GetString(req.Config, "framework", "")
+	documentsImport := base.GetString(req.Config, "documentsImport", "./graphql")
+	omitSuffix := base.GetBool(req.Config, "omitOperationSuffix", false)
+	// Type names must match the ones generated by typescript-operations
+	naming, err := base.GetNamingConvention(req.Config, "namingConvention")
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*ast.OperationDefinition)
+	for _, op := range documents.CollectAllOperations(req.Documents) {
+		if op.Name == "" {
+			continue
+		}
+		// react-query has no subscription hook
+		if framework == "react-query" && op.Operation == ast.Subscription {
+			continue
+		}
+		if _, ok := byName[op.Name]; !ok {
+			byName[op.Name] = op
+		}
+	}
+
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	operations := make([]hookOperation, 0, len(names))
+	for _, name := range names {
+		op := byName[name]
+		resultType, variables := base.OperationTypeNames(op, naming, omitSuffix)
+		operations = append(operations, hookOperation{
+			op:           op,
+			hookName:     "use" + base.ToPascalCase(name) + base.ToPascalCase(string(op.Operation)),
+			documentName: base.ToPascalCase(name) + "Document",
+			resultType:   resultType,
+			variables:    variables,
+		})
+	}
+
+	var sb strings.Builder
+	if len(operations) == 0 {
+		sb.WriteString("export {};\n")
+		return &plugin.GenerateResponse{
+			Files: map[string][]byte{req.OutputPath: []byte(sb.String())},
+		}, nil
+	}
+
+	switch framework {
+	case "apollo":
+		sb.WriteString("import * as Apollo from '@apollo/client';\n")
+	case "urql":
+		sb.WriteString("import * as Urql from 'urql';\n")
+	case "react-query":
+		module, name, _ := parseFetcher(base.GetString(req.Config, "fetcher", "./fetcher#fetcher"))
+		sb.WriteString("import { useMutation, useQuery } from '@tanstack/react-query';\n")
+		sb.WriteString("import type { UseMutationOptions, UseQueryOptions } from '@tanstack/react-query';\n")
+		sb.WriteString(fmt.Sprintf("import { %s as fetcher } from '%s';\n", name, module))
+	}
+	writeDocumentImports(&sb, operations, documentsImport)
+
+	for _, op := range operations {
+		sb.WriteString("\n")
+		switch framework {
+		case "apollo":
+			writeApolloHook(&sb, op)
+		case "urql":
+			writeUrqlHook(&sb, op)
+		case "react-query":
+			writeReactQueryHook(&sb, op)
+		}
+	}
+
+	return &plugin.GenerateResponse{
+		Files: map[string][]byte{req.OutputPath: []byte(sb.String())},
+	}, nil
+}
+
+// writeDocumentImports imports the documents and their types
+func writeDocumentImports(sb *strings.Builder, operations []hookOperation, path string) {
+	documentNames := make([]string, 0, len(operations))
+	var typeNames []string
+	for _, op := range operations {
+		documentNames = append(documentNames, op.documentName)
+		typeNames = append(typeNames, op.resultType, op.variables)
+	}
+	sort.Strings(documentNames)
+	sort.Strings(typeNames)
+
+	sb.WriteString(fmt.Sprintf("import { %s } from '%s';\n", strings.Join(documentNames, ", "), path))
+	sb.WriteString(fmt.Sprintf("import type { %s } from '%s';\n", strings.Join(typeNames, ", "), path))
+}
+
+func writeApolloHook(sb *strings.Builder, op hookOperation) {
+	var options, hook string
+	switch op.op.Operation {
+	case ast.Mutation:
+		options, hook = "MutationHookOptions", "useMutation"
+	case ast.Subscription:
+		options, hook = "SubscriptionHookOptions", "useSubscription"
+	default:
+		options, hook = "QueryHookOptions", "useQuery"
+	}
+
+	sb.WriteString(fmt.Sprintf("export function %s(options?: Apollo.%s<%s, %s>) {\n", op.hookName, options, op.resultType, op.variables))
+	sb.WriteString(fmt.Sprintf("  return Apollo.%s(%s, options);\n", hook, op.documentName))
+	sb.WriteString("}\n")
+}
+
+func writeUrqlHook(sb *strings.Builder, op hookOperation) {
+	switch op.op.Operation {
+	case ast.Mutation:
+		sb.WriteString(fmt.Sprintf("export function %s() {\n", op.hookName))
+		sb.WriteString(fmt.Sprintf("  return Urql.useMutation<%s, %s>(%s);\n", op.resultType, op.variables, op.documentName))
+	case ast.Subscription:
+		sb.WriteString(fmt.Sprintf("export function %s(options%s: Omit<Urql.UseSubscriptionArgs<%s, %s>, 'query'>) {\n",
+			op.hookName, optionalMark(op.op), op.variables, op.resultType))
+		sb.WriteString(fmt.Sprintf("  return Urql.useSubscription<%s, %s, %s>({ query: %s, ...options });\n",
+			op.resultType, op.resultType, op.variables, op.documentName))
+	default:
+		sb.WriteString(fmt.Sprintf("export function %s(options%s: Omit<Urql.UseQueryArgs<%s, %s>, 'query'>) {\n",
+			op.hookName, optionalMark(op.op), op.variables, op.resultType))
+		sb.WriteString(fmt.Sprintf("  return Urql.useQuery<%s, %s>({ query: %s, ...options });\n",
+			op.resultType, op.variables, op.documentName))
+	}
+	sb.WriteString("}\n")
+}
+
+func writeReactQueryHook(sb *strings.Builder, op hookOperation) {
+	if op.op.Operation == ast.Mutation {
+		sb.WriteString(fmt.Sprintf("export function %s(options?: Omit<UseMutationOptions<%s, Error, %s>, 'mutationFn'>) {\n",
+			op.hookName, op.resultType, op.variables))
+		sb.WriteString("  return useMutation({\n")
+		sb.WriteString(fmt.Sprintf("    mutationKey: ['%s'],\n", op.op.Name))
+		sb.WriteString(fmt.Sprintf("    mutationFn: (variables: %s) => fetcher(%s, variables),\n", op.variables, op.documentName))
+		sb.WriteString("    ...options,\n")
+		sb.WriteString("  });\n")
+		sb.WriteString("}\n")
+		return
+	}
+
+	options := fmt.Sprintf("options?: Omit<UseQueryOptions<%s>, 'queryKey' | 'queryFn'>", op.resultType)
+	if len(op.op.VariableDefinitions) == 0 {
+		sb.WriteString(fmt.Sprintf("export function %s(%s) {\n", op.hookName, options))
+		sb.WriteString("  return useQuery({\n")
+		sb.WriteString(fmt.Sprintf("    queryKey: ['%s'],\n", op.op.Name))
+		sb.WriteString(fmt.Sprintf("    queryFn: () => fetcher(%s),\n", op.documentName))
+	} else {
+		sb.WriteString(fmt.Sprintf("export function %s(variables%s: %s, %s) {\n", op.hookName, optionalMark(op.op), op.variables, options))
+		sb.WriteString("  return useQuery({\n")
+		sb.WriteString(fmt.Sprintf("    queryKey: ['%s', variables],\n", op.op.Name))
+		sb.WriteString(fmt.Sprintf("    queryFn: () => fetcher(%s, variables),\n", op.documentName))
+	}
+	sb.WriteString("    ...options,\n")
+	sb.WriteString("  });\n")
+	sb.WriteString("}\n")
+}
+
+// optionalMark returns "?" unless the operation has a required variable
+func optionalMark(op *ast.OperationDefinition) string {
+	for _, v := range op.VariableDefinitions {
+		if v.Type.NonNull && v.DefaultValue == nil {
+			return ""
+		}
+	}
+	return "?"
+}
@@ -0,0 +1,111 @@
+package framework_hooks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jzeiders/graphql-go-gen/pkg/documents"
+	"github.com/jzeiders/graphql-go-gen/pkg/plugin"
+	"github.com/jzeiders/graphql-go-gen/pkg/plugins/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+)
+
+const hooksQuery = `
+	query GetUser($id: ID!) { user(id: $id) { id } }
+	query GetPosts($published: Boolean) { posts(published: $published) { id } }
+	query CurrentUser { currentUser { id } }
+	mutation DeleteUser($id: ID!) { deleteUser(id: $id) }
+	subscription OnUserCreated { userCreated { id } }
+`
+
+func generateHooks(t *testing.T, config map[string]interface{}) string {
+	t.Helper()
+
+	req := testutil.CreateTestRequest(t, config)
+	queryDoc, gqlErr := gqlparser.LoadQuery(req.Schema.Raw(), hooksQuery)
+	require.Nil(t, gqlErr)
+	req.Documents = []*documents.Document{{FilePath: "hooks.graphql", Content: hooksQuery, AST: queryDoc}}
+
+	resp, err := New().Generate(context.Background(), req)
+	require.NoError(t, err)
+	return string(resp.Files[req.OutputPath])
+}
+
+func TestPlugin_Name(t *testing.T) {
+	assert.Equal(t, "framework-hooks", New().Name())
+}
+
+func TestPlugin_Apollo(t *testing.T) {
+	output := generateHooks(t, map[string]interface{}{"framework": "apollo"})
+
+	for _, want := range []string{
+		"import * as Apollo from '@apollo/client';\n",
+		"import { CurrentUserDocument, DeleteUserDocument, GetPostsDocument, GetUserDocument, OnUserCreatedDocument } from './graphql';\n",
+		"export function useGetUserQuery(options?: Apollo.QueryHookOptions<GetUserQuery, GetUserQueryVariables>) {\n  return Apollo.useQuery(GetUserDocument, options);\n}\n",
+		"export function useDeleteUserMutation(options?: Apollo.MutationHookOptions<DeleteUserMutation, DeleteUserMutationVariables>) {\n  return Apollo.useMutation(DeleteUserDocument, options);\n}\n",
+		"export function useOnUserCreatedSubscription(options?: Apollo.SubscriptionHookOptions<OnUserCreatedSubscription, OnUserCreatedSubscriptionVariables>) {\n  return Apollo.useSubscription(OnUserCreatedDocument, options);\n}\n",
+	} {
+		assert.Contains(t, output, want)
+	}
+}
+
+func TestPlugin_Urql(t *testing.T) {
+	output := generateHooks(t, map[string]interface{}{"framework": "urql"})
+
+	for _, want := range []string{
+		"import * as Urql from 'urql';\n",
+		// Options are required while a variable is
+		"export function useGetUserQuery(options: Omit<Urql.UseQueryArgs<GetUserQueryVariables, GetUserQuery>, 'query'>) {\n  return Urql.useQuery<GetUserQuery, GetUserQueryVariables>({ query: GetUserDocument, ...options });\n}\n",
+		"export function useGetPostsQuery(options?: Omit<Urql.UseQueryArgs<GetPostsQueryVariables, GetPostsQuery>, 'query'>) {",
+		"export function useDeleteUserMutation() {\n  return Urql.useMutation<DeleteUserMutation, DeleteUserMutationVariables>(DeleteUserDocument);\n}\n",
+		"export function useOnUserCreatedSubscription(options?: Omit<Urql.UseSubscriptionArgs<OnUserCreatedSubscriptionVariables, OnUserCreatedSubscription>, 'query'>) {",
+	} {
+		assert.Contains(t, output, want)
+	}
+}
+
+func TestPlugin_ReactQuery(t *testing.T) {
+	output := generateHooks(t, map[string]interface{}{
+		"framework":       "react-query",
+		"fetcher":         "../lib/client#request",
+		"documentsImport": "./graphql.js",
+	})
+
+	for _, want := range []string{
+		"import { request as fetcher } from '../lib/client';\n",
+		"from './graphql.js';\n",
+		"export function useGetUserQuery(variables: GetUserQueryVariables, options?: Omit<UseQueryOptions<GetUserQuery>, 'queryKey' | 'queryFn'>) {\n  return useQuery({\n    queryKey: ['GetUser', variables],\n    queryFn: () => fetcher(GetUserDocument, variables),\n    ...options,\n  });\n}\n",
+		"export function useGetPostsQuery(variables?: GetPostsQueryVariables,",
+		"export function useCurrentUserQuery(options?: Omit<UseQueryOptions<CurrentUserQuery>, 'queryKey' | 'queryFn'>) {\n  return useQuery({\n    queryKey: ['CurrentUser'],\n    queryFn: () => fetcher(CurrentUserDocument),\n",
+		"    mutationFn: (variables: DeleteUserMutationVariables) => fetcher(DeleteUserDocument, variables),\n",
+	} {
+		assert.Contains(t, output, want)
+	}
+	// react-query has no subscription hook
+	assert.NotContains(t, output, "OnUserCreated")
+}
+
+func TestPlugin_OmitOperationSuffix(t *testing.T) {
+	output := generateHooks(t, map[string]interface{}{"framework": "apollo", "omitOperationSuffix": true})
+
+	// Hooks keep their suffix while the types drop it
+	assert.Contains(t, output, "export function useGetUserQuery(options?: Apollo.QueryHookOptions<GetUser, GetUserVariables>) {")
+}
+
+func TestPlugin_ValidateConfig(t *testing.T) {
+	p := New()
+	assert.NoError(t, p.ValidateConfig(map[string]interface{}{"framework": "urql"}))
+
+	err := p.ValidateConfig(map[string]interface{}{"framework": "relay"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unsupported framework "relay"`)
+
+	err = p.ValidateConfig(map[string]interface{}{"framework": "react-query", "fetcher": "./fetcher"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "module#function")
+
+	_, err = p.Generate(context.Background(), &plugin.GenerateRequest{Config: map[string]interface{}{}})
+	assert.Error(t, err)
+}
@@ -3,6 +3,7 @@ package gql_tag_operations
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"sort"
 	"strings"
 
@@ -50,6 +51,12 @@ func (p *Plugin) DefaultConfig() map[string]interface{} {
 		"augmentedModuleName":      nil,
 		"emitLegacyCommonJSImports": false,
 		"documentMode":             "graphQLTag",
+		"inline":                   false,
+		"sortOverloadsBy":          "source",
+		"emitAmbientDeclaration":   false,
+		// importExtension, ".js", ".ts" or "", overrides the extension of
+		// imports of graphql.ts implied by emitLegacyCommonJSImports
+		"importExtension": nil,
 	}
 }
 
@@ -65,6 +72,15 @@ func (p *Plugin) ValidateConfig(config map[string]interface{}) error {
 			return fmt.Errorf("invalid documentMode: %s", mode)
 		}
 	}
+	if sortBy, ok := config["sortOverloadsBy"].(string); ok && sortBy != "source" && sortBy != "name" {
+		return fmt.Errorf("invalid sortOverloadsBy: %s (must be source or name)", sortBy)
+	}
+	if err := base.ValidateImportExtension(config); err != nil {
+		return err
+	}
+	if base.GetBool(config, "emitAmbientDeclaration", false) && base.GetStringPtr(config, "augmentedModuleName") == nil {
+		return fmt.Errorf("emitAmbientDeclaration requires augmentedModuleName")
+	}
 	return nil
 }
 
@@ -74,8 +90,16 @@ func (p *Plugin) Generate(ctx context.Context, req *plugin.GenerateRequest) (*pl
 	gqlTagName := base.GetString(req.Config, "gqlTagName", "graphql")
 	useTypeImports := base.GetBool(req.Config, "useTypeImports", false)
 	augmentedModuleName := base.GetStringPtr(req.Config, "augmentedModuleName")
-	emitLegacyCommonJSImports := base.GetBool(req.Config, "emitLegacyCommonJSImports", false)
+	importExtension := base.GetImportExtension(req.Config)
 	documentMode := base.GetString(req.Config, "documentMode", "graphQLTag")
+	// Inline output is appended to the operations file, so the documents are
+	// referenced directly instead of through an import of graphql.ts
+	inline := base.GetBool(req.Config, "inline", false)
+	sortOverloadsBy := base.GetString(req.Config, "sortOverloadsBy", "source")
+	emitAmbientDeclaration := base.GetBool(req.Config, "emitAmbientDeclaration", false)
+	if err := base.ValidateImportExtension(req.Config); err != nil {
+		return nil, err
+	}
 
 	// Process sources from config
 	sourcesWithOperations := p.processSources(req)
@@ -87,22 +111,37 @@ func (p *Plugin) Generate(ctx context.Context, req *plugin.GenerateRequest) (*pl
 		}
 	}
 
+	// Sources declaring several definitions get an entry per definition too
+	sourcesWithOperations = uniqueSources(p.expandSources(sourcesWithOperations))
+	if sortOverloadsBy == "name" {
+		sortSourcesByName(sourcesWithOperations)
+	}
+
 	var sb strings.Builder
 
 	// Generate based on document mode
 	if documentMode == "string" {
-		p.generateStringMode(&sb, sourcesWithOperations, gqlTagName, emitLegacyCommonJSImports)
+		p.generateStringMode(&sb, sourcesWithOperations, gqlTagName, importExtension, inline)
 	} else if augmentedModuleName != nil {
-		p.generateAugmentedMode(&sb, sourcesWithOperations, gqlTagName, *augmentedModuleName, emitLegacyCommonJSImports)
+		p.generateAugmentedMode(&sb, sourcesWithOperations, gqlTagName, *augmentedModuleName, importExtension)
 	} else {
-		p.generateStandardMode(&sb, sourcesWithOperations, gqlTagName, useTypeImports, emitLegacyCommonJSImports)
+		p.generateStandardMode(&sb, sourcesWithOperations, gqlTagName, useTypeImports, importExtension, inline)
 	}
 
-	return &plugin.GenerateResponse{
+	resp := &plugin.GenerateResponse{
 		Files: map[string][]byte{
 			req.OutputPath: []byte(sb.String()),
 		},
-	}, nil
+	}
+
+	// The declaration sits next to the output, e.g. gql.d.ts for gql.ts
+	if emitAmbientDeclaration && augmentedModuleName != nil && documentMode != "string" {
+		var ambient strings.Builder
+		p.generateAmbientDeclaration(&ambient, sourcesWithOperations, gqlTagName, *augmentedModuleName, importExtension)
+		name := filepath.Base(req.OutputPath)
+		resp.Files[strings.TrimSuffix(name, filepath.Ext(name))+".d.ts"] = []byte(ambient.String())
+	}
+	return resp, nil
 }
 
 // processSources processes documents to extract operations and fragments
@@ -117,6 +156,12 @@ func (p *Plugin) processSources(req *plugin.GenerateRequest) []SourceWithOperati
 			continue
 		}
 
+		// Documents with identical content declare the same definitions, so
+		// only the first one is collected regardless of document order
+		if _, ok := sourceMap[strings.ReplaceAll(doc.Content, "\r\n", "\n")]; ok {
+			continue
+		}
+
 		// Process operations
 		for _, op := range doc.AST.Operations {
 			if op.Name == "" {
@@ -157,13 +202,115 @@ func (p *Plugin) processSources(req *plugin.GenerateRequest) []SourceWithOperati
 	}
 
 	// Sort for consistent output
-	sort.Slice(result, func(i, j int) bool {
+	sort.SliceStable(result, func(i, j int) bool {
 		return result[i].Source < result[j].Source
 	})
 
 	return result
 }
 
+// expandSources adds an entry for every definition of a source that declares
+// more than one operation or fragment. The source itself still resolves to its
+// first definition, as the runtime lookup returns a single document.
+func (p *Plugin) expandSources(sources []SourceWithOperations) []SourceWithOperations {
+	var result []SourceWithOperations
+	for _, source := range sources {
+		result = append(result, source)
+		if len(source.Operations) < 2 {
+			continue
+		}
+
+		for i, opOrFrag := range source.Operations {
+			text := definitionSource(source.Operations, i)
+			if text == "" || text == source.Source {
+				continue
+			}
+			result = append(result, SourceWithOperations{
+				Source:     text,
+				Operations: []OperationOrFragment{opOrFrag},
+			})
+		}
+	}
+	return result
+}
+
+// uniqueSources keeps the first entry for each source string. A definition
+// cut out of a larger source can match another document's source exactly, and
+// the registry may only declare each key once.
+func uniqueSources(sources []SourceWithOperations) []SourceWithOperations {
+	seen := make(map[string]bool)
+	result := make([]SourceWithOperations, 0, len(sources))
+	for _, source := range sources {
+		if len(source.Operations) == 0 || seen[source.Source] {
+			continue
+		}
+		seen[source.Source] = true
+		result = append(result, source)
+	}
+	return result
+}
+
+// sortSourcesByName orders sources by the name of their first definition, so
+// the registry and the overloads read alphabetically by operation. Sources
+// resolving to the same name keep their source order.
+func sortSourcesByName(sources []SourceWithOperations) {
+	sort.SliceStable(sources, func(i, j int) bool {
+		a, b := definitionName(sources[i].Operations[0]), definitionName(sources[j].Operations[0])
+		if a != b {
+			return a < b
+		}
+		return sources[i].Source < sources[j].Source
+	})
+}
+
+// definitionName returns the name of an operation or fragment
+func definitionName(opOrFrag OperationOrFragment) string {
+	if opOrFrag.Operation != nil {
+		return opOrFrag.Operation.Name
+	}
+	if opOrFrag.Fragment != nil {
+		return opOrFrag.Fragment.Name
+	}
+	return opOrFrag.InitialName
+}
+
+// definitionSource cuts the text of the i-th definition out of its source. A
+// definition runs until the next definition parsed from the same source.
+func definitionSource(definitions []OperationOrFragment, i int) string {
+	pos := definitionPosition(definitions[i])
+	if pos == nil || pos.Src == nil {
+		return ""
+	}
+
+	input := []rune(pos.Src.Input)
+	end := len(input)
+	for _, other := range definitions {
+		otherPos := definitionPosition(other)
+		if otherPos == nil || otherPos.Src != pos.Src {
+			continue
+		}
+		if otherPos.Start > pos.Start && otherPos.Start < end {
+			end = otherPos.Start
+		}
+	}
+	if pos.Start < 0 || pos.Start > end {
+		return ""
+	}
+
+	// Normalize linebreaks in source (CRLF to LF)
+	return strings.TrimSpace(strings.ReplaceAll(string(input[pos.Start:end]), "\r\n", "\n"))
+}
+
+func definitionPosition(opOrFrag OperationOrFragment) *ast.Position {
+	if opOrFrag.Operation != nil {
+		return opOrFrag.Operation.Position
+	}
+	if opOrFrag.Fragment != nil {
+		return opOrFrag.Fragment.Position
+	}
+	return nil
+}
+
 // getOperationVariableName generates the variable name for an operation
 func (p *Plugin) getOperationVariableName(op *ast.OperationDefinition) string {
 	if op.Name == "" {
@@ -180,24 +327,21 @@ func (p *Plugin) getFragmentVariableName(frag *ast.FragmentDefinition) string {
 }
 
 // generateStringMode generates code for string document mode
-func (p *Plugin) generateStringMode(sb *strings.Builder, sources []SourceWithOperations, gqlTagName string, emitLegacyCommonJSImports bool) {
-	jsExt := ""
-	if !emitLegacyCommonJSImports {
-		jsExt = ".js"
+func (p *Plugin) generateStringMode(sb *strings.Builder, sources []SourceWithOperations, gqlTagName string, importExtension string, inline bool) {
+	if !inline {
+		sb.WriteString(fmt.Sprintf("import * as types from './graphql%s';\n\n", importExtension))
 	}
 
-	sb.WriteString(fmt.Sprintf("import * as types from './graphql%s';\n\n", jsExt))
-
 	// Generate document registry
 	if len(sources) > 0 {
-		p.generateDocumentRegistry(sb, sources, "augmented")
+		p.generateDocumentRegistry(sb, sources, "augmented", inline)
 	} else {
 		sb.WriteString("const documents = {};\n")
 	}
 
 	// Generate gql function overloads
 	if len(sources) > 0 {
-		p.generateGqlOverloads(sb, sources, gqlTagName, "augmented", emitLegacyCommonJSImports)
+		p.generateGqlOverloads(sb, sources, gqlTagName, "augmented", importExtension, inline)
 		sb.WriteString("\n")
 	}
 
@@ -208,24 +352,23 @@ func (p *Plugin) generateStringMode(sb *strings.Builder, sources []SourceWithOpe
 }
 
 // generateStandardMode generates code for standard mode with TypedDocumentNode
-func (p *Plugin) generateStandardMode(sb *strings.Builder, sources []SourceWithOperations, gqlTagName string, useTypeImports bool, emitLegacyCommonJSImports bool) {
-	jsExt := ""
-	if !emitLegacyCommonJSImports {
-		jsExt = ".js"
-	}
-
-	// Imports
-	sb.WriteString(fmt.Sprintf("import * as types from './graphql%s';\n", jsExt))
-
-	importType := "import"
-	if useTypeImports {
-		importType = "import type"
+func (p *Plugin) generateStandardMode(sb *strings.Builder, sources []SourceWithOperations, gqlTagName string, useTypeImports bool, importExtension string, inline bool) {
+	// Imports; the operations file already imports TypedDocumentNode
+	documentNode := "TypedDocumentNode"
+	if !inline {
+		sb.WriteString(fmt.Sprintf("import * as types from './graphql%s';\n", importExtension))
+
+		importType := "import"
+		if useTypeImports {
+			importType = "import type"
+		}
+		sb.WriteString(fmt.Sprintf("%s { TypedDocumentNode as DocumentNode } from '@graphql-typed-document-node/core';\n\n", importType))
+		documentNode = "DocumentNode"
 	}
-	sb.WriteString(fmt.Sprintf("%s { TypedDocumentNode as DocumentNode } from '@graphql-typed-document-node/core';\n\n", importType))
 
 	// Generate document registry
 	if len(sources) > 0 {
-		p.generateDocumentRegistry(sb, sources, "lookup")
+		p.generateDocumentRegistry(sb, sources, "lookup", inline)
 	} else {
 		sb.WriteString("const documents = [];\n")
 	}
@@ -246,7 +389,7 @@ func (p *Plugin) generateStandardMode(sb *strings.Builder, sources []SourceWithO
 
 	// Generate gql function overloads
 	if len(sources) > 0 {
-		p.generateGqlOverloads(sb, sources, gqlTagName, "lookup", emitLegacyCommonJSImports)
+		p.generateGqlOverloads(sb, sources, gqlTagName, "lookup", importExtension, inline)
 		sb.WriteString("\n")
 	}
 
@@ -256,7 +399,7 @@ func (p *Plugin) generateStandardMode(sb *strings.Builder, sources []SourceWithO
 	sb.WriteString("}\n\n")
 
 	// DocumentType helper
-	sb.WriteString("export type DocumentType<TDocumentNode extends DocumentNode<any, any>> = TDocumentNode extends DocumentNode<\n")
+	sb.WriteString(fmt.Sprintf("export type DocumentType<TDocumentNode extends %s<any, any>> = TDocumentNode extends %s<\n", documentNode, documentNode))
 	sb.WriteString("  infer TType,\n")
 	sb.WriteString("  any\n")
 	sb.WriteString(">\n")
@@ -265,16 +408,38 @@ func (p *Plugin) generateStandardMode(sb *strings.Builder, sources []SourceWithO
 }
 
 // generateAugmentedMode generates code for module augmentation mode
-func (p *Plugin) generateAugmentedMode(sb *strings.Builder, sources []SourceWithOperations, gqlTagName string, augmentedModuleName string, emitLegacyCommonJSImports bool) {
+func (p *Plugin) generateAugmentedMode(sb *strings.Builder, sources []SourceWithOperations, gqlTagName string, augmentedModuleName string, importExtension string) {
 	sb.WriteString("import { TypedDocumentNode as DocumentNode } from '@graphql-typed-document-node/core';\n")
 	sb.WriteString(fmt.Sprintf("declare module \"%s\" {\n", augmentedModuleName))
 
-	// Indent content
 	var content strings.Builder
 	content.WriteString("\n")
+	p.generateModuleDeclarations(&content, sources, gqlTagName, importExtension)
+	writeIndented(sb, content.String())
 
+	sb.WriteString("}\n")
+}
+
+// generateAmbientDeclaration generates a standalone .d.ts declaring the
+// augmented module. Without top-level imports the file is a script, so it
+// applies to the whole project without being imported, e.g. when a bundler
+// plugin provides the graphql() function at runtime.
+func (p *Plugin) generateAmbientDeclaration(sb *strings.Builder, sources []SourceWithOperations, gqlTagName string, augmentedModuleName string, importExtension string) {
+	sb.WriteString(fmt.Sprintf("declare module \"%s\" {\n", augmentedModuleName))
+
+	var content strings.Builder
+	content.WriteString("import type { TypedDocumentNode as DocumentNode } from '@graphql-typed-document-node/core';\n\n")
+	p.generateModuleDeclarations(&content, sources, gqlTagName, importExtension)
+	writeIndented(sb, content.String())
+
+	sb.WriteString("}\n")
+}
+
+// generateModuleDeclarations generates the overloads and helper type
+// declared inside the augmented module
+func (p *Plugin) generateModuleDeclarations(content *strings.Builder, sources []SourceWithOperations, gqlTagName string, importExtension string) {
 	if len(sources) > 0 {
-		p.generateGqlOverloads(&content, sources, gqlTagName, "augmented", emitLegacyCommonJSImports)
+		p.generateGqlOverloads(content, sources, gqlTagName, "augmented", importExtension, false)
 	}
 
 	content.WriteString(fmt.Sprintf("export function %s(source: string): unknown;\n\n", gqlTagName))
@@ -286,22 +451,27 @@ func (p *Plugin) generateAugmentedMode(sb *strings.Builder, sources []SourceWith
 	content.WriteString(">\n")
 	content.WriteString("  ? TType\n")
 	content.WriteString("  : never;\n")
+}
 
-	// Indent all lines
-	lines := strings.Split(content.String(), "\n")
-	for _, line := range lines {
+// writeIndented writes content indented by two spaces, leaving empty lines
+// empty
+func writeIndented(sb *strings.Builder, content string) {
+	for _, line := range strings.Split(content, "\n") {
 		if line == "" {
 			sb.WriteString("\n")
 		} else {
 			sb.WriteString("  " + line + "\n")
 		}
 	}
-
-	sb.WriteString("}\n")
 }
 
 // generateDocumentRegistry generates the document registry
-func (p *Plugin) generateDocumentRegistry(sb *strings.Builder, sources []SourceWithOperations, mode string) {
+func (p *Plugin) generateDocumentRegistry(sb *strings.Builder, sources []SourceWithOperations, mode string, inline bool) {
+	typesRef := "types."
+	if inline {
+		typesRef = ""
+	}
+
 	sb.WriteString("/**\n")
 	sb.WriteString(" * Map of all GraphQL operations in the project.\n")
 	sb.WriteString(" *\n")
@@ -314,41 +484,23 @@ func (p *Plugin) generateDocumentRegistry(sb *strings.Builder, sources []SourceW
 	sb.WriteString(" * Learn more about it here: https://the-guild.dev/graphql/codegen/plugins/presets/preset-client#reducing-bundle-size\n")
 	sb.WriteString(" */\n")
 
-	// Use a map to dedupe
-	seenLines := make(map[string]bool)
-
-	// Type definition
+	// Type definition; sources are already unique, so each key is written once
 	sb.WriteString("type Documents = {\n")
 	for _, source := range sources {
-		if len(source.Operations) > 0 {
-			line := fmt.Sprintf("    %s: typeof types.%s,\n", escapeString(source.Source), source.Operations[0].InitialName)
-			if !seenLines[line] {
-				sb.WriteString(line)
-				seenLines[line] = true
-			}
-		}
+		sb.WriteString(fmt.Sprintf("    %s: typeof %s%s,\n", escapeString(source.Source), typesRef, source.Operations[0].InitialName))
 	}
 	sb.WriteString("};\n")
 
-	// Reset for actual values
-	seenLines = make(map[string]bool)
-
 	// Actual document registry
 	sb.WriteString("const documents: Documents = {\n")
 	for _, source := range sources {
-		if len(source.Operations) > 0 {
-			line := fmt.Sprintf("    %s: types.%s,\n", escapeString(source.Source), source.Operations[0].InitialName)
-			if !seenLines[line] {
-				sb.WriteString(line)
-				seenLines[line] = true
-			}
-		}
+		sb.WriteString(fmt.Sprintf("    %s: %s%s,\n", escapeString(source.Source), typesRef, source.Operations[0].InitialName))
 	}
 	sb.WriteString("};\n")
 }
 
 // generateGqlOverloads generates the overloaded gql function signatures
-func (p *Plugin) generateGqlOverloads(sb *strings.Builder, sources []SourceWithOperations, gqlTagName string, mode string, emitLegacyCommonJSImports bool) {
+func (p *Plugin) generateGqlOverloads(sb *strings.Builder, sources []SourceWithOperations, gqlTagName string, mode string, importExtension string, inline bool) {
 	// Use a set to dedupe
 	seen := make(map[string]bool)
 
@@ -360,12 +512,10 @@ func (p *Plugin) generateGqlOverloads(sb *strings.Builder, sources []SourceWithO
 		var returnType string
 		if mode == "lookup" {
 			returnType = fmt.Sprintf("(typeof documents)[%s]", escapeString(source.Source))
+		} else if inline {
+			returnType = "typeof " + source.Operations[0].InitialName
 		} else {
-			jsExt := ""
-			if !emitLegacyCommonJSImports {
-				jsExt = ".js"
-			}
-			returnType = fmt.Sprintf("typeof import('./graphql%s').%s", jsExt, source.Operations[0].InitialName)
+			returnType = fmt.Sprintf("typeof import('./graphql%s').%s", importExtension, source.Operations[0].InitialName)
 		}
 
 		signature := fmt.Sprintf("/**\n * The %s function is used to parse GraphQL queries into a document that can be used by GraphQL clients.\n */\nexport function %s(source: %s): %s;\n",
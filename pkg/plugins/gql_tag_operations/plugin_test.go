@@ -0,0 +1,320 @@
+package gql_tag_operations
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jzeiders/graphql-go-gen/pkg/documents"
+	"github.com/jzeiders/graphql-go-gen/pkg/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+const registrySchema = `
+type User {
+	id: ID!
+	name: String!
+	email: String!
+}
+
+type Query {
+	user(id: ID!): User
+}
+`
+
+func TestPlugin_Generate_RegistersEveryDefinitionOfASource(t *testing.T) {
+	// Parsed without validation, as fragments declared alone are unused
+	source := "\n  fragment UserName on User {\n    name\n  }\n\n  fragment UserEmail on User {\n    email\n  }\n"
+	doc, err := parser.ParseQuery(&ast.Source{Name: "src/user.ts", Input: source})
+	require.NoError(t, err)
+
+	p := &Plugin{}
+	resp, err := p.Generate(context.Background(), &plugin.GenerateRequest{
+		Documents:  []*documents.Document{{FilePath: "src/user.ts", Content: source, AST: doc}},
+		Config:     map[string]interface{}{},
+		OutputPath: "gql.ts",
+	})
+	require.NoError(t, err)
+	output := string(resp.Files["gql.ts"])
+
+	nameSource := `"fragment UserName on User {\n    name\n  }"`
+	emailSource := `"fragment UserEmail on User {\n    email\n  }"`
+
+	// The whole source still resolves to its first definition
+	assert.Contains(t, output, escapeString(source)+": typeof types.UserNameFragmentDoc,")
+
+	assert.Contains(t, output, nameSource+": typeof types.UserNameFragmentDoc,")
+	assert.Contains(t, output, emailSource+": typeof types.UserEmailFragmentDoc,")
+	assert.Contains(t, output, nameSource+": types.UserNameFragmentDoc,")
+	assert.Contains(t, output, emailSource+": types.UserEmailFragmentDoc,")
+
+	assert.Contains(t, output, "export function graphql(source: "+nameSource+"): (typeof documents)["+nameSource+"];")
+	assert.Contains(t, output, "export function graphql(source: "+emailSource+"): (typeof documents)["+emailSource+"];")
+}
+
+func TestPlugin_Generate_SingleDefinitionSourceIsNotDuplicated(t *testing.T) {
+	s, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: registrySchema})
+	require.NoError(t, err)
+
+	source := "query GetUser($id: ID!) { user(id: $id) { id } }"
+	doc, gqlErr := gqlparser.LoadQuery(s, source)
+	require.Nil(t, gqlErr)
+
+	p := &Plugin{}
+	resp, err := p.Generate(context.Background(), &plugin.GenerateRequest{
+		Documents:  []*documents.Document{{FilePath: "src/user.ts", Content: source, AST: doc}},
+		Config:     map[string]interface{}{},
+		OutputPath: "gql.ts",
+	})
+	require.NoError(t, err)
+	output := string(resp.Files["gql.ts"])
+
+	assert.Equal(t, 1, strings.Count(output, ": typeof types.GetUserDocument,"))
+}
+
+func TestPlugin_Generate_DuplicateContentSourcesAreStable(t *testing.T) {
+	s, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: registrySchema})
+	require.NoError(t, err)
+
+	load := func(path, source string) *documents.Document {
+		doc, gqlErr := gqlparser.LoadQuery(s, source)
+		require.Nil(t, gqlErr)
+		return &documents.Document{FilePath: path, Content: source, AST: doc}
+	}
+
+	userQuery := "query GetUser($id: ID!) { user(id: $id) { id } }"
+	nameQuery := "query GetName($id: ID!) { user(id: $id) { name } }"
+	// The second definition of this source matches nameQuery exactly
+	both := "query GetEmail($id: ID!) { user(id: $id) { email } }\n" + nameQuery
+
+	docs := []*documents.Document{
+		load("src/a.ts", userQuery),
+		load("src/b.ts", nameQuery),
+		load("src/c.ts", userQuery),
+		load("src/d.ts", both),
+	}
+
+	generate := func(docs []*documents.Document) string {
+		p := &Plugin{}
+		resp, err := p.Generate(context.Background(), &plugin.GenerateRequest{
+			Documents:  docs,
+			Config:     map[string]interface{}{},
+			OutputPath: "gql.ts",
+		})
+		require.NoError(t, err)
+		return string(resp.Files["gql.ts"])
+	}
+
+	output := generate(docs)
+
+	for _, source := range []string{userQuery, nameQuery, both} {
+		key := escapeString(source)
+		assert.Equal(t, 1, strings.Count(output, "    "+key+": typeof types."), "type entry for %s", key)
+		assert.Equal(t, 1, strings.Count(output, "    "+key+": types."), "value entry for %s", key)
+		assert.Equal(t, 1, strings.Count(output, "export function graphql(source: "+key+")"), "overload for %s", key)
+	}
+
+	// Document order does not change the output
+	reversed := make([]*documents.Document, len(docs))
+	for i, doc := range docs {
+		reversed[len(docs)-1-i] = doc
+	}
+	assert.Equal(t, output, generate(reversed))
+	assert.Equal(t, output, generate(docs))
+}
+
+func TestPlugin_Generate_InlineReferencesDocumentsDirectly(t *testing.T) {
+	s, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: registrySchema})
+	require.NoError(t, err)
+
+	source := "query GetUser($id: ID!) { user(id: $id) { id } }"
+	doc, gqlErr := gqlparser.LoadQuery(s, source)
+	require.Nil(t, gqlErr)
+
+	generate := func(config map[string]interface{}) string {
+		p := &Plugin{}
+		resp, err := p.Generate(context.Background(), &plugin.GenerateRequest{
+			Documents:  []*documents.Document{{FilePath: "src/user.ts", Content: source, AST: doc}},
+			Config:     config,
+			OutputPath: "generated.ts",
+		})
+		require.NoError(t, err)
+		return string(resp.Files["generated.ts"])
+	}
+
+	output := generate(map[string]interface{}{"inline": true})
+	assert.NotContains(t, output, "import ")
+	assert.NotContains(t, output, "types.GetUserDocument")
+	assert.Contains(t, output, escapeString(source)+": typeof GetUserDocument,")
+	assert.Contains(t, output, escapeString(source)+": GetUserDocument,")
+	assert.Contains(t, output, "export type DocumentType<TDocumentNode extends TypedDocumentNode<any, any>> = TDocumentNode extends TypedDocumentNode<")
+
+	output = generate(map[string]interface{}{"inline": true, "documentMode": "string"})
+	assert.NotContains(t, output, "import ")
+	assert.Contains(t, output, "): typeof GetUserDocument;")
+}
+
+func TestPlugin_Generate_SortOverloadsBy(t *testing.T) {
+	// Source order and name order disagree, as fragments sort before queries
+	// by source. Parsed without validation, as the fragment is unused.
+	var docs []*documents.Document
+	for _, source := range []string{
+		"query Zeta { user(id: 1) { id } }",
+		"query Alpha { user(id: 2) { name } }",
+		"fragment Mid on User { email }",
+	} {
+		doc, err := parser.ParseQuery(&ast.Source{Name: "src/ops.ts", Input: source})
+		require.NoError(t, err)
+		docs = append(docs, &documents.Document{FilePath: "src/ops.ts", Content: source, AST: doc})
+	}
+
+	overloadOrder := func(t *testing.T, config map[string]interface{}) []string {
+		t.Helper()
+		p := &Plugin{}
+		require.NoError(t, p.ValidateConfig(config))
+		resp, err := p.Generate(context.Background(), &plugin.GenerateRequest{
+			Documents:  docs,
+			Config:     config,
+			OutputPath: "gql.ts",
+		})
+		require.NoError(t, err)
+
+		var names []string
+		for _, line := range strings.Split(string(resp.Files["gql.ts"]), "\n") {
+			source, ok := strings.CutPrefix(line, "export function graphql(source: \"")
+			if !ok {
+				continue
+			}
+			names = append(names, strings.Fields(source)[1])
+		}
+		return names
+	}
+
+	t.Run("source", func(t *testing.T) {
+		assert.Equal(t, []string{"Mid", "Alpha", "Zeta"}, overloadOrder(t, map[string]interface{}{}))
+	})
+
+	t.Run("name", func(t *testing.T) {
+		names := overloadOrder(t, map[string]interface{}{"sortOverloadsBy": "name"})
+		assert.Equal(t, []string{"Alpha", "Mid", "Zeta"}, names)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		p := &Plugin{}
+		err := p.ValidateConfig(map[string]interface{}{"sortOverloadsBy": "length"})
+		assert.ErrorContains(t, err, "invalid sortOverloadsBy")
+	})
+}
+
+func TestPlugin_Generate_AmbientDeclaration(t *testing.T) {
+	s, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: registrySchema})
+	require.NoError(t, err)
+
+	source := "query GetUser($id: ID!) { user(id: $id) { id } }"
+	doc, gqlErr := gqlparser.LoadQuery(s, source)
+	require.Nil(t, gqlErr)
+
+	config := map[string]interface{}{
+		"augmentedModuleName":    "@app/graphql",
+		"emitAmbientDeclaration": true,
+	}
+	p := &Plugin{}
+	require.NoError(t, p.ValidateConfig(config))
+	resp, err := p.Generate(context.Background(), &plugin.GenerateRequest{
+		Documents:  []*documents.Document{{FilePath: "src/user.ts", Content: source, AST: doc}},
+		Config:     config,
+		OutputPath: "src/gql/gql.ts",
+	})
+	require.NoError(t, err)
+
+	require.Contains(t, resp.Files, "src/gql/gql.ts", "the augmentation is still generated")
+	ambient := string(resp.Files["gql.d.ts"])
+	require.NotEmpty(t, ambient, "files: %v", resp.Files)
+
+	// Imports inside the module block keep the file a script, so it needs
+	// no import to take effect
+	assert.True(t, strings.HasPrefix(ambient, "declare module \"@app/graphql\" {\n"), ambient)
+	assert.True(t, strings.HasSuffix(ambient, "}\n"), ambient)
+	assert.Contains(t, ambient, "\n  import type { TypedDocumentNode as DocumentNode } from '@graphql-typed-document-node/core';\n")
+	assert.Contains(t, ambient, "  export function graphql(source: "+escapeString(source)+"): typeof import('./graphql.js').GetUserDocument;\n")
+	assert.Contains(t, ambient, "  export function graphql(source: string): unknown;\n")
+	assert.Contains(t, ambient, "  export type DocumentType<TDocumentNode extends DocumentNode<any, any>>")
+	for _, line := range strings.Split(ambient, "\n") {
+		if strings.HasPrefix(line, "import") || strings.HasPrefix(line, "export") {
+			t.Errorf("top-level %q would make the declaration a module", line)
+		}
+	}
+
+	t.Run("requires augmentedModuleName", func(t *testing.T) {
+		err := p.ValidateConfig(map[string]interface{}{"emitAmbientDeclaration": true})
+		assert.ErrorContains(t, err, "requires augmentedModuleName")
+	})
+
+	t.Run("off by default", func(t *testing.T) {
+		resp, err := p.Generate(context.Background(), &plugin.GenerateRequest{
+			Documents:  []*documents.Document{{FilePath: "src/user.ts", Content: source, AST: doc}},
+			Config:     map[string]interface{}{"augmentedModuleName": "@app/graphql"},
+			OutputPath: "gql.ts",
+		})
+		require.NoError(t, err)
+		assert.Len(t, resp.Files, 1)
+	})
+}
+
+func TestPlugin_Generate_ImportExtension(t *testing.T) {
+	s, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: registrySchema})
+	require.NoError(t, err)
+
+	source := "query GetUser($id: ID!) { user(id: $id) { id } }"
+	doc, gqlErr := gqlparser.LoadQuery(s, source)
+	require.Nil(t, gqlErr)
+
+	generate := func(config map[string]interface{}) string {
+		p := &Plugin{}
+		resp, err := p.Generate(context.Background(), &plugin.GenerateRequest{
+			Documents:  []*documents.Document{{FilePath: "src/user.ts", Content: source, AST: doc}},
+			Config:     config,
+			OutputPath: "gql.ts",
+		})
+		require.NoError(t, err)
+		return string(resp.Files["gql.ts"])
+	}
+
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+		want   string
+	}{
+		{"ES modules by default", map[string]interface{}{}, "'./graphql.js'"},
+		{"legacy CommonJS", map[string]interface{}{"emitLegacyCommonJSImports": true}, "'./graphql'"},
+		{"extensionless for bundlers", map[string]interface{}{"importExtension": ""}, "'./graphql'"},
+		{"overrides legacy CommonJS", map[string]interface{}{"importExtension": ".ts", "emitLegacyCommonJSImports": true}, "'./graphql.ts'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Contains(t, generate(tt.config), "import * as types from "+tt.want+";")
+
+			tt.config["documentMode"] = "string"
+			output := generate(tt.config)
+			assert.Contains(t, output, "import * as types from "+tt.want+";")
+			assert.Contains(t, output, "typeof import("+tt.want+").GetUserDocument;")
+
+			delete(tt.config, "documentMode")
+			tt.config["augmentedModuleName"] = "graphql-tag"
+			assert.Contains(t, generate(tt.config), "typeof import("+tt.want+").GetUserDocument;")
+		})
+	}
+
+	t.Run("invalid extension", func(t *testing.T) {
+		_, err := (&Plugin{}).Generate(context.Background(), &plugin.GenerateRequest{
+			Config:     map[string]interface{}{"importExtension": "js"},
+			OutputPath: "gql.ts",
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "importExtension")
+	})
+}
@@ -0,0 +1,115 @@
+package operation_documents
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/jzeiders/graphql-go-gen/pkg/documents"
+	"github.com/jzeiders/graphql-go-gen/pkg/plugin"
+	"github.com/jzeiders/graphql-go-gen/pkg/plugins/base"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+)
+
+// Plugin writes each GraphQL operation, together with the fragments it uses,
+// to its own .graphql file
+type Plugin struct{}
+
+// New creates a new operation documents plugin
+func New() plugin.Plugin {
+	return &Plugin{}
+}
+
+// Name returns the plugin name
+func (p *Plugin) Name() string {
+	return "operation-documents"
+}
+
+// Description returns the plugin description
+func (p *Plugin) Description() string {
+	return "Writes one GraphQL document per operation using a filename template"
+}
+
+// DefaultConfig returns the default configuration
+func (p *Plugin) DefaultConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"filenameTemplate": base.DefaultOperationFilenameTemplate,
+	}
+}
+
+// ValidateConfig validates the plugin configuration
+func (p *Plugin) ValidateConfig(config map[string]interface{}) error {
+	_, err := base.ParseFilenameTemplate(base.GetString(config, "filenameTemplate", base.DefaultOperationFilenameTemplate))
+	return err
+}
+
+// Generate writes one document per named operation. Paths produced by the
+// template are relative to the directory of the output target.
+func (p *Plugin) Generate(ctx context.Context, req *plugin.GenerateRequest) (*plugin.GenerateResponse, error) {
+	template, err := base.ParseFilenameTemplate(base.GetString(req.Config, "filenameTemplate", base.DefaultOperationFilenameTemplate))
+	if err != nil {
+		return nil, err
+	}
+
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, frag := range documents.CollectAllFragments(req.Documents) {
+		fragments[frag.Name] = frag
+	}
+
+	resp := &plugin.GenerateResponse{
+		Files: make(map[string][]byte),
+	}
+	owners := make(map[string]string)
+
+	for _, op := range documents.CollectAllOperations(req.Documents) {
+		if op.Name == "" {
+			resp.Warnings = append(resp.Warnings, fmt.Sprintf("skipping anonymous %s operation", op.Operation))
+			continue
+		}
+
+		path := template.Execute(op)
+		if owner, exists := owners[path]; exists {
+			return nil, fmt.Errorf("operations %q and %q both map to %s with filename template %q",
+				owner, op.Name, path, template)
+		}
+		owners[path] = op.Name
+
+		resp.Files[path] = printOperation(op, fragments)
+	}
+
+	return resp, nil
+}
+
+// printOperation prints an operation followed by every fragment it uses
+func printOperation(op *ast.OperationDefinition, fragments map[string]*ast.FragmentDefinition) []byte {
+	doc := &ast.QueryDocument{
+		Operations: ast.OperationList{op},
+	}
+
+	seen := make(map[string]bool)
+	var collect func(selections ast.SelectionSet)
+	collect = func(selections ast.SelectionSet) {
+		for _, sel := range selections {
+			switch s := sel.(type) {
+			case *ast.Field:
+				collect(s.SelectionSet)
+			case *ast.InlineFragment:
+				collect(s.SelectionSet)
+			case *ast.FragmentSpread:
+				frag, ok := fragments[s.Name]
+				if !ok || seen[s.Name] {
+					continue
+				}
+				seen[s.Name] = true
+				doc.Fragments = append(doc.Fragments, frag)
+				collect(frag.SelectionSet)
+			}
+		}
+	}
+	collect(op.SelectionSet)
+
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf).FormatQueryDocument(doc)
+	return buf.Bytes()
+}
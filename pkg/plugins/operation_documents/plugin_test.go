@@ -0,0 +1,95 @@
+package operation_documents
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/jzeiders/graphql-go-gen/pkg/plugins/base"
+	"github.com/jzeiders/graphql-go-gen/pkg/plugins/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestPlugin_Name(t *testing.T) {
+	p := New()
+	assert.Equal(t, "operation-documents", p.Name())
+}
+
+func TestPlugin_GenerateTemplatedOperations(t *testing.T) {
+	p := New()
+	req := testutil.CreateTestRequest(t, map[string]interface{}{
+		"filenameTemplate": "{operationType}/{operationName}.graphql",
+	})
+
+	resp, err := p.Generate(context.Background(), req)
+	require.NoError(t, err)
+
+	query, ok := resp.Files[filepath.Join("query", "GetUser.graphql")]
+	require.True(t, ok, "expected query file, got %v", keys(resp.Files))
+	assert.Contains(t, string(query), "query GetUser ")
+
+	mutation, ok := resp.Files[filepath.Join("mutation", "CreateUser.graphql")]
+	require.True(t, ok, "expected mutation file, got %v", keys(resp.Files))
+	assert.Contains(t, string(mutation), "mutation CreateUser ")
+
+	_, ok = resp.Files[filepath.Join("subscription", "OnUserCreated.graphql")]
+	assert.True(t, ok)
+}
+
+func TestPlugin_GenerateOperationsIncludeFragments(t *testing.T) {
+	p := New()
+	req := testutil.CreateTestRequest(t, nil)
+
+	resp, err := p.Generate(context.Background(), req)
+	require.NoError(t, err)
+
+	content, ok := resp.Files["GetPostWithFragments.graphql"]
+	require.True(t, ok, "expected default template path, got %v", keys(resp.Files))
+	assert.Contains(t, string(content), "fragment PostFields on Post")
+	assert.Contains(t, string(content), "fragment UserFields on User")
+
+	_, ok = resp.Files["GetUser.graphql"]
+	assert.True(t, ok)
+}
+
+func TestPlugin_GenerateOperationsPathCollision(t *testing.T) {
+	p := New()
+	req := testutil.CreateTestRequest(t, map[string]interface{}{
+		"filenameTemplate": "{operationType}.graphql",
+	})
+
+	_, err := p.Generate(context.Background(), req)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "both map to")
+}
+
+func TestParseFilenameTemplate(t *testing.T) {
+	op := &ast.OperationDefinition{Operation: ast.Mutation, Name: "CreateUser"}
+
+	tmpl, err := base.ParseFilenameTemplate("ops/{operationType}/{operationName}.ts")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("ops", "mutation", "CreateUser.ts"), tmpl.Execute(op))
+
+	invalid := []string{
+		"",
+		"{operationKind}/{operationName}.graphql",
+		"{operationName.graphql",
+		"/abs/{operationName}.graphql",
+	}
+	for _, template := range invalid {
+		_, err := base.ParseFilenameTemplate(template)
+		assert.Error(t, err, "template %q", template)
+	}
+
+	assert.Error(t, New().ValidateConfig(map[string]interface{}{"filenameTemplate": "{nope}"}))
+}
+
+func keys(files map[string][]byte) []string {
+	result := make([]string, 0, len(files))
+	for path := range files {
+		result = append(result, path)
+	}
+	return result
+}
@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/jzeiders/graphql-go-gen/pkg/plugin"
 	"github.com/jzeiders/graphql-go-gen/pkg/plugins/base"
+	gqlschema "github.com/jzeiders/graphql-go-gen/pkg/schema"
 	"github.com/vektah/gqlparser/v2/ast"
 	"github.com/vektah/gqlparser/v2/formatter"
 )
@@ -27,18 +29,19 @@ func (p *Plugin) Name() string {
 
 // Description returns the plugin description
 func (p *Plugin) Description() string {
-	return "Generates GraphQL schema as TypeScript/JavaScript AST or SDL string export"
+	return "Generates GraphQL schema as TypeScript/JavaScript AST, SDL string export or plain SDL"
 }
 
 // DefaultConfig returns the default configuration
 func (p *Plugin) DefaultConfig() map[string]interface{} {
 	return map[string]interface{}{
-		"outputFormat":     "graphql",    // "graphql", "introspection", "ast"
+		"outputFormat":     "graphql",    // "graphql", "introspection", "ast", "sdl"
 		"includeDirectives": true,
 		"includeIntrospection": false,
 		"commentDescriptions": true,
 		"noExport": false,
 		"constName": "schema",
+		"sort": false,
 	}
 }
 
@@ -49,6 +52,7 @@ func (p *Plugin) ValidateConfig(config map[string]interface{}) error {
 		"graphql":       true,
 		"introspection": true,
 		"ast":          true,
+		"sdl":          true,
 	}
 
 	if !validFormats[format] {
@@ -66,17 +70,23 @@ func (p *Plugin) Generate(ctx context.Context, req *plugin.GenerateRequest) (*pl
 
 	var sb strings.Builder
 
-	// Write header
-	sb.WriteString("// Generated by graphql-go-gen - Schema AST Plugin\n")
-	sb.WriteString("// DO NOT EDIT THIS FILE MANUALLY\n\n")
-
 	// Get configuration
 	outputFormat := base.GetString(req.Config, "outputFormat", "graphql")
+
+	// Write header, as a GraphQL comment so plain SDL stays valid
+	if outputFormat == "sdl" {
+		sb.WriteString("# Generated by graphql-go-gen - Schema AST Plugin\n")
+		sb.WriteString("# DO NOT EDIT THIS FILE MANUALLY\n\n")
+	} else {
+		sb.WriteString("// Generated by graphql-go-gen - Schema AST Plugin\n")
+		sb.WriteString("// DO NOT EDIT THIS FILE MANUALLY\n\n")
+	}
 	includeDirectives := base.GetBool(req.Config, "includeDirectives", true)
 	includeIntrospection := base.GetBool(req.Config, "includeIntrospection", false)
 	commentDescriptions := base.GetBool(req.Config, "commentDescriptions", true)
 	noExport := base.GetBool(req.Config, "noExport", false)
 	constName := base.GetString(req.Config, "constName", "schema")
+	sorted := base.GetBool(req.Config, "sort", false)
 
 	exportPrefix := "export "
 	if noExport {
@@ -87,11 +97,13 @@ func (p *Plugin) Generate(ctx context.Context, req *plugin.GenerateRequest) (*pl
 
 	switch outputFormat {
 	case "graphql":
-		p.generateGraphQLSDL(&sb, astSchema, includeDirectives, includeIntrospection, commentDescriptions, exportPrefix, constName)
+		p.generateGraphQLSDL(&sb, astSchema, includeDirectives, includeIntrospection, commentDescriptions, sorted, exportPrefix, constName)
 	case "introspection":
 		p.generateIntrospectionJSON(&sb, req.Schema, exportPrefix, constName)
 	case "ast":
-		p.generateASTExport(&sb, astSchema, exportPrefix, constName)
+		p.generateASTExport(&sb, astSchema, sorted, exportPrefix, constName)
+	case "sdl":
+		p.generateRawSDL(&sb, astSchema, includeIntrospection, sorted)
 	}
 
 	return &plugin.GenerateResponse{
@@ -102,18 +114,12 @@ func (p *Plugin) Generate(ctx context.Context, req *plugin.GenerateRequest) (*pl
 }
 
 // generateGraphQLSDL generates the schema as a GraphQL SDL string
-func (p *Plugin) generateGraphQLSDL(sb *strings.Builder, schema *ast.Schema, includeDirectives bool, includeIntrospection bool, commentDescriptions bool, exportPrefix string, constName string) {
+func (p *Plugin) generateGraphQLSDL(sb *strings.Builder, schema *ast.Schema, includeDirectives bool, includeIntrospection bool, commentDescriptions bool, sorted bool, exportPrefix string, constName string) {
 	// Import graphql-tag if needed
 	sb.WriteString("import { buildSchema } from 'graphql';\n\n")
 
 	// Generate SDL
-	var buf bytes.Buffer
-	f := formatter.NewFormatter(&buf)
-
-	// Format the schema
-	f.FormatSchema(schema)
-
-	sdl := buf.String()
+	sdl := p.formatSchema(schema, sorted)
 
 	// Clean up the SDL
 	if !includeIntrospection {
@@ -127,6 +133,42 @@ func (p *Plugin) generateGraphQLSDL(sb *strings.Builder, schema *ast.Schema, inc
 	sb.WriteString(fmt.Sprintf("%sconst %s = buildSchema(%sSDL);\n", exportPrefix, constName, constName))
 }
 
+// generateRawSDL writes the schema as plain SDL, without any TypeScript
+// around it, so the output can be saved as a .graphql file
+func (p *Plugin) generateRawSDL(sb *strings.Builder, schema *ast.Schema, includeIntrospection bool, sorted bool) {
+	sdl := p.formatSchema(schema, sorted)
+	if !includeIntrospection {
+		sdl = p.removeIntrospectionTypes(sdl)
+	}
+
+	sb.WriteString(strings.TrimSpace(sdl))
+	sb.WriteString("\n")
+}
+
+// formatSchema formats the schema as SDL. When sorted, the schema
+// definition and directives come first, followed by the types grouped by
+// kind as ordered by SortedDefinitions.
+func (p *Plugin) formatSchema(s *ast.Schema, sorted bool) string {
+	var buf bytes.Buffer
+	f := formatter.NewFormatter(&buf)
+	if !sorted {
+		f.FormatSchema(s)
+		return buf.String()
+	}
+
+	// Without types, FormatSchema writes only the schema definition and
+	// the directives
+	f.FormatSchema(&ast.Schema{
+		Query:            s.Query,
+		Mutation:         s.Mutation,
+		Subscription:     s.Subscription,
+		SchemaDirectives: s.SchemaDirectives,
+		Directives:       s.Directives,
+	})
+	f.FormatSchemaDocument(&ast.SchemaDocument{Definitions: gqlschema.SortedDefinitions(s)})
+	return buf.String()
+}
+
 // generateIntrospectionJSON generates the schema as introspection JSON
 func (p *Plugin) generateIntrospectionJSON(sb *strings.Builder, schema interface{ Raw() *ast.Schema }, exportPrefix string, constName string) {
 	sb.WriteString("// Schema introspection result\n")
@@ -159,7 +201,7 @@ func (p *Plugin) generateIntrospectionJSON(sb *strings.Builder, schema interface
 }
 
 // generateASTExport generates the schema as a JavaScript AST
-func (p *Plugin) generateASTExport(sb *strings.Builder, schema *ast.Schema, exportPrefix string, constName string) {
+func (p *Plugin) generateASTExport(sb *strings.Builder, schema *ast.Schema, sorted bool, exportPrefix string, constName string) {
 	sb.WriteString("import { DocumentNode } from 'graphql';\n\n")
 	sb.WriteString("// Schema as GraphQL AST\n")
 	sb.WriteString(fmt.Sprintf("%sconst %sAST: DocumentNode = {\n", exportPrefix, constName))
@@ -167,12 +209,29 @@ func (p *Plugin) generateASTExport(sb *strings.Builder, schema *ast.Schema, expo
 	sb.WriteString("  definitions: [\n")
 
 	// Generate type definitions
-	first := true
+	var types []*ast.Definition
 	for _, typ := range schema.Types {
 		if strings.HasPrefix(typ.Name, "__") {
 			continue // Skip introspection types
 		}
+		types = append(types, typ)
+	}
+
+	hasSchemaDefinition := schema.Query != nil || schema.Mutation != nil || schema.Subscription != nil
+	first := true
+
+	// Sorted output starts with the schema definition
+	if sorted {
+		sort.Slice(types, func(i, j int) bool {
+			return gqlschema.LessDefinition(types[i].Kind, types[i].Name, types[j].Kind, types[j].Name)
+		})
+		if hasSchemaDefinition {
+			p.generateSchemaDefinitionAST(sb, schema, "    ")
+			first = false
+		}
+	}
 
+	for _, typ := range types {
 		if !first {
 			sb.WriteString(",\n")
 		}
@@ -182,7 +241,7 @@ func (p *Plugin) generateASTExport(sb *strings.Builder, schema *ast.Schema, expo
 	}
 
 	// Add schema definition if it has custom names
-	if schema.Query != nil || schema.Mutation != nil || schema.Subscription != nil {
+	if hasSchemaDefinition && !sorted {
 		if !first {
 			sb.WriteString(",\n")
 		}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/jzeiders/graphql-go-gen/pkg/plugins/schema_ast"
 	"github.com/jzeiders/graphql-go-gen/pkg/plugins/testutil"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
 )
 
 func TestSchemaASTPlugin_Generate(t *testing.T) {
@@ -274,6 +276,13 @@ func TestSchemaASTPlugin_ValidateConfig(t *testing.T) {
 			},
 			wantError: false,
 		},
+		{
+			name: "valid sdl format",
+			config: map[string]interface{}{
+				"outputFormat": "sdl",
+			},
+			wantError: false,
+		},
 		{
 			name: "invalid output format",
 			config: map[string]interface{}{
@@ -358,6 +367,82 @@ func TestSchemaASTPlugin_ASTFormat(t *testing.T) {
 	testutil.AssertContains(t, output, "operation: 'subscription'")
 }
 
+func TestSchemaASTPlugin_SDLFormat(t *testing.T) {
+	plugin := schema_ast.New()
+	req := testutil.CreateTestRequest(t, map[string]interface{}{
+		"outputFormat": "sdl",
+	})
+
+	resp, err := plugin.Generate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+
+	output := string(resp.Files["test.ts"])
+
+	// Header uses GraphQL comments and there is no TypeScript scaffolding
+	if !strings.HasPrefix(output, "# Generated by graphql-go-gen - Schema AST Plugin\n") {
+		t.Errorf("expected a # header, got:\n%s", output)
+	}
+	testutil.AssertNotContains(t, output, "//")
+	testutil.AssertNotContains(t, output, "import ")
+	testutil.AssertNotContains(t, output, "const ")
+	testutil.AssertNotContains(t, output, "`")
+	testutil.AssertNotContains(t, output, "type __Schema")
+
+	testutil.AssertContains(t, output, "type Query {")
+	testutil.AssertContains(t, output, "union SearchResult = User | Post | Comment")
+
+	// The output is valid SDL that loads back into a schema
+	if _, gqlErr := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: output}); gqlErr != nil {
+		t.Fatalf("generated SDL does not load: %v", gqlErr)
+	}
+}
+
+func TestSchemaASTPlugin_Sort(t *testing.T) {
+	plugin := schema_ast.New()
+
+	for _, format := range []string{"graphql", "sdl", "ast"} {
+		t.Run(format, func(t *testing.T) {
+			generate := func() string {
+				req := testutil.CreateTestRequest(t, map[string]interface{}{
+					"outputFormat": format,
+					"sort":         true,
+				})
+				resp, err := plugin.Generate(context.Background(), req)
+				if err != nil {
+					t.Fatalf("generate failed: %v", err)
+				}
+				return string(resp.Files["test.ts"])
+			}
+
+			output := generate()
+			for i := 0; i < 5; i++ {
+				if again := generate(); again != output {
+					t.Fatalf("sorted output differs between runs")
+				}
+			}
+
+			// Kind groups in order, alphabetical within each group
+			markers := []string{"scalar Date", "scalar JSON", "enum UserRole", "interface Node", "union SearchResult", "type Comment", "type Mutation", "type Post", "type Query"}
+			if format == "ast" {
+				markers = []string{"kind: 'SchemaDefinition'", "kind: 'ScalarTypeDefinition'", "kind: 'EnumTypeDefinition'", "kind: 'InterfaceTypeDefinition'", "kind: 'UnionTypeDefinition'", "kind: 'ObjectTypeDefinition'"}
+			}
+			last := -1
+			for _, marker := range markers {
+				idx := strings.Index(output, marker)
+				if idx == -1 {
+					t.Fatalf("missing %q in output", marker)
+				}
+				if idx < last {
+					t.Errorf("%q is out of order", marker)
+				}
+				last = idx
+			}
+		})
+	}
+}
+
 // Benchmark test
 func BenchmarkSchemaASTPlugin_Generate(b *testing.B) {
 	plugin := schema_ast.New()
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/jzeiders/graphql-go-gen/pkg/documents"
@@ -42,6 +43,7 @@ func (p *Plugin) DefaultConfig() map[string]interface{} {
 		"noExport":              false,
 		"dedupeOperationSuffix": false,
 		"omitOperationSuffix":   false,
+		"persistedDocuments":    false,
 	}
 }
 
@@ -59,9 +61,57 @@ func (p *Plugin) ValidateConfig(config map[string]interface{}) error {
 		return fmt.Errorf("invalid documentMode: %s", mode)
 	}
 
+	if _, err := parsePersistedDocuments(config); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// persistedDocumentsConfig controls the hash embedded in each operation
+// document. It mirrors the client preset's persistedDocuments option.
+type persistedDocumentsConfig struct {
+	// HashPropertyName is the __meta__ property holding the hash
+	HashPropertyName string
+	// HashAlgorithm is "sha1", "sha256" or a func(string) string
+	HashAlgorithm interface{}
+}
+
+// parsePersistedDocuments reads the persistedDocuments option, which is
+// either a bool or { hashPropertyName, hashAlgorithm }. unstable_omitDefinitions
+// replaces documents with their hash and so enables it as well.
+func parsePersistedDocuments(config map[string]interface{}) (*persistedDocumentsConfig, error) {
+	persisted := &persistedDocumentsConfig{
+		HashPropertyName: "hash",
+		HashAlgorithm:    "sha1",
+	}
+
+	switch value := config["persistedDocuments"].(type) {
+	case nil:
+		if !base.GetBool(config, "unstable_omitDefinitions", false) {
+			return nil, nil
+		}
+	case bool:
+		if !value && !base.GetBool(config, "unstable_omitDefinitions", false) {
+			return nil, nil
+		}
+	case map[string]interface{}:
+		persisted.HashPropertyName = base.GetString(value, "hashPropertyName", persisted.HashPropertyName)
+		if algorithm, ok := value["hashAlgorithm"]; ok && algorithm != nil {
+			switch algorithm.(type) {
+			case string, func(string) string:
+				persisted.HashAlgorithm = algorithm
+			default:
+				return nil, fmt.Errorf("persistedDocuments.hashAlgorithm must be a string, got %T", algorithm)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("persistedDocuments must be a boolean or an object, got %T", value)
+	}
+
+	return persisted, nil
+}
+
 // Generate generates TypedDocumentNode exports
 func (p *Plugin) Generate(ctx context.Context, req *plugin.GenerateRequest) (*plugin.GenerateResponse, error) {
 	if len(req.Documents) == 0 {
@@ -85,6 +135,20 @@ func (p *Plugin) Generate(ctx context.Context, req *plugin.GenerateRequest) (*pl
 	documentNodeImport := base.GetString(req.Config, "documentNodeImport", "@graphql-typed-document-node/core")
 	noExport := base.GetBool(req.Config, "noExport", false)
 	omitSuffix := base.GetBool(req.Config, "omitOperationSuffix", false)
+	omitDefinitions := base.GetBool(req.Config, "unstable_omitDefinitions", false)
+	typesImport := base.GetString(req.Config, "typesImport", "")
+	persisted, err := parsePersistedDocuments(req.Config)
+	if err != nil {
+		return nil, err
+	}
+	// A plain string cannot carry the hash, so string documents become
+	// TypedDocumentString objects holding it in __meta__
+	documentStrings := persisted != nil && documentMode == "string" && !omitDefinitions
+	// Type names must match the ones generated by typescript-operations
+	naming, err := base.GetNamingConvention(req.Config, "namingConvention")
+	if err != nil {
+		return nil, err
+	}
 
 	exportPrefix := "export "
 	if noExport {
@@ -92,7 +156,7 @@ func (p *Plugin) Generate(ctx context.Context, req *plugin.GenerateRequest) (*pl
 	}
 
 	// Write imports based on mode
-	p.writeImports(&sb, documentMode, gqlImport, documentNodeImport)
+	p.writeImports(&sb, documentMode, gqlImport, documentNodeImport, documentStrings)
 
 	// Collect all operations and fragments
 	allOps := documents.CollectAllOperations(req.Documents)
@@ -111,11 +175,21 @@ func (p *Plugin) Generate(ctx context.Context, req *plugin.GenerateRequest) (*pl
 		fragsMap[frag.Name] = frag
 	}
 
+	// Types generated into another module are imported from it, and the
+	// operation types re-exported alongside their documents
+	if typesImport != "" {
+		p.writeTypesImport(&sb, typesImport, opsMap, fragsMap, naming, omitSuffix)
+	}
+
+	if documentStrings {
+		p.writeTypedDocumentString(&sb, exportPrefix)
+	}
+
 	// Generate fragments first
-	p.generateFragments(&sb, fragsMap, documentMode, exportPrefix)
+	p.generateFragments(&sb, fragsMap, documentMode, naming, exportPrefix)
 
 	// Generate operations
-	p.generateOperations(&sb, opsMap, fragsMap, documentMode, omitSuffix, exportPrefix)
+	p.generateOperations(&sb, opsMap, fragsMap, documentMode, naming, omitSuffix, exportPrefix, persisted, omitDefinitions)
 
 	return &plugin.GenerateResponse{
 		Files: map[string][]byte{
@@ -125,7 +199,7 @@ func (p *Plugin) Generate(ctx context.Context, req *plugin.GenerateRequest) (*pl
 }
 
 // writeImports writes the necessary imports
-func (p *Plugin) writeImports(sb *strings.Builder, mode string, gqlImport string, docNodeImport string) {
+func (p *Plugin) writeImports(sb *strings.Builder, mode string, gqlImport string, docNodeImport string, documentStrings bool) {
 	switch mode {
 	case "graphQLTag":
 		sb.WriteString("import gql from '" + gqlImport + "';\n")
@@ -133,12 +207,42 @@ func (p *Plugin) writeImports(sb *strings.Builder, mode string, gqlImport string
 	case "documentNode", "documentNodeImportExt":
 		sb.WriteString("import { TypedDocumentNode, DocumentNode } from '" + docNodeImport + "';\n\n")
 	case "string":
+		if documentStrings {
+			sb.WriteString("import { TypedDocumentNode, DocumentTypeDecoration } from '" + docNodeImport + "';\n\n")
+			return
+		}
 		sb.WriteString("import { TypedDocumentNode } from '" + docNodeImport + "';\n\n")
 	}
 }
 
+// writeTypedDocumentString writes the TypedDocumentString class, a string
+// document that carries the operation's __meta__ and its result and
+// variables types
+func (p *Plugin) writeTypedDocumentString(sb *strings.Builder, exportPrefix string) {
+	sb.WriteString(exportPrefix + `class TypedDocumentString<TResult, TVariables>
+  extends String
+  implements DocumentTypeDecoration<TResult, TVariables>
+{
+  __apiType?: DocumentTypeDecoration<TResult, TVariables>['__apiType'];
+  private value: string;
+  public __meta__?: Record<string, any> | undefined;
+
+  constructor(value: string, __meta__?: Record<string, any> | undefined) {
+    super(value);
+    this.value = value;
+    this.__meta__ = __meta__;
+  }
+
+  toString(): string & DocumentTypeDecoration<TResult, TVariables> {
+    return this.value;
+  }
+}
+
+`)
+}
+
 // generateFragments generates fragment definitions
-func (p *Plugin) generateFragments(sb *strings.Builder, fragments map[string]*ast.FragmentDefinition, mode string, exportPrefix string) {
+func (p *Plugin) generateFragments(sb *strings.Builder, fragments map[string]*ast.FragmentDefinition, mode string, naming base.NamingConvention, exportPrefix string) {
 	if len(fragments) == 0 {
 		return
 	}
@@ -163,7 +267,7 @@ func (p *Plugin) generateFragments(sb *strings.Builder, fragments map[string]*as
 		fragStr := normalizeGraphQLString(buf.String())
 
 		constName := name + "FragmentDoc"
-		typeName := base.ToPascalCase(name) + "Fragment"
+		typeName := naming.Convert(name) + "Fragment"
 
 		switch mode {
 		case "graphQLTag":
@@ -180,7 +284,7 @@ func (p *Plugin) generateFragments(sb *strings.Builder, fragments map[string]*as
 }
 
 // generateOperations generates operation definitions
-func (p *Plugin) generateOperations(sb *strings.Builder, operations map[string]*ast.OperationDefinition, fragments map[string]*ast.FragmentDefinition, mode string, omitSuffix bool, exportPrefix string) {
+func (p *Plugin) generateOperations(sb *strings.Builder, operations map[string]*ast.OperationDefinition, fragments map[string]*ast.FragmentDefinition, mode string, naming base.NamingConvention, omitSuffix bool, exportPrefix string, persisted *persistedDocumentsConfig, omitDefinitions bool) {
 	if len(operations) == 0 {
 		return
 	}
@@ -201,36 +305,29 @@ func (p *Plugin) generateOperations(sb *strings.Builder, operations map[string]*
 
 		// Determine type names
 		constName := base.ToPascalCase(name) + "Document"
-
-		resultTypeName := base.ToPascalCase(name)
-		if !omitSuffix {
-			switch op.Operation {
-			case ast.Query:
-				resultTypeName += "Query"
-			case ast.Mutation:
-				resultTypeName += "Mutation"
-			case ast.Subscription:
-				resultTypeName += "Subscription"
+		resultTypeName, varTypeName := operationTypeNames(op, naming, omitSuffix)
+
+		meta := p.operationMeta(op, fragments, persisted, mode)
+		if meta != "" {
+			if persisted != nil && omitDefinitions {
+				// The server only needs the hash, so the document body is left out
+				sb.WriteString(fmt.Sprintf("%sconst %s = { __meta__: %s } as unknown as TypedDocumentNode<%s, %s>;\n\n",
+					exportPrefix, constName, meta, resultTypeName, varTypeName))
+				continue
 			}
-		}
 
-		varTypeName := "never"
-		if len(op.VariableDefinitions) > 0 {
-			varTypeName = base.ToPascalCase(name)
-			if !omitSuffix {
-				switch op.Operation {
-				case ast.Query:
-					varTypeName += "QueryVariables"
-				case ast.Mutation:
-					varTypeName += "MutationVariables"
-				case ast.Subscription:
-					varTypeName += "SubscriptionVariables"
-				default:
-					varTypeName += "Variables"
-				}
-			} else {
-				varTypeName += "Variables"
+			switch mode {
+			case "graphQLTag":
+				sb.WriteString(fmt.Sprintf("%sconst %s = { ...gql`\n%s\n`, __meta__: %s } as unknown as TypedDocumentNode<%s, %s>;\n\n",
+					exportPrefix, constName, opStr, meta, resultTypeName, varTypeName))
+			case "string":
+				sb.WriteString(fmt.Sprintf("%sconst %s = new TypedDocumentString(`\n%s\n`, %s) as unknown as TypedDocumentString<%s, %s>;\n\n",
+					exportPrefix, constName, opStr, meta, resultTypeName, varTypeName))
+			case "documentNode", "documentNodeImportExt":
+				sb.WriteString(fmt.Sprintf("%sconst %s = { ...%s, __meta__: %s } as unknown as TypedDocumentNode<%s, %s>;\n\n",
+					exportPrefix, constName, p.generateOperationNodeAST(op), meta, resultTypeName, varTypeName))
 			}
+			continue
 		}
 
 		// Generate based on mode
@@ -248,6 +345,152 @@ func (p *Plugin) generateOperations(sb *strings.Builder, operations map[string]*
 	}
 }
 
+// operationTypeNames returns the result and variables type names
+// typescript-operations generates for op. Operations without variables use
+// never for the variables.
+func operationTypeNames(op *ast.OperationDefinition, naming base.NamingConvention, omitSuffix bool) (string, string) {
+	resultTypeName, varTypeName := base.OperationTypeNames(op, naming, omitSuffix)
+	if len(op.VariableDefinitions) == 0 {
+		varTypeName = "never"
+	}
+	return resultTypeName, varTypeName
+}
+
+// writeTypesImport imports the fragment and operation types the documents
+// reference from the module at path, and re-exports the operation types
+func (p *Plugin) writeTypesImport(sb *strings.Builder, path string, operations map[string]*ast.OperationDefinition, fragments map[string]*ast.FragmentDefinition, naming base.NamingConvention, omitSuffix bool) {
+	var operationTypes []string
+	for _, op := range operations {
+		resultTypeName, varTypeName := operationTypeNames(op, naming, omitSuffix)
+		operationTypes = append(operationTypes, resultTypeName)
+		if varTypeName != "never" {
+			operationTypes = append(operationTypes, varTypeName)
+		}
+	}
+	sort.Strings(operationTypes)
+
+	imported := append([]string(nil), operationTypes...)
+	for name := range fragments {
+		imported = append(imported, naming.Convert(name)+"Fragment")
+	}
+	sort.Strings(imported)
+	if len(imported) == 0 {
+		return
+	}
+
+	sb.WriteString(fmt.Sprintf("import type { %s } from '%s';\n", strings.Join(imported, ", "), path))
+	if len(operationTypes) > 0 {
+		sb.WriteString(fmt.Sprintf("export type { %s } from '%s';\n", strings.Join(operationTypes, ", "), path))
+	}
+	sb.WriteString("\n")
+}
+
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// operationMeta renders the value of the operation's __meta__ property, or
+// "" when there is nothing to attach. It carries the persisted document hash, which covers
+// the same normalized document the client preset writes to
+// persisted-documents.json, and the deferredFields read by isFragmentReady.
+func (p *Plugin) operationMeta(op *ast.OperationDefinition, fragments map[string]*ast.FragmentDefinition, persisted *persistedDocumentsConfig, mode string) string {
+	opDoc := documents.OperationDocument(op, fragments)
+
+	var entries []string
+	if persisted != nil {
+		content := documents.NormalizePersistedDocument(opDoc)
+		hash := documents.HashPersistedDocument(content, persisted.HashAlgorithm)
+
+		key := persisted.HashPropertyName
+		if !identifierRegexp.MatchString(key) {
+			key = strconv.Quote(key)
+		}
+		entries = append(entries, fmt.Sprintf("%s: %q", key, hash))
+	}
+
+	// Plain string documents cannot carry extra properties; isFragmentReady
+	// then treats every fragment as ready
+	if mode != "string" || persisted != nil {
+		if deferred := p.deferredFields(opDoc, fragments); deferred != "" {
+			entries = append(entries, "deferredFields: "+deferred)
+		}
+	}
+
+	if len(entries) == 0 {
+		return ""
+	}
+	return "{ " + strings.Join(entries, ", ") + " }"
+}
+
+// deferredFields renders the fields selected by each fragment spread with
+// @defer in the document, keyed by fragment name, or "" when nothing is
+// deferred
+func (p *Plugin) deferredFields(doc *ast.QueryDocument, fragments map[string]*ast.FragmentDefinition) string {
+	deferred := make(map[string]bool)
+
+	var collect func(ast.SelectionSet)
+	collect = func(selections ast.SelectionSet) {
+		for _, sel := range selections {
+			switch s := sel.(type) {
+			case *ast.Field:
+				collect(s.SelectionSet)
+			case *ast.InlineFragment:
+				collect(s.SelectionSet)
+			case *ast.FragmentSpread:
+				if isDeferred(s.Directives) {
+					deferred[s.Name] = true
+				}
+			}
+		}
+	}
+	for _, op := range doc.Operations {
+		collect(op.SelectionSet)
+	}
+	for _, frag := range doc.Fragments {
+		collect(frag.SelectionSet)
+	}
+
+	var names []string
+	for name := range deferred {
+		if _, ok := fragments[name]; ok {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+
+	var parts []string
+	for _, name := range names {
+		var fields []string
+		for _, sel := range fragments[name].SelectionSet {
+			if field, ok := sel.(*ast.Field); ok {
+				// isFragmentReady checks the keys of the result data
+				key := field.Alias
+				if key == "" {
+					key = field.Name
+				}
+				fields = append(fields, strconv.Quote(key))
+			}
+		}
+		parts = append(parts, fmt.Sprintf("%s: [%s]", name, strings.Join(fields, ", ")))
+	}
+
+	return "{ " + strings.Join(parts, ", ") + " }"
+}
+
+// isDeferred reports whether a spread carries @defer that is not disabled
+// with a literal if: false
+func isDeferred(directives ast.DirectiveList) bool {
+	directive := directives.ForName("defer")
+	if directive == nil {
+		return false
+	}
+	if arg := directive.Arguments.ForName("if"); arg != nil && arg.Value != nil {
+		return arg.Value.Kind != ast.BooleanValue || arg.Value.Raw != "false"
+	}
+	return true
+}
+
 // buildOperationString builds the complete operation string including fragments
 func (p *Plugin) buildOperationString(op *ast.OperationDefinition, fragments map[string]*ast.FragmentDefinition) string {
 	var sb strings.Builder
@@ -5,8 +5,11 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/jzeiders/graphql-go-gen/pkg/documents"
 	"github.com/jzeiders/graphql-go-gen/pkg/plugins/testutil"
 	"github.com/jzeiders/graphql-go-gen/pkg/plugins/typed_document_node"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
 )
 
 func TestTypedDocumentNodePlugin_Generate(t *testing.T) {
@@ -289,6 +292,30 @@ func TestTypedDocumentNodePlugin_ValidateConfig(t *testing.T) {
 			config:    map[string]interface{}{},
 			wantError: false,
 		},
+		{
+			name: "persistedDocuments with string mode",
+			config: map[string]interface{}{
+				"documentMode":       "string",
+				"persistedDocuments": true,
+			},
+			wantError: false,
+		},
+		{
+			name: "persistedDocuments with string mode omitting definitions",
+			config: map[string]interface{}{
+				"documentMode":             "string",
+				"persistedDocuments":       true,
+				"unstable_omitDefinitions": true,
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid persistedDocuments",
+			config: map[string]interface{}{
+				"persistedDocuments": "yes",
+			},
+			wantError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -340,6 +367,166 @@ func TestTypedDocumentNodePlugin_FragmentUsage(t *testing.T) {
 	testutil.AssertContains(t, documentSection, "...UserFields")
 }
 
+func TestTypedDocumentNodePlugin_PersistedDocuments(t *testing.T) {
+	plugin := typed_document_node.New()
+
+	persistedHash := func(t *testing.T, docs []*documents.Document, name string, algorithm interface{}) string {
+		op, _ := documents.FindOperationByName(docs, name)
+		if op == nil {
+			t.Fatalf("operation %s not found", name)
+		}
+		fragments := make(map[string]*ast.FragmentDefinition)
+		for _, frag := range documents.CollectAllFragments(docs) {
+			fragments[frag.Name] = frag
+		}
+		content := documents.NormalizePersistedDocument(documents.OperationDocument(op, fragments))
+		return documents.HashPersistedDocument(content, algorithm)
+	}
+
+	t.Run("embeds hash in __meta__", func(t *testing.T) {
+		req := testutil.CreateTestRequest(t, map[string]interface{}{
+			"documentMode":       "graphQLTag",
+			"persistedDocuments": true,
+		})
+
+		resp, err := plugin.Generate(context.Background(), req)
+		if err != nil {
+			t.Fatalf("generate failed: %v", err)
+		}
+		output := string(resp.Files["test.ts"])
+
+		hash := persistedHash(t, req.Documents, "GetPostWithFragments", "sha1")
+		testutil.AssertContains(t, output, "const GetPostWithFragmentsDocument = { ...gql`")
+		testutil.AssertContains(t, output, "`, __meta__: { hash: \""+hash+"\" } } as unknown as TypedDocumentNode<GetPostWithFragmentsQuery, GetPostWithFragmentsQueryVariables>;")
+
+		// Fragment documents are not persisted on their own
+		testutil.AssertContains(t, output, "const PostFieldsFragmentDoc = gql`")
+	})
+
+	t.Run("uses configured hash algorithm and property", func(t *testing.T) {
+		req := testutil.CreateTestRequest(t, map[string]interface{}{
+			"documentMode": "documentNode",
+			"persistedDocuments": map[string]interface{}{
+				"hashAlgorithm":    "sha256",
+				"hashPropertyName": "documentId",
+			},
+		})
+
+		resp, err := plugin.Generate(context.Background(), req)
+		if err != nil {
+			t.Fatalf("generate failed: %v", err)
+		}
+		output := string(resp.Files["test.ts"])
+
+		hash := persistedHash(t, req.Documents, "GetUser", "sha256")
+		if len(hash) != 64 {
+			t.Fatalf("expected a sha256 hash, got %q", hash)
+		}
+		testutil.AssertContains(t, output, "const GetUserDocument = { ...{")
+		testutil.AssertContains(t, output, "__meta__: { documentId: \""+hash+"\" } } as unknown as TypedDocumentNode<GetUserQuery, GetUserQueryVariables>;")
+	})
+
+	t.Run("omits definitions", func(t *testing.T) {
+		req := testutil.CreateTestRequest(t, map[string]interface{}{
+			"documentMode":             "string",
+			"unstable_omitDefinitions": true,
+		})
+
+		resp, err := plugin.Generate(context.Background(), req)
+		if err != nil {
+			t.Fatalf("generate failed: %v", err)
+		}
+		output := string(resp.Files["test.ts"])
+
+		hash := persistedHash(t, req.Documents, "GetUser", "sha1")
+		testutil.AssertContains(t, output, "export const GetUserDocument = { __meta__: { hash: \""+hash+"\" } } as unknown as TypedDocumentNode<GetUserQuery, GetUserQueryVariables>;")
+		testutil.AssertNotContains(t, output, "query GetUser(")
+	})
+
+	t.Run("embeds hash in TypedDocumentString", func(t *testing.T) {
+		req := testutil.CreateTestRequest(t, map[string]interface{}{
+			"documentMode":       "string",
+			"persistedDocuments": true,
+		})
+
+		resp, err := plugin.Generate(context.Background(), req)
+		if err != nil {
+			t.Fatalf("generate failed: %v", err)
+		}
+		output := string(resp.Files["test.ts"])
+
+		hash := persistedHash(t, req.Documents, "GetUser", "sha1")
+		testutil.AssertContains(t, output, "import { TypedDocumentNode, DocumentTypeDecoration } from '@graphql-typed-document-node/core';")
+		testutil.AssertContains(t, output, "export class TypedDocumentString<TResult, TVariables>")
+		testutil.AssertContains(t, output, "export const GetUserDocument = new TypedDocumentString(`\nquery GetUser(")
+		testutil.AssertContains(t, output, "`, { hash: \""+hash+"\" }) as unknown as TypedDocumentString<GetUserQuery, GetUserQueryVariables>;")
+
+		// Fragment documents are not persisted on their own
+		testutil.AssertContains(t, output, "export const PostFieldsFragmentDoc = `")
+	})
+
+	t.Run("plain string documents without persisted documents", func(t *testing.T) {
+		req := testutil.CreateTestRequest(t, map[string]interface{}{
+			"documentMode": "string",
+		})
+
+		resp, err := plugin.Generate(context.Background(), req)
+		if err != nil {
+			t.Fatalf("generate failed: %v", err)
+		}
+		output := string(resp.Files["test.ts"])
+
+		testutil.AssertNotContains(t, output, "TypedDocumentString")
+		testutil.AssertContains(t, output, "export const GetUserDocument = `")
+	})
+}
+
+func TestTypedDocumentNodePlugin_DeferredFields(t *testing.T) {
+	plugin := typed_document_node.New()
+	req := testutil.CreateTestRequest(t, map[string]interface{}{
+		"documentMode": "graphQLTag",
+	})
+
+	query := `
+query GetDeferredUser($id: ID!) {
+  user(id: $id) {
+    id
+    ...UserProfile @defer
+    ...UserRole @defer(if: false)
+  }
+}
+
+fragment UserProfile on User {
+  userName: name
+  email
+  ...UserStatus @defer
+}
+
+fragment UserStatus on User {
+  status
+}
+
+fragment UserRole on User {
+  role
+}
+`
+	doc, gqlErr := gqlparser.LoadQuery(req.Schema.Raw(), query)
+	if gqlErr != nil {
+		t.Fatalf("failed to parse query: %v", gqlErr)
+	}
+	req.Documents = []*documents.Document{{FilePath: "deferred.graphql", Content: query, AST: doc}}
+
+	resp, err := plugin.Generate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	output := string(resp.Files["test.ts"])
+
+	testutil.AssertContains(t, output, "const GetDeferredUserDocument = { ...gql`")
+	testutil.AssertContains(t, output, "`, __meta__: { deferredFields: { UserProfile: [\"userName\", \"email\"], UserStatus: [\"status\"] } } } as unknown as TypedDocumentNode<GetDeferredUserQuery, GetDeferredUserQueryVariables>;")
+	testutil.AssertNotContains(t, output, "UserRole: [")
+}
+
 // Benchmark test
 func BenchmarkTypedDocumentNodePlugin_Generate(b *testing.B) {
 	plugin := typed_document_node.New()
@@ -34,10 +34,12 @@ func (p *Plugin) DefaultConfig() map[string]interface{} {
 	return map[string]interface{}{
 		"strictNulls":     false,
 		"enumsAsTypes":    false,
+		"enumsAsConst":    false,
 		"immutableTypes":  false,
 		"maybeValue":      "T | null",
 		"inputMaybeValue": "Maybe<T>",
 		"noExport":        false,
+		"onlyEnums":       false,
 	}
 }
 
@@ -52,6 +54,7 @@ const (
 	makeMaybeSignature    = "type MakeMaybe<T, K extends keyof T> = Omit<T, K> & { [SubKey in K]: Maybe<T[SubKey]> };"
 	makeEmptySignature    = "type MakeEmpty<T extends { [key: string]: unknown }, K extends keyof T> = { [_ in K]?: never };"
 	incrementalSignature  = "type Incremental<T> = T | { [P in keyof T]?: P extends ' $fragmentName' | '__typename' ? T[P] : never };"
+	streamedSignature     = "type Streamed<T extends ReadonlyArray<unknown>> = T;"
 )
 
 type scalarDefinition struct {
@@ -62,10 +65,15 @@ type scalarDefinition struct {
 type tsConfig struct {
 	strictNulls     bool
 	enumsAsTypes    bool
+	enumsAsConst    bool
 	immutableTypes  bool
 	noExport        bool
+	onlyEnums       bool
+	payloadResults  bool
 	maybeValue      string
 	inputMaybeValue string
+	// brandedIdTypes holds the types whose id field gets a branded ID type
+	brandedIdTypes map[string]bool
 }
 
 type generator struct {
@@ -87,10 +95,14 @@ func (p *Plugin) Generate(ctx context.Context, req *plugin.GenerateRequest) (*pl
 	cfg := tsConfig{
 		strictNulls:     base.GetBool(req.Config, "strictNulls", false),
 		enumsAsTypes:    base.GetBool(req.Config, "enumsAsTypes", false),
+		enumsAsConst:    base.GetBool(req.Config, "enumsAsConst", false),
 		immutableTypes:  base.GetBool(req.Config, "immutableTypes", false),
 		noExport:        base.GetBool(req.Config, "noExport", false),
+		onlyEnums:       base.GetBool(req.Config, "onlyEnums", false),
+		payloadResults:  base.GetBool(req.Config, "payloadResults", false),
 		maybeValue:      base.GetString(req.Config, "maybeValue", ""),
 		inputMaybeValue: base.GetString(req.Config, "inputMaybeValue", ""),
+		brandedIdTypes:  base.GetBrandedIDTypes(req.Config, "brandedIdTypes"),
 	}
 
 	if req.Options.StrictNulls {
@@ -110,7 +122,24 @@ func (p *Plugin) Generate(ctx context.Context, req *plugin.GenerateRequest) (*pl
 		cfg.inputMaybeValue = "Maybe<T>"
 	}
 
-	scalarDefs, customOrder := buildScalarDefinitions(astSchema, req.ScalarMap)
+	// The plugin's own `scalars` config overrides the request's ScalarMap and
+	// may map the input and output of a scalar to different types
+	overrides := make(map[string]base.ScalarMapping, len(req.ScalarMap))
+	for name, tsType := range req.ScalarMap {
+		overrides[name] = base.ScalarMapping{Input: tsType, Output: tsType}
+	}
+	for name, mapping := range base.GetScalars(req.Config, "scalars") {
+		overrides[name] = mapping
+	}
+	scalarDefs, customOrder := buildScalarDefinitions(astSchema, overrides)
+
+	var warnings []string
+	for _, name := range sortedNames(cfg.brandedIdTypes) {
+		if !hasIDField(astSchema.Types[name]) {
+			warnings = append(warnings, fmt.Sprintf("brandedIdTypes: %s is not an object or interface type with an id: ID field", name))
+			delete(cfg.brandedIdTypes, name)
+		}
+	}
 
 	var sb strings.Builder
 	sb.WriteString("// Generated by graphql-go-gen - TypeScript Plugin\n")
@@ -124,22 +153,32 @@ func (p *Plugin) Generate(ctx context.Context, req *plugin.GenerateRequest) (*pl
 		sb:                &sb,
 	}
 
-	gen.writeHelperTypes()
-	gen.writeScalars()
-	gen.writeEnums()
-	gen.writeInputTypes()
-	gen.writeObjectTypes()
-	gen.writeInterfaceTypes()
-	gen.writeUnionTypes()
+	if cfg.onlyEnums {
+		// Enums have no dependencies on the helper or scalar types
+		gen.writeEnums()
+	} else {
+		gen.writeHelperTypes()
+		gen.writeScalars()
+		gen.writeBrandedIDs()
+		gen.writeEnums()
+		gen.writeInputTypes()
+		gen.writeObjectTypes()
+		gen.writeInterfaceTypes()
+		gen.writeUnionTypes()
+		if cfg.payloadResults {
+			gen.writePayloadResults()
+		}
+	}
 
 	return &plugin.GenerateResponse{
 		Files: map[string][]byte{
 			req.OutputPath: []byte(sb.String()),
 		},
+		Warnings: warnings,
 	}, nil
 }
 
-func buildScalarDefinitions(s *ast.Schema, overrides map[string]string) (map[string]scalarDefinition, []string) {
+func buildScalarDefinitions(s *ast.Schema, overrides map[string]base.ScalarMapping) (map[string]scalarDefinition, []string) {
 	result := map[string]scalarDefinition{
 		"ID":      {Input: "string", Output: "string"},
 		"String":  {Input: "string", Output: "string"},
@@ -168,11 +207,11 @@ func buildScalarDefinitions(s *ast.Schema, overrides map[string]string) (map[str
 	sort.Strings(order)
 
 	for _, name := range order {
-		mapped := overrides[name]
-		if mapped == "" {
-			mapped = "any"
+		mapped, ok := overrides[name]
+		if !ok || mapped.Input == "" {
+			mapped = base.ScalarMapping{Input: "any", Output: "any"}
 		}
-		result[name] = scalarDefinition{Input: mapped, Output: mapped}
+		result[name] = scalarDefinition{Input: mapped.Input, Output: mapped.Output}
 	}
 
 	return result, order
@@ -203,6 +242,7 @@ func (g *generator) writeHelperTypes() {
 	g.sb.WriteString(fmt.Sprintf("%s%s\n", exportPrefix, makeMaybeSignature))
 	g.sb.WriteString(fmt.Sprintf("%s%s\n", exportPrefix, makeEmptySignature))
 	g.sb.WriteString(fmt.Sprintf("%s%s\n", exportPrefix, incrementalSignature))
+	g.sb.WriteString(fmt.Sprintf("%s%s\n", exportPrefix, streamedSignature))
 	g.sb.WriteString("\n")
 }
 
@@ -221,6 +261,39 @@ func (g *generator) writeScalars() {
 	g.sb.WriteString("};\n\n")
 }
 
+// writeBrandedIDs declares the branded ID types of brandedIdTypes, e.g.
+// UserId, which the id fields of those types use
+func (g *generator) writeBrandedIDs() {
+	names := sortedNames(g.cfg.brandedIdTypes)
+	if len(names) == 0 {
+		return
+	}
+	exportPrefix := g.exportPrefix()
+	g.sb.WriteString("/** Branded IDs, which keep the IDs of different types apart */\n")
+	for _, name := range names {
+		g.sb.WriteString(fmt.Sprintf("%stype %s = %s;\n", exportPrefix, base.BrandedIDName(name), base.BrandedIDType(name, "Scalars['ID']['output']")))
+	}
+	g.sb.WriteString("\n")
+}
+
+// hasIDField reports whether def is an object or interface with an id: ID field
+func hasIDField(def *ast.Definition) bool {
+	if def == nil || (def.Kind != ast.Object && def.Kind != ast.Interface) {
+		return false
+	}
+	field := def.Fields.ForName("id")
+	return field != nil && field.Type.Name() == "ID"
+}
+
+func sortedNames(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func (g *generator) writeEnums() {
 	enums := g.collectDefinitions(ast.Enum)
 	if len(enums) == 0 {
@@ -231,7 +304,20 @@ func (g *generator) writeEnums() {
 		if enum.Description != "" {
 			g.sb.WriteString(base.FormatComment(enum.Description, ""))
 		}
-		if g.cfg.enumsAsTypes {
+		switch {
+		case g.cfg.enumsAsConst:
+			// typescript-operations references the values of the const object
+			// as (typeof Name)[keyof typeof Name], so this wins over enumsAsTypes
+			g.sb.WriteString(fmt.Sprintf("%sconst %s = {\n", exportPrefix, enum.Name))
+			for _, value := range enum.EnumValues {
+				if value.Description != "" {
+					g.sb.WriteString(base.FormatComment(value.Description, "  "))
+				}
+				g.sb.WriteString(fmt.Sprintf("  %s: '%s',\n", value.Name, value.Name))
+			}
+			g.sb.WriteString("} as const;\n\n")
+			g.sb.WriteString(fmt.Sprintf("%stype %s = (typeof %s)[keyof typeof %s];\n", exportPrefix, enum.Name, enum.Name, enum.Name))
+		case g.cfg.enumsAsTypes:
 			g.sb.WriteString(fmt.Sprintf("%stype %s =\n", exportPrefix, enum.Name))
 			for idx, value := range enum.EnumValues {
 				g.sb.WriteString(fmt.Sprintf("  | '%s'", value.Name))
@@ -244,7 +330,7 @@ func (g *generator) writeEnums() {
 					g.sb.WriteString("\n")
 				}
 			}
-		} else {
+		default:
 			g.sb.WriteString(fmt.Sprintf("%senum %s {\n", exportPrefix, enum.Name))
 			for _, value := range enum.EnumValues {
 				if value.Description != "" {
@@ -316,7 +402,7 @@ func (g *generator) writeObjectTypes() {
 			if g.cfg.immutableTypes {
 				g.sb.WriteString("readonly ")
 			}
-			g.sb.WriteString(fmt.Sprintf("%s: %s;\n", name, ctx.render(field.Type)))
+			g.sb.WriteString(fmt.Sprintf("%s: %s;\n", name, g.fieldContext(ctx, obj, field).render(field.Type)))
 		}
 		g.sb.WriteString("};\n\n")
 		g.writeFieldArguments(obj)
@@ -373,7 +459,7 @@ func (g *generator) writeInterfaceTypes() {
 			if g.cfg.immutableTypes {
 				g.sb.WriteString("readonly ")
 			}
-			g.sb.WriteString(fmt.Sprintf("%s: %s;\n", name, ctx.render(field.Type)))
+			g.sb.WriteString(fmt.Sprintf("%s: %s;\n", name, g.fieldContext(ctx, iface, field).render(field.Type)))
 		}
 		g.sb.WriteString("};\n\n")
 	}
@@ -398,6 +484,70 @@ func (g *generator) writeUnionTypes() {
 	}
 }
 
+// writePayloadResults writes a Result type discriminated on ok and a Result
+// alias for every payload type: an object with one data field and an errors
+// list of objects carrying a message, e.g. { user, errors { field message } }
+func (g *generator) writePayloadResults() {
+	type payloadResult struct {
+		name   string
+		data   *ast.FieldDefinition
+		errors *ast.FieldDefinition
+	}
+	var payloads []payloadResult
+	for _, obj := range g.collectDefinitions(ast.Object) {
+		if data, errors := g.payloadFields(obj); data != nil {
+			payloads = append(payloads, payloadResult{name: obj.Name, data: data, errors: errors})
+		}
+	}
+	if len(payloads) == 0 {
+		return
+	}
+
+	exportPrefix := g.exportPrefix()
+	g.sb.WriteString("/** The outcome of a payload: its data when there are no errors, otherwise the errors */\n")
+	g.sb.WriteString(fmt.Sprintf("%stype Result<TData, TErrors> =\n", exportPrefix))
+	g.sb.WriteString("  | { ok: true; data: TData; errors?: never }\n")
+	g.sb.WriteString("  | { ok: false; data?: never; errors: TErrors };\n\n")
+
+	ctx := g.outputContext()
+	for _, payload := range payloads {
+		g.sb.WriteString(fmt.Sprintf("%stype %sResult = Result<%s, %s>;\n", exportPrefix, payload.name,
+			ctx.renderNonNull(nonNull(payload.data.Type)), ctx.renderNonNull(nonNull(payload.errors.Type))))
+	}
+	g.sb.WriteString("\n")
+}
+
+// payloadFields returns the data and errors fields of a payload type, or nil
+// when obj does not have the payload shape
+func (g *generator) payloadFields(obj *ast.Definition) (data, errors *ast.FieldDefinition) {
+	if obj == g.schema.Query || obj == g.schema.Mutation || obj == g.schema.Subscription {
+		return nil, nil
+	}
+
+	for _, field := range obj.Fields {
+		if strings.HasPrefix(field.Name, "__") {
+			continue
+		}
+		if field.Name == "errors" {
+			errors = field
+			continue
+		}
+		if data != nil {
+			return nil, nil
+		}
+		data = field
+	}
+	if data == nil || errors == nil || errors.Type.Elem == nil {
+		return nil, nil
+	}
+
+	errorType := g.schema.Types[errors.Type.Elem.Name()]
+	if errorType == nil || (errorType.Kind != ast.Object && errorType.Kind != ast.Interface) || errorType.Fields.ForName("message") == nil {
+		return nil, nil
+	}
+	return data, errors
+}
+
 func (g *generator) collectDefinitions(kind ast.DefinitionKind) []*ast.Definition {
 	var defs []*ast.Definition
 	for _, def := range g.schema.Types {
@@ -419,6 +569,8 @@ type typeContext struct {
 	immutable    bool
 	maybeWrapper string
 	scalarUsage  string
+	// idType replaces the ID scalar, for branded id fields
+	idType string
 }
 
 func (g *generator) outputContext() typeContext {
@@ -441,6 +593,15 @@ func (g *generator) inputContext() typeContext {
 	}
 }
 
+// fieldContext returns ctx for a field of def, using the branded ID type for
+// the id field of a type listed in brandedIdTypes
+func (g *generator) fieldContext(ctx typeContext, def *ast.Definition, field *ast.FieldDefinition) typeContext {
+	if base.IsBrandedIDField(g.cfg.brandedIdTypes, def.Name, field.Name, field.Type.Name()) {
+		ctx.idType = base.BrandedIDName(def.Name)
+	}
+	return ctx
+}
+
 func (ctx typeContext) render(t *ast.Type) string {
 	if t == nil {
 		return "any"
@@ -465,6 +626,9 @@ func (ctx typeContext) renderNonNull(t *ast.Type) string {
 }
 
 func (ctx typeContext) namedType(name string) string {
+	if name == "ID" && ctx.idType != "" {
+		return ctx.idType
+	}
 	if _, ok := ctx.scalars[name]; ok {
 		if ctx.scalarUsage == "input" {
 			return fmt.Sprintf("Scalars['%s']['input']", name)
@@ -474,6 +638,13 @@ func (ctx typeContext) namedType(name string) string {
 	return name
 }
 
+// nonNull returns t without its outer nullability
+func nonNull(t *ast.Type) *ast.Type {
+	nn := *t
+	nn.NonNull = true
+	return &nn
+}
+
 func isNonNull(t *ast.Type) bool {
 	if t == nil {
 		return false
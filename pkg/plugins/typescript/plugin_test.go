@@ -7,8 +7,12 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/jzeiders/graphql-go-gen/pkg/plugin"
 	"github.com/jzeiders/graphql-go-gen/pkg/plugins/testutil"
 	"github.com/jzeiders/graphql-go-gen/pkg/plugins/typescript"
+	"github.com/jzeiders/graphql-go-gen/pkg/schema"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
 )
 
 func TestTypeScriptPlugin_MatchesReferenceOutput(t *testing.T) {
@@ -55,6 +59,30 @@ func TestTypeScriptPlugin_EnumsAsTypes(t *testing.T) {
 	}
 }
 
+func TestTypeScriptPlugin_EnumsAsConst(t *testing.T) {
+	plugin := typescript.New()
+	req := testutil.CreateTestRequest(t, map[string]interface{}{
+		"enumsAsConst": true,
+		"enumsAsTypes": true,
+	})
+
+	resp, err := plugin.Generate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+
+	output := string(resp.Files[req.OutputPath])
+
+	want := "export const UserRole = {\n  ADMIN: 'ADMIN',\n  USER: 'USER',\n  GUEST: 'GUEST',\n} as const;\n\n" +
+		"export type UserRole = (typeof UserRole)[keyof typeof UserRole];\n"
+	if !strings.Contains(output, want) {
+		t.Fatalf("expected enums as const objects in output:\n%s", output)
+	}
+	if strings.Contains(output, "enum UserRole") || strings.Contains(output, "| 'ADMIN'") {
+		t.Fatalf("expected const objects to replace enum declarations and unions")
+	}
+}
+
 func TestTypeScriptPlugin_NoExport(t *testing.T) {
 	plugin := typescript.New()
 	req := testutil.CreateTestRequest(t, map[string]interface{}{
@@ -119,6 +147,31 @@ func TestTypeScriptPlugin_CustomMaybeValue(t *testing.T) {
 	}
 }
 
+func TestTypeScriptPlugin_OnlyEnums(t *testing.T) {
+	plugin := typescript.New()
+	req := testutil.CreateTestRequest(t, map[string]interface{}{
+		"onlyEnums": true,
+	})
+
+	resp, err := plugin.Generate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+
+	output := string(resp.Files[req.OutputPath])
+
+	for _, want := range []string{"export enum UserRole {", "export enum Status {"} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output:\n%s", want, output)
+		}
+	}
+	for _, unwanted := range []string{"Maybe<T>", "Scalars", "CreateUserInput", "type User =", "type Node =", "type SearchResult =", "type Query ="} {
+		if strings.Contains(output, unwanted) {
+			t.Fatalf("expected only enums, found %q in output:\n%s", unwanted, output)
+		}
+	}
+}
+
 func TestTypeScriptPlugin_DefaultConfig(t *testing.T) {
 	plugin := typescript.New()
 	config := plugin.DefaultConfig()
@@ -169,3 +222,129 @@ func BenchmarkTypeScriptPlugin_Generate(b *testing.B) {
 		}
 	}
 }
+
+func TestTypeScriptPlugin_PayloadResults(t *testing.T) {
+	astSchema, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+		type User { id: ID! }
+		type UserError { field: String message: String! }
+		type CreateUserPayload { user: User errors: [UserError!] }
+		type DeletePayload { success: Boolean! message: String errors: [UserError!] }
+		type Query { me: User }
+		type Mutation { createUser: CreateUserPayload! deleteUser: DeletePayload! }
+	`})
+	if err != nil {
+		t.Fatalf("load schema: %v", err)
+	}
+
+	generate := func(config map[string]interface{}) string {
+		t.Helper()
+		resp, err := typescript.New().Generate(context.Background(), &plugin.GenerateRequest{
+			Schema:     schema.NewSchema(astSchema, ""),
+			Config:     config,
+			OutputPath: "types.ts",
+		})
+		if err != nil {
+			t.Fatalf("generate failed: %v", err)
+		}
+		return string(resp.Files["types.ts"])
+	}
+
+	output := generate(map[string]interface{}{"payloadResults": true})
+	for _, want := range []string{
+		"export type Result<TData, TErrors> =\n  | { ok: true; data: TData; errors?: never }\n  | { ok: false; data?: never; errors: TErrors };",
+		"export type CreateUserPayloadResult = Result<User, Array<UserError>>;",
+	} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output:\n%s", want, output)
+		}
+	}
+
+	// Payloads with more than one data field do not match the convention
+	if strings.Contains(output, "DeletePayloadResult") {
+		t.Fatalf("unexpected result type for DeletePayload:\n%s", output)
+	}
+
+	if strings.Contains(generate(map[string]interface{}{}), "Result<") {
+		t.Fatalf("result types generated without payloadResults")
+	}
+}
+
+func TestTypeScriptPlugin_NestedListInputs(t *testing.T) {
+	astSchema, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+		input CellsInput { grid: [[Int]] strict: [[Int!]!] ids: [Int!]! }
+		type Query { cells(grid: [[Int]], strict: [[Int!]!], ids: [Int!]!): [[Int]] }
+	`})
+	if err != nil {
+		t.Fatalf("load schema: %v", err)
+	}
+
+	resp, err := typescript.New().Generate(context.Background(), &plugin.GenerateRequest{
+		Schema:     schema.NewSchema(astSchema, ""),
+		Config:     map[string]interface{}{},
+		OutputPath: "types.ts",
+	})
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	output := string(resp.Files["types.ts"])
+
+	for _, want := range []string{
+		"grid?: InputMaybe<Array<InputMaybe<Array<InputMaybe<Scalars['Int']['input']>>>>>;",
+		"strict?: InputMaybe<Array<Array<Scalars['Int']['input']>>>;",
+		"ids: Array<Scalars['Int']['input']>;",
+		// Outputs nest Maybe the same way
+		"cells?: Maybe<Array<Maybe<Array<Maybe<Scalars['Int']['output']>>>>>;",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q\ngot:\n%s", want, output)
+		}
+	}
+	if got := strings.Count(output, "strict?: InputMaybe<Array<Array<Scalars['Int']['input']>>>;"); got != 2 {
+		t.Errorf("expected the input object and the arguments type to render strict, got %d", got)
+	}
+}
+
+func TestTypeScriptPlugin_BrandedIdTypes(t *testing.T) {
+	astSchema, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+		interface Node { id: ID! }
+		type User implements Node { id: ID! authorOf: [Post!]! }
+		type Post implements Node { id: ID! authorId: ID! }
+		type Comment { id: ID! }
+		type Query { user(id: ID!): User }
+	`})
+	if err != nil {
+		t.Fatalf("load schema: %v", err)
+	}
+
+	resp, err := typescript.New().Generate(context.Background(), &plugin.GenerateRequest{
+		Schema:     schema.NewSchema(astSchema, ""),
+		Config:     map[string]interface{}{"brandedIdTypes": []interface{}{"User", "Post", "Missing"}},
+		OutputPath: "types.ts",
+	})
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	output := string(resp.Files["types.ts"])
+
+	for _, want := range []string{
+		"export type PostId = Scalars['ID']['output'] & { readonly __brand: 'PostId' };\n",
+		"export type UserId = Scalars['ID']['output'] & { readonly __brand: 'UserId' };\n",
+		"export type User = {\n  __typename?: 'User';\n  id: UserId;\n",
+		"export type Post = {\n  __typename?: 'Post';\n  id: PostId;\n",
+		// Other ID fields, unlisted types and arguments are not branded
+		"authorId: Scalars['ID']['output'];",
+		"export type Comment = {\n  __typename?: 'Comment';\n  id: Scalars['ID']['output'];\n",
+		"export type Node = {\n  id: Scalars['ID']['output'];\n",
+		"export type QueryUserArgs = {\n  id: Scalars['ID']['input'];\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q\ngot:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "MissingId") {
+		t.Errorf("expected no brand for an unknown type\ngot:\n%s", output)
+	}
+	if len(resp.Warnings) != 1 || !strings.Contains(resp.Warnings[0], "Missing") {
+		t.Errorf("expected a warning about Missing, got %v", resp.Warnings)
+	}
+}
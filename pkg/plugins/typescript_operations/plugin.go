@@ -12,6 +12,44 @@ import (
 	"github.com/vektah/gqlparser/v2/ast"
 )
 
+// defaultNoOperationsPlaceholder is emitted when there are no operations or fragments
+const defaultNoOperationsPlaceholder = "// No GraphQL operations found\n"
+
+// inlineFragmentTypes values: "inline" expands fragment spreads into the
+// parent type, "combine" intersects the parent with the fragment's type
+const (
+	inlineFragmentTypesInline  = "inline"
+	inlineFragmentTypesCombine = "combine"
+)
+
+// memberStyle is how the members of a rendered object type are separated:
+// by Separator, and after the last member too when Trailing is set
+type memberStyle struct {
+	Separator string
+	Trailing  bool
+}
+
+// after returns the separator following member i of n
+func (s memberStyle) after(i, n int) string {
+	if i < n-1 || s.Trailing {
+		return s.Separator
+	}
+	return ""
+}
+
+// Without statementStyle, variables end each member with ";" and result
+// objects separate fields with ","
+var (
+	defaultVariablesStyle = memberStyle{Separator: ";", Trailing: true}
+	defaultObjectStyle    = memberStyle{Separator: ","}
+)
+
+// statementSeparators maps the statementStyle separator names to separators
+var statementSeparators = map[string]string{
+	"semicolon": ";",
+	"comma":     ",",
+}
+
 // Plugin generates TypeScript types for GraphQL operations
 type Plugin struct{}
 
@@ -33,15 +71,31 @@ func (p *Plugin) Description() string {
 // DefaultConfig returns the default configuration
 func (p *Plugin) DefaultConfig() map[string]interface{} {
 	return map[string]interface{}{
-		"strictNulls":           false,
-		"immutableTypes":        false,
-		"noExport":              false,
-		"preResolveTypes":       true,
-		"skipTypename":          false,
-		"dedupeOperationSuffix": false,
-		"omitOperationSuffix":   false,
-		"flattenGeneratedTypes": false,
-		"avoidOptionals":        false,
+		"strictNulls":             false,
+		"immutableTypes":          false,
+		"noExport":                false,
+		"preResolveTypes":         true,
+		"skipTypename":            false,
+		"dedupeOperationSuffix":   false,
+		"omitOperationSuffix":     false,
+		"flattenGeneratedTypes":   false,
+		"avoidOptionals":          false,
+		"explicitNulls":           false,
+		"variablesAsInterface":    false,
+		"resultsAsInterface":      false,
+		"noOperationsPlaceholder": defaultNoOperationsPlaceholder,
+		"defaultScalarType":       "any",
+		"unionDiscriminator":      "__typename",
+		"descriptions":            true,
+		"enumsAsConst":            false,
+		"futureProofEnums":        false,
+		"inlineFragmentTypes":     inlineFragmentTypesInline,
+		"emitSourceMap":           false,
+		"importTypesFrom":         "",
+		"emitAccessors":           false,
+		// statementStyle, e.g. { separator: semicolon, trailing: true },
+		// separates the members of variables and result types alike;
+		// unset keeps ";" after variables and "," between result fields
 	}
 }
 
@@ -57,7 +111,10 @@ func (p *Plugin) Generate(ctx context.Context, req *plugin.GenerateRequest) (*pl
 	}
 
 	astSchema := req.Schema.Raw()
-	cfg := parseConfig(req.Config)
+	cfg, err := parseConfig(req.Config, req.ScalarMap)
+	if err != nil {
+		return nil, err
+	}
 
 	allOps := documents.CollectAllOperations(req.Documents)
 	operations := make([]*ast.OperationDefinition, 0, len(allOps))
@@ -76,16 +133,21 @@ func (p *Plugin) Generate(ctx context.Context, req *plugin.GenerateRequest) (*pl
 	}
 
 	if len(operations) == 0 && len(fragments) == 0 {
+		// An empty placeholder produces an empty file
+		placeholder := base.GetString(req.Config, "noOperationsPlaceholder", defaultNoOperationsPlaceholder)
+		if placeholder != "" && !strings.HasSuffix(placeholder, "\n") {
+			placeholder += "\n"
+		}
 		return &plugin.GenerateResponse{
 			Files: map[string][]byte{
-				req.OutputPath: []byte("// No GraphQL operations found\n"),
+				req.OutputPath: []byte(placeholder),
 			},
 		}, nil
 	}
 
 	gen := newGenerator(astSchema, cfg, fragmentMap)
 
-	var sections []string
+	var sections []renderedDefinition
 	if cfg.FlattenGeneratedTypes {
 		sections = append(sections, gen.renderFragments(fragments)...)
 		sections = append(sections, gen.renderOperations(operations)...)
@@ -94,23 +156,70 @@ func (p *Plugin) Generate(ctx context.Context, req *plugin.GenerateRequest) (*pl
 		sections = append(sections, gen.renderFragments(fragments)...)
 	}
 
-	content := strings.Join(filterNonEmpty(sections), "\n\n")
+	var content strings.Builder
+	content.WriteString(base.RenderTypesImport(gen.referenced, gen.config.ImportTypesFrom))
+	var mappings []plugin.SourceMapping
+	sources := definitionDocuments(req.Documents)
+	for _, section := range sections {
+		if strings.TrimSpace(section.Code) == "" {
+			continue
+		}
+		if content.Len() > 0 {
+			content.WriteString("\n\n")
+		}
+		start := content.Len()
+		content.WriteString(section.Code)
+
+		if doc := sources[section.Position]; cfg.EmitSourceMap && doc != nil {
+			line, column := doc.Locate(section.Position)
+			mappings = append(mappings, plugin.SourceMapping{
+				Start:  start,
+				End:    content.Len(),
+				Name:   section.Name,
+				Kind:   section.Kind,
+				Source: doc.FilePath,
+				Line:   line,
+				Column: column,
+			})
+		}
+	}
 
-	return &plugin.GenerateResponse{
+	resp := &plugin.GenerateResponse{
 		Files: map[string][]byte{
-			req.OutputPath: []byte(content),
+			req.OutputPath: []byte(content.String()),
 		},
-	}, nil
+	}
+	if len(mappings) > 0 {
+		resp.SourceMappings = map[string][]plugin.SourceMapping{req.OutputPath: mappings}
+	}
+	return resp, nil
 }
 
-func filterNonEmpty(parts []string) []string {
-	out := make([]string, 0, len(parts))
-	for _, part := range parts {
-		if strings.TrimSpace(part) != "" {
-			out = append(out, part)
+// renderedDefinition is the generated code of one operation or fragment
+type renderedDefinition struct {
+	Code     string
+	Name     string
+	Kind     string
+	Position *ast.Position
+}
+
+// definitionDocuments maps the positions of the operations and fragments of
+// docs to their documents, to locate the definitions for source maps
+func definitionDocuments(docs []*documents.Document) map[*ast.Position]*documents.Document {
+	sources := make(map[*ast.Position]*documents.Document)
+	for _, doc := range docs {
+		for _, op := range documents.GetOperations(doc) {
+			if op.Position != nil {
+				sources[op.Position] = doc
+			}
+		}
+		for _, frag := range documents.GetFragments(doc) {
+			if frag.Position != nil {
+				sources[frag.Position] = doc
+			}
 		}
 	}
-	return out
+	return sources
 }
 
 type operationsConfig struct {
@@ -119,18 +228,121 @@ type operationsConfig struct {
 	OmitOperationSuffix     bool
 	FlattenGeneratedTypes   bool
 	FlattenIncludeFragments bool
-	AvoidOptionals          bool
+	// AvoidOptionals makes nullable result fields and variables required
+	// members, separately for each kind
+	AvoidOptionals base.AvoidOptionals
+	// ExplicitNulls renders nullable result fields as required `T | null`,
+	// since servers send null rather than omitting the field
+	ExplicitNulls        bool
+	VariablesAsInterface bool
+	ResultsAsInterface   bool
+	Descriptions         bool
+	EnumsAsConst         bool
+	FutureProofEnums     bool
+	// InlineFragmentTypes is "combine" to reference fragment types from
+	// spreads instead of inlining their fields
+	InlineFragmentTypes string
+	// NamingConvention converts operation and fragment names into type names
+	NamingConvention  base.NamingConvention
+	Scalars           map[string]string
+	DefaultScalarType string
+	// UnionDiscriminator names a selected field, other than __typename, that
+	// narrows union members; UnionDiscriminatorValues maps member type names
+	// to the field's value
+	UnionDiscriminator       string
+	UnionDiscriminatorValues map[string]string
+	// BrandedIdTypes holds the types whose id field gets a branded ID type,
+	// matching the UserId types of the typescript plugin
+	BrandedIdTypes map[string]bool
+	// EmitSourceMap records which operation or fragment each generated type
+	// comes from, for the .map.json sidecar of the output
+	EmitSourceMap bool
+	// ImportTypesFrom is the module the typescript plugin's types are in,
+	// when written to another file; the helpers, scalars, enums and inputs
+	// the operations use are imported from it
+	ImportTypesFrom string
+	// EmitAccessors adds a <Result>Accessors type per operation, mapping
+	// each nested path of the result to the type optional chaining yields
+	EmitAccessors bool
+	// VariablesStyle and ObjectStyle separate the members of variables and
+	// result object types
+	VariablesStyle memberStyle
+	ObjectStyle    memberStyle
 }
 
-func parseConfig(cfg map[string]interface{}) operationsConfig {
-	return operationsConfig{
-		ImmutableTypes:          base.GetBool(cfg, "immutableTypes", false),
-		SkipTypename:            base.GetBool(cfg, "skipTypename", false),
-		OmitOperationSuffix:     base.GetBool(cfg, "omitOperationSuffix", false),
-		FlattenGeneratedTypes:   base.GetBool(cfg, "flattenGeneratedTypes", false),
-		FlattenIncludeFragments: base.GetBool(cfg, "flattenGeneratedTypesIncludeFragments", false),
-		AvoidOptionals:          base.GetBool(cfg, "avoidOptionals", false),
+// parseConfig reads the plugin config. Scalar mappings from the request's
+// ScalarMap are overridden by the plugin's own `scalars` config; results use
+// the output side of `{ input, output }` mappings.
+func parseConfig(cfg map[string]interface{}, scalarMap map[string]string) (operationsConfig, error) {
+	scalars := make(map[string]string, len(scalarMap))
+	for name, tsType := range scalarMap {
+		scalars[name] = tsType
+	}
+	for name, mapping := range base.GetScalars(cfg, "scalars") {
+		scalars[name] = mapping.Output
+	}
+
+	discriminatorValues := make(map[string]string)
+	if values, ok := cfg["unionDiscriminatorValues"].(map[string]interface{}); ok {
+		for typeName, value := range values {
+			if literal, ok := value.(string); ok {
+				discriminatorValues[typeName] = literal
+			}
+		}
 	}
+
+	discriminator := base.GetString(cfg, "unionDiscriminator", "__typename")
+	if discriminator == "__typename" {
+		discriminator = ""
+	}
+
+	namingConvention, err := base.GetNamingConvention(cfg, "namingConvention")
+	if err != nil {
+		return operationsConfig{}, err
+	}
+
+	variablesStyle, objectStyle := defaultVariablesStyle, defaultObjectStyle
+	if value, ok := cfg["statementStyle"]; ok && value != nil {
+		// A bare value such as `statementStyle: commas` is rejected like an
+		// unknown separator rather than ignored
+		style, ok := value.(map[string]interface{})
+		if !ok {
+			return operationsConfig{}, fmt.Errorf("statementStyle.separator must be \"semicolon\" or \"comma\", got statementStyle: %v", value)
+		}
+		separator, ok := statementSeparators[base.GetString(style, "separator", "semicolon")]
+		if !ok {
+			return operationsConfig{}, fmt.Errorf("statementStyle.separator must be \"semicolon\" or \"comma\", got %q", base.GetString(style, "separator", ""))
+		}
+		variablesStyle = memberStyle{Separator: separator, Trailing: base.GetBool(style, "trailing", false)}
+		objectStyle = variablesStyle
+	}
+
+	return operationsConfig{
+		NamingConvention:         namingConvention,
+		Scalars:                  scalars,
+		DefaultScalarType:        base.GetString(cfg, "defaultScalarType", "any"),
+		ImmutableTypes:           base.GetBool(cfg, "immutableTypes", false),
+		SkipTypename:             base.GetBool(cfg, "skipTypename", false),
+		OmitOperationSuffix:      base.GetBool(cfg, "omitOperationSuffix", false),
+		FlattenGeneratedTypes:    base.GetBool(cfg, "flattenGeneratedTypes", false),
+		FlattenIncludeFragments:  base.GetBool(cfg, "flattenGeneratedTypesIncludeFragments", false),
+		AvoidOptionals:           base.GetAvoidOptionals(cfg, "avoidOptionals"),
+		ExplicitNulls:            base.GetBool(cfg, "explicitNulls", false),
+		VariablesAsInterface:     base.GetBool(cfg, "variablesAsInterface", false),
+		ResultsAsInterface:       base.GetBool(cfg, "resultsAsInterface", false),
+		Descriptions:             base.GetBool(cfg, "descriptions", true),
+		EnumsAsConst:             base.GetBool(cfg, "enumsAsConst", false),
+		FutureProofEnums:         base.GetBool(cfg, "futureProofEnums", false),
+		InlineFragmentTypes:      base.GetString(cfg, "inlineFragmentTypes", inlineFragmentTypesInline),
+		UnionDiscriminator:       discriminator,
+		UnionDiscriminatorValues: discriminatorValues,
+		BrandedIdTypes:           base.GetBrandedIDTypes(cfg, "brandedIdTypes"),
+		EmitSourceMap:            base.GetBool(cfg, "emitSourceMap", false),
+		ImportTypesFrom:          base.GetString(cfg, "importTypesFrom", ""),
+		EmitAccessors:            base.GetBool(cfg, "emitAccessors", false),
+		VariablesStyle:           variablesStyle,
+		ObjectStyle:              objectStyle,
+	}, nil
 }
 
 type generator struct {
@@ -138,6 +350,17 @@ type generator struct {
 	config    operationsConfig
 	fragments map[string]*ast.FragmentDefinition
 	scalars   map[string]string
+	// inlining holds the @oneOf inputs being rendered, so a recursive input
+	// refers to itself by name instead of expanding forever
+	inlining map[string]bool
+	// referenced holds the names of the typescript plugin's types that the
+	// rendered operations use, for importTypesFrom
+	referenced map[string]bool
+	// deferSupported is set when the schema declares @defer itself, beyond
+	// the built-in definition every schema gets
+	deferSupported bool
+	// streamSupported is set when the schema declares @stream
+	streamSupported bool
 }
 
 func newGenerator(schema *ast.Schema, cfg operationsConfig, fragments map[string]*ast.FragmentDefinition) *generator {
@@ -148,27 +371,60 @@ func newGenerator(schema *ast.Schema, cfg operationsConfig, fragments map[string
 		"Int":     "number",
 		"Float":   "number",
 	}
+	for name, tsType := range cfg.Scalars {
+		scalars[name] = tsType
+	}
 	return &generator{
-		schema:    schema,
-		config:    cfg,
-		fragments: fragments,
-		scalars:   scalars,
+		schema:     schema,
+		config:     cfg,
+		fragments:  fragments,
+		scalars:    scalars,
+		inlining:   make(map[string]bool),
+		referenced: make(map[string]bool),
+
+		deferSupported:  declaresDirective(schema, "defer"),
+		streamSupported: declaresDirective(schema, "stream"),
 	}
 }
 
-func (g *generator) renderOperations(ops []*ast.OperationDefinition) []string {
-	sections := make([]string, 0, len(ops))
+// declaresDirective reports whether the schema's own SDL declares the
+// directive, as opposed to only the built-in definitions of the parser
+func declaresDirective(schema *ast.Schema, name string) bool {
+	if schema == nil {
+		return false
+	}
+	dir := schema.Directives[name]
+	if dir == nil {
+		return false
+	}
+	return dir.Position == nil || dir.Position.Src == nil || !dir.Position.Src.BuiltIn
+}
+
+// ref records a reference to a type of the typescript plugin and returns
+// its name
+func (g *generator) ref(name string) string {
+	g.referenced[name] = true
+	return name
+}
+
+func (g *generator) renderOperations(ops []*ast.OperationDefinition) []renderedDefinition {
+	sections := make([]renderedDefinition, 0, len(ops))
 	for _, op := range ops {
 		if op.Name == "" {
 			continue
 		}
-		sections = append(sections, g.renderOperation(op))
+		sections = append(sections, renderedDefinition{
+			Code:     g.renderOperation(op),
+			Name:     op.Name,
+			Kind:     string(op.Operation),
+			Position: op.Position,
+		})
 	}
 	return sections
 }
 
 func (g *generator) renderOperation(op *ast.OperationDefinition) string {
-	baseName := base.ToPascalCase(op.Name)
+	baseName := g.config.NamingConvention.Convert(op.Name)
 	suffix := ""
 	if !g.config.OmitOperationSuffix {
 		switch op.Operation {
@@ -184,16 +440,116 @@ func (g *generator) renderOperation(op *ast.OperationDefinition) string {
 	variablesName := baseName + suffix + "Variables"
 	resultName := baseName + suffix
 
-	variablesBlock := g.renderVariablesType(op)
 	resultType := g.renderOperationResult(op)
 
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("export type %s = %s;\n\n\n", variablesName, variablesBlock))
-	sb.WriteString(fmt.Sprintf("export type %s = %s;", resultName, resultType.Render("")))
+	if g.config.VariablesAsInterface {
+		sb.WriteString(fmt.Sprintf("export interface %s %s\n\n\n", variablesName, g.renderVariablesInterface(op)))
+	} else {
+		sb.WriteString(fmt.Sprintf("export type %s = %s;\n\n\n", variablesName, g.renderVariablesType(op)))
+	}
+
+	// Only object results can be declared as interfaces
+	if _, isObject := resultType.(*tsObject); isObject && g.config.ResultsAsInterface {
+		sb.WriteString(fmt.Sprintf("export interface %s %s", resultName, resultType.Render("")))
+	} else {
+		sb.WriteString(fmt.Sprintf("export type %s = %s;", resultName, resultType.Render("")))
+	}
+
+	if g.config.EmitAccessors {
+		if accessors := g.renderAccessors(op, resultName); accessors != "" {
+			sb.WriteString("\n\n")
+			sb.WriteString(accessors)
+		}
+	}
 	return sb.String()
 }
 
-func (g *generator) renderFragments(frags []*ast.FragmentDefinition) []string {
+// accessor is one nested path of an operation result and the type reading
+// it with optional chaining yields
+type accessor struct {
+	Path string
+	Type string
+}
+
+// renderAccessors renders the <Result>Accessors type of an operation. Paths
+// run through single objects and interfaces, two or more fields deep; lists
+// and abstract types needing narrowing end a path. Each type indexes the
+// result type, adding undefined when a field on the way may be missing.
+func (g *generator) renderAccessors(op *ast.OperationDefinition, resultName string) string {
+	var rootType *ast.Definition
+	switch op.Operation {
+	case ast.Query:
+		rootType = g.schema.Query
+	case ast.Mutation:
+		rootType = g.schema.Mutation
+	case ast.Subscription:
+		rootType = g.schema.Subscription
+	}
+	if rootType == nil {
+		return ""
+	}
+
+	var accessors []accessor
+	g.collectAccessors(rootType, op.SelectionSet, nil, resultName, false, &accessors)
+	if len(accessors) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("export type %sAccessors = {\n", resultName))
+	for _, a := range accessors {
+		sb.WriteString(fmt.Sprintf("  '%s': %s;\n", a.Path, a.Type))
+	}
+	sb.WriteString("};")
+	return sb.String()
+}
+
+// collectAccessors appends the paths below the selection of def, whose
+// result type is expr. maybeMissing is set when a field on the way to def is
+// nullable or conditional.
+func (g *generator) collectAccessors(def *ast.Definition, selectionSet ast.SelectionSet, path []string, expr string, maybeMissing bool, accessors *[]accessor) {
+	// Fields of combined fragment spreads are indexed through the
+	// intersection like inlined ones
+	inlined := *g
+	inlined.config.InlineFragmentTypes = inlineFragmentTypesInline
+	collector := newFieldCollector()
+	inlined.applySelections(def, selectionSet, collector, make(map[string]bool), false)
+
+	for _, name := range collector.order {
+		field := collector.fields[name]
+		if field.IsTypename || field.Type == nil {
+			continue
+		}
+		fieldPath := append(append([]string{}, path...), name)
+		fieldExpr := fmt.Sprintf("%s['%s']", expr, name)
+		if len(fieldPath) > 1 {
+			fieldType := fieldExpr
+			if maybeMissing {
+				fieldType += " | undefined"
+			}
+			*accessors = append(*accessors, accessor{Path: strings.Join(fieldPath, "."), Type: fieldType})
+		}
+
+		if field.Type.Elem != nil || len(field.SelectionSets) == 0 {
+			continue
+		}
+		fieldDef := g.schema.Types[field.Type.NamedType]
+		selection := combineSelectionSets(field.SelectionSets)
+		if fieldDef == nil || fieldDef.Kind == ast.Union ||
+			(fieldDef.Kind == ast.Interface && g.hasNarrowingFragments(fieldDef, selection, make(map[string]bool))) {
+			continue
+		}
+
+		fieldMissing := !field.Type.NonNull || field.Conditional
+		if fieldMissing {
+			fieldExpr = "NonNullable<" + fieldExpr + ">"
+		}
+		g.collectAccessors(fieldDef, selection, fieldPath, fieldExpr, maybeMissing || fieldMissing, accessors)
+	}
+}
+
+func (g *generator) renderFragments(frags []*ast.FragmentDefinition) []renderedDefinition {
 	if len(frags) == 0 {
 		return nil
 	}
@@ -206,44 +562,85 @@ func (g *generator) renderFragments(frags []*ast.FragmentDefinition) []string {
 		})
 	}
 
-	sections := make([]string, 0, len(fragments))
+	sections := make([]renderedDefinition, 0, len(fragments))
 	for _, frag := range fragments {
 		if frag == nil {
 			continue
 		}
-		typeName := base.ToPascalCase(frag.Name) + "Fragment"
-		selection := g.renderSelection(frag.TypeCondition, frag.SelectionSet, !g.config.SkipTypename)
-		sections = append(sections, fmt.Sprintf("export type %s = %s;", typeName, selection.Render("")))
+		typeName := g.fragmentTypeName(frag.Name)
+		selection := g.renderSelection(frag.TypeCondition, documents.FragmentSelections(frag, nil), !g.config.SkipTypename)
+		sections = append(sections, renderedDefinition{
+			Code:     fmt.Sprintf("export type %s = %s;", typeName, selection.Render("")),
+			Name:     frag.Name,
+			Kind:     "fragment",
+			Position: frag.Position,
+		})
 	}
 	return sections
 }
 
+func (g *generator) fragmentTypeName(name string) string {
+	return g.config.NamingConvention.Convert(name) + "Fragment"
+}
+
 func (g *generator) renderVariablesType(op *ast.OperationDefinition) string {
-	if len(op.VariableDefinitions) == 0 {
-		return "Exact<{ [key: string]: never; }>"
+	lines := g.renderVariableMembers(op)
+	if len(lines) == 0 {
+		return g.ref("Exact") + "<" + g.noVariables() + ">"
 	}
 
-	lines := make([]string, 0, len(op.VariableDefinitions))
+	return g.ref("Exact") + "<{\n" + strings.Join(lines, "\n") + "\n}>"
+}
+
+// renderVariablesInterface renders the variables as an interface body.
+// Exact<> is a mapped type and cannot be extended by an interface, so the
+// members are emitted directly; an operation without variables keeps the
+// never index signature so extra keys are still rejected.
+func (g *generator) renderVariablesInterface(op *ast.OperationDefinition) string {
+	lines := g.renderVariableMembers(op)
+	if len(lines) == 0 {
+		return g.noVariables()
+	}
+
+	return "{\n" + strings.Join(lines, "\n") + "\n}"
+}
+
+// noVariables is the type of an operation without variables, whose never
+// index signature rejects any key
+func (g *generator) noVariables() string {
+	return "{ [key: string]: never" + g.config.VariablesStyle.after(0, 1) + " }"
+}
+
+func (g *generator) renderVariableMembers(op *ast.OperationDefinition) []string {
+	vars := make([]*ast.VariableDefinition, 0, len(op.VariableDefinitions))
 	for _, v := range op.VariableDefinitions {
-		name := v.Variable
-		if name == "" {
-			continue
+		if v.Variable != "" {
+			vars = append(vars, v)
 		}
+	}
+
+	lines := make([]string, 0, len(vars))
+	for i, v := range vars {
 		typ := g.renderVariableType(v.Type)
-		optional := !v.Type.NonNull && !g.config.AvoidOptionals
-		suffix := ";"
+		optional := !v.Type.NonNull && !g.avoidOptionalVariable(v)
+		suffix := g.config.VariablesStyle.after(i, len(vars))
 		if optional {
-			lines = append(lines, fmt.Sprintf("  %s?: %s%s", name, typ, suffix))
+			lines = append(lines, fmt.Sprintf("  %s?: %s%s", v.Variable, typ, suffix))
 		} else {
-			lines = append(lines, fmt.Sprintf("  %s: %s%s", name, typ, suffix))
+			lines = append(lines, fmt.Sprintf("  %s: %s%s", v.Variable, typ, suffix))
 		}
 	}
 
-	if len(lines) == 0 {
-		return "Exact<{ [key: string]: never; }>"
-	}
+	return lines
+}
 
-	return "Exact<{\n" + strings.Join(lines, "\n") + "\n}>"
+// avoidOptionalVariable reports whether a nullable variable is rendered as
+// a required member. Variables with a default value need defaultValue too.
+func (g *generator) avoidOptionalVariable(v *ast.VariableDefinition) bool {
+	if v.DefaultValue != nil {
+		return g.config.AvoidOptionals.InputValue && g.config.AvoidOptionals.DefaultValue
+	}
+	return g.config.AvoidOptionals.InputValue
 }
 
 func (g *generator) renderVariableType(t *ast.Type) string {
@@ -252,11 +649,13 @@ func (g *generator) renderVariableType(t *ast.Type) string {
 	}
 	baseType := g.renderInputBaseType(t)
 	if !t.NonNull {
-		return fmt.Sprintf("InputMaybe<%s>", baseType)
+		return fmt.Sprintf("%s<%s>", g.ref("InputMaybe"), baseType)
 	}
 	return baseType
 }
 
+// renderInputBaseType renders t without its own InputMaybe; each nullable
+// list element is wrapped, so [[Int]] keeps one InputMaybe per level.
 func (g *generator) renderInputBaseType(t *ast.Type) string {
 	if t == nil {
 		return "any"
@@ -264,7 +663,7 @@ func (g *generator) renderInputBaseType(t *ast.Type) string {
 	if t.Elem != nil {
 		inner := g.renderInputBaseType(t.Elem)
 		if !t.Elem.NonNull {
-			inner = fmt.Sprintf("InputMaybe<%s>", inner)
+			inner = fmt.Sprintf("%s<%s>", g.ref("InputMaybe"), inner)
 		}
 		listType := "Array"
 		if g.config.ImmutableTypes {
@@ -276,12 +675,44 @@ func (g *generator) renderInputBaseType(t *ast.Type) string {
 	if name == "" {
 		return "any"
 	}
-	if def := g.schema.Types[name]; def != nil && def.Kind == ast.Scalar {
-		return fmt.Sprintf("Scalars['%s']['input']", name)
+	if def := g.schema.Types[name]; def != nil {
+		switch def.Kind {
+		case ast.Scalar:
+			return fmt.Sprintf("%s['%s']['input']", g.ref("Scalars"), name)
+		case ast.Enum:
+			return g.enumReference(name)
+		case ast.InputObject:
+			if isOneOf(def) && !g.inlining[name] {
+				return g.renderOneOfInput(def)
+			}
+		}
+		return g.ref(name)
 	}
 	return name
 }
 
+// renderOneOfInput renders a @oneOf input as a union with one single-key
+// object per field, since exactly one field may be set and it may not be
+// null: { a: A } | { b: B }
+func (g *generator) renderOneOfInput(def *ast.Definition) string {
+	g.inlining[def.Name] = true
+	defer delete(g.inlining, def.Name)
+
+	options := make([]string, 0, len(def.Fields))
+	for _, field := range def.Fields {
+		options = append(options, fmt.Sprintf("{ %s: %s }", field.Name, g.renderInputBaseType(field.Type)))
+	}
+	if len(options) == 0 {
+		return g.ref(def.Name)
+	}
+	return strings.Join(options, " | ")
+}
+
+// isOneOf reports whether an input object has the @oneOf directive
+func isOneOf(def *ast.Definition) bool {
+	return def.Directives.ForName("oneOf") != nil
+}
+
 func (g *generator) renderOperationResult(op *ast.OperationDefinition) tsType {
 	var rootType *ast.Definition
 	switch op.Operation {
@@ -307,30 +738,206 @@ func (g *generator) renderSelection(typeName string, selectionSet ast.SelectionS
 	if def.Kind == ast.Union {
 		return g.renderUnionSelection(def, selectionSet)
 	}
+	if def.Kind == ast.Interface && g.hasNarrowingFragments(def, selectionSet, make(map[string]bool)) {
+		return g.renderPossibleTypesSelection(g.possibleTypeNames(def), selectionSet)
+	}
 
-	collector := newFieldCollector(g.config.ImmutableTypes)
-	g.applySelections(def, selectionSet, collector, make(map[string]bool))
+	collector := newFieldCollector()
+	g.applySelections(def, selectionSet, collector, make(map[string]bool), false)
+	deferred := g.deferredParts(def, collector)
 	fields := collector.Finalize(g, def, allowTypename && !g.config.SkipTypename, def.Name, false)
-	return &tsObject{Fields: fields}
+	if len(collector.spreads) == 0 && len(deferred) == 0 {
+		return &tsObject{Fields: fields, Style: g.config.ObjectStyle}
+	}
+
+	intersection := &tsIntersection{}
+	if len(fields) > 0 {
+		intersection.Parts = append(intersection.Parts, &tsObject{Fields: fields, Style: g.config.ObjectStyle})
+	}
+	for _, name := range collector.spreads {
+		intersection.Parts = append(intersection.Parts, &tsPrimitive{Code: g.fragmentTypeName(name)})
+	}
+	intersection.Parts = append(intersection.Parts, deferred...)
+	return intersection
+}
+
+// collectorFor returns the collector for the selections of a fragment with
+// directives: collector itself, or a new collector among its deferred ones
+// when the fragment is deferred
+func (g *generator) collectorFor(collector *fieldCollector, directives ast.DirectiveList) *fieldCollector {
+	if !g.isDeferred(directives) {
+		return collector
+	}
+	deferred := newFieldCollector()
+	collector.deferred = append(collector.deferred, deferred)
+	return deferred
+}
+
+// isDeferred reports whether a fragment with directives is delivered in a
+// later payload: it uses @defer, the schema declares @defer, and the
+// directive is not disabled with a literal if: false
+func (g *generator) isDeferred(directives ast.DirectiveList) bool {
+	if !g.deferSupported {
+		return false
+	}
+	return incrementalEnabled(directives.ForName("defer"))
+}
+
+// isStreamed reports whether the items of a list field with directives are
+// delivered in later payloads: it uses @stream, the schema declares
+// @stream, and the directive is not disabled with a literal if: false
+func (g *generator) isStreamed(directives ast.DirectiveList) bool {
+	return g.streamSupported && incrementalEnabled(directives.ForName("stream"))
+}
+
+// incrementalEnabled reports whether a @defer or @stream directive is
+// present and not disabled with a literal if: false
+func incrementalEnabled(directive *ast.Directive) bool {
+	if directive == nil {
+		return false
+	}
+	arg := directive.Arguments.ForName("if")
+	return arg == nil || arg.Value == nil || arg.Value.Kind != ast.BooleanValue || arg.Value.Raw != "false"
+}
+
+// deferredParts returns an Incremental<...> type for each deferred fragment
+// of collector, which holds either all of the fragment's fields or none.
+// Fields the fragment shares with the unconditional selections are taken
+// out first: selections on them are added to the shared field as deferred
+// inline fragments, so they are deferred one level down, and leaf fields
+// are present either way. Deferred fragments nested in deferred fragments
+// get parts of their own.
+func (g *generator) deferredParts(def *ast.Definition, collector *fieldCollector) []tsType {
+	var parts []tsType
+	for _, deferred := range collector.deferred {
+		for _, name := range deferred.order {
+			field, shared := deferred.fields[name], collector.fields[name]
+			if field == nil || shared == nil || shared.Conditional {
+				continue
+			}
+			if len(field.SelectionSets) > 0 {
+				shared.SelectionSets = append(shared.SelectionSets, ast.SelectionSet{&ast.InlineFragment{
+					Directives:   ast.DirectiveList{{Name: "defer"}},
+					SelectionSet: combineSelectionSets(field.SelectionSets),
+				}})
+			}
+			delete(deferred.fields, name)
+		}
+
+		nested := g.deferredParts(def, deferred)
+		if fields := deferred.Finalize(g, def, false, def.Name, false); len(fields) > 0 {
+			object := &tsObject{Fields: fields, Style: g.config.ObjectStyle}
+			parts = append(parts, &tsPrimitive{Code: g.ref("Incremental") + "<" + object.Render("") + ">"})
+		}
+		for _, name := range deferred.spreads {
+			parts = append(parts, &tsPrimitive{Code: g.ref("Incremental") + "<" + g.fragmentTypeName(name) + ">"})
+		}
+		parts = append(parts, nested...)
+	}
+	return parts
 }
 
 func (g *generator) renderUnionSelection(def *ast.Definition, selectionSet ast.SelectionSet) tsType {
-	options := make([]tsType, 0, len(def.Types))
-	for _, typeName := range def.Types {
+	return g.renderPossibleTypesSelection(def.Types, selectionSet)
+}
+
+// renderPossibleTypesSelection renders a discriminated union with one branch
+// per possible type, each holding the fields selected for that type
+func (g *generator) renderPossibleTypesSelection(typeNames []string, selectionSet ast.SelectionSet) tsType {
+	options := make([]tsType, 0, len(typeNames))
+	for _, typeName := range typeNames {
 		typeDef := g.schema.Types[typeName]
 		if typeDef == nil {
 			continue
 		}
-		collector := newFieldCollector(g.config.ImmutableTypes)
+		collector := newFieldCollector()
 		collector.AddTypenameLiteral(typeName, true)
-		g.applyUnionSelections(typeDef, selectionSet, collector, make(map[string]bool), typeName)
+		g.applyUnionSelections(typeDef, selectionSet, collector, make(map[string]bool), typeName, false)
+		if g.config.UnionDiscriminator != "" {
+			collector.SetDiscriminator(g.config.UnionDiscriminator, g.discriminatorValue(typeDef))
+		}
+		deferred := g.deferredParts(typeDef, collector)
 		fields := collector.Finalize(g, typeDef, false, typeName, true)
-		options = append(options, &tsObject{Fields: fields})
+		var option tsType = &tsObject{Fields: fields, Style: g.config.ObjectStyle}
+		if len(collector.spreads) > 0 || len(deferred) > 0 {
+			intersection := &tsIntersection{Parts: []tsType{option}}
+			for _, name := range collector.spreads {
+				intersection.Parts = append(intersection.Parts, &tsPrimitive{Code: g.fragmentTypeName(name)})
+			}
+			intersection.Parts = append(intersection.Parts, deferred...)
+			option = intersection
+		}
+		options = append(options, option)
 	}
 	return &tsUnion{Options: options}
 }
 
-func (g *generator) applySelections(typeDef *ast.Definition, selectionSet ast.SelectionSet, collector *fieldCollector, visited map[string]bool) {
+// possibleTypeNames returns the object types implementing an interface, by name
+func (g *generator) possibleTypeNames(def *ast.Definition) []string {
+	var names []string
+	for _, possible := range g.schema.GetPossibleTypes(def) {
+		if possible.Kind == ast.Object {
+			names = append(names, possible.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// hasNarrowingFragments reports whether a selection on an interface contains
+// fragments on more specific types, such as `... on User`
+func (g *generator) hasNarrowingFragments(def *ast.Definition, selectionSet ast.SelectionSet, visited map[string]bool) bool {
+	appliesToInterface := func(typeCondition string) bool {
+		return typeCondition == "" || typeCondition == def.Name || typeImplements(def, typeCondition)
+	}
+
+	for _, sel := range selectionSet {
+		switch s := sel.(type) {
+		case *ast.InlineFragment:
+			if !appliesToInterface(s.TypeCondition) || g.hasNarrowingFragments(def, s.SelectionSet, visited) {
+				return true
+			}
+		case *ast.FragmentSpread:
+			frag := g.fragments[s.Name]
+			if frag == nil || visited[frag.Name] {
+				continue
+			}
+			if !appliesToInterface(frag.TypeCondition) {
+				return true
+			}
+			visited[frag.Name] = true
+			narrowing := g.hasNarrowingFragments(def, frag.SelectionSet, visited)
+			delete(visited, frag.Name)
+			if narrowing {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// discriminatorValue returns the value of the union discriminator field for a
+// member type: an explicit unionDiscriminatorValues entry, the enum value
+// matching the type name (GOLDEN_RETRIEVER for GoldenRetriever), or the type name
+func (g *generator) discriminatorValue(typeDef *ast.Definition) string {
+	if value, ok := g.config.UnionDiscriminatorValues[typeDef.Name]; ok {
+		return value
+	}
+	if fieldDef := findFieldDefinition(typeDef, g.config.UnionDiscriminator); fieldDef != nil {
+		if enumDef := g.schema.Types[unwrapTypeName(fieldDef.Type)]; enumDef != nil && enumDef.Kind == ast.Enum {
+			for _, value := range enumDef.EnumValues {
+				if strings.EqualFold(strings.ReplaceAll(value.Name, "_", ""), typeDef.Name) {
+					return value.Name
+				}
+			}
+		}
+	}
+	return typeDef.Name
+}
+
+// applySelections collects the fields selected on typeDef. conditional is set
+// when the selections sit under a fragment guarded by @skip or @include.
+func (g *generator) applySelections(typeDef *ast.Definition, selectionSet ast.SelectionSet, collector *fieldCollector, visited map[string]bool, conditional bool) {
 	for _, sel := range selectionSet {
 		switch s := sel.(type) {
 		case *ast.Field:
@@ -338,19 +945,23 @@ func (g *generator) applySelections(typeDef *ast.Definition, selectionSet ast.Se
 			if responseName == "" {
 				responseName = s.Name
 			}
+			fieldConditional := conditional || isConditional(s.Directives)
 			if s.Name == "__typename" {
-				collector.AddField(responseName, s.Name, nil, &ast.Type{NamedType: "String"}, nil)
+				collector.AddField(responseName, s.Name, nil, &ast.Type{NamedType: "String"}, nil, fieldConditional)
 				continue
 			}
 			fieldDef := findFieldDefinition(typeDef, s.Name)
 			if fieldDef == nil {
 				continue
 			}
-			collector.AddField(responseName, s.Name, fieldDef, fieldDef.Type, s.SelectionSet)
+			collector.AddField(responseName, s.Name, fieldDef, fieldDef.Type, s.SelectionSet, fieldConditional)
+			if g.isStreamed(s.Directives) {
+				collector.SetStreamed(responseName)
+			}
 		case *ast.InlineFragment:
 			typeCondition := s.TypeCondition
 			if typeCondition == "" || typeCondition == typeDef.Name || typeImplements(typeDef, typeCondition) {
-				g.applySelections(typeDef, s.SelectionSet, collector, visited)
+				g.applySelections(typeDef, s.SelectionSet, g.collectorFor(collector, s.Directives), visited, conditional || isConditional(s.Directives))
 			}
 		case *ast.FragmentSpread:
 			frag := g.fragments[s.Name]
@@ -360,16 +971,23 @@ func (g *generator) applySelections(typeDef *ast.Definition, selectionSet ast.Se
 			if visited[frag.Name] {
 				continue
 			}
+			spreadConditional := conditional || isConditional(s.Directives)
+			// Combined spreads keep their own type, unless the fragment's
+			// __typename would differ or the spread may be skipped
+			if g.config.InlineFragmentTypes == inlineFragmentTypesCombine && frag.TypeCondition == typeDef.Name && !spreadConditional {
+				g.collectorFor(collector, s.Directives).AddFragmentSpread(frag.Name)
+				continue
+			}
 			if frag.TypeCondition == typeDef.Name || typeImplements(typeDef, frag.TypeCondition) || frag.TypeCondition == "" {
 				visited[frag.Name] = true
-				g.applySelections(typeDef, frag.SelectionSet, collector, visited)
+				g.applySelections(typeDef, documents.FragmentSelections(frag, s), g.collectorFor(collector, s.Directives), visited, spreadConditional)
 				delete(visited, frag.Name)
 			}
 		}
 	}
 }
 
-func (g *generator) applyUnionSelections(typeDef *ast.Definition, selectionSet ast.SelectionSet, collector *fieldCollector, visited map[string]bool, typeName string) {
+func (g *generator) applyUnionSelections(typeDef *ast.Definition, selectionSet ast.SelectionSet, collector *fieldCollector, visited map[string]bool, typeName string, conditional bool) {
 	for _, sel := range selectionSet {
 		switch s := sel.(type) {
 		case *ast.Field:
@@ -384,10 +1002,13 @@ func (g *generator) applyUnionSelections(typeDef *ast.Definition, selectionSet a
 			if responseName == "" {
 				responseName = s.Name
 			}
-			collector.AddField(responseName, s.Name, fieldDef, fieldDef.Type, s.SelectionSet)
+			collector.AddField(responseName, s.Name, fieldDef, fieldDef.Type, s.SelectionSet, conditional || isConditional(s.Directives))
+			if g.isStreamed(s.Directives) {
+				collector.SetStreamed(responseName)
+			}
 		case *ast.InlineFragment:
 			if s.TypeCondition == "" || s.TypeCondition == typeName || typeImplements(typeDef, s.TypeCondition) {
-				g.applySelections(typeDef, s.SelectionSet, collector, visited)
+				g.applySelections(typeDef, s.SelectionSet, g.collectorFor(collector, s.Directives), visited, conditional || isConditional(s.Directives))
 			}
 		case *ast.FragmentSpread:
 			frag := g.fragments[s.Name]
@@ -397,9 +1018,16 @@ func (g *generator) applyUnionSelections(typeDef *ast.Definition, selectionSet a
 			if visited[frag.Name] {
 				continue
 			}
+			spreadConditional := conditional || isConditional(s.Directives)
+			// As in applySelections, combined spreads on the member type keep
+			// their own type
+			if g.config.InlineFragmentTypes == inlineFragmentTypesCombine && frag.TypeCondition == typeName && !spreadConditional {
+				g.collectorFor(collector, s.Directives).AddFragmentSpread(frag.Name)
+				continue
+			}
 			if frag.TypeCondition == typeName || typeImplements(typeDef, frag.TypeCondition) || frag.TypeCondition == "" {
 				visited[frag.Name] = true
-				g.applySelections(typeDef, frag.SelectionSet, collector, visited)
+				g.applySelections(typeDef, documents.FragmentSelections(frag, s), g.collectorFor(collector, s.Directives), visited, spreadConditional)
 				delete(visited, frag.Name)
 			}
 		}
@@ -435,14 +1063,18 @@ func (g *generator) renderTypeForField(fieldType *ast.Type, selectionSets []ast.
 	case ast.Scalar:
 		return &tsPrimitive{Code: g.scalarOutput(name)}
 	case ast.Enum:
-		return &tsPrimitive{Code: def.Name}
+		code := g.enumReference(def.Name)
+		if g.config.FutureProofEnums {
+			code += " | '%future added value'"
+		}
+		return &tsPrimitive{Code: code}
 	case ast.Union:
 		combined := combineSelectionSets(selectionSets)
 		return g.renderUnionSelection(def, combined)
 	case ast.Object, ast.Interface:
 		combined := combineSelectionSets(selectionSets)
 		if len(combined) == 0 {
-			return &tsObject{Fields: []*tsField{}}
+			return &tsObject{Fields: []*tsField{}, Style: g.config.ObjectStyle}
 		}
 		return g.renderSelection(def.Name, combined, true)
 	default:
@@ -450,11 +1082,21 @@ func (g *generator) renderTypeForField(fieldType *ast.Type, selectionSets []ast.
 	}
 }
 
+// enumReference returns the type used for an enum. With enumsAsConst the enum
+// is declared as a const object, so its values are taken from the object type.
+func (g *generator) enumReference(name string) string {
+	g.ref(name)
+	if g.config.EnumsAsConst {
+		return fmt.Sprintf("(typeof %s)[keyof typeof %s]", name, name)
+	}
+	return name
+}
+
 func (g *generator) scalarOutput(name string) string {
 	if v, ok := g.scalars[name]; ok {
 		return v
 	}
-	return "any"
+	return g.config.DefaultScalarType
 }
 
 func (g *generator) isScalar(name string) bool {
@@ -487,6 +1129,9 @@ func (g *generator) isScalarOutputType(t *ast.Type) bool {
 	return false
 }
 
+// combineSelectionSets concatenates the selection sets of a field selected
+// several times. The fieldCollector rendering the result merges fields with
+// the same response name, so their sub-selections are unioned at every depth.
 func combineSelectionSets(sets []ast.SelectionSet) ast.SelectionSet {
 	if len(sets) == 0 {
 		return nil
@@ -520,6 +1165,29 @@ func findFieldDefinition(def *ast.Definition, name string) *ast.FieldDefinition
 	return nil
 }
 
+// isConditional reports whether a selection may be left out of the response
+// because of @skip or @include. Literal arguments that always keep the
+// selection, like @include(if: true), do not count.
+func isConditional(directives ast.DirectiveList) bool {
+	for _, directive := range directives {
+		var keepValue string
+		switch directive.Name {
+		case "include":
+			keepValue = "true"
+		case "skip":
+			keepValue = "false"
+		default:
+			continue
+		}
+
+		arg := directive.Arguments.ForName("if")
+		if arg == nil || arg.Value == nil || arg.Value.Kind != ast.BooleanValue || arg.Value.Raw != keepValue {
+			return true
+		}
+	}
+	return false
+}
+
 func typeImplements(def *ast.Definition, interfaceName string) bool {
 	if def == nil {
 		return false
@@ -533,10 +1201,14 @@ func typeImplements(def *ast.Definition, interfaceName string) bool {
 }
 
 type fieldCollector struct {
-	immutable   bool
 	order       []string
 	fields      map[string]*collectedField
 	hasTypename bool
+	// spreads are the fragments referenced by type rather than inlined
+	spreads []string
+	// deferred collects the fields of each fragment spread or inline
+	// fragment with @defer, which arrive in a later payload
+	deferred []*fieldCollector
 }
 
 type collectedField struct {
@@ -548,20 +1220,27 @@ type collectedField struct {
 	IsTypename      bool
 	TypenameLiteral string
 	ForceRequired   bool
+	// Conditional is set when every selection of the field uses @skip or @include
+	Conditional bool
+	// DiscriminatorLiteral narrows a union member on a field other than __typename
+	DiscriminatorLiteral string
+	// Streamed is set when the field is a list selected with @stream
+	Streamed bool
 }
 
-func newFieldCollector(immutable bool) *fieldCollector {
+func newFieldCollector() *fieldCollector {
 	return &fieldCollector{
-		immutable: immutable,
-		fields:    make(map[string]*collectedField),
+		fields: make(map[string]*collectedField),
 	}
 }
 
-func (c *fieldCollector) AddField(responseName, graphQLName string, def *ast.FieldDefinition, typ *ast.Type, selection ast.SelectionSet) {
+func (c *fieldCollector) AddField(responseName, graphQLName string, def *ast.FieldDefinition, typ *ast.Type, selection ast.SelectionSet, conditional bool) {
 	if existing, ok := c.fields[responseName]; ok {
 		if selection != nil && len(selection) > 0 {
 			existing.SelectionSets = append(existing.SelectionSets, selection)
 		}
+		// An unconditional selection guarantees the field is present
+		existing.Conditional = existing.Conditional && conditional
 		return
 	}
 
@@ -570,6 +1249,7 @@ func (c *fieldCollector) AddField(responseName, graphQLName string, def *ast.Fie
 		GraphQLName:  graphQLName,
 		Definition:   def,
 		Type:         typ,
+		Conditional:  conditional,
 	}
 	if selection != nil && len(selection) > 0 {
 		field.SelectionSets = append(field.SelectionSets, selection)
@@ -584,6 +1264,36 @@ func (c *fieldCollector) AddField(responseName, graphQLName string, def *ast.Fie
 	c.order = append(c.order, responseName)
 }
 
+// AddFragmentSpread records a fragment whose type is combined with the fields
+func (c *fieldCollector) AddFragmentSpread(name string) {
+	for _, existing := range c.spreads {
+		if existing == name {
+			return
+		}
+	}
+	c.spreads = append(c.spreads, name)
+}
+
+// SetStreamed marks a selected list field as delivered with @stream. Fields
+// that are not lists are left unchanged.
+func (c *fieldCollector) SetStreamed(responseName string) {
+	field, ok := c.fields[responseName]
+	if !ok || field.Type == nil || field.Type.Elem == nil {
+		return
+	}
+	field.Streamed = true
+}
+
+// SetDiscriminator types a selected discriminator field as a literal. Fields
+// that are not selected, or only selected conditionally, are left unchanged.
+func (c *fieldCollector) SetDiscriminator(responseName, literal string) {
+	field, ok := c.fields[responseName]
+	if !ok || field.IsTypename || field.Conditional {
+		return
+	}
+	field.DiscriminatorLiteral = literal
+}
+
 func (c *fieldCollector) AddTypenameLiteral(typeName string, required bool) {
 	if c.hasTypename {
 		return
@@ -630,7 +1340,7 @@ func (c *fieldCollector) Finalize(g *generator, parentDef *ast.Definition, addTy
 		if cf == nil {
 			continue
 		}
-		field := g.buildTsField(cf)
+		field := g.buildTsField(cf, parentDef)
 		if cf.IsTypename || g.isScalarOutputType(cf.Type) {
 			scalarFields = append(scalarFields, field)
 		} else {
@@ -640,7 +1350,10 @@ func (c *fieldCollector) Finalize(g *generator, parentDef *ast.Definition, addTy
 	return append(scalarFields, objectFields...)
 }
 
-func (g *generator) buildTsField(cf *collectedField) *tsField {
+// buildTsField builds every field of a result type, including those of union
+// branches, list elements and fragments, so immutableTypes makes the whole
+// result tree readonly; lists are made readonly in renderTypeForField.
+func (g *generator) buildTsField(cf *collectedField, parentDef *ast.Definition) *tsField {
 	readonly := g.config.ImmutableTypes
 
 	if cf.IsTypename {
@@ -659,24 +1372,44 @@ func (g *generator) buildTsField(cf *collectedField) *tsField {
 		}
 	}
 
+	if cf.DiscriminatorLiteral != "" {
+		return &tsField{
+			Name:     cf.ResponseName,
+			Readonly: readonly,
+			Type:     &tsPrimitive{Code: fmt.Sprintf("'%s'", cf.DiscriminatorLiteral)},
+		}
+	}
+
 	typ := cf.Type
 	selectionSets := cf.SelectionSets
 	var tsType tsType
 	if typ == nil {
 		tsType = &tsPrimitive{Code: "any"}
+	} else if parentDef != nil && typ.Elem == nil && base.IsBrandedIDField(g.config.BrandedIdTypes, parentDef.Name, cf.GraphQLName, typ.NamedType) {
+		tsType = &tsPrimitive{Code: base.BrandedIDType(parentDef.Name, g.scalarOutput("ID"))}
 	} else {
 		tsType = g.renderTypeForField(typ, selectionSets)
 	}
+	if list, ok := tsType.(*tsArray); ok && cf.Streamed {
+		list.Wrapper = g.ref("Streamed")
+	}
 
-	optional := typ != nil && !typ.NonNull && !g.config.AvoidOptionals
+	// Fields guarded by @skip/@include may be absent regardless of nullability
+	optional := (typ != nil && !typ.NonNull && !g.config.AvoidOptionals.Field && !g.config.ExplicitNulls) || cf.Conditional
 	nullable := typ != nil && !typ.NonNull
 
+	description := ""
+	if g.config.Descriptions && cf.Definition != nil {
+		description = cf.Definition.Description
+	}
+
 	return &tsField{
-		Name:     cf.ResponseName,
-		Optional: optional,
-		Nullable: nullable,
-		Readonly: readonly,
-		Type:     tsType,
+		Name:        cf.ResponseName,
+		Optional:    optional,
+		Nullable:    nullable,
+		Readonly:    readonly,
+		Type:        tsType,
+		Description: description,
 	}
 }
 
@@ -703,9 +1436,17 @@ func (n *tsNullable) Render(indent string) string {
 type tsArray struct {
 	Elem      tsType
 	Immutable bool
+	// Wrapper names a generic type the list is wrapped in, e.g. Streamed
+	// for a list delivered with @stream
+	Wrapper string
 }
 
 func (a *tsArray) Render(indent string) string {
+	if a.Wrapper != "" {
+		unwrapped := *a
+		unwrapped.Wrapper = ""
+		return a.Wrapper + "<" + unwrapped.Render(indent) + ">"
+	}
 	listType := "Array"
 	if a.Immutable {
 		listType = "ReadonlyArray"
@@ -744,31 +1485,77 @@ func (u *tsUnion) renderOptions(indent string) string {
 	return sb.String()
 }
 
+type tsIntersection struct {
+	Parts []tsType
+}
+
+func (i *tsIntersection) Render(indent string) string {
+	parts := make([]string, len(i.Parts))
+	for idx, part := range i.Parts {
+		parts[idx] = part.Render(indent)
+	}
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return "( " + strings.Join(parts, " & ") + " )"
+}
+
 type tsObject struct {
 	Fields []*tsField
+	Style  memberStyle
 }
 
 func (o *tsObject) Render(indent string) string {
 	if len(o.Fields) == 0 {
 		return "{}"
 	}
-	parts := make([]string, len(o.Fields))
+
+	// Documented fields need their own lines for the JSDoc comments
+	for _, field := range o.Fields {
+		if field.Description != "" {
+			return o.renderMultiline(indent)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("{ ")
 	for i, field := range o.Fields {
-		parts[i] = field.Render(indent)
+		if i > 0 {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(field.Render(indent) + o.Style.after(i, len(o.Fields)))
 	}
-	return "{ " + strings.Join(parts, ", ") + " }"
+	sb.WriteString(" }")
+	return sb.String()
+}
+
+func (o *tsObject) renderMultiline(indent string) string {
+	fieldIndent := indent + "  "
+	var sb strings.Builder
+	sb.WriteString("{\n")
+	for i, field := range o.Fields {
+		sb.WriteString(fieldIndent + field.Render(fieldIndent) + o.Style.after(i, len(o.Fields)))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(indent + "}")
+	return sb.String()
 }
 
 type tsField struct {
-	Name     string
-	Optional bool
-	Nullable bool
-	Readonly bool
-	Type     tsType
+	Name        string
+	Optional    bool
+	Nullable    bool
+	Readonly    bool
+	Type        tsType
+	Description string
 }
 
 func (f *tsField) Render(indent string) string {
 	var sb strings.Builder
+	if f.Description != "" {
+		sb.WriteString(renderJSDoc(f.Description, indent))
+		sb.WriteString("\n" + indent)
+	}
 	if f.Readonly {
 		sb.WriteString("readonly ")
 	}
@@ -783,3 +1570,24 @@ func (f *tsField) Render(indent string) string {
 	}
 	return sb.String()
 }
+
+// renderJSDoc renders a description as a JSDoc comment. Continuation lines
+// are indented with indent, and "*/" is escaped so it cannot end the comment.
+func renderJSDoc(description string, indent string) string {
+	description = strings.ReplaceAll(strings.TrimSpace(description), "*/", "*\\/")
+	lines := strings.Split(strings.ReplaceAll(description, "\r\n", "\n"), "\n")
+	if len(lines) == 1 {
+		return "/** " + lines[0] + " */"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("/**")
+	for _, line := range lines {
+		sb.WriteString("\n" + indent + " *")
+		if line = strings.TrimRight(line, " \t"); line != "" {
+			sb.WriteString(" " + line)
+		}
+	}
+	sb.WriteString("\n" + indent + " */")
+	return sb.String()
+}
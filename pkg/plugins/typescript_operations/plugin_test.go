@@ -4,10 +4,20 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/jzeiders/graphql-go-gen/pkg/documents"
+	"github.com/jzeiders/graphql-go-gen/pkg/plugin"
 	"github.com/jzeiders/graphql-go-gen/pkg/plugins/testutil"
+	"github.com/jzeiders/graphql-go-gen/pkg/plugins/typescript"
 	"github.com/jzeiders/graphql-go-gen/pkg/plugins/typescript_operations"
+	"github.com/jzeiders/graphql-go-gen/pkg/schema"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
 )
 
 func TestTypeScriptOperationsPlugin_Parity(t *testing.T) {
@@ -92,3 +102,1416 @@ func TestTypeScriptOperationsPlugin_Parity(t *testing.T) {
 		})
 	}
 }
+
+func TestTypeScriptOperationsPlugin_VariablesAsInterface(t *testing.T) {
+	generate := func(t *testing.T, config map[string]interface{}) string {
+		t.Helper()
+		req := testutil.CreateTestRequest(t, config)
+		resp, err := typescript_operations.New().Generate(context.Background(), req)
+		if err != nil {
+			t.Fatalf("generate failed: %v", err)
+		}
+		return string(resp.Files[req.OutputPath])
+	}
+
+	t.Run("variables", func(t *testing.T) {
+		got := generate(t, map[string]interface{}{"variablesAsInterface": true})
+
+		for _, want := range []string{
+			"export interface GetUserQueryVariables {\n  id: Scalars['ID']['input'];\n}\n",
+			"export interface GetUsersQueryVariables {\n  first?: InputMaybe<Scalars['Int']['input']>;",
+			"export interface OnUserCreatedSubscriptionVariables { [key: string]: never; }\n",
+			"export type GetUserQuery = { __typename?: 'Query'",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("expected output to contain %q\ngot:\n%s", want, got)
+			}
+		}
+		if strings.Contains(got, "Exact<") {
+			t.Errorf("interfaces must not use the Exact<> wrapper\ngot:\n%s", got)
+		}
+	})
+
+	t.Run("results", func(t *testing.T) {
+		got := generate(t, map[string]interface{}{
+			"variablesAsInterface": true,
+			"resultsAsInterface":   true,
+		})
+
+		for _, want := range []string{
+			"export interface GetUserQuery { __typename?: 'Query', user?: { __typename?: 'User'",
+			"export interface CreateUserMutation { __typename?: 'Mutation'",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("expected output to contain %q\ngot:\n%s", want, got)
+			}
+		}
+	})
+}
+
+func TestTypeScriptOperationsPlugin_NoOperationsPlaceholder(t *testing.T) {
+	cases := []struct {
+		name   string
+		config map[string]interface{}
+		want   string
+	}{
+		{
+			name:   "default",
+			config: map[string]interface{}{},
+			want:   "// No GraphQL operations found\n",
+		},
+		{
+			name:   "custom",
+			config: map[string]interface{}{"noOperationsPlaceholder": "export {};"},
+			want:   "export {};\n",
+		},
+		{
+			name:   "empty",
+			config: map[string]interface{}{"noOperationsPlaceholder": ""},
+			want:   "",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			req := testutil.CreateTestRequest(t, tt.config)
+			req.Documents = nil
+
+			resp, err := typescript_operations.New().Generate(context.Background(), req)
+			if err != nil {
+				t.Fatalf("generate failed: %v", err)
+			}
+
+			if got := string(resp.Files[req.OutputPath]); got != tt.want {
+				t.Fatalf("placeholder mismatch\nwant: %q\ngot:  %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// generateForDocument runs the plugin against a single inline document
+func generateForDocument(t *testing.T, config map[string]interface{}, query string) string {
+	t.Helper()
+
+	req := testutil.CreateTestRequest(t, config)
+	queryDoc, gqlErr := gqlparser.LoadQuery(req.Schema.Raw(), query)
+	if gqlErr != nil {
+		t.Fatalf("failed to parse document: %v", gqlErr)
+	}
+	req.Documents = []*documents.Document{{FilePath: "inline.graphql", Content: query, AST: queryDoc}}
+
+	resp, err := typescript_operations.New().Generate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	return string(resp.Files[req.OutputPath])
+}
+
+func TestTypeScriptOperationsPlugin_ConditionalFields(t *testing.T) {
+	got := generateForDocument(t, nil, `
+		query GetUserConditional($id: ID!, $withEmail: Boolean!, $skipPosts: Boolean!) {
+			user(id: $id) {
+				id
+				email @include(if: $withEmail)
+				posts @skip(if: $skipPosts) {
+					id
+				}
+				name @include(if: true)
+				... on User @include(if: $withEmail) {
+					role
+				}
+			}
+		}
+	`)
+
+	for _, want := range []string{
+		"id: string",
+		"email?: string",
+		"name: string",
+		"role?: UserRole",
+		"posts?: Array<{ __typename?: 'Post', id: string }>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q\ngot:\n%s", want, got)
+		}
+	}
+}
+
+func TestTypeScriptOperationsPlugin_ExplicitNulls(t *testing.T) {
+	query := `
+		query GetUserProfile($id: ID!, $withBio: Boolean!, $published: Boolean) {
+			posts(published: $published) {
+				id
+			}
+			user(id: $id) {
+				age
+				profile {
+					avatar
+					bio @include(if: $withBio)
+				}
+			}
+		}
+	`
+	got := generateForDocument(t, map[string]interface{}{"explicitNulls": true}, query)
+
+	for _, want := range []string{
+		// Nullable fields are always present in the response
+		"user: { __typename?: 'User', age: number | null, profile: { __typename?: 'Profile', avatar: string | null",
+		// Conditional fields may still be absent
+		"bio?: string | null",
+		// Variables are unaffected
+		"published?: InputMaybe<Scalars['Boolean']['input']>;",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q\ngot:\n%s", want, got)
+		}
+	}
+
+	got = generateForDocument(t, nil, query)
+	if !strings.Contains(got, "age?: number | null, profile?:") {
+		t.Errorf("expected nullable fields to be optional by default\ngot:\n%s", got)
+	}
+}
+
+func TestTypeScriptOperationsPlugin_EnumsAsConst(t *testing.T) {
+	query := `query GetUserRole($id: ID!) { user(id: $id) { role status } }`
+
+	// The operation types read the values of the const objects the
+	// typescript plugin declares, so check the output of both together
+	config := map[string]interface{}{"enumsAsConst": true}
+	schemaTypes, err := typescript.New().Generate(context.Background(), testutil.CreateTestRequest(t, config))
+	if err != nil {
+		t.Fatalf("typescript generate failed: %v", err)
+	}
+	got := string(schemaTypes.Files["test.ts"]) + generateForDocument(t, config, query)
+
+	references := regexp.MustCompile(`\(typeof (\w+)\)\[keyof typeof (\w+)\]`).FindAllStringSubmatch(got, -1)
+	if len(references) == 0 {
+		t.Fatalf("expected enums to reference const objects\ngot:\n%s", got)
+	}
+	for _, ref := range references {
+		if !strings.Contains(got, "export const "+ref[1]+" = {") {
+			t.Errorf("%s references const %s, which is not declared\ngot:\n%s", ref[0], ref[1], got)
+		}
+	}
+	for _, want := range []string{
+		"export type UserRole = (typeof UserRole)[keyof typeof UserRole];",
+		"role: (typeof UserRole)[keyof typeof UserRole]",
+		"status: (typeof Status)[keyof typeof Status]",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q\ngot:\n%s", want, got)
+		}
+	}
+
+	got = generateForDocument(t, map[string]interface{}{"enumsAsConst": true, "futureProofEnums": true}, query)
+	want := "role: (typeof UserRole)[keyof typeof UserRole] | '%future added value'"
+	if !strings.Contains(got, want) {
+		t.Errorf("expected output to contain %q\ngot:\n%s", want, got)
+	}
+
+	got = generateForDocument(t, nil, query)
+	if !strings.Contains(got, "role: UserRole,") {
+		t.Errorf("expected enums to reference the enum type by default\ngot:\n%s", got)
+	}
+}
+
+func TestTypeScriptOperationsPlugin_InlineFragmentTypesCombine(t *testing.T) {
+	req := testutil.CreateTestRequest(t, map[string]interface{}{"inlineFragmentTypes": "combine"})
+	resp, err := typescript_operations.New().Generate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	got := string(resp.Files[req.OutputPath])
+
+	for _, want := range []string{
+		"post?: ( { __typename?: 'Post', comments: Array<",
+		"author: ( { __typename?: 'User' } & UserFieldsFragment ) }> } & PostFieldsFragment ) | null",
+		"export type PostFieldsFragment = { __typename?: 'Post', id: string, title: string, content: string, published: boolean, tags: Array<string>, author: ( { __typename?: 'User' } & UserFieldsFragment ) };",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q\ngot:\n%s", want, got)
+		}
+	}
+
+	// Spreads that may be skipped are still inlined so their fields stay optional
+	got = generateForDocument(t, map[string]interface{}{"inlineFragmentTypes": "combine"}, `
+		query GetUserMaybeFields($id: ID!, $full: Boolean!) {
+			user(id: $id) {
+				...UserFields @include(if: $full)
+			}
+		}
+		fragment UserFields on User { id name }
+	`)
+	if !strings.Contains(got, "user?: { __typename?: 'User', id?: string, name?: string } | null") {
+		t.Errorf("expected conditional spread to be inlined\ngot:\n%s", got)
+	}
+
+	// Spreads inside union and interface branches are combined per branch
+	got = generateForDocument(t, map[string]interface{}{"inlineFragmentTypes": "combine"}, `
+		query SearchWithFragments($q: String!, $id: ID!) {
+			search(query: $q) {
+				... on User { ...UserBits }
+			}
+			node(id: $id) {
+				id
+				... on Post { ...PostBits }
+			}
+		}
+		fragment UserBits on User { name }
+		fragment PostBits on Post { title }
+	`)
+	for _, want := range []string{
+		"( { __typename: 'User' } & UserBitsFragment )",
+		"( { __typename: 'Post', id: string } & PostBitsFragment )",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q\ngot:\n%s", want, got)
+		}
+	}
+
+	// Spreads placed directly on the union are combined in their member's branch
+	got = generateForDocument(t, map[string]interface{}{"inlineFragmentTypes": "combine"}, `
+		query SearchWithDirectSpread($q: String!) {
+			search(query: $q) {
+				...UserBits
+			}
+		}
+		fragment UserBits on User { name }
+	`)
+	if !strings.Contains(got, "( { __typename: 'User' } & UserBitsFragment )") {
+		t.Errorf("expected the direct spread to be combined in the User branch\ngot:\n%s", got)
+	}
+}
+
+func TestTypeScriptOperationsPlugin_NamingConvention(t *testing.T) {
+	query := `
+		query get_user_v2($id: ID!) { user(id: $id) { ...user_bits } }
+		fragment user_bits on User { id }
+	`
+
+	tests := []struct {
+		name       string
+		convention interface{}
+		want       []string
+	}{
+		{
+			name: "default",
+			want: []string{"export type Get_user_v2QueryVariables =", "export type Get_user_v2Query =", "export type User_bitsFragment =", "& User_bitsFragment ) | null"},
+		},
+		{
+			name:       "keep",
+			convention: "keep",
+			want:       []string{"export type get_user_v2QueryVariables =", "export type get_user_v2Query =", "export type user_bitsFragment =", "& user_bitsFragment ) | null"},
+		},
+		{
+			name:       "transform underscore",
+			convention: map[string]interface{}{"typeNames": "pascal-case", "transformUnderscore": true},
+			want:       []string{"export type GetUserV2QueryVariables =", "export type GetUserV2Query =", "export type UserBitsFragment =", "& UserBitsFragment ) | null"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := map[string]interface{}{"inlineFragmentTypes": "combine"}
+			if tt.convention != nil {
+				config["namingConvention"] = tt.convention
+			}
+			got := generateForDocument(t, config, query)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("expected output to contain %q\ngot:\n%s", want, got)
+				}
+			}
+		})
+	}
+
+	req := testutil.CreateTestRequest(t, map[string]interface{}{"namingConvention": "snake-case"})
+	if _, err := typescript_operations.New().Generate(context.Background(), req); err == nil {
+		t.Error("expected an error for an unsupported naming convention")
+	}
+}
+
+func TestTypeScriptOperationsPlugin_CustomScalars(t *testing.T) {
+	query := `
+		mutation CreateUserScalars($input: CreateUserInput!) {
+			createUser(input: $input) {
+				id
+				createdAt
+			}
+		}
+	`
+
+	t.Run("request scalar map", func(t *testing.T) {
+		got := generateForDocument(t, nil, query)
+		if !strings.Contains(got, "createdAt: string") {
+			t.Errorf("expected Date to use the request scalar map\ngot:\n%s", got)
+		}
+	})
+
+	t.Run("scalars config overrides scalar map", func(t *testing.T) {
+		got := generateForDocument(t, map[string]interface{}{
+			"scalars": map[string]interface{}{"Date": "Date"},
+		}, query)
+		if !strings.Contains(got, "createdAt: Date") {
+			t.Errorf("expected Date to use the scalars config\ngot:\n%s", got)
+		}
+	})
+
+	t.Run("default scalar type", func(t *testing.T) {
+		req := testutil.CreateTestRequest(t, map[string]interface{}{"defaultScalarType": "unknown"})
+		req.ScalarMap = nil
+		queryDoc, gqlErr := gqlparser.LoadQuery(req.Schema.Raw(), query)
+		if gqlErr != nil {
+			t.Fatalf("failed to parse document: %v", gqlErr)
+		}
+		req.Documents = []*documents.Document{{FilePath: "inline.graphql", Content: query, AST: queryDoc}}
+
+		resp, err := typescript_operations.New().Generate(context.Background(), req)
+		if err != nil {
+			t.Fatalf("generate failed: %v", err)
+		}
+		if got := string(resp.Files[req.OutputPath]); !strings.Contains(got, "createdAt: unknown") {
+			t.Errorf("expected unmapped scalar to use defaultScalarType\ngot:\n%s", got)
+		}
+	})
+}
+
+const animalSchema = `
+	enum AnimalKind { CAT CANINE }
+
+	interface Pet {
+		name: String!
+	}
+
+	type Cat implements Pet {
+		name: String!
+		kind: AnimalKind!
+		meows: Boolean!
+	}
+
+	type Dog implements Pet {
+		name: String!
+		kind: AnimalKind!
+		barks: Boolean!
+	}
+
+	type Bird implements Pet {
+		name: String!
+		kind: AnimalKind!
+		sings: Boolean!
+	}
+
+	union Animal = Cat | Dog | Bird
+
+	type Query {
+		animals: [Animal!]!
+		pets: [Pet!]!
+	}
+`
+
+func TestTypeScriptOperationsPlugin_UnionDiscriminator(t *testing.T) {
+	rawSchema, err := gqlparser.LoadSchema(&ast.Source{Name: "animals.graphql", Input: animalSchema})
+	if err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+	query := `
+		query GetAnimals {
+			animals {
+				... on Cat { kind meows }
+				... on Dog { kind barks }
+				... on Bird { kind sings }
+			}
+		}
+	`
+	queryDoc, gqlErr := gqlparser.LoadQuery(rawSchema, query)
+	if gqlErr != nil {
+		t.Fatalf("failed to parse document: %v", gqlErr)
+	}
+
+	req := &plugin.GenerateRequest{
+		Schema:     schema.NewSchema(rawSchema, "animals.graphql"),
+		Documents:  []*documents.Document{{FilePath: "animals.graphql", Content: query, AST: queryDoc}},
+		OutputPath: "animals.ts",
+		Config: map[string]interface{}{
+			"unionDiscriminator": "kind",
+			"unionDiscriminatorValues": map[string]interface{}{
+				"Dog": "CANINE",
+			},
+		},
+	}
+
+	resp, err := typescript_operations.New().Generate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	got := string(resp.Files[req.OutputPath])
+
+	for _, want := range []string{
+		// Enum value matching the type name
+		"{ __typename: 'Cat', kind: 'CAT', meows: boolean }",
+		// Explicit value
+		"{ __typename: 'Dog', kind: 'CANINE', barks: boolean }",
+		// Falls back to the type name
+		"{ __typename: 'Bird', kind: 'Bird', sings: boolean }",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q\ngot:\n%s", want, got)
+		}
+	}
+}
+
+const documentedSchema = `
+type Query {
+	"The signed-in user"
+	viewer: User
+}
+
+type User {
+	id: ID!
+	"""
+	Display name.
+	Falls back to the handle when unset.
+	"""
+	name: String
+	"Never contains */ in practice"
+	bio: String
+}
+`
+
+func TestTypeScriptOperationsPlugin_Descriptions(t *testing.T) {
+	rawSchema, err := gqlparser.LoadSchema(&ast.Source{Name: "documented.graphql", Input: documentedSchema})
+	if err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+	query := `query GetViewer { viewer { id name bio } }`
+	queryDoc, gqlErr := gqlparser.LoadQuery(rawSchema, query)
+	if gqlErr != nil {
+		t.Fatalf("failed to parse document: %v", gqlErr)
+	}
+
+	generate := func(config map[string]interface{}) string {
+		req := &plugin.GenerateRequest{
+			Schema:     schema.NewSchema(rawSchema, "documented.graphql"),
+			Documents:  []*documents.Document{{FilePath: "viewer.graphql", Content: query, AST: queryDoc}},
+			OutputPath: "viewer.ts",
+			Config:     config,
+		}
+		resp, err := typescript_operations.New().Generate(context.Background(), req)
+		if err != nil {
+			t.Fatalf("generate failed: %v", err)
+		}
+		return string(resp.Files[req.OutputPath])
+	}
+
+	got := generate(nil)
+	for _, want := range []string{
+		"  /** The signed-in user */\n  viewer?: {\n",
+		"    /**\n     * Display name.\n     * Falls back to the handle when unset.\n     */\n    name?: string | null,\n",
+		`/** Never contains *\/ in practice */`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q\ngot:\n%s", want, got)
+		}
+	}
+
+	got = generate(map[string]interface{}{"descriptions": false})
+	if strings.Contains(got, "/**") {
+		t.Errorf("expected no JSDoc comments with descriptions disabled\ngot:\n%s", got)
+	}
+}
+
+func TestTypeScriptOperationsPlugin_InterfaceInlineFragments(t *testing.T) {
+	rawSchema, err := gqlparser.LoadSchema(&ast.Source{Name: "animals.graphql", Input: animalSchema})
+	if err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+
+	generate := func(query string) string {
+		queryDoc, gqlErr := gqlparser.LoadQuery(rawSchema, query)
+		if gqlErr != nil {
+			t.Fatalf("failed to parse document: %v", gqlErr)
+		}
+		req := &plugin.GenerateRequest{
+			Schema:     schema.NewSchema(rawSchema, "animals.graphql"),
+			Documents:  []*documents.Document{{FilePath: "pets.graphql", Content: query, AST: queryDoc}},
+			OutputPath: "pets.ts",
+		}
+		resp, err := typescript_operations.New().Generate(context.Background(), req)
+		if err != nil {
+			t.Fatalf("generate failed: %v", err)
+		}
+		return string(resp.Files[req.OutputPath])
+	}
+
+	got := generate(`
+		query GetPets {
+			pets {
+				name
+				... on Cat { meows }
+				...DogFields
+			}
+		}
+		fragment DogFields on Dog { barks }
+	`)
+	for _, want := range []string{
+		"| { __typename: 'Bird', name: string }",
+		"| { __typename: 'Cat', name: string, meows: boolean }",
+		"| { __typename: 'Dog', name: string, barks: boolean }",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q\ngot:\n%s", want, got)
+		}
+	}
+
+	// Without fragments on concrete types the interface shape is kept
+	got = generate(`query GetPetNames { pets { name } }`)
+	if !strings.Contains(got, "pets: Array<{ __typename?: 'Pet', name: string }>") {
+		t.Errorf("expected a single interface shape\ngot:\n%s", got)
+	}
+}
+
+func TestTypeScriptOperationsPlugin_NestedListVariables(t *testing.T) {
+	rawSchema, err := gqlparser.LoadSchema(&ast.Source{Name: "matrix.graphql", Input: `
+		type Query { cells(grid: [[Int]], strict: [[Int!]!], ids: [Int!]!, rows: [[Int]!]): Int }
+	`})
+	if err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+	query := `
+		query GetCells($grid: [[Int]], $strict: [[Int!]!], $ids: [Int!]!, $rows: [[Int]!]) {
+			cells(grid: $grid, strict: $strict, ids: $ids, rows: $rows)
+		}
+	`
+	queryDoc, gqlErr := gqlparser.LoadQuery(rawSchema, query)
+	if gqlErr != nil {
+		t.Fatalf("failed to parse document: %v", gqlErr)
+	}
+
+	generate := func(config map[string]interface{}) string {
+		req := &plugin.GenerateRequest{
+			Schema:     schema.NewSchema(rawSchema, "matrix.graphql"),
+			Documents:  []*documents.Document{{FilePath: "matrix.graphql", Content: query, AST: queryDoc}},
+			OutputPath: "matrix.ts",
+			Config:     config,
+		}
+		resp, err := typescript_operations.New().Generate(context.Background(), req)
+		if err != nil {
+			t.Fatalf("generate failed: %v", err)
+		}
+		return string(resp.Files[req.OutputPath])
+	}
+
+	got := generate(map[string]interface{}{})
+	for _, want := range []string{
+		"grid?: InputMaybe<Array<InputMaybe<Array<InputMaybe<Scalars['Int']['input']>>>>>;",
+		"strict?: InputMaybe<Array<Array<Scalars['Int']['input']>>>;",
+		"ids: Array<Scalars['Int']['input']>;",
+		"rows?: InputMaybe<Array<Array<InputMaybe<Scalars['Int']['input']>>>>;",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q\ngot:\n%s", want, got)
+		}
+	}
+
+	got = generate(map[string]interface{}{"immutableTypes": true})
+	want := "grid?: InputMaybe<ReadonlyArray<InputMaybe<ReadonlyArray<InputMaybe<Scalars['Int']['input']>>>>>;"
+	if !strings.Contains(got, want) {
+		t.Errorf("expected output to contain %q\ngot:\n%s", want, got)
+	}
+}
+
+func TestTypeScriptOperationsPlugin_MergesOverlappingFragmentSelections(t *testing.T) {
+	query := `
+		query GetUserProfile($id: ID!) {
+			user(id: $id) {
+				...UserAvatar
+				...UserBio
+				profile { website }
+				posts { ...PostAuthorBio }
+				posts { ...PostAuthorAvatar }
+			}
+		}
+		query SearchProfiles {
+			search(query: "ada") {
+				... on User { profile { bio } }
+				...UserAvatar
+			}
+		}
+		fragment UserAvatar on User { profile { avatar user { name } } }
+		fragment UserBio on User { profile { bio user { email } } }
+		fragment PostAuthorBio on Post { author { profile { bio } } }
+		fragment PostAuthorAvatar on Post { author { profile { avatar } } }
+	`
+	got := generateForDocument(t, nil, query)
+
+	for _, want := range []string{
+		// The same nested field selected by several fragments holds the union
+		// of their sub-selections, at every depth
+		"profile?: { __typename?: 'Profile', avatar?: string | null, bio?: string | null, website?: string | null, user: { __typename?: 'User', name: string, email: string } } | null",
+		"posts: Array<{ __typename?: 'Post', author: { __typename?: 'User', profile?: { __typename?: 'Profile', bio?: string | null, avatar?: string | null } | null } }>",
+		// Union members merge inline fragments and spreads
+		"| { __typename: 'User', profile?: { __typename?: 'Profile', bio?: string | null, avatar?: string | null, user: { __typename?: 'User', name: string } } | null }",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q\ngot:\n%s", want, got)
+		}
+	}
+
+	// The merge does not depend on the order of the spreads
+	reordered := strings.Replace(query, "...UserAvatar\n\t\t\t\t...UserBio", "...UserBio\n\t\t\t\t...UserAvatar", 1)
+	if reordered == query {
+		t.Fatal("expected the spreads to be reordered")
+	}
+	got = generateForDocument(t, nil, reordered)
+	want := "profile?: { __typename?: 'Profile', bio?: string | null, avatar?: string | null, website?: string | null, user: { __typename?: 'User', email: string, name: string } } | null"
+	if !strings.Contains(got, want) {
+		t.Errorf("expected output to contain %q\ngot:\n%s", want, got)
+	}
+}
+
+func TestTypeScriptOperationsPlugin_BrandedIdTypes(t *testing.T) {
+	rawSchema, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+		type User { id: ID! bestFriend: User posts: [Post!]! }
+		type Post { id: ID! authorId: ID! }
+		type Query { user(id: ID!): User }
+	`})
+	if err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+	query := `
+		query GetUser($id: ID!) {
+			user(id: $id) { id bestFriend { id } posts { id authorId } }
+		}
+	`
+	queryDoc, gqlErr := gqlparser.LoadQuery(rawSchema, query)
+	if gqlErr != nil {
+		t.Fatalf("failed to parse document: %v", gqlErr)
+	}
+
+	req := &plugin.GenerateRequest{
+		Schema:     schema.NewSchema(rawSchema, "schema.graphql"),
+		Documents:  []*documents.Document{{FilePath: "user.graphql", Content: query, AST: queryDoc}},
+		OutputPath: "user.ts",
+		Config:     map[string]interface{}{"brandedIdTypes": []string{"User", "Post"}},
+	}
+	resp, err := typescript_operations.New().Generate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	output := string(resp.Files[req.OutputPath])
+
+	userID := "id: string & { readonly __brand: 'UserId' }"
+	for _, want := range []string{
+		userID,
+		"id: string & { readonly __brand: 'PostId' }",
+		// Only the id field of a branded type is branded
+		"authorId: string }",
+		// Variables take plain IDs
+		"id: Scalars['ID']['input'];",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q\ngot:\n%s", want, output)
+		}
+	}
+	if got := strings.Count(output, userID); got != 2 {
+		t.Errorf("expected the user and best friend ids to be branded, got %d\n%s", got, output)
+	}
+}
+
+func TestTypeScriptOperationsPlugin_StatementStyle(t *testing.T) {
+	rawSchema, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+		type User { id: ID! name: String! }
+		type Query { user(id: ID!, name: String): User viewer: User }
+	`})
+	if err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+	query := `
+		query GetUser($id: ID!, $name: String) { user(id: $id, name: $name) { id name } }
+		query GetViewer { viewer { id } }
+	`
+	queryDoc, gqlErr := gqlparser.LoadQuery(rawSchema, query)
+	if gqlErr != nil {
+		t.Fatalf("failed to parse document: %v", gqlErr)
+	}
+
+	generate := func(t *testing.T, config map[string]interface{}) (string, error) {
+		t.Helper()
+		req := &plugin.GenerateRequest{
+			Schema:     schema.NewSchema(rawSchema, "schema.graphql"),
+			Documents:  []*documents.Document{{FilePath: "user.graphql", Content: query, AST: queryDoc}},
+			OutputPath: "user.ts",
+			Config:     config,
+		}
+		resp, err := typescript_operations.New().Generate(context.Background(), req)
+		if err != nil {
+			return "", err
+		}
+		return string(resp.Files[req.OutputPath]), nil
+	}
+
+	tests := []struct {
+		name  string
+		style map[string]interface{}
+		want  []string
+	}{
+		{
+			name: "default",
+			want: []string{
+				"  id: Scalars['ID']['input'];\n  name?: InputMaybe<Scalars['String']['input']>;\n}>",
+				"Exact<{ [key: string]: never; }>",
+				"{ __typename?: 'User', id: string, name: string }",
+			},
+		},
+		{
+			name:  "semicolons",
+			style: map[string]interface{}{"separator": "semicolon"},
+			want: []string{
+				"  id: Scalars['ID']['input'];\n  name?: InputMaybe<Scalars['String']['input']>\n}>",
+				"Exact<{ [key: string]: never }>",
+				"{ __typename?: 'User'; id: string; name: string }",
+			},
+		},
+		{
+			name:  "semicolons trailing",
+			style: map[string]interface{}{"separator": "semicolon", "trailing": true},
+			want: []string{
+				"  id: Scalars['ID']['input'];\n  name?: InputMaybe<Scalars['String']['input']>;\n}>",
+				"Exact<{ [key: string]: never; }>",
+				"{ __typename?: 'User'; id: string; name: string; }",
+			},
+		},
+		{
+			name:  "commas",
+			style: map[string]interface{}{"separator": "comma"},
+			want: []string{
+				"  id: Scalars['ID']['input'],\n  name?: InputMaybe<Scalars['String']['input']>\n}>",
+				"Exact<{ [key: string]: never }>",
+				"{ __typename?: 'User', id: string, name: string }",
+			},
+		},
+		{
+			name:  "commas trailing",
+			style: map[string]interface{}{"separator": "comma", "trailing": true},
+			want: []string{
+				"  id: Scalars['ID']['input'],\n  name?: InputMaybe<Scalars['String']['input']>,\n}>",
+				"Exact<{ [key: string]: never, }>",
+				"{ __typename?: 'User', id: string, name: string, }",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := map[string]interface{}{}
+			if tt.style != nil {
+				config["statementStyle"] = tt.style
+			}
+			output, err := generate(t, config)
+			if err != nil {
+				t.Fatalf("generate failed: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(output, want) {
+					t.Errorf("expected output to contain %q\ngot:\n%s", want, output)
+				}
+			}
+		})
+	}
+
+	t.Run("invalid separator", func(t *testing.T) {
+		_, err := generate(t, map[string]interface{}{"statementStyle": map[string]interface{}{"separator": "newline"}})
+		if err == nil || !strings.Contains(err.Error(), "statementStyle.separator") {
+			t.Errorf("expected a statementStyle.separator error, got %v", err)
+		}
+	})
+
+	t.Run("not a map", func(t *testing.T) {
+		_, err := generate(t, map[string]interface{}{"statementStyle": "commas"})
+		if err == nil || !strings.Contains(err.Error(), "statementStyle.separator") || !strings.Contains(err.Error(), "commas") {
+			t.Errorf("expected a statementStyle.separator error, got %v", err)
+		}
+	})
+}
+
+func TestTypeScriptOperationsPlugin_GranularAvoidOptionals(t *testing.T) {
+	rawSchema, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+		type User { id: ID! name: String }
+		type Query { users(name: String, limit: Int): [User!]! }
+	`})
+	if err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+	query := `query GetUsers($name: String, $limit: Int = 10) { users(name: $name, limit: $limit) { id name } }`
+	queryDoc, gqlErr := gqlparser.LoadQuery(rawSchema, query)
+	if gqlErr != nil {
+		t.Fatalf("failed to parse document: %v", gqlErr)
+	}
+
+	tests := []struct {
+		name           string
+		avoidOptionals interface{}
+		want           []string
+	}{
+		{
+			name:           "fields only",
+			avoidOptionals: map[string]interface{}{"inputValue": false, "field": true},
+			want: []string{
+				"name?: InputMaybe<Scalars['String']['input']>;",
+				"limit?: InputMaybe<Scalars['Int']['input']>;",
+				"name: string | null",
+			},
+		},
+		{
+			name:           "input values only",
+			avoidOptionals: map[string]interface{}{"inputValue": true, "field": false},
+			want: []string{
+				"name: InputMaybe<Scalars['String']['input']>;",
+				// Variables with a default value also need defaultValue
+				"limit?: InputMaybe<Scalars['Int']['input']>;",
+				"name?: string | null",
+			},
+		},
+		{
+			name:           "input values with defaults",
+			avoidOptionals: map[string]interface{}{"inputValue": true, "defaultValue": true},
+			want: []string{
+				"name: InputMaybe<Scalars['String']['input']>;",
+				"limit: InputMaybe<Scalars['Int']['input']>;",
+				"name?: string | null",
+			},
+		},
+		{
+			name:           "everything",
+			avoidOptionals: true,
+			want: []string{
+				"name: InputMaybe<Scalars['String']['input']>;",
+				"limit: InputMaybe<Scalars['Int']['input']>;",
+				"name: string | null",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &plugin.GenerateRequest{
+				Schema:     schema.NewSchema(rawSchema, "schema.graphql"),
+				Documents:  []*documents.Document{{FilePath: "users.graphql", Content: query, AST: queryDoc}},
+				OutputPath: "users.ts",
+				Config:     map[string]interface{}{"avoidOptionals": tt.avoidOptionals},
+			}
+			resp, err := typescript_operations.New().Generate(context.Background(), req)
+			if err != nil {
+				t.Fatalf("generate failed: %v", err)
+			}
+			output := string(resp.Files[req.OutputPath])
+			for _, want := range tt.want {
+				if !strings.Contains(output, want) {
+					t.Errorf("expected output to contain %q\ngot:\n%s", want, output)
+				}
+			}
+		})
+	}
+}
+
+func TestTypeScriptOperationsPlugin_ImmutableTypesDeep(t *testing.T) {
+	rawSchema, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+		type PageInfo { hasNextPage: Boolean! }
+		type Post { id: ID! title: String! tags: [[String!]] }
+		type Photo { id: ID! url: String! }
+		union Media = Post | Photo
+		type PostEdge { cursor: String! node: Post }
+		type PostConnection { edges: [PostEdge!]! pageInfo: PageInfo! }
+		type User { id: ID! posts: PostConnection! media: [Media!]! }
+		type Query { user: User }
+	`})
+	if err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+	query := `
+		query GetUser {
+			user {
+				id
+				posts { edges { cursor node { ...PostFields } } pageInfo { hasNextPage } }
+				media { ... on Post { id title } ... on Photo { url } }
+			}
+		}
+		fragment PostFields on Post { id title tags }
+	`
+	queryDoc, gqlErr := gqlparser.LoadQuery(rawSchema, query)
+	if gqlErr != nil {
+		t.Fatalf("failed to parse document: %v", gqlErr)
+	}
+
+	req := &plugin.GenerateRequest{
+		Schema:     schema.NewSchema(rawSchema, "schema.graphql"),
+		Documents:  []*documents.Document{{FilePath: "user.graphql", Content: query, AST: queryDoc}},
+		OutputPath: "user.ts",
+		Config:     map[string]interface{}{"immutableTypes": true},
+	}
+	resp, err := typescript_operations.New().Generate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	output := string(resp.Files[req.OutputPath])
+
+	for _, want := range []string{
+		"readonly posts: { readonly __typename?: 'PostConnection', readonly edges: ReadonlyArray<",
+		"readonly cursor: string, readonly node?: { readonly __typename?: 'Post', readonly id: string",
+		"readonly tags?: ReadonlyArray<ReadonlyArray<string> | null> | null",
+		"readonly pageInfo: { readonly __typename?: 'PageInfo', readonly hasNextPage: boolean }",
+		"| { readonly __typename: 'Post', readonly id: string, readonly title: string }",
+		"| { readonly __typename: 'Photo', readonly url: string }",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q\ngot:\n%s", want, output)
+		}
+	}
+
+	// Every member of every result and fragment type is readonly
+	for _, line := range strings.Split(output, "\n") {
+		if strings.Contains(line, "Variables = ") {
+			continue
+		}
+		for _, member := range memberPattern.FindAllStringSubmatch(line, -1) {
+			if member[1] == "" {
+				t.Errorf("member %q is not readonly in line:\n%s", member[2], line)
+			}
+		}
+	}
+	if strings.Contains(output, " Array<") {
+		t.Errorf("expected only ReadonlyArray lists\ngot:\n%s", output)
+	}
+}
+
+// memberPattern matches the members of inline object types
+var memberPattern = regexp.MustCompile(`(?:[{,]|\| \{) (readonly )?(\w+)\??: `)
+
+func TestTypeScriptOperationsPlugin_EmitSourceMap(t *testing.T) {
+	rawSchema, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+		type User { id: ID! name: String! }
+		type Query { user: User users: [User!]! }
+	`})
+	if err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+	query := "query GetUser {\n  user { ...UserFields }\n}\n\nfragment UserFields on User { id name }\n\nquery ListUsers { users { id } }\n"
+	queryDoc, gqlErr := gqlparser.LoadQuery(rawSchema, query)
+	if gqlErr != nil {
+		t.Fatalf("failed to parse document: %v", gqlErr)
+	}
+
+	generate := func(cfg map[string]interface{}) *plugin.GenerateResponse {
+		t.Helper()
+		req := &plugin.GenerateRequest{
+			Schema:     schema.NewSchema(rawSchema, "schema.graphql"),
+			Documents:  []*documents.Document{{FilePath: "user.graphql", Content: query, AST: queryDoc}},
+			OutputPath: "user.ts",
+			Config:     cfg,
+		}
+		resp, err := typescript_operations.New().Generate(context.Background(), req)
+		if err != nil {
+			t.Fatalf("generate failed: %v", err)
+		}
+		return resp
+	}
+
+	resp := generate(map[string]interface{}{"emitSourceMap": true})
+	output := string(resp.Files["user.ts"])
+	mappings := resp.SourceMappings["user.ts"]
+	if len(mappings) != 3 {
+		t.Fatalf("expected 3 mappings, got %+v", mappings)
+	}
+
+	expected := map[string]struct {
+		kind   string
+		line   int
+		prefix string
+	}{
+		"GetUser":    {"query", 1, "export type GetUserQueryVariables"},
+		"ListUsers":  {"query", 7, "export type ListUsersQueryVariables"},
+		"UserFields": {"fragment", 5, "export type UserFieldsFragment ="},
+	}
+	for _, m := range mappings {
+		want, ok := expected[m.Name]
+		if !ok {
+			t.Fatalf("unexpected mapping %+v", m)
+		}
+		if m.Kind != want.kind || m.Source != "user.graphql" || m.Line != want.line || m.Column != 1 {
+			t.Errorf("mapping of %s = %+v, want kind %s at user.graphql:%d:1", m.Name, m, want.kind, want.line)
+		}
+		generated := output[m.Start:m.End]
+		if !strings.HasPrefix(generated, want.prefix) || !strings.HasSuffix(generated, ";") {
+			t.Errorf("range of %s should cover its types, got:\n%s", m.Name, generated)
+		}
+	}
+
+	if resp := generate(nil); resp.SourceMappings != nil {
+		t.Errorf("source mappings should be opt-in, got %+v", resp.SourceMappings)
+	}
+}
+
+func TestTypeScriptOperationsPlugin_OneOfVariables(t *testing.T) {
+	rawSchema, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+		input UserBy @oneOf { id: ID email: String }
+		input PetBy @oneOf { id: ID owner: UserBy tags: [String!] }
+		input Filter @oneOf { name: String not: Filter }
+		input Page { first: Int }
+		type User { id: ID! }
+		type Query {
+			user(by: UserBy!): User
+			users(by: [UserBy!]): [User!]
+			pet(by: PetBy): User
+			search(filter: Filter!, page: Page): [User!]
+		}
+	`})
+	if err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+	query := `
+		query GetUser($by: UserBy!) { user(by: $by) { id } }
+		query ListUsers($by: [UserBy!]) { users(by: $by) { id } }
+		query GetPet($by: PetBy) { pet(by: $by) { id } }
+		query Search($filter: Filter!, $page: Page) { search(filter: $filter, page: $page) { id } }
+	`
+	queryDoc, gqlErr := gqlparser.LoadQuery(rawSchema, query)
+	if gqlErr != nil {
+		t.Fatalf("failed to parse document: %v", gqlErr)
+	}
+
+	req := &plugin.GenerateRequest{
+		Schema:     schema.NewSchema(rawSchema, "schema.graphql"),
+		Documents:  []*documents.Document{{FilePath: "users.graphql", Content: query, AST: queryDoc}},
+		OutputPath: "users.ts",
+		Config:     map[string]interface{}{},
+	}
+	resp, err := typescript_operations.New().Generate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	output := string(resp.Files[req.OutputPath])
+
+	expected := []string{
+		"  by: { id: Scalars['ID']['input'] } | { email: Scalars['String']['input'] };",
+		"  by?: InputMaybe<Array<{ id: Scalars['ID']['input'] } | { email: Scalars['String']['input'] }>>;",
+		"  by?: InputMaybe<{ id: Scalars['ID']['input'] } | { owner: { id: Scalars['ID']['input'] } | { email: Scalars['String']['input'] } } | { tags: Array<Scalars['String']['input']> }>;",
+		"  filter: { name: Scalars['String']['input'] } | { not: Filter };",
+		"  page?: InputMaybe<Page>;",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q\n%s", want, output)
+		}
+	}
+}
+
+func TestTypeScriptOperationsPlugin_ImportTypesFrom(t *testing.T) {
+	rawSchema, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+		enum Role { ADMIN MEMBER }
+		input CreateUserInput { name: String! role: Role }
+		type User { id: ID! name: String! role: Role! }
+		type Query { user(id: ID!): User }
+		type Mutation { createUser(input: CreateUserInput!, notify: Boolean): User }
+	`})
+	if err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+	query := `
+		mutation CreateUser($input: CreateUserInput!, $notify: Boolean) {
+			createUser(input: $input, notify: $notify) { id role }
+		}
+	`
+	queryDoc, gqlErr := gqlparser.LoadQuery(rawSchema, query)
+	if gqlErr != nil {
+		t.Fatalf("failed to parse document: %v", gqlErr)
+	}
+
+	generate := func(cfg map[string]interface{}) string {
+		t.Helper()
+		req := &plugin.GenerateRequest{
+			Schema:     schema.NewSchema(rawSchema, "schema.graphql"),
+			Documents:  []*documents.Document{{FilePath: "user.graphql", Content: query, AST: queryDoc}},
+			OutputPath: "user.ts",
+			Config:     cfg,
+		}
+		resp, err := typescript_operations.New().Generate(context.Background(), req)
+		if err != nil {
+			t.Fatalf("generate failed: %v", err)
+		}
+		return string(resp.Files[req.OutputPath])
+	}
+
+	output := generate(map[string]interface{}{"importTypesFrom": "./types"})
+	importLine := "import type { CreateUserInput, Exact, InputMaybe, Role, Scalars } from './types';\n\n"
+	if !strings.HasPrefix(output, importLine) {
+		t.Errorf("expected output to start with %q\n%s", importLine, output)
+	}
+	for _, want := range []string{
+		"  input: CreateUserInput;",
+		"  notify?: InputMaybe<Scalars['Boolean']['input']>;",
+		"role: Role",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to reference %q\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "export type CreateUserInput") || strings.Contains(output, "export enum Role") {
+		t.Errorf("shared types should be imported, not rendered\n%s", output)
+	}
+
+	if output := generate(nil); strings.Contains(output, "import type") {
+		t.Errorf("types should only be imported with importTypesFrom\n%s", output)
+	}
+}
+
+func TestTypeScriptOperationsPlugin_EmitAccessors(t *testing.T) {
+	rawSchema, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+		type Image { url: String! width: Int }
+		type Profile { bio: String avatar: Image }
+		type Account { profile: Profile! tags: [String!]! }
+		type User { id: ID! account: Account! profile: Profile friends: [User!]! }
+		type Query { viewer: User! user(id: ID!): User }
+	`})
+	if err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+	query := `
+		query GetUser($id: ID!) {
+			viewer { account { profile { bio } tags } }
+			user(id: $id) {
+				...UserProfile
+				friends { id }
+			}
+		}
+		fragment UserProfile on User { profile { avatar { url width } } }
+	`
+	queryDoc, gqlErr := gqlparser.LoadQuery(rawSchema, query)
+	if gqlErr != nil {
+		t.Fatalf("failed to parse document: %v", gqlErr)
+	}
+
+	req := &plugin.GenerateRequest{
+		Schema:     schema.NewSchema(rawSchema, "schema.graphql"),
+		Documents:  []*documents.Document{{FilePath: "user.graphql", Content: query, AST: queryDoc}},
+		OutputPath: "user.ts",
+		Config:     map[string]interface{}{"emitAccessors": true},
+	}
+	resp, err := typescript_operations.New().Generate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	output := string(resp.Files[req.OutputPath])
+
+	want := `export type GetUserQueryAccessors = {
+  'viewer.account': GetUserQuery['viewer']['account'];
+  'viewer.account.profile': GetUserQuery['viewer']['account']['profile'];
+  'viewer.account.profile.bio': GetUserQuery['viewer']['account']['profile']['bio'];
+  'viewer.account.tags': GetUserQuery['viewer']['account']['tags'];
+  'user.profile': NonNullable<GetUserQuery['user']>['profile'] | undefined;
+  'user.profile.avatar': NonNullable<NonNullable<GetUserQuery['user']>['profile']>['avatar'] | undefined;
+  'user.profile.avatar.url': NonNullable<NonNullable<NonNullable<GetUserQuery['user']>['profile']>['avatar']>['url'] | undefined;
+  'user.profile.avatar.width': NonNullable<NonNullable<NonNullable<GetUserQuery['user']>['profile']>['avatar']>['width'] | undefined;
+  'user.friends': NonNullable<GetUserQuery['user']>['friends'] | undefined;
+};`
+	if !strings.Contains(output, want) {
+		t.Errorf("expected accessors\n%s\nin output\n%s", want, output)
+	}
+	if strings.Contains(output, "'user.friends.id'") {
+		t.Errorf("paths should end at lists\n%s", output)
+	}
+	if strings.Count(output, "Accessors = {") != 1 {
+		t.Errorf("fragments should not get accessors\n%s", output)
+	}
+
+	req.Config = nil
+	resp, err = typescript_operations.New().Generate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if strings.Contains(string(resp.Files[req.OutputPath]), "Accessors") {
+		t.Errorf("accessors should only be emitted with emitAccessors")
+	}
+}
+
+func TestTypeScriptOperationsPlugin_FragmentArguments(t *testing.T) {
+	rawSchema, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+		type Post { id: ID! title: String! }
+		type User { id: ID! name: String! posts(first: Int): [Post!]! }
+		type Query { viewer: User! author: User! }
+	`})
+	if err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+	query := `
+		query GetUsers {
+			viewer { ...UserPosts @arguments(count: 5, withName: true) }
+			author { ...UserPosts }
+		}
+		fragment UserPosts on User @arguments(count: Int, withName: {type: "Boolean!", defaultValue: false}) {
+			posts(first: $count) { title }
+			name @include(if: $withName)
+		}
+	`
+	queryDoc, parseErr := parser.ParseQuery(&ast.Source{Name: "users.graphql", Input: query})
+	if parseErr != nil {
+		t.Fatalf("failed to parse document: %v", parseErr)
+	}
+	if errs := documents.AllowFragmentArguments(queryDoc, validator.Validate(rawSchema, queryDoc)); len(errs) > 0 {
+		t.Fatalf("invalid document: %v", errs)
+	}
+
+	req := &plugin.GenerateRequest{
+		Schema:     schema.NewSchema(rawSchema, "schema.graphql"),
+		Documents:  []*documents.Document{{FilePath: "users.graphql", Content: query, AST: queryDoc}},
+		OutputPath: "users.ts",
+		Config:     map[string]interface{}{},
+	}
+	resp, err := typescript_operations.New().Generate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	output := string(resp.Files[req.OutputPath])
+
+	posts := "posts: Array<{ __typename?: 'Post', title: string }>"
+	for _, want := range []string{
+		"viewer: { __typename?: 'User', name: string, " + posts + " }",
+		"author: { __typename?: 'User', name?: string, " + posts + " }",
+		"export type UserPostsFragment = { __typename?: 'User', name?: string, " + posts + " };",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output:\n%s", want, output)
+		}
+	}
+}
+
+func TestTypeScriptOperationsPlugin_Defer(t *testing.T) {
+	const types = `
+		type Post { id: ID! title: String! }
+		type User { id: ID! name: String! posts: [Post!]! }
+		type Query { viewer: User! }
+	`
+	query := `
+		query GetViewer {
+			viewer {
+				id
+				...UserDetails @defer
+				... @defer(if: false) { name }
+			}
+		}
+		fragment UserDetails on User {
+			id
+			name
+			posts { id }
+		}
+	`
+	const deferDirective = `directive @defer(if: Boolean! = true, label: String) on FRAGMENT_SPREAD | INLINE_FRAGMENT`
+	generate := func(t *testing.T, sdl string, config map[string]interface{}) string {
+		rawSchema, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: sdl})
+		if err != nil {
+			t.Fatalf("failed to parse schema: %v", err)
+		}
+		queryDoc, gqlErr := gqlparser.LoadQuery(rawSchema, query)
+		if gqlErr != nil {
+			t.Fatalf("failed to parse document: %v", gqlErr)
+		}
+		req := &plugin.GenerateRequest{
+			Schema:     schema.NewSchema(rawSchema, "schema.graphql"),
+			Documents:  []*documents.Document{{FilePath: "viewer.graphql", Content: query, AST: queryDoc}},
+			OutputPath: "viewer.ts",
+			Config:     config,
+		}
+		resp, err := typescript_operations.New().Generate(context.Background(), req)
+		if err != nil {
+			t.Fatalf("generate failed: %v", err)
+		}
+		return string(resp.Files[req.OutputPath])
+	}
+
+	t.Run("schema declares @defer", func(t *testing.T) {
+		output := generate(t, deferDirective+types, map[string]interface{}{})
+		want := "viewer: ( { __typename?: 'User', id: string, name: string } & Incremental<{ posts: Array<{ __typename?: 'Post', id: string }> }> )"
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output:\n%s", want, output)
+		}
+	})
+
+	t.Run("built-in @defer only", func(t *testing.T) {
+		output := generate(t, types, map[string]interface{}{})
+		want := "viewer: { __typename?: 'User', id: string, name: string, posts: Array<{ __typename?: 'Post', id: string }> }"
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output:\n%s", want, output)
+		}
+		if strings.Contains(output, "Incremental") {
+			t.Errorf("unexpected Incremental in output:\n%s", output)
+		}
+	})
+
+	t.Run("combined fragment types", func(t *testing.T) {
+		output := generate(t, deferDirective+types, map[string]interface{}{"inlineFragmentTypes": "combine"})
+		want := "viewer: ( { __typename?: 'User', id: string, name: string } & Incremental<UserDetailsFragment> )"
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output:\n%s", want, output)
+		}
+	})
+}
+
+func TestTypeScriptOperationsPlugin_Stream(t *testing.T) {
+	const streamDirective = `directive @stream(if: Boolean! = true, label: String, initialCount: Int = 0) on FIELD`
+	const types = `
+		type Post { id: ID! tags: [String!] }
+		type User { id: ID! posts: [Post!]! drafts: [Post] }
+		type Query { viewer: User! search: [User!]! }
+	`
+	query := `
+		query GetStreamedPosts($live: Boolean!) {
+			viewer {
+				posts @stream(initialCount: 2) { id tags @stream }
+				drafts @stream(if: false) { id }
+				id @stream(if: $live)
+			}
+		}
+	`
+	rawSchema, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: streamDirective + types})
+	if err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+	queryDoc, gqlErr := gqlparser.LoadQuery(rawSchema, query)
+	if gqlErr != nil {
+		t.Fatalf("failed to parse document: %v", gqlErr)
+	}
+	generate := func(t *testing.T, config map[string]interface{}) string {
+		req := &plugin.GenerateRequest{
+			Schema:     schema.NewSchema(rawSchema, "schema.graphql"),
+			Documents:  []*documents.Document{{FilePath: "posts.graphql", Content: query, AST: queryDoc}},
+			OutputPath: "posts.ts",
+			Config:     config,
+		}
+		resp, err := typescript_operations.New().Generate(context.Background(), req)
+		if err != nil {
+			t.Fatalf("generate failed: %v", err)
+		}
+		return string(resp.Files[req.OutputPath])
+	}
+
+	output := generate(t, map[string]interface{}{})
+	for _, want := range []string{
+		"drafts?: Array<{ __typename?: 'Post', id: string } | null> | null",
+		"posts: Streamed<Array<{ __typename?: 'Post', id: string, tags?: Streamed<Array<string>> | null }>>",
+		"viewer: { __typename?: 'User', id: string,",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output:\n%s", want, output)
+		}
+	}
+
+	output = generate(t, map[string]interface{}{"immutableTypes": true, "importTypesFrom": "./types"})
+	for _, want := range []string{
+		"readonly posts: Streamed<ReadonlyArray<",
+		"import type { Exact, Scalars, Streamed } from './types';",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output:\n%s", want, output)
+		}
+	}
+}
@@ -30,7 +30,7 @@ export type CreateUserMutationVariables = Exact<{
 }>;
 
 
-export type CreateUserMutation = { __typename?: 'Mutation', createUser: { __typename?: 'User', id: string, name: string, email: string, role: UserRole, createdAt: any } };
+export type CreateUserMutation = { __typename?: 'Mutation', createUser: { __typename?: 'User', id: string, name: string, email: string, role: UserRole, createdAt: string } };
 
 export type UpdateUserMutationVariables = Exact<{
   id: Scalars['ID']['input'];
@@ -38,14 +38,14 @@ export type UpdateUserMutationVariables = Exact<{
 }>;
 
 
-export type UpdateUserMutation = { __typename?: 'Mutation', updateUser: { __typename?: 'User', id: string, name: string, email: string, status: Status, updatedAt: any } | null };
+export type UpdateUserMutation = { __typename?: 'Mutation', updateUser: { __typename?: 'User', id: string, name: string, email: string, status: Status, updatedAt: string } | null };
 
 export type PublishPostMutationVariables = Exact<{
   postId: Scalars['ID']['input'];
 }>;
 
 
-export type PublishPostMutation = { __typename?: 'Mutation', publishPost: { __typename?: 'Post', id: string, title: string, published: boolean, publishedAt: any | null } | null };
+export type PublishPostMutation = { __typename?: 'Mutation', publishPost: { __typename?: 'Post', id: string, title: string, published: boolean, publishedAt: string | null } | null };
 
 export type OnUserCreatedSubscriptionVariables = Exact<{ [key: string]: never; }>;
 
@@ -57,7 +57,7 @@ export type OnCommentAddedSubscriptionVariables = Exact<{
 }>;
 
 
-export type OnCommentAddedSubscription = { __typename?: 'Subscription', commentAdded: { __typename?: 'Comment', id: string, content: string, createdAt: any, author: { __typename?: 'User', name: string } } };
+export type OnCommentAddedSubscription = { __typename?: 'Subscription', commentAdded: { __typename?: 'Comment', id: string, content: string, createdAt: string, author: { __typename?: 'User', name: string } } };
 
 export type UserFieldsFragment = { __typename?: 'User', id: string, name: string, email: string, role: UserRole, status: Status };
 
@@ -30,7 +30,7 @@ export type CreateUserMutationVariables = Exact<{
 }>;
 
 
-export type CreateUserMutation = { readonly __typename?: 'Mutation', readonly createUser: { readonly __typename?: 'User', readonly id: string, readonly name: string, readonly email: string, readonly role: UserRole, readonly createdAt: any } };
+export type CreateUserMutation = { readonly __typename?: 'Mutation', readonly createUser: { readonly __typename?: 'User', readonly id: string, readonly name: string, readonly email: string, readonly role: UserRole, readonly createdAt: string } };
 
 export type UpdateUserMutationVariables = Exact<{
   id: Scalars['ID']['input'];
@@ -38,14 +38,14 @@ export type UpdateUserMutationVariables = Exact<{
 }>;
 
 
-export type UpdateUserMutation = { readonly __typename?: 'Mutation', readonly updateUser?: { readonly __typename?: 'User', readonly id: string, readonly name: string, readonly email: string, readonly status: Status, readonly updatedAt: any } | null };
+export type UpdateUserMutation = { readonly __typename?: 'Mutation', readonly updateUser?: { readonly __typename?: 'User', readonly id: string, readonly name: string, readonly email: string, readonly status: Status, readonly updatedAt: string } | null };
 
 export type PublishPostMutationVariables = Exact<{
   postId: Scalars['ID']['input'];
 }>;
 
 
-export type PublishPostMutation = { readonly __typename?: 'Mutation', readonly publishPost?: { readonly __typename?: 'Post', readonly id: string, readonly title: string, readonly published: boolean, readonly publishedAt?: any | null } | null };
+export type PublishPostMutation = { readonly __typename?: 'Mutation', readonly publishPost?: { readonly __typename?: 'Post', readonly id: string, readonly title: string, readonly published: boolean, readonly publishedAt?: string | null } | null };
 
 export type OnUserCreatedSubscriptionVariables = Exact<{ [key: string]: never; }>;
 
@@ -57,7 +57,7 @@ export type OnCommentAddedSubscriptionVariables = Exact<{
 }>;
 
 
-export type OnCommentAddedSubscription = { readonly __typename?: 'Subscription', readonly commentAdded: { readonly __typename?: 'Comment', readonly id: string, readonly content: string, readonly createdAt: any, readonly author: { readonly __typename?: 'User', readonly name: string } } };
+export type OnCommentAddedSubscription = { readonly __typename?: 'Subscription', readonly commentAdded: { readonly __typename?: 'Comment', readonly id: string, readonly content: string, readonly createdAt: string, readonly author: { readonly __typename?: 'User', readonly name: string } } };
 
 export type UserFieldsFragment = { readonly __typename?: 'User', readonly id: string, readonly name: string, readonly email: string, readonly role: UserRole, readonly status: Status };
 
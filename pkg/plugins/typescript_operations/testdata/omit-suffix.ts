@@ -30,7 +30,7 @@ export type CreateUserVariables = Exact<{
 }>;
 
 
-export type CreateUser = { __typename?: 'Mutation', createUser: { __typename?: 'User', id: string, name: string, email: string, role: UserRole, createdAt: any } };
+export type CreateUser = { __typename?: 'Mutation', createUser: { __typename?: 'User', id: string, name: string, email: string, role: UserRole, createdAt: string } };
 
 export type UpdateUserVariables = Exact<{
   id: Scalars['ID']['input'];
@@ -38,14 +38,14 @@ export type UpdateUserVariables = Exact<{
 }>;
 
 
-export type UpdateUser = { __typename?: 'Mutation', updateUser?: { __typename?: 'User', id: string, name: string, email: string, status: Status, updatedAt: any } | null };
+export type UpdateUser = { __typename?: 'Mutation', updateUser?: { __typename?: 'User', id: string, name: string, email: string, status: Status, updatedAt: string } | null };
 
 export type PublishPostVariables = Exact<{
   postId: Scalars['ID']['input'];
 }>;
 
 
-export type PublishPost = { __typename?: 'Mutation', publishPost?: { __typename?: 'Post', id: string, title: string, published: boolean, publishedAt?: any | null } | null };
+export type PublishPost = { __typename?: 'Mutation', publishPost?: { __typename?: 'Post', id: string, title: string, published: boolean, publishedAt?: string | null } | null };
 
 export type OnUserCreatedVariables = Exact<{ [key: string]: never; }>;
 
@@ -57,7 +57,7 @@ export type OnCommentAddedVariables = Exact<{
 }>;
 
 
-export type OnCommentAdded = { __typename?: 'Subscription', commentAdded: { __typename?: 'Comment', id: string, content: string, createdAt: any, author: { __typename?: 'User', name: string } } };
+export type OnCommentAdded = { __typename?: 'Subscription', commentAdded: { __typename?: 'Comment', id: string, content: string, createdAt: string, author: { __typename?: 'User', name: string } } };
 
 export type UserFields = { __typename?: 'User', id: string, name: string, email: string, role: UserRole, status: Status };
 
@@ -30,7 +30,7 @@ export type CreateUserMutationVariables = Exact<{
 }>;
 
 
-export type CreateUserMutation = { createUser: { id: string, name: string, email: string, role: UserRole, createdAt: any } };
+export type CreateUserMutation = { createUser: { id: string, name: string, email: string, role: UserRole, createdAt: string } };
 
 export type UpdateUserMutationVariables = Exact<{
   id: Scalars['ID']['input'];
@@ -38,14 +38,14 @@ export type UpdateUserMutationVariables = Exact<{
 }>;
 
 
-export type UpdateUserMutation = { updateUser?: { id: string, name: string, email: string, status: Status, updatedAt: any } | null };
+export type UpdateUserMutation = { updateUser?: { id: string, name: string, email: string, status: Status, updatedAt: string } | null };
 
 export type PublishPostMutationVariables = Exact<{
   postId: Scalars['ID']['input'];
 }>;
 
 
-export type PublishPostMutation = { publishPost?: { id: string, title: string, published: boolean, publishedAt?: any | null } | null };
+export type PublishPostMutation = { publishPost?: { id: string, title: string, published: boolean, publishedAt?: string | null } | null };
 
 export type OnUserCreatedSubscriptionVariables = Exact<{ [key: string]: never; }>;
 
@@ -57,7 +57,7 @@ export type OnCommentAddedSubscriptionVariables = Exact<{
 }>;
 
 
-export type OnCommentAddedSubscription = { commentAdded: { id: string, content: string, createdAt: any, author: { name: string } } };
+export type OnCommentAddedSubscription = { commentAdded: { id: string, content: string, createdAt: string, author: { name: string } } };
 
 export type UserFieldsFragment = { id: string, name: string, email: string, role: UserRole, status: Status };
 
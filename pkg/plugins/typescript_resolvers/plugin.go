@@ -0,0 +1,237 @@
+package typescript_resolvers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jzeiders/graphql-go-gen/pkg/plugin"
+	"github.com/jzeiders/graphql-go-gen/pkg/plugins/base"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Plugin generates resolver signatures for implementing a GraphQL schema in
+// TypeScript. The signatures refer to the types of the typescript plugin, so
+// it runs after that plugin in the same output or imports its types with
+// importTypesFrom.
+type Plugin struct{}
+
+// New creates a new TypeScript resolvers plugin
+func New() plugin.Plugin {
+	return &Plugin{}
+}
+
+// Name returns the plugin name
+func (p *Plugin) Name() string {
+	return "typescript-resolvers"
+}
+
+// Description returns the plugin description
+func (p *Plugin) Description() string {
+	return "Generates TypeScript resolver signatures for implementing the schema"
+}
+
+// DefaultConfig returns the default configuration
+func (p *Plugin) DefaultConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"contextType":     "any",
+		"rootValueType":   "{}",
+		"noExport":        false,
+		"importTypesFrom": "",
+	}
+}
+
+// ValidateConfig validates the plugin configuration
+func (p *Plugin) ValidateConfig(config map[string]interface{}) error {
+	for _, key := range []string{"contextType", "rootValueType"} {
+		if value, ok := config[key]; ok {
+			if s, isString := value.(string); !isString || strings.TrimSpace(s) == "" {
+				return fmt.Errorf("%s must be a non-empty TypeScript type", key)
+			}
+		}
+	}
+	return nil
+}
+
+const (
+	fieldResolverSignature = `type FieldResolver<TResult, TParent = {}, TContext = {}, TArgs = {}> = (
+  parent: TParent,
+  args: TArgs,
+  context: TContext,
+  info: GraphQLResolveInfo
+) => Promise<TResult> | TResult;`
+	requireFieldsSignature = "type RequireFields<T, K extends keyof T> = Omit<T, K> & { [P in K]-?: NonNullable<T[P]> };"
+)
+
+type resolversConfig struct {
+	contextType     string
+	rootValueType   string
+	noExport        bool
+	importTypesFrom string
+}
+
+type generator struct {
+	schema *ast.Schema
+	cfg    resolversConfig
+	sb     *strings.Builder
+	// referenced holds the names of the typescript plugin's types that the
+	// signatures use, for importTypesFrom
+	referenced map[string]bool
+}
+
+// Generate generates resolver signatures for the query, mutation and object
+// types of the schema. Subscriptions are not covered.
+func (p *Plugin) Generate(ctx context.Context, req *plugin.GenerateRequest) (*plugin.GenerateResponse, error) {
+	if req.Schema == nil || req.Schema.Raw() == nil {
+		return nil, fmt.Errorf("schema is required")
+	}
+	if err := p.ValidateConfig(req.Config); err != nil {
+		return nil, err
+	}
+
+	gen := generator{
+		schema: req.Schema.Raw(),
+		cfg: resolversConfig{
+			contextType:     base.GetString(req.Config, "contextType", "any"),
+			rootValueType:   base.GetString(req.Config, "rootValueType", "{}"),
+			noExport:        base.GetBool(req.Config, "noExport", false),
+			importTypesFrom: base.GetString(req.Config, "importTypesFrom", ""),
+		},
+		sb:         &strings.Builder{},
+		referenced: make(map[string]bool),
+	}
+	body := gen.render()
+
+	var sb strings.Builder
+	sb.WriteString("// Generated by graphql-go-gen - TypeScript Resolvers Plugin\n")
+	sb.WriteString("// DO NOT EDIT THIS FILE MANUALLY\n\n")
+	sb.WriteString("import type { GraphQLResolveInfo } from 'graphql';\n")
+	if imports := base.RenderTypesImport(gen.referenced, gen.cfg.importTypesFrom); imports != "" {
+		sb.WriteString(imports + "\n")
+	}
+	sb.WriteString("\n")
+	sb.WriteString(body)
+
+	return &plugin.GenerateResponse{
+		Files: map[string][]byte{
+			req.OutputPath: []byte(sb.String()),
+		},
+	}, nil
+}
+
+func (g *generator) exportPrefix() string {
+	if g.cfg.noExport {
+		return ""
+	}
+	return "export "
+}
+
+// render writes the helper types, a Resolvers type per object type and the
+// Resolvers map of all of them
+func (g *generator) render() string {
+	exportPrefix := g.exportPrefix()
+	g.sb.WriteString(exportPrefix + fieldResolverSignature + "\n\n")
+	g.sb.WriteString(exportPrefix + requireFieldsSignature + "\n\n")
+
+	types := g.resolverTypes()
+	for _, def := range types {
+		g.writeTypeResolvers(def)
+	}
+
+	g.sb.WriteString("/** The resolvers of every type, to pass to the server */\n")
+	g.sb.WriteString(fmt.Sprintf("%stype Resolvers<ContextType = %s> = {\n", exportPrefix, g.cfg.contextType))
+	for _, def := range types {
+		g.sb.WriteString(fmt.Sprintf("  %s?: %sResolvers<ContextType>;\n", def.Name, def.Name))
+	}
+	g.sb.WriteString("};\n")
+	return g.sb.String()
+}
+
+// resolverTypes returns the object types that get resolvers, sorted by name,
+// leaving out introspection types and the subscription type
+func (g *generator) resolverTypes() []*ast.Definition {
+	var defs []*ast.Definition
+	for _, def := range g.schema.Types {
+		if def.Kind != ast.Object || strings.HasPrefix(def.Name, "__") || def == g.schema.Subscription {
+			continue
+		}
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool {
+		return defs[i].Name < defs[j].Name
+	})
+	return defs
+}
+
+// isRoot reports whether def is the query or mutation type, whose resolvers
+// receive the root value as their parent
+func (g *generator) isRoot(def *ast.Definition) bool {
+	return def == g.schema.Query || def == g.schema.Mutation
+}
+
+func (g *generator) writeTypeResolvers(def *ast.Definition) {
+	parent := g.cfg.rootValueType
+	if !g.isRoot(def) {
+		parent = g.reference(def.Name)
+	}
+
+	if def.Description != "" {
+		g.sb.WriteString(base.FormatComment(def.Description, ""))
+	}
+	g.sb.WriteString(fmt.Sprintf("%stype %sResolvers<ContextType = %s, ParentType = %s> = {\n", g.exportPrefix(), def.Name, g.cfg.contextType, parent))
+	for _, field := range def.Fields {
+		if strings.HasPrefix(field.Name, "__") {
+			continue
+		}
+		if field.Description != "" {
+			g.sb.WriteString(base.FormatComment(field.Description, "  "))
+		}
+		g.sb.WriteString(fmt.Sprintf("  %s?: FieldResolver<%s, ParentType, ContextType, %s>;\n", field.Name, g.renderType(field.Type), g.argsType(def, field)))
+	}
+	g.sb.WriteString("};\n\n")
+}
+
+// argsType returns the type of a field's args: the Args type of the
+// typescript plugin, with arguments that have a default value required, as
+// the server fills them in
+func (g *generator) argsType(def *ast.Definition, field *ast.FieldDefinition) string {
+	if len(field.Arguments) == 0 {
+		return "{}"
+	}
+	name := g.reference(def.Name + base.ToPascalCase(field.Name) + "Args")
+
+	var defaulted []string
+	for _, arg := range field.Arguments {
+		if arg.DefaultValue != nil && !arg.Type.NonNull {
+			defaulted = append(defaulted, fmt.Sprintf("'%s'", arg.Name))
+		}
+	}
+	if len(defaulted) == 0 {
+		return name
+	}
+	return fmt.Sprintf("RequireFields<%s, %s>", name, strings.Join(defaulted, " | "))
+}
+
+// renderType renders the result type of a field the way the typescript
+// plugin renders output fields
+func (g *generator) renderType(t *ast.Type) string {
+	if !t.NonNull {
+		nn := *t
+		nn.NonNull = true
+		return fmt.Sprintf("%s<%s>", g.reference("Maybe"), g.renderType(&nn))
+	}
+	if t.Elem != nil {
+		return fmt.Sprintf("Array<%s>", g.renderType(t.Elem))
+	}
+	if def := g.schema.Types[t.NamedType]; def != nil && def.Kind == ast.Scalar {
+		return fmt.Sprintf("%s['%s']['output']", g.reference("Scalars"), t.NamedType)
+	}
+	return g.reference(t.NamedType)
+}
+
+// reference records that the output uses a type of the typescript plugin
+func (g *generator) reference(name string) string {
+	g.referenced[name] = true
+	return name
+}
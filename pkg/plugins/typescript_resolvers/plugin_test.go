@@ -0,0 +1,65 @@
+package typescript_resolvers_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jzeiders/graphql-go-gen/pkg/plugins/testutil"
+	"github.com/jzeiders/graphql-go-gen/pkg/plugins/typescript_resolvers"
+)
+
+func TestTypeScriptResolversPlugin_MatchesReferenceOutput(t *testing.T) {
+	plugin := typescript_resolvers.New()
+	req := testutil.CreateTestRequest(t, map[string]interface{}{})
+
+	resp, err := plugin.Generate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	actual := string(resp.Files[req.OutputPath])
+
+	expected, err := os.ReadFile(filepath.Join("testdata", "default.ts"))
+	if err != nil {
+		t.Fatalf("read golden file: %v", err)
+	}
+	if string(expected) != actual {
+		t.Fatalf("typescript-resolvers output mismatch\nwant:\n%s\n\ngot:\n%s", expected, actual)
+	}
+}
+
+func TestTypeScriptResolversPlugin_Config(t *testing.T) {
+	plugin := typescript_resolvers.New()
+	req := testutil.CreateTestRequest(t, map[string]interface{}{
+		"contextType":     "import('./context').Context",
+		"rootValueType":   "RootValue",
+		"importTypesFrom": "./types",
+		"noExport":        true,
+	})
+
+	resp, err := plugin.Generate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	output := string(resp.Files[req.OutputPath])
+
+	testutil.AssertContains(t, output, "import type { Comment, Maybe, MutationAddCommentArgs, MutationCreatePostArgs,")
+	testutil.AssertContains(t, output, " QuerySearchArgs, QueryUserArgs, QueryUsersArgs, Scalars, SearchResult, Status, User, UserConnection, UserEdge, UserRole } from './types';")
+	testutil.AssertContains(t, output, "type Resolvers<ContextType = import('./context').Context> = {")
+	testutil.AssertContains(t, output, "type QueryResolvers<ContextType = import('./context').Context, ParentType = RootValue> = {")
+	testutil.AssertNotContains(t, output, "export type")
+}
+
+func TestTypeScriptResolversPlugin_ValidateConfig(t *testing.T) {
+	plugin := typescript_resolvers.New()
+	if err := plugin.ValidateConfig(map[string]interface{}{"contextType": "Context"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := plugin.ValidateConfig(map[string]interface{}{"contextType": ""}); err == nil {
+		t.Fatal("expected an error for an empty contextType")
+	}
+	if err := plugin.ValidateConfig(map[string]interface{}{"rootValueType": 1}); err == nil {
+		t.Fatal("expected an error for a rootValueType that is not a string")
+	}
+}
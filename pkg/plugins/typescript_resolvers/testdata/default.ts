@@ -0,0 +1,106 @@
+// Generated by graphql-go-gen - TypeScript Resolvers Plugin
+// DO NOT EDIT THIS FILE MANUALLY
+
+import type { GraphQLResolveInfo } from 'graphql';
+
+export type FieldResolver<TResult, TParent = {}, TContext = {}, TArgs = {}> = (
+  parent: TParent,
+  args: TArgs,
+  context: TContext,
+  info: GraphQLResolveInfo
+) => Promise<TResult> | TResult;
+
+export type RequireFields<T, K extends keyof T> = Omit<T, K> & { [P in K]-?: NonNullable<T[P]> };
+
+export type CommentResolvers<ContextType = any, ParentType = Comment> = {
+  id?: FieldResolver<Scalars['ID']['output'], ParentType, ContextType, {}>;
+  content?: FieldResolver<Scalars['String']['output'], ParentType, ContextType, {}>;
+  author?: FieldResolver<User, ParentType, ContextType, {}>;
+  post?: FieldResolver<Post, ParentType, ContextType, {}>;
+  createdAt?: FieldResolver<Scalars['Date']['output'], ParentType, ContextType, {}>;
+};
+
+export type MutationResolvers<ContextType = any, ParentType = {}> = {
+  createUser?: FieldResolver<User, ParentType, ContextType, MutationCreateUserArgs>;
+  updateUser?: FieldResolver<Maybe<User>, ParentType, ContextType, MutationUpdateUserArgs>;
+  deleteUser?: FieldResolver<Scalars['Boolean']['output'], ParentType, ContextType, MutationDeleteUserArgs>;
+  createPost?: FieldResolver<Post, ParentType, ContextType, MutationCreatePostArgs>;
+  publishPost?: FieldResolver<Maybe<Post>, ParentType, ContextType, MutationPublishPostArgs>;
+  deletePost?: FieldResolver<Scalars['Boolean']['output'], ParentType, ContextType, MutationDeletePostArgs>;
+  addComment?: FieldResolver<Comment, ParentType, ContextType, MutationAddCommentArgs>;
+  deleteComment?: FieldResolver<Scalars['Boolean']['output'], ParentType, ContextType, MutationDeleteCommentArgs>;
+};
+
+export type PageInfoResolvers<ContextType = any, ParentType = PageInfo> = {
+  hasNextPage?: FieldResolver<Scalars['Boolean']['output'], ParentType, ContextType, {}>;
+  hasPreviousPage?: FieldResolver<Scalars['Boolean']['output'], ParentType, ContextType, {}>;
+  startCursor?: FieldResolver<Maybe<Scalars['String']['output']>, ParentType, ContextType, {}>;
+  endCursor?: FieldResolver<Maybe<Scalars['String']['output']>, ParentType, ContextType, {}>;
+};
+
+export type PostResolvers<ContextType = any, ParentType = Post> = {
+  id?: FieldResolver<Scalars['ID']['output'], ParentType, ContextType, {}>;
+  title?: FieldResolver<Scalars['String']['output'], ParentType, ContextType, {}>;
+  content?: FieldResolver<Scalars['String']['output'], ParentType, ContextType, {}>;
+  published?: FieldResolver<Scalars['Boolean']['output'], ParentType, ContextType, {}>;
+  author?: FieldResolver<User, ParentType, ContextType, {}>;
+  comments?: FieldResolver<Array<Comment>, ParentType, ContextType, {}>;
+  tags?: FieldResolver<Array<Scalars['String']['output']>, ParentType, ContextType, {}>;
+  createdAt?: FieldResolver<Scalars['Date']['output'], ParentType, ContextType, {}>;
+  publishedAt?: FieldResolver<Maybe<Scalars['Date']['output']>, ParentType, ContextType, {}>;
+};
+
+export type ProfileResolvers<ContextType = any, ParentType = Profile> = {
+  id?: FieldResolver<Scalars['ID']['output'], ParentType, ContextType, {}>;
+  bio?: FieldResolver<Maybe<Scalars['String']['output']>, ParentType, ContextType, {}>;
+  avatar?: FieldResolver<Maybe<Scalars['String']['output']>, ParentType, ContextType, {}>;
+  website?: FieldResolver<Maybe<Scalars['String']['output']>, ParentType, ContextType, {}>;
+  user?: FieldResolver<User, ParentType, ContextType, {}>;
+};
+
+export type QueryResolvers<ContextType = any, ParentType = {}> = {
+  user?: FieldResolver<Maybe<User>, ParentType, ContextType, QueryUserArgs>;
+  users?: FieldResolver<UserConnection, ParentType, ContextType, QueryUsersArgs>;
+  currentUser?: FieldResolver<Maybe<User>, ParentType, ContextType, {}>;
+  post?: FieldResolver<Maybe<Post>, ParentType, ContextType, QueryPostArgs>;
+  posts?: FieldResolver<Array<Post>, ParentType, ContextType, QueryPostsArgs>;
+  search?: FieldResolver<Array<SearchResult>, ParentType, ContextType, RequireFields<QuerySearchArgs, 'limit'>>;
+  node?: FieldResolver<Maybe<Node>, ParentType, ContextType, QueryNodeArgs>;
+};
+
+export type UserResolvers<ContextType = any, ParentType = User> = {
+  id?: FieldResolver<Scalars['ID']['output'], ParentType, ContextType, {}>;
+  name?: FieldResolver<Scalars['String']['output'], ParentType, ContextType, {}>;
+  email?: FieldResolver<Scalars['String']['output'], ParentType, ContextType, {}>;
+  age?: FieldResolver<Maybe<Scalars['Int']['output']>, ParentType, ContextType, {}>;
+  role?: FieldResolver<UserRole, ParentType, ContextType, {}>;
+  status?: FieldResolver<Status, ParentType, ContextType, {}>;
+  posts?: FieldResolver<Array<Post>, ParentType, ContextType, {}>;
+  profile?: FieldResolver<Maybe<Profile>, ParentType, ContextType, {}>;
+  createdAt?: FieldResolver<Scalars['Date']['output'], ParentType, ContextType, {}>;
+  updatedAt?: FieldResolver<Scalars['Date']['output'], ParentType, ContextType, {}>;
+};
+
+export type UserConnectionResolvers<ContextType = any, ParentType = UserConnection> = {
+  edges?: FieldResolver<Array<UserEdge>, ParentType, ContextType, {}>;
+  pageInfo?: FieldResolver<PageInfo, ParentType, ContextType, {}>;
+  totalCount?: FieldResolver<Scalars['Int']['output'], ParentType, ContextType, {}>;
+};
+
+export type UserEdgeResolvers<ContextType = any, ParentType = UserEdge> = {
+  node?: FieldResolver<User, ParentType, ContextType, {}>;
+  cursor?: FieldResolver<Scalars['String']['output'], ParentType, ContextType, {}>;
+};
+
+/** The resolvers of every type, to pass to the server */
+export type Resolvers<ContextType = any> = {
+  Comment?: CommentResolvers<ContextType>;
+  Mutation?: MutationResolvers<ContextType>;
+  PageInfo?: PageInfoResolvers<ContextType>;
+  Post?: PostResolvers<ContextType>;
+  Profile?: ProfileResolvers<ContextType>;
+  Query?: QueryResolvers<ContextType>;
+  User?: UserResolvers<ContextType>;
+  UserConnection?: UserConnectionResolvers<ContextType>;
+  UserEdge?: UserEdgeResolvers<ContextType>;
+};
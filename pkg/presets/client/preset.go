@@ -2,11 +2,15 @@ package client
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 
 	"github.com/jzeiders/graphql-go-gen/pkg/documents"
+	"github.com/jzeiders/graphql-go-gen/pkg/plugins/base"
+	"github.com/jzeiders/graphql-go-gen/pkg/plugins/framework_hooks"
 	"github.com/jzeiders/graphql-go-gen/pkg/plugins/gql_tag_operations"
 	"github.com/jzeiders/graphql-go-gen/pkg/presets"
 	"github.com/vektah/gqlparser/v2/ast"
@@ -16,6 +20,9 @@ import (
 type FragmentMaskingConfig struct {
 	// UnmaskFunctionName is the name of the function used to unmask fragments (default: "useFragment")
 	UnmaskFunctionName string `yaml:"unmaskFunctionName" json:"unmaskFunctionName"`
+	// FragmentArrayName names a helper type for arrays of fragment refs,
+	// e.g. "FragmentArray" (default: arrays are written as Array<FragmentType<...>>)
+	FragmentArrayName string `yaml:"fragmentArrayName" json:"fragmentArrayName"`
 }
 
 // PersistedDocumentsConfig configures persisted documents/queries
@@ -25,14 +32,35 @@ type PersistedDocumentsConfig struct {
 	Mode string `yaml:"mode" json:"mode"`
 	// HashPropertyName is the name of the property that contains the hash (default: "hash")
 	HashPropertyName string `yaml:"hashPropertyName" json:"hashPropertyName"`
-	// HashAlgorithm is the algorithm to use for hashing (sha1, sha256, or custom function)
+	// HashAlgorithm is the algorithm to use for hashing (sha1, sha256,
+	// sha256-trunc, fnv, or custom function)
 	HashAlgorithm interface{} `yaml:"hashAlgorithm" json:"hashAlgorithm"`
+	// KeepExisting merges the manifest already on disk into the new one, so the
+	// hashes of removed documents keep working for deployed clients. Also set
+	// by mergeWithExisting.
+	KeepExisting bool `yaml:"keepExisting" json:"keepExisting"`
 }
 
 // ClientPresetConfig configures the client preset
 type ClientPresetConfig struct {
 	// FragmentMasking configures fragment masking (true, false, or config object)
 	FragmentMasking interface{} `yaml:"fragmentMasking" json:"fragmentMasking"`
+	// InlineFragmentMasking writes the fragment masking helpers into graphql.ts
+	// instead of a separate fragment-masking.ts
+	InlineFragmentMasking bool `yaml:"inlineFragmentMasking" json:"inlineFragmentMasking"`
+	// OutputMode is "directory" (default) to write graphql.ts, gql.ts,
+	// fragment-masking.ts and index.ts, or "single" to write all of them into
+	// the output file
+	OutputMode string `yaml:"outputMode" json:"outputMode"`
+	// OperationModules writes each operation's document to operations/<Name>.ts
+	// with an index of lazy import() loaders for code splitting
+	OperationModules bool `yaml:"operationModules" json:"operationModules"`
+	// Framework generates hooks.ts with typed hooks for the given client:
+	// "apollo", "urql" or "react-query"
+	Framework string `yaml:"framework" json:"framework"`
+	// Fetcher is the "module#function" react-query hooks call to execute a
+	// document (default: "./fetcher#fetcher")
+	Fetcher string `yaml:"fetcher" json:"fetcher"`
 	// GqlTagName is the name of the GraphQL tag function (default: "graphql")
 	GqlTagName string `yaml:"gqlTagName" json:"gqlTagName"`
 	// PersistedDocuments configures persisted queries/documents
@@ -41,8 +69,9 @@ type ClientPresetConfig struct {
 	OnExecutableDocumentNode func(doc interface{}) map[string]interface{} `yaml:"-" json:"-"`
 
 	// TypeScript Configuration Options
-	// Scalars extends or overrides the built-in scalars and custom GraphQL scalars to a custom type
-	Scalars map[string]string `yaml:"scalars" json:"scalars"`
+	// Scalars extends or overrides the built-in scalars and custom GraphQL scalars to a custom type,
+	// either one type for both directions or an { input, output } object
+	Scalars map[string]base.ScalarMapping `yaml:"scalars" json:"scalars"`
 	// DefaultScalarType allows you to override the type that unknown scalars will have (default: "any")
 	DefaultScalarType string `yaml:"defaultScalarType" json:"defaultScalarType"`
 	// StrictScalars if scalars are found in the schema that are not defined in scalars, an error will be thrown
@@ -51,6 +80,10 @@ type ClientPresetConfig struct {
 	NamingConvention interface{} `yaml:"namingConvention" json:"namingConvention"`
 	// EmitLegacyCommonJSImports controls CommonJS imports generation
 	EmitLegacyCommonJSImports bool `yaml:"emitLegacyCommonJSImports" json:"emitLegacyCommonJSImports"`
+	// ImportExtension is the extension of imports between the generated
+	// files, ".js", ".ts" or "" for bundler module resolution. Nil derives it
+	// from EmitLegacyCommonJSImports.
+	ImportExtension *string `yaml:"importExtension" json:"importExtension"`
 	// UseTypeImports will use import type {} rather than import {} when importing only types
 	UseTypeImports bool `yaml:"useTypeImports" json:"useTypeImports"`
 	// SkipTypename does not add __typename to the generated types, unless it was specified in the selection set
@@ -71,6 +104,8 @@ type ClientPresetConfig struct {
 	AvoidOptionals interface{} `yaml:"avoidOptionals" json:"avoidOptionals"`
 	// DocumentMode allows you to control how the documents are generated
 	DocumentMode string `yaml:"documentMode" json:"documentMode"`
+	// SortOverloadsBy orders the graphql() overloads by "source" (default) or operation "name"
+	SortOverloadsBy string `yaml:"sortOverloadsBy" json:"sortOverloadsBy"`
 	// SkipTypeNameForRoot avoid adding __typename for root types
 	SkipTypeNameForRoot bool `yaml:"skipTypeNameForRoot" json:"skipTypeNameForRoot"`
 	// OnlyOperationTypes causes the generator to emit types required for operations only
@@ -105,24 +140,64 @@ func (p *ClientPreset) PrepareDocuments(outputFilePath string, docs []*documents
 
 // BuildGeneratesSection builds the generation configuration for the client preset
 func (p *ClientPreset) BuildGeneratesSection(options *presets.PresetOptions) ([]*presets.GenerateOptions, error) {
-	// Validate that output is a directory
-	if !strings.HasSuffix(options.BaseOutputDir, "/") {
-		return nil, fmt.Errorf("client-preset requires output to be a directory (must end with /)")
-	}
-
 	// Parse preset config
 	config := p.parsePresetConfig(options.PresetConfig)
 
-	// Determine fragment masking settings
+	// Validate that output is a directory, or a file in single file mode
+	singleFile := false
+	switch config.OutputMode {
+	case "", "directory":
+		if !strings.HasSuffix(options.BaseOutputDir, "/") {
+			return nil, fmt.Errorf("client-preset requires output to be a directory (must end with /)")
+		}
+	case "single":
+		if strings.HasSuffix(options.BaseOutputDir, "/") {
+			return nil, fmt.Errorf("client-preset outputMode single requires output to be a file (must not end with /)")
+		}
+		if config.OperationModules || config.Framework != "" {
+			return nil, fmt.Errorf("client-preset outputMode single does not support operationModules or framework")
+		}
+		singleFile = true
+	default:
+		return nil, fmt.Errorf("client-preset: unsupported outputMode %q (expected \"directory\" or \"single\")", config.OutputMode)
+	}
+
+	if config.ImportExtension != nil {
+		if err := base.ValidateImportExtension(map[string]interface{}{"importExtension": *config.ImportExtension}); err != nil {
+			return nil, fmt.Errorf("client-preset: %w", err)
+		}
+	}
+
+	if config.Framework != "" {
+		if err := framework_hooks.ValidateFramework(config.Framework); err != nil {
+			return nil, fmt.Errorf("client-preset: %w", err)
+		}
+	}
+
+	// graphql.ts, or the output file itself in single file mode
+	graphqlFilename := filepath.Join(options.BaseOutputDir, "graphql.ts")
+	if singleFile {
+		graphqlFilename = options.BaseOutputDir
+	}
+
+	// A shared enums package needs neither operations nor the gql function
+	if config.OnlyEnums {
+		return p.buildOnlyEnumsGenerates(options, config, graphqlFilename), nil
+	}
+
+	// Determine fragment masking settings; a single file holds the helpers
 	fragmentMaskingConfig := p.parseFragmentMasking(config.FragmentMasking)
 	isFragmentMaskingEnabled := fragmentMaskingConfig != nil
+	inlineFragmentMasking := isFragmentMaskingEnabled && (config.InlineFragmentMasking || singleFile)
 
 	// Determine persisted documents settings
 	persistedDocsConfig := p.parsePersistedDocuments(config.PersistedDocuments)
 
 	// Initialize persisted documents map if needed
 	if persistedDocsConfig != nil {
+		p.mu.Lock()
 		p.persistedDocumentsMap = make(PersistedDocumentsManifest)
+		p.mu.Unlock()
 	}
 
 	// Process sources to extract operations and fragments
@@ -139,9 +214,22 @@ func (p *ClientPreset) BuildGeneratesSection(options *presets.PresetOptions) ([]
 	if isFragmentMaskingEnabled {
 		graphqlConfig["inlineFragmentTypes"] = "mask"
 	}
+	if config.EnumsAsConst {
+		// Read by typescript, which declares the const objects, and by
+		// typescript-operations, which references them
+		graphqlConfig["enumsAsConst"] = true
+	}
+	if len(config.Scalars) > 0 {
+		// Preset scalars override those of the output config
+		scalars := base.GetScalars(graphqlConfig, "scalars")
+		for name, mapping := range config.Scalars {
+			scalars[name] = mapping
+		}
+		graphqlConfig["scalars"] = scalars
+	}
 
-	generates = append(generates, &presets.GenerateOptions{
-		Filename: filepath.Join(options.BaseOutputDir, "graphql.ts"),
+	graphqlGen := &presets.GenerateOptions{
+		Filename: graphqlFilename,
 		Plugins: []string{
 			"add",
 			"typescript",
@@ -155,20 +243,56 @@ func (p *ClientPreset) BuildGeneratesSection(options *presets.PresetOptions) ([]
 			"typescript": map[string]interface{}{
 				"maybeValue": "T | null | undefined",
 			},
-			"typed-document-node": map[string]interface{}{
-				"unstable_omitDefinitions": persistedDocsConfig != nil && persistedDocsConfig.Mode == "replaceDocumentWithHash",
-			},
+			"typed-document-node": typedDocumentNodeConfig(persistedDocsConfig, config.DocumentMode),
 		},
 		Schema:    options.Schema,
 		Documents: options.Documents,
 		Config:    graphqlConfig,
-	})
+	}
+	if inlineFragmentMasking {
+		fragmentMaskingPluginConfig := fragmentMaskingConfigFor(fragmentMaskingConfig, config)
+		fragmentMaskingPluginConfig["inline"] = true
+		graphqlGen.Plugins = append(graphqlGen.Plugins, "fragment-masking")
+		graphqlGen.PluginConfig["fragment-masking"] = fragmentMaskingPluginConfig
+	}
+	generates = append(generates, graphqlGen)
 
 	// 2. gql.ts file with graphql tag functions
 	gqlTagName := config.GqlTagName
 	if gqlTagName == "" {
 		gqlTagName = "graphql"
 	}
+	gqlTagConfig := map[string]interface{}{
+		"gqlTagName":                gqlTagName,
+		"sourcesWithOperations":     sourcesWithOperations,
+		"useTypeImports":            config.UseTypeImports,
+		"emitLegacyCommonJSImports": config.EmitLegacyCommonJSImports,
+		"documentMode":              config.DocumentMode,
+	}
+	if config.SortOverloadsBy != "" {
+		gqlTagConfig["sortOverloadsBy"] = config.SortOverloadsBy
+	}
+	if config.ImportExtension != nil {
+		gqlTagConfig["importExtension"] = *config.ImportExtension
+	}
+
+	// A single file appends the gql function to the operations it looks up
+	// and needs neither index.ts nor the other modules
+	if singleFile {
+		gqlTagConfig["inline"] = true
+		graphqlGen.Plugins = append(graphqlGen.Plugins, "gql-tag-operations")
+		graphqlGen.PluginConfig["gql-tag-operations"] = gqlTagConfig
+
+		if persistedDocsConfig != nil {
+			manifestPath := filepath.Join(filepath.Dir(options.BaseOutputDir), "persisted-documents.json")
+			manifestGen, err := p.buildPersistedDocumentsManifest(options, persistedDocsConfig, manifestPath)
+			if err != nil {
+				return nil, err
+			}
+			generates = append(generates, manifestGen)
+		}
+		return generates, nil
+	}
 
 	generates = append(generates, &presets.GenerateOptions{
 		Filename: filepath.Join(options.BaseOutputDir, "gql.ts"),
@@ -180,28 +304,15 @@ func (p *ClientPreset) BuildGeneratesSection(options *presets.PresetOptions) ([]
 			"add": map[string]interface{}{
 				"content": "/* eslint-disable */",
 			},
-			"gql-tag-operations": map[string]interface{}{
-				"gqlTagName":              gqlTagName,
-				"sourcesWithOperations":   sourcesWithOperations,
-				"useTypeImports":          config.UseTypeImports,
-				"emitLegacyCommonJSImports": config.EmitLegacyCommonJSImports,
-				"documentMode":            config.DocumentMode,
-			},
+			"gql-tag-operations": gqlTagConfig,
 		},
 		Schema:    options.Schema,
 		Documents: options.Documents,
 		Config:    options.Config,
 	})
 
-	// 3. fragment-masking.ts file (if enabled)
-	if isFragmentMaskingEnabled {
-		fragmentMaskingPluginConfig := map[string]interface{}{
-			"useTypeImports": options.Config["useTypeImports"],
-		}
-		if fragmentMaskingConfig.UnmaskFunctionName != "" {
-			fragmentMaskingPluginConfig["unmaskFunctionName"] = fragmentMaskingConfig.UnmaskFunctionName
-		}
-
+	// 3. fragment-masking.ts file (if enabled and not inlined)
+	if isFragmentMaskingEnabled && !inlineFragmentMasking {
 		generates = append(generates, &presets.GenerateOptions{
 			Filename: filepath.Join(options.BaseOutputDir, "fragment-masking.ts"),
 			Plugins: []string{
@@ -212,12 +323,7 @@ func (p *ClientPreset) BuildGeneratesSection(options *presets.PresetOptions) ([]
 				"add": map[string]interface{}{
 					"content": "/* eslint-disable */",
 				},
-				"fragment-masking": map[string]interface{}{
-					"unmaskFunctionName":       fragmentMaskingConfig.UnmaskFunctionName,
-					"useTypeImports":           config.UseTypeImports,
-					"emitLegacyCommonJSImports": config.EmitLegacyCommonJSImports,
-					"isStringDocumentMode":     config.DocumentMode == "string",
-				},
+				"fragment-masking": fragmentMaskingConfigFor(fragmentMaskingConfig, config),
 			},
 			Schema:    options.Schema,
 			Documents: []*documents.Document{}, // No documents needed for fragment masking
@@ -228,14 +334,26 @@ func (p *ClientPreset) BuildGeneratesSection(options *presets.PresetOptions) ([]
 	// 4. index.ts file to re-export everything
 	var exports []string
 	exports = append(exports, "gql")
-	if isFragmentMaskingEnabled {
+	if isFragmentMaskingEnabled && !inlineFragmentMasking {
 		exports = append(exports, "fragment-masking")
 	}
+	if config.Framework != "" {
+		exports = append(exports, "hooks")
+	}
 
 	exportContent := ""
 	for _, exp := range exports {
 		exportContent += fmt.Sprintf("export * from './%s';\n", exp)
 	}
+	if inlineFragmentMasking {
+		// graphql.ts is not re-exported wholesale, so name the helpers
+		unmaskFunctionName := fragmentMaskingConfig.UnmaskFunctionName
+		if unmaskFunctionName == "" {
+			unmaskFunctionName = "useFragment"
+		}
+		exportContent += fmt.Sprintf("export { %s, makeFragmentData, isFragmentReady } from './graphql';\n", unmaskFunctionName)
+		exportContent += "export type { FragmentType } from './graphql';\n"
+	}
 
 	generates = append(generates, &presets.GenerateOptions{
 		Filename: filepath.Join(options.BaseOutputDir, "index.ts"),
@@ -250,26 +368,188 @@ func (p *ClientPreset) BuildGeneratesSection(options *presets.PresetOptions) ([]
 		Config:    map[string]interface{}{},
 	})
 
-	// 5. persisted-documents.json (if enabled)
+	// 5. hooks.ts with typed framework hooks (if enabled)
+	if config.Framework != "" {
+		generates = append(generates, p.buildHooks(options, config, graphqlConfig))
+	}
+
+	// 6. operations/<Name>.ts modules and their lazy index (if enabled)
+	if config.OperationModules {
+		generates = append(generates, p.buildOperationModules(options, config, graphqlConfig, persistedDocsConfig)...)
+	}
+
+	// 7. persisted-documents.json (if enabled)
 	if persistedDocsConfig != nil {
-		// Generate persisted documents manifest
-		p.generatePersistedDocumentsMap(options.Documents, persistedDocsConfig)
+		manifestPath := filepath.Join(options.BaseOutputDir, "persisted-documents.json")
+		manifestGen, err := p.buildPersistedDocumentsManifest(options, persistedDocsConfig, manifestPath)
+		if err != nil {
+			return nil, err
+		}
+		generates = append(generates, manifestGen)
+	}
+
+	return generates, nil
+}
+
+// buildPersistedDocumentsManifest generates the persisted documents manifest
+// at manifestPath, merged with the one on disk when keepExisting is set
+func (p *ClientPreset) buildPersistedDocumentsManifest(options *presets.PresetOptions, persistedDocsConfig *PersistedDocumentsConfig, manifestPath string) (*presets.GenerateOptions, error) {
+	manifest := p.generatePersistedDocumentsMap(options.Documents, persistedDocsConfig)
+	if persistedDocsConfig.KeepExisting {
+		existing, err := readPersistedDocumentsManifest(manifestPath)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Merge(existing)
+	}
+
+	return &presets.GenerateOptions{
+		Filename: manifestPath,
+		Plugins:  []string{"add"},
+		PluginConfig: map[string]interface{}{
+			"add": map[string]interface{}{
+				"content": manifest.ToJSON(),
+			},
+		},
+		Schema:    options.Schema,
+		Documents: []*documents.Document{},
+		Config:    map[string]interface{}{},
+	}, nil
+}
+
+// buildHooks generates hooks.ts, wrapping each operation document of
+// graphql.ts in a hook of the configured framework
+func (p *ClientPreset) buildHooks(options *presets.PresetOptions, config *ClientPresetConfig, graphqlConfig map[string]interface{}) *presets.GenerateOptions {
+	hooksConfig := map[string]interface{}{
+		"framework":       config.Framework,
+		"documentsImport": "./graphql" + config.importExtension(),
+	}
+	if config.Fetcher != "" {
+		hooksConfig["fetcher"] = config.Fetcher
+	}
+
+	return &presets.GenerateOptions{
+		Filename: filepath.Join(options.BaseOutputDir, "hooks.ts"),
+		Plugins: []string{
+			"add",
+			"framework-hooks",
+		},
+		PluginConfig: map[string]interface{}{
+			"add": map[string]interface{}{
+				"content": "/* eslint-disable */",
+			},
+			"framework-hooks": hooksConfig,
+		},
+		Schema:    options.Schema,
+		Documents: options.Documents,
+		Config:    graphqlConfig,
+	}
+}
+
+// buildOperationModules generates a module per named operation holding its
+// document, with the types imported from graphql.ts, and an index whose
+// loaders import each module on demand
+func (p *ClientPreset) buildOperationModules(options *presets.PresetOptions, config *ClientPresetConfig, graphqlConfig map[string]interface{}, persistedDocsConfig *PersistedDocumentsConfig) []*presets.GenerateOptions {
+	jsExt := config.importExtension()
+
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, frag := range documents.CollectAllFragments(options.Documents) {
+		fragments[frag.Name] = frag
+	}
+
+	operations := make(map[string]*documents.Document)
+	opDefs := make(map[string]*ast.OperationDefinition)
+	for _, doc := range options.Documents {
+		if doc.AST == nil {
+			continue
+		}
+		for _, op := range doc.AST.Operations {
+			if op.Name == "" || opDefs[op.Name] != nil {
+				continue
+			}
+			opDefs[op.Name] = op
+			operations[op.Name] = doc
+		}
+	}
+
+	names := make([]string, 0, len(opDefs))
+	for name := range opDefs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var generates []*presets.GenerateOptions
+	var index strings.Builder
+	index.WriteString("/* eslint-disable */\n")
+	for _, name := range names {
+		op := opDefs[name]
+		tdnConfig := typedDocumentNodeConfig(persistedDocsConfig, config.DocumentMode)
+		tdnConfig["typesImport"] = "../graphql" + jsExt
 
 		generates = append(generates, &presets.GenerateOptions{
-			Filename: filepath.Join(options.BaseOutputDir, "persisted-documents.json"),
-			Plugins:  []string{"add"},
+			Filename: filepath.Join(options.BaseOutputDir, "operations", name+".ts"),
+			Plugins: []string{
+				"add",
+				"typed-document-node",
+			},
 			PluginConfig: map[string]interface{}{
 				"add": map[string]interface{}{
-					"content": p.persistedDocumentsMap.ToJSON(),
+					"content": "/* eslint-disable */",
 				},
+				"typed-document-node": tdnConfig,
 			},
-			Schema:    options.Schema,
-			Documents: []*documents.Document{},
-			Config:    map[string]interface{}{},
+			Schema: options.Schema,
+			Documents: []*documents.Document{{
+				FilePath: operations[name].FilePath,
+				AST:      documents.OperationDocument(op, fragments),
+			}},
+			Config: graphqlConfig,
 		})
+
+		documentName := DefaultBuildName(op, nil)
+		index.WriteString(fmt.Sprintf("export const load%s = () => import('./%s%s').then((m) => m.%s);\n",
+			documentName, name, jsExt, documentName))
 	}
 
-	return generates, nil
+	generates = append(generates, &presets.GenerateOptions{
+		Filename: filepath.Join(options.BaseOutputDir, "operations", "index.ts"),
+		Plugins:  []string{"add"},
+		PluginConfig: map[string]interface{}{
+			"add": map[string]interface{}{
+				"content": index.String(),
+			},
+		},
+		Schema:    options.Schema,
+		Documents: []*documents.Document{},
+		Config:    map[string]interface{}{},
+	})
+
+	return generates
+}
+
+// buildOnlyEnumsGenerates generates graphql.ts with the schema's enums only
+func (p *ClientPreset) buildOnlyEnumsGenerates(options *presets.PresetOptions, config *ClientPresetConfig, filename string) []*presets.GenerateOptions {
+	return []*presets.GenerateOptions{
+		{
+			Filename: filename,
+			Plugins: []string{
+				"add",
+				"typescript",
+			},
+			PluginConfig: map[string]interface{}{
+				"add": map[string]interface{}{
+					"content": "/* eslint-disable */",
+				},
+				"typescript": map[string]interface{}{
+					"onlyEnums":    true,
+					"enumsAsConst": config.EnumsAsConst,
+				},
+			},
+			Schema:    options.Schema,
+			Documents: []*documents.Document{},
+			Config:    options.Config,
+		},
+	}
 }
 
 // parsePresetConfig parses the preset configuration
@@ -290,6 +570,26 @@ func (p *ClientPreset) parsePresetConfig(cfg interface{}) *ClientPresetConfig {
 			config.FragmentMasking = true // Default to enabled
 		}
 
+		if inline, ok := mapConfig["inlineFragmentMasking"].(bool); ok {
+			config.InlineFragmentMasking = inline
+		}
+
+		if outputMode, ok := mapConfig["outputMode"].(string); ok {
+			config.OutputMode = outputMode
+		}
+
+		if operationModules, ok := mapConfig["operationModules"].(bool); ok {
+			config.OperationModules = operationModules
+		}
+
+		if framework, ok := mapConfig["framework"].(string); ok {
+			config.Framework = framework
+		}
+
+		if fetcher, ok := mapConfig["fetcher"].(string); ok {
+			config.Fetcher = fetcher
+		}
+
 		// GQL tag name
 		if tagName, ok := mapConfig["gqlTagName"].(string); ok {
 			config.GqlTagName = tagName
@@ -301,13 +601,8 @@ func (p *ClientPreset) parsePresetConfig(cfg interface{}) *ClientPresetConfig {
 		}
 
 		// TypeScript type configuration
-		if scalars, ok := mapConfig["scalars"].(map[string]interface{}); ok {
-			config.Scalars = make(map[string]string)
-			for k, v := range scalars {
-				if strVal, ok := v.(string); ok {
-					config.Scalars[k] = strVal
-				}
-			}
+		if _, ok := mapConfig["scalars"]; ok {
+			config.Scalars = base.GetScalars(mapConfig, "scalars")
 		}
 
 		if defaultScalar, ok := mapConfig["defaultScalarType"].(string); ok {
@@ -322,6 +617,10 @@ func (p *ClientPreset) parsePresetConfig(cfg interface{}) *ClientPresetConfig {
 			config.NamingConvention = naming
 		}
 
+		if importExtension, ok := mapConfig["importExtension"].(string); ok {
+			config.ImportExtension = &importExtension
+		}
+
 		if useTypeImports, ok := mapConfig["useTypeImports"].(bool); ok {
 			config.UseTypeImports = useTypeImports
 		}
@@ -364,6 +663,10 @@ func (p *ClientPreset) parsePresetConfig(cfg interface{}) *ClientPresetConfig {
 			config.DocumentMode = docMode
 		}
 
+		if sortOverloadsBy, ok := mapConfig["sortOverloadsBy"].(string); ok {
+			config.SortOverloadsBy = sortOverloadsBy
+		}
+
 		if skipRootTypename, ok := mapConfig["skipTypeNameForRoot"].(bool); ok {
 			config.SkipTypeNameForRoot = skipRootTypename
 		}
@@ -389,6 +692,18 @@ func (p *ClientPreset) parsePresetConfig(cfg interface{}) *ClientPresetConfig {
 	return config
 }
 
+// importExtension returns the extension of imports between the generated
+// files
+func (c *ClientPresetConfig) importExtension() string {
+	if c.ImportExtension != nil {
+		return *c.ImportExtension
+	}
+	if c.EmitLegacyCommonJSImports {
+		return ""
+	}
+	return ".js"
+}
+
 // parseFragmentMasking parses fragment masking configuration
 func (p *ClientPreset) parseFragmentMasking(cfg interface{}) *FragmentMaskingConfig {
 	if cfg == nil {
@@ -406,6 +721,9 @@ func (p *ClientPreset) parseFragmentMasking(cfg interface{}) *FragmentMaskingCon
 		if unmaskName, ok := v["unmaskFunctionName"].(string); ok {
 			config.UnmaskFunctionName = unmaskName
 		}
+		if arrayName, ok := v["fragmentArrayName"].(string); ok {
+			config.FragmentArrayName = arrayName
+		}
 		return config
 	default:
 		// Default to enabled
@@ -413,6 +731,48 @@ func (p *ClientPreset) parseFragmentMasking(cfg interface{}) *FragmentMaskingCon
 	}
 }
 
+// fragmentMaskingConfigFor returns the fragment-masking plugin config
+func fragmentMaskingConfigFor(fragmentMaskingConfig *FragmentMaskingConfig, config *ClientPresetConfig) map[string]interface{} {
+	pluginConfig := map[string]interface{}{
+		"useTypeImports":            config.UseTypeImports,
+		"emitLegacyCommonJSImports": config.EmitLegacyCommonJSImports,
+		"isStringDocumentMode":      config.DocumentMode == "string",
+	}
+	if config.ImportExtension != nil {
+		pluginConfig["importExtension"] = *config.ImportExtension
+	}
+	if fragmentMaskingConfig.UnmaskFunctionName != "" {
+		pluginConfig["unmaskFunctionName"] = fragmentMaskingConfig.UnmaskFunctionName
+	}
+	if fragmentMaskingConfig.FragmentArrayName != "" {
+		pluginConfig["fragmentArrayName"] = fragmentMaskingConfig.FragmentArrayName
+	}
+	return pluginConfig
+}
+
+// typedDocumentNodeConfig passes the document mode and persisted documents
+// settings on to the typed-document-node plugin so the hashes it embeds match
+// the manifest. String documents then embed them as TypedDocumentString
+// objects.
+func typedDocumentNodeConfig(persistedDocsConfig *PersistedDocumentsConfig, documentMode string) map[string]interface{} {
+	tdnConfig := map[string]interface{}{
+		"unstable_omitDefinitions": false,
+	}
+	if documentMode != "" {
+		tdnConfig["documentMode"] = documentMode
+	}
+	if persistedDocsConfig == nil {
+		return tdnConfig
+	}
+
+	tdnConfig["unstable_omitDefinitions"] = persistedDocsConfig.Mode == "replaceDocumentWithHash"
+	tdnConfig["persistedDocuments"] = map[string]interface{}{
+		"hashPropertyName": persistedDocsConfig.HashPropertyName,
+		"hashAlgorithm":    persistedDocsConfig.HashAlgorithm,
+	}
+	return tdnConfig
+}
+
 // parsePersistedDocuments parses persisted documents configuration
 func (p *ClientPreset) parsePersistedDocuments(cfg interface{}) *PersistedDocumentsConfig {
 	if cfg == nil {
@@ -445,6 +805,12 @@ func (p *ClientPreset) parsePersistedDocuments(cfg interface{}) *PersistedDocume
 		if hashAlg, ok := v["hashAlgorithm"]; ok {
 			config.HashAlgorithm = hashAlg
 		}
+		if keepExisting, ok := v["keepExisting"].(bool); ok {
+			config.KeepExisting = keepExisting
+		}
+		if mergeWithExisting, ok := v["mergeWithExisting"].(bool); ok {
+			config.KeepExisting = config.KeepExisting || mergeWithExisting
+		}
 
 		return config
 	default:
@@ -475,25 +841,63 @@ func (p *ClientPreset) processDocuments(docs []*documents.Document) []gql_tag_op
 	return result
 }
 
-// generatePersistedDocumentsMap generates the persisted documents manifest
-func (p *ClientPreset) generatePersistedDocumentsMap(docs []*documents.Document, config *PersistedDocumentsConfig) {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+// generatePersistedDocumentsMap generates the persisted documents manifest.
+// The manifest is built for this output alone, so targets using the preset
+// concurrently do not mix their documents.
+func (p *ClientPreset) generatePersistedDocumentsMap(docs []*documents.Document, config *PersistedDocumentsConfig) PersistedDocumentsManifest {
+	manifest := make(PersistedDocumentsManifest)
 
-	for _, doc := range docs {
-		if doc.AST == nil {
+	// Documents are read in file order and a fragment name defined twice
+	// resolves to its first definition, the same one on every run
+	docs = append([]*documents.Document(nil), docs...)
+	sort.SliceStable(docs, func(i, j int) bool { return docs[i].FilePath < docs[j].FilePath })
+
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, frag := range documents.CollectAllFragments(docs) {
+		if _, ok := fragments[frag.Name]; !ok {
+			fragments[frag.Name] = frag
+		}
+	}
+
+	// Each operation is persisted with the fragments it uses, the same
+	// document typed-document-node hashes into the operation's __meta__
+	for _, op := range documents.CollectAllOperations(docs) {
+		if op.Name == "" {
 			continue
 		}
 
 		// Normalize and print the document
-		documentString := NormalizeAndPrintDocumentNode(doc.AST)
+		documentString := NormalizeAndPrintDocumentNode(documents.OperationDocument(op, fragments))
 
 		// Generate hash
 		hash := GenerateDocumentHash(documentString, config.HashAlgorithm)
 
 		// Store in manifest
-		p.persistedDocumentsMap[hash] = documentString
+		manifest[hash] = documentString
+	}
+
+	p.mu.Lock()
+	p.persistedDocumentsMap = manifest
+	p.mu.Unlock()
+	return manifest
+}
+
+// readPersistedDocumentsManifest reads the manifest of a previous run; a
+// missing file is an empty manifest
+func readPersistedDocumentsManifest(path string) (PersistedDocumentsManifest, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return PersistedDocumentsManifest{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading existing persisted documents: %w", err)
+	}
+
+	manifest, err := ParsePersistedDocumentsManifest(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing existing persisted documents %s: %w", path, err)
 	}
+	return manifest, nil
 }
 
 // OnExecutableDocumentNode handles document processing hooks
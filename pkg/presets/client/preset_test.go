@@ -1,11 +1,21 @@
 package client
 
 import (
+	"context"
+	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/jzeiders/graphql-go-gen/pkg/documents"
+	"github.com/jzeiders/graphql-go-gen/pkg/plugin"
+	"github.com/jzeiders/graphql-go-gen/pkg/plugins/framework_hooks"
+	"github.com/jzeiders/graphql-go-gen/pkg/plugins/typed_document_node"
+	"github.com/jzeiders/graphql-go-gen/pkg/plugins/typescript"
+	"github.com/jzeiders/graphql-go-gen/pkg/plugins/typescript_operations"
 	"github.com/jzeiders/graphql-go-gen/pkg/presets"
+	"github.com/jzeiders/graphql-go-gen/pkg/schema"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/vektah/gqlparser/v2"
@@ -104,6 +114,54 @@ func TestClientPreset_BuildGeneratesSection(t *testing.T) {
 		}
 	})
 
+	t.Run("inlines fragment masking into graphql.ts", func(t *testing.T) {
+		preset := &ClientPreset{}
+		options := &presets.PresetOptions{
+			BaseOutputDir: "src/gql/",
+			Schema:        schema,
+			Documents:     []*documents.Document{},
+			Config:        map[string]interface{}{},
+			PresetConfig: map[string]interface{}{
+				"fragmentMasking":       map[string]interface{}{"unmaskFunctionName": "getFragmentData"},
+				"inlineFragmentMasking": true,
+			},
+		}
+
+		generates, err := preset.BuildGeneratesSection(options)
+		require.NoError(t, err)
+
+		// Should generate: graphql.ts, gql.ts, index.ts (no fragment-masking.ts)
+		require.Len(t, generates, 3)
+		files := make(map[string]*presets.GenerateOptions)
+		for _, gen := range generates {
+			files[filepath.Base(gen.Filename)] = gen
+		}
+		assert.NotContains(t, files, "fragment-masking.ts")
+
+		graphqlGen := files["graphql.ts"]
+		require.NotNil(t, graphqlGen)
+		assert.Equal(t, "fragment-masking", graphqlGen.Plugins[len(graphqlGen.Plugins)-1])
+
+		fragmentMasking, ok := plugin.Get("fragment-masking")
+		require.True(t, ok)
+		resp, err := fragmentMasking.Generate(context.Background(), &plugin.GenerateRequest{
+			Config:     graphqlGen.PluginConfig["fragment-masking"].(map[string]interface{}),
+			OutputPath: graphqlGen.Filename,
+		})
+		require.NoError(t, err)
+
+		output := string(resp.Files[graphqlGen.Filename])
+		assert.Contains(t, output, "export function getFragmentData<TType>(")
+		assert.Contains(t, output, "export function makeFragmentData<")
+		assert.NotContains(t, output, "from './graphql")
+		assert.NotContains(t, output, "TypedDocumentNode }")
+
+		index := files["index.ts"].PluginConfig["add"].(map[string]interface{})["content"].(string)
+		assert.NotContains(t, index, "./fragment-masking")
+		assert.Contains(t, index, "export { getFragmentData, makeFragmentData, isFragmentReady } from './graphql';")
+		assert.Contains(t, index, "export type { FragmentType } from './graphql';")
+	})
+
 	t.Run("uses custom gql tag name", func(t *testing.T) {
 		preset := &ClientPreset{}
 		options := &presets.PresetOptions{
@@ -159,6 +217,27 @@ func TestClientPreset_BuildGeneratesSection(t *testing.T) {
 		assert.True(t, hasPersistedDocs)
 	})
 
+	t.Run("generates only enums", func(t *testing.T) {
+		preset := &ClientPreset{}
+		options := &presets.PresetOptions{
+			BaseOutputDir: "src/gql/",
+			Schema:        schema,
+			Documents:     []*documents.Document{},
+			Config:        map[string]interface{}{},
+			PresetConfig: map[string]interface{}{
+				"onlyEnums": true,
+			},
+		}
+
+		generates, err := preset.BuildGeneratesSection(options)
+		require.NoError(t, err)
+
+		require.Len(t, generates, 1)
+		assert.Equal(t, "graphql.ts", filepath.Base(generates[0].Filename))
+		assert.Equal(t, []string{"add", "typescript"}, generates[0].Plugins)
+		assert.Equal(t, true, generates[0].PluginConfig["typescript"].(map[string]interface{})["onlyEnums"])
+	})
+
 	t.Run("configures persisted documents with options", func(t *testing.T) {
 		preset := &ClientPreset{}
 		options := &presets.PresetOptions{
@@ -225,10 +304,15 @@ func TestClientPreset_parseFragmentMasking(t *testing.T) {
 	t.Run("parses config object", func(t *testing.T) {
 		config := map[string]interface{}{
 			"unmaskFunctionName": "readFragment",
+			"fragmentArrayName":  "FragmentArray",
 		}
 		result := preset.parseFragmentMasking(config)
 		assert.NotNil(t, result)
 		assert.Equal(t, "readFragment", result.UnmaskFunctionName)
+		assert.Equal(t, "FragmentArray", result.FragmentArrayName)
+
+		pluginConfig := fragmentMaskingConfigFor(result, &ClientPresetConfig{})
+		assert.Equal(t, "FragmentArray", pluginConfig["fragmentArrayName"])
 	})
 }
 
@@ -258,12 +342,20 @@ func TestClientPreset_parsePersistedDocuments(t *testing.T) {
 			"mode":             "replaceDocumentWithHash",
 			"hashPropertyName": "id",
 			"hashAlgorithm":    "sha256",
+			"keepExisting":     true,
 		}
 		result := preset.parsePersistedDocuments(config)
 		assert.NotNil(t, result)
 		assert.Equal(t, "replaceDocumentWithHash", result.Mode)
 		assert.Equal(t, "id", result.HashPropertyName)
 		assert.Equal(t, "sha256", result.HashAlgorithm)
+		assert.True(t, result.KeepExisting)
+	})
+
+	t.Run("accepts mergeWithExisting", func(t *testing.T) {
+		result := preset.parsePersistedDocuments(map[string]interface{}{"mergeWithExisting": true})
+		assert.NotNil(t, result)
+		assert.True(t, result.KeepExisting)
 	})
 }
 
@@ -387,4 +479,573 @@ func TestPersistedDocumentsManifest(t *testing.T) {
 		assert.Contains(t, json, "query GetUser")
 		assert.Contains(t, json, "query GetPosts")
 	})
-}
\ No newline at end of file
+
+	t.Run("round trips escaped documents", func(t *testing.T) {
+		escaped := PersistedDocumentsManifest{
+			"hash1": "query Search { search(text: \"a\\b\") { id } }",
+			"hash2": "query Multi {\n  id\n}",
+		}
+		parsed, err := ParsePersistedDocumentsManifest([]byte(escaped.ToJSON()))
+		require.NoError(t, err)
+		assert.Equal(t, escaped, parsed)
+	})
+}
+
+func TestClientPreset_PersistedDocumentsManifestFile(t *testing.T) {
+	astSchema, err := gqlparser.LoadSchema(&ast.Source{
+		Name:  "schema.graphql",
+		Input: `type User { id: ID! name: String! } type Query { user: User viewer: User }`,
+	})
+	require.NoError(t, err)
+
+	load := func(path, query string) *documents.Document {
+		doc, err := parser.ParseQuery(&ast.Source{Name: path, Input: query})
+		require.NoError(t, err)
+		return &documents.Document{FilePath: path, Content: query, AST: doc}
+	}
+	docs := []*documents.Document{
+		load("src/a.graphql", `query GetUser { user { ...UserFields } } fragment UserFields on User { id }`),
+		// Defines the fragment again; the first file by path wins on every run
+		load("src/b.graphql", `query GetViewer { viewer { ...UserFields } } fragment UserFields on User { name }`),
+	}
+
+	manifestFor := func(dir string, docs []*documents.Document, persisted map[string]interface{}) string {
+		t.Helper()
+		generates, err := (&ClientPreset{}).BuildGeneratesSection(&presets.PresetOptions{
+			BaseOutputDir: dir + "/",
+			Schema:        astSchema,
+			Documents:     docs,
+			Config:        map[string]interface{}{},
+			PresetConfig:  map[string]interface{}{"persistedDocuments": persisted},
+		})
+		require.NoError(t, err)
+		for _, gen := range generates {
+			if filepath.Base(gen.Filename) == "persisted-documents.json" {
+				return gen.PluginConfig["add"].(map[string]interface{})["content"].(string)
+			}
+		}
+		t.Fatal("no persisted-documents.json generated")
+		return ""
+	}
+
+	t.Run("does not depend on document order", func(t *testing.T) {
+		dir := t.TempDir()
+		first := manifestFor(dir, docs, map[string]interface{}{})
+		reversed := manifestFor(dir, []*documents.Document{docs[1], docs[0]}, map[string]interface{}{})
+		assert.Equal(t, first, reversed)
+		assert.Equal(t, 2, strings.Count(first, `fragment UserFields on User {\n\tid\n}`))
+	})
+
+	t.Run("keeps existing hashes", func(t *testing.T) {
+		dir := t.TempDir()
+		manifestPath := filepath.Join(dir, "persisted-documents.json")
+		require.NoError(t, os.WriteFile(manifestPath, []byte(`{
+  "removed": "query Removed { user { id } }",
+  "stale": "stale"
+}`), 0644))
+
+		kept := manifestFor(dir, docs[:1], map[string]interface{}{"keepExisting": true})
+		parsed, err := ParsePersistedDocumentsManifest([]byte(kept))
+		require.NoError(t, err)
+		assert.Len(t, parsed, 3)
+		assert.Equal(t, "query Removed { user { id } }", parsed["removed"])
+
+		// Writing the merged manifest back is stable across runs
+		require.NoError(t, os.WriteFile(manifestPath, []byte(kept), 0644))
+		assert.Equal(t, kept, manifestFor(dir, docs[:1], map[string]interface{}{"keepExisting": true}))
+
+		fresh := manifestFor(dir, docs[:1], map[string]interface{}{})
+		assert.NotContains(t, fresh, "removed")
+	})
+
+	t.Run("merges with the existing manifest across incremental builds", func(t *testing.T) {
+		dir := t.TempDir()
+		manifestPath := filepath.Join(dir, "persisted-documents.json")
+		merge := map[string]interface{}{"mergeWithExisting": true}
+
+		// The first build persists both documents, a later one only the first
+		first := manifestFor(dir, docs, merge)
+		require.NoError(t, os.WriteFile(manifestPath, []byte(first), 0644))
+		deployed, err := ParsePersistedDocumentsManifest([]byte(first))
+		require.NoError(t, err)
+
+		second := manifestFor(dir, docs[:1], merge)
+		merged, err := ParsePersistedDocumentsManifest([]byte(second))
+		require.NoError(t, err)
+		assert.Equal(t, deployed, merged)
+	})
+
+	t.Run("rejects an invalid existing manifest", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "persisted-documents.json"), []byte("not json"), 0644))
+		_, err := (&ClientPreset{}).BuildGeneratesSection(&presets.PresetOptions{
+			BaseOutputDir: dir + "/",
+			Schema:        astSchema,
+			Documents:     docs,
+			Config:        map[string]interface{}{},
+			PresetConfig:  map[string]interface{}{"persistedDocuments": map[string]interface{}{"keepExisting": true}},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "parsing existing persisted documents")
+	})
+}
+func TestClientPreset_PersistedDocumentHashesMatchTypedDocumentNode(t *testing.T) {
+	schema, err := gqlparser.LoadSchema(&ast.Source{
+		Name: "schema.graphql",
+		Input: `
+			type User { id: ID! name: String! }
+			type Query { user: User viewer: User }
+		`,
+	})
+	require.NoError(t, err)
+
+	// Parsed without validation so the client-only @client directive is kept
+	doc, err := parser.ParseQuery(&ast.Source{Input: `
+		query GetUser { user { ...UserFields } }
+		query GetViewer { viewer { id @client } }
+		fragment UserFields on User { id name }
+	`})
+	require.NoError(t, err)
+
+	for algorithm, length := range map[string]int{"sha256": 64, "sha256-trunc": 16, "fnv": 16} {
+		t.Run(algorithm, func(t *testing.T) {
+			preset := &ClientPreset{}
+			generates, err := preset.BuildGeneratesSection(&presets.PresetOptions{
+				BaseOutputDir: "src/gql/",
+				Schema:        schema,
+				Documents:     []*documents.Document{{FilePath: "src/queries.graphql", AST: doc}},
+				Config:        map[string]interface{}{},
+				PresetConfig: map[string]interface{}{
+					"persistedDocuments": map[string]interface{}{
+						"hashAlgorithm": algorithm,
+					},
+				},
+			})
+			require.NoError(t, err)
+
+			var graphqlGen *presets.GenerateOptions
+			for _, gen := range generates {
+				if filepath.Base(gen.Filename) == "graphql.ts" {
+					graphqlGen = gen
+				}
+			}
+			require.NotNil(t, graphqlGen)
+
+			tdnConfig := graphqlGen.PluginConfig["typed-document-node"].(map[string]interface{})
+			resp, err := typed_document_node.New().Generate(context.Background(), &plugin.GenerateRequest{
+				Documents:  graphqlGen.Documents,
+				Config:     tdnConfig,
+				OutputPath: "graphql.ts",
+			})
+			require.NoError(t, err)
+
+			hashes := regexp.MustCompile(`__meta__: \{ hash: "([0-9a-f]+)" \}`).FindAllStringSubmatch(string(resp.Files["graphql.ts"]), -1)
+			require.Len(t, hashes, 2)
+			assert.Len(t, preset.persistedDocumentsMap, 2)
+			for _, hash := range hashes {
+				assert.Len(t, hash[1], length)
+				assert.Contains(t, preset.persistedDocumentsMap, hash[1])
+			}
+
+			// The document hook hashes with the same algorithm
+			config := &ClientPresetConfig{}
+			persistedDocsConfig := &PersistedDocumentsConfig{HashPropertyName: "hash", HashAlgorithm: algorithm}
+			for _, op := range documents.CollectAllOperations([]*documents.Document{{AST: doc}}) {
+				opDoc := documents.OperationDocument(op, map[string]*ast.FragmentDefinition{"UserFields": doc.Fragments.ForName("UserFields")})
+				meta := preset.OnExecutableDocumentNode(opDoc, config, persistedDocsConfig)
+				assert.Contains(t, []string{hashes[0][1], hashes[1][1]}, meta["hash"])
+			}
+			assert.Len(t, preset.persistedDocumentsMap, 2)
+		})
+	}
+}
+
+func TestClientPreset_StringDocumentsEmbedPersistedHash(t *testing.T) {
+	schema, err := gqlparser.LoadSchema(&ast.Source{
+		Name:  "schema.graphql",
+		Input: `type User { id: ID! name: String! } type Query { user: User }`,
+	})
+	require.NoError(t, err)
+
+	doc, gqlErr := gqlparser.LoadQuery(schema, `query GetUser { user { ...UserFields } } fragment UserFields on User { id name }`)
+	require.Nil(t, gqlErr)
+
+	preset := &ClientPreset{}
+	generates, err := preset.BuildGeneratesSection(&presets.PresetOptions{
+		BaseOutputDir: "src/gql/",
+		Schema:        schema,
+		Documents:     []*documents.Document{{FilePath: "src/queries.graphql", AST: doc}},
+		Config:        map[string]interface{}{},
+		PresetConfig: map[string]interface{}{
+			"documentMode":       "string",
+			"persistedDocuments": true,
+		},
+	})
+	require.NoError(t, err)
+
+	var graphqlGen *presets.GenerateOptions
+	for _, gen := range generates {
+		if filepath.Base(gen.Filename) == "graphql.ts" {
+			graphqlGen = gen
+		}
+	}
+	require.NotNil(t, graphqlGen)
+
+	tdnConfig := graphqlGen.PluginConfig["typed-document-node"].(map[string]interface{})
+	assert.Equal(t, "string", tdnConfig["documentMode"])
+	resp, err := typed_document_node.New().Generate(context.Background(), &plugin.GenerateRequest{
+		Documents:  graphqlGen.Documents,
+		Config:     tdnConfig,
+		OutputPath: "graphql.ts",
+	})
+	require.NoError(t, err)
+	output := string(resp.Files["graphql.ts"])
+
+	assert.Contains(t, output, "export class TypedDocumentString<TResult, TVariables>")
+	hashes := regexp.MustCompile("export const GetUserDocument = new TypedDocumentString\\(`[^`]*`, \\{ hash: \"([0-9a-f]+)\" \\}\\)").FindStringSubmatch(output)
+	require.Len(t, hashes, 2, output)
+	assert.Contains(t, preset.persistedDocumentsMap, hashes[1])
+}
+
+func TestClientPreset_SplitScalars(t *testing.T) {
+	astSchema, err := gqlparser.LoadSchema(&ast.Source{
+		Name: "schema.graphql",
+		Input: `
+			scalar DateTime
+			scalar JSON
+			type Event { id: ID! startsAt: DateTime! payload: JSON }
+			type Query { events(after: DateTime!): [Event!]! }
+		`,
+	})
+	require.NoError(t, err)
+
+	doc, gqlErr := gqlparser.LoadQuery(astSchema, `query GetEvents($after: DateTime!) { events(after: $after) { id startsAt payload } }`)
+	require.Nil(t, gqlErr)
+
+	preset := &ClientPreset{}
+	generates, err := preset.BuildGeneratesSection(&presets.PresetOptions{
+		BaseOutputDir: "src/gql/",
+		Schema:        astSchema,
+		Documents:     []*documents.Document{{FilePath: "src/events.graphql", AST: doc}},
+		Config:        map[string]interface{}{},
+		PresetConfig: map[string]interface{}{
+			"scalars": map[string]interface{}{
+				"DateTime": map[string]interface{}{"input": "string", "output": "Date"},
+				"JSON":     "Record<string, unknown>",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	var graphqlGen *presets.GenerateOptions
+	for _, gen := range generates {
+		if filepath.Base(gen.Filename) == "graphql.ts" {
+			graphqlGen = gen
+		}
+	}
+	require.NotNil(t, graphqlGen)
+
+	// Run the plugins the way the generator does, with the plugin config
+	// merged over the file config
+	generate := func(p plugin.Plugin) string {
+		cfg := make(map[string]interface{})
+		for k, v := range graphqlGen.Config {
+			cfg[k] = v
+		}
+		if pluginConfig, ok := graphqlGen.PluginConfig[p.Name()].(map[string]interface{}); ok {
+			for k, v := range pluginConfig {
+				cfg[k] = v
+			}
+		}
+		resp, err := p.Generate(context.Background(), &plugin.GenerateRequest{
+			Schema:     schema.NewSchema(astSchema, ""),
+			Documents:  graphqlGen.Documents,
+			Config:     cfg,
+			OutputPath: "graphql.ts",
+		})
+		require.NoError(t, err)
+		return string(resp.Files["graphql.ts"])
+	}
+
+	types := generate(typescript.New())
+	assert.Contains(t, types, "DateTime: { input: string; output: Date };")
+	assert.Contains(t, types, "JSON: { input: Record<string, unknown>; output: Record<string, unknown> };")
+
+	operations := generate(typescript_operations.New())
+	assert.Contains(t, operations, "after: Scalars['DateTime']['input'];")
+	assert.Contains(t, operations, "startsAt: Date")
+	assert.NotContains(t, operations, "startsAt: string")
+}
+
+func TestClientPreset_OperationModules(t *testing.T) {
+	astSchema, err := gqlparser.LoadSchema(&ast.Source{
+		Name: "schema.graphql",
+		Input: `
+			type User { id: ID! name: String! }
+			type Query { user(id: ID!): User viewer: User }
+		`,
+	})
+	require.NoError(t, err)
+
+	doc, gqlErr := gqlparser.LoadQuery(astSchema, `
+		query GetUser($id: ID!) { user(id: $id) { ...UserFields } }
+		query GetViewer { viewer { id } }
+		fragment UserFields on User { id name }
+	`)
+	require.Nil(t, gqlErr)
+
+	preset := &ClientPreset{}
+	generates, err := preset.BuildGeneratesSection(&presets.PresetOptions{
+		BaseOutputDir: "src/gql/",
+		Schema:        astSchema,
+		Documents:     []*documents.Document{{FilePath: "src/queries.graphql", AST: doc}},
+		Config:        map[string]interface{}{},
+		PresetConfig: map[string]interface{}{
+			"operationModules": true,
+		},
+	})
+	require.NoError(t, err)
+
+	byName := make(map[string]*presets.GenerateOptions)
+	for _, gen := range generates {
+		byName[filepath.ToSlash(gen.Filename)] = gen
+	}
+	require.Contains(t, byName, "src/gql/operations/GetUser.ts")
+	require.Contains(t, byName, "src/gql/operations/GetViewer.ts")
+	require.Contains(t, byName, "src/gql/operations/index.ts")
+
+	assert.Equal(t, "/* eslint-disable */\n"+
+		"export const loadGetUserDocument = () => import('./GetUser.js').then((m) => m.GetUserDocument);\n"+
+		"export const loadGetViewerDocument = () => import('./GetViewer.js').then((m) => m.GetViewerDocument);\n",
+		byName["src/gql/operations/index.ts"].PluginConfig["add"].(map[string]interface{})["content"])
+
+	// Each module holds its own document and the fragments it spreads
+	getUser := byName["src/gql/operations/GetUser.ts"]
+	assert.Equal(t, []string{"add", "typed-document-node"}, getUser.Plugins)
+	require.Len(t, getUser.Documents, 1)
+	require.Len(t, getUser.Documents[0].AST.Operations, 1)
+	assert.Equal(t, "GetUser", getUser.Documents[0].AST.Operations[0].Name)
+	require.Len(t, getUser.Documents[0].AST.Fragments, 1)
+
+	resp, err := typed_document_node.New().Generate(context.Background(), &plugin.GenerateRequest{
+		Documents:  getUser.Documents,
+		Config:     getUser.PluginConfig["typed-document-node"].(map[string]interface{}),
+		OutputPath: "GetUser.ts",
+	})
+	require.NoError(t, err)
+	output := string(resp.Files["GetUser.ts"])
+
+	assert.Contains(t, output, "import type { GetUserQuery, GetUserQueryVariables, UserFieldsFragment } from '../graphql.js';")
+	assert.Contains(t, output, "export type { GetUserQuery, GetUserQueryVariables } from '../graphql.js';")
+	assert.Contains(t, output, "export const GetUserDocument =")
+	assert.Contains(t, output, "export const UserFieldsFragmentDoc =")
+	assert.NotContains(t, output, "GetViewerDocument")
+}
+
+func TestClientPreset_SingleFileOutput(t *testing.T) {
+	astSchema, err := gqlparser.LoadSchema(&ast.Source{
+		Name: "schema.graphql",
+		Input: `
+			type User { id: ID! name: String! }
+			type Query { user(id: ID!): User }
+		`,
+	})
+	require.NoError(t, err)
+
+	doc, gqlErr := gqlparser.LoadQuery(astSchema, `query GetUser($id: ID!) { user(id: $id) { id name } }`)
+	require.Nil(t, gqlErr)
+
+	build := func(output string, presetConfig map[string]interface{}) ([]*presets.GenerateOptions, error) {
+		return (&ClientPreset{}).BuildGeneratesSection(&presets.PresetOptions{
+			BaseOutputDir: output,
+			Schema:        astSchema,
+			Documents:     []*documents.Document{{FilePath: "src/queries.graphql", AST: doc}},
+			Config:        map[string]interface{}{},
+			PresetConfig:  presetConfig,
+		})
+	}
+
+	generates, err := build("src/generated.ts", map[string]interface{}{"outputMode": "single"})
+	require.NoError(t, err)
+	require.Len(t, generates, 1)
+
+	gen := generates[0]
+	assert.Equal(t, "src/generated.ts", gen.Filename)
+	// One eslint-disable header, then the operations, the fragment masking
+	// helpers and the gql function that looks up the operations
+	assert.Equal(t, []string{"add", "typescript", "typescript-operations", "typed-document-node", "fragment-masking", "gql-tag-operations"}, gen.Plugins)
+	assert.Equal(t, true, gen.PluginConfig["fragment-masking"].(map[string]interface{})["inline"])
+	assert.Equal(t, true, gen.PluginConfig["gql-tag-operations"].(map[string]interface{})["inline"])
+
+	// The persisted documents manifest is written next to the file
+	generates, err = build("src/generated.ts", map[string]interface{}{
+		"outputMode":         "single",
+		"persistedDocuments": true,
+	})
+	require.NoError(t, err)
+	require.Len(t, generates, 2)
+	assert.Equal(t, filepath.Join("src", "persisted-documents.json"), generates[1].Filename)
+
+	_, err = build("src/gql/", map[string]interface{}{"outputMode": "single"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must not end with /")
+
+	_, err = build("src/generated.ts", map[string]interface{}{"outputMode": "single", "operationModules": true})
+	require.Error(t, err)
+
+	_, err = build("src/gql/", map[string]interface{}{"outputMode": "files"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unsupported outputMode "files"`)
+}
+
+func TestClientPreset_FrameworkHooks(t *testing.T) {
+	astSchema, err := gqlparser.LoadSchema(&ast.Source{
+		Name: "schema.graphql",
+		Input: `
+			type User { id: ID! name: String! }
+			type Query { user(id: ID!): User }
+		`,
+	})
+	require.NoError(t, err)
+
+	doc, gqlErr := gqlparser.LoadQuery(astSchema, `query GetUser($id: ID!) { user(id: $id) { id name } }`)
+	require.Nil(t, gqlErr)
+
+	build := func(presetConfig map[string]interface{}) ([]*presets.GenerateOptions, error) {
+		return (&ClientPreset{}).BuildGeneratesSection(&presets.PresetOptions{
+			BaseOutputDir: "src/gql/",
+			Schema:        astSchema,
+			Documents:     []*documents.Document{{FilePath: "src/queries.graphql", AST: doc}},
+			Config:        map[string]interface{}{"omitOperationSuffix": true},
+			PresetConfig:  presetConfig,
+		})
+	}
+
+	generates, err := build(map[string]interface{}{
+		"framework": "react-query",
+		"fetcher":   "../client#request",
+	})
+	require.NoError(t, err)
+
+	byName := make(map[string]*presets.GenerateOptions)
+	for _, gen := range generates {
+		byName[filepath.ToSlash(gen.Filename)] = gen
+	}
+	require.Contains(t, byName, "src/gql/hooks.ts")
+	assert.Contains(t, byName["src/gql/index.ts"].PluginConfig["add"].(map[string]interface{})["content"], "export * from './hooks';\n")
+
+	hooks := byName["src/gql/hooks.ts"]
+	assert.Equal(t, []string{"add", "framework-hooks"}, hooks.Plugins)
+	hooksConfig := hooks.PluginConfig["framework-hooks"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"framework":       "react-query",
+		"documentsImport": "./graphql.js",
+		"fetcher":         "../client#request",
+	}, hooksConfig)
+
+	// Type names follow the graphql.ts config
+	config := make(map[string]interface{})
+	for k, v := range hooks.Config {
+		config[k] = v
+	}
+	for k, v := range hooksConfig {
+		config[k] = v
+	}
+	resp, err := framework_hooks.New().Generate(context.Background(), &plugin.GenerateRequest{
+		Documents:  hooks.Documents,
+		Config:     config,
+		OutputPath: "hooks.ts",
+	})
+	require.NoError(t, err)
+	assert.Contains(t, string(resp.Files["hooks.ts"]), "export function useGetUserQuery(variables: GetUserVariables, options?: Omit<UseQueryOptions<GetUser>, 'queryKey' | 'queryFn'>) {")
+
+	// Without a framework no hooks are generated
+	generates, err = build(map[string]interface{}{})
+	require.NoError(t, err)
+	for _, gen := range generates {
+		assert.NotEqual(t, "hooks.ts", filepath.Base(gen.Filename))
+	}
+
+	_, err = build(map[string]interface{}{"framework": "relay"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unsupported framework "relay"`)
+}
+
+func TestClientPreset_ImportExtension(t *testing.T) {
+	astSchema, err := gqlparser.LoadSchema(&ast.Source{
+		Name: "schema.graphql",
+		Input: `
+			type User { id: ID! name: String! }
+			type Query { user(id: ID!): User }
+		`,
+	})
+	require.NoError(t, err)
+
+	doc, gqlErr := gqlparser.LoadQuery(astSchema, `query GetUser($id: ID!) { user(id: $id) { id name } }`)
+	require.Nil(t, gqlErr)
+
+	build := func(presetConfig map[string]interface{}) ([]*presets.GenerateOptions, error) {
+		return (&ClientPreset{}).BuildGeneratesSection(&presets.PresetOptions{
+			BaseOutputDir: "src/gql/",
+			Schema:        astSchema,
+			Documents:     []*documents.Document{{FilePath: "src/queries.graphql", AST: doc}},
+			PresetConfig:  presetConfig,
+		})
+	}
+
+	generates, err := build(map[string]interface{}{
+		"importExtension": "",
+		"framework":       "react-query",
+	})
+	require.NoError(t, err)
+
+	byName := make(map[string]*presets.GenerateOptions)
+	for _, gen := range generates {
+		byName[filepath.ToSlash(gen.Filename)] = gen
+	}
+	assert.Equal(t, "", byName["src/gql/gql.ts"].PluginConfig["gql-tag-operations"].(map[string]interface{})["importExtension"])
+	assert.Equal(t, "", byName["src/gql/fragment-masking.ts"].PluginConfig["fragment-masking"].(map[string]interface{})["importExtension"])
+	assert.Equal(t, "./graphql", byName["src/gql/hooks.ts"].PluginConfig["framework-hooks"].(map[string]interface{})["documentsImport"])
+
+	_, err = build(map[string]interface{}{"importExtension": "mjs"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid importExtension")
+}
+
+func TestClientPreset_EnumsAsConst(t *testing.T) {
+	astSchema, err := gqlparser.LoadSchema(&ast.Source{
+		Name: "schema.graphql",
+		Input: `
+			enum Role { ADMIN USER }
+			type User { id: ID! role: Role! }
+			type Query { user(id: ID!): User }
+		`,
+	})
+	require.NoError(t, err)
+
+	doc, gqlErr := gqlparser.LoadQuery(astSchema, `query GetUser($id: ID!) { user(id: $id) { id role } }`)
+	require.Nil(t, gqlErr)
+
+	build := func(presetConfig map[string]interface{}) []*presets.GenerateOptions {
+		generates, err := (&ClientPreset{}).BuildGeneratesSection(&presets.PresetOptions{
+			BaseOutputDir: "src/gql/",
+			Schema:        astSchema,
+			Documents:     []*documents.Document{{FilePath: "src/queries.graphql", AST: doc}},
+			PresetConfig:  presetConfig,
+		})
+		require.NoError(t, err)
+		return generates
+	}
+
+	generates := build(map[string]interface{}{"enumsAsConst": true})
+	require.NotEmpty(t, generates)
+	assert.Equal(t, "src/gql/graphql.ts", filepath.ToSlash(generates[0].Filename))
+	assert.Equal(t, true, generates[0].Config["enumsAsConst"])
+
+	generates = build(map[string]interface{}{"enumsAsConst": true, "onlyEnums": true})
+	require.Len(t, generates, 1)
+	assert.Equal(t, true, generates[0].PluginConfig["typescript"].(map[string]interface{})["enumsAsConst"])
+
+	generates = build(map[string]interface{}{})
+	assert.NotContains(t, generates[0].Config, "enumsAsConst")
+}
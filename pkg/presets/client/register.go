@@ -3,6 +3,7 @@ package client
 import (
 	"github.com/jzeiders/graphql-go-gen/pkg/plugin"
 	"github.com/jzeiders/graphql-go-gen/pkg/plugins/fragment_masking"
+	"github.com/jzeiders/graphql-go-gen/pkg/plugins/framework_hooks"
 	"github.com/jzeiders/graphql-go-gen/pkg/plugins/gql_tag_operations"
 	"github.com/jzeiders/graphql-go-gen/pkg/presets"
 )
@@ -15,4 +16,5 @@ func init() {
 	// These will be registered when the plugin packages are imported
 	_ = plugin.Register("gql-tag-operations", gql_tag_operations.New())
 	_ = plugin.Register("fragment-masking", fragment_masking.New())
+	_ = plugin.Register("framework-hooks", framework_hooks.New())
 }
\ No newline at end of file
@@ -0,0 +1,119 @@
+package schema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// CanonicalHash returns a SHA-256 hash of the structure of a schema: its
+// root types, types and directive definitions, each sorted by name. Source
+// positions, whitespace, comments and the order types are declared in do
+// not affect it. Descriptions, the order of fields, arguments, enum values
+// and union members, and applied directives do, as they shape the
+// generated code.
+func CanonicalHash(s *ast.Schema) string {
+	var sb strings.Builder
+	if s != nil {
+		writeCanonicalSchema(&sb, s)
+	}
+	sum := sha256.Sum256([]byte(sb.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// writeCanonicalSchema writes one line per schema element, quoting every
+// name and text so no two schemas produce the same output
+func writeCanonicalSchema(sb *strings.Builder, s *ast.Schema) {
+	for _, root := range []struct {
+		operation string
+		def       *ast.Definition
+	}{{"query", s.Query}, {"mutation", s.Mutation}, {"subscription", s.Subscription}} {
+		if root.def != nil {
+			fmt.Fprintf(sb, "schema %s %q\n", root.operation, root.def.Name)
+		}
+	}
+
+	typeNames := make([]string, 0, len(s.Types))
+	for name := range s.Types {
+		typeNames = append(typeNames, name)
+	}
+	sort.Strings(typeNames)
+	for _, name := range typeNames {
+		writeCanonicalDefinition(sb, s.Types[name])
+	}
+
+	directiveNames := make([]string, 0, len(s.Directives))
+	for name := range s.Directives {
+		directiveNames = append(directiveNames, name)
+	}
+	sort.Strings(directiveNames)
+	for _, name := range directiveNames {
+		dir := s.Directives[name]
+		locations := make([]string, 0, len(dir.Locations))
+		for _, loc := range dir.Locations {
+			locations = append(locations, string(loc))
+		}
+		sort.Strings(locations)
+		fmt.Fprintf(sb, "directive %q %q repeatable=%t on %s\n", dir.Name, dir.Description, dir.IsRepeatable, strings.Join(locations, "|"))
+		writeCanonicalArguments(sb, dir.Arguments)
+	}
+}
+
+func writeCanonicalDefinition(sb *strings.Builder, def *ast.Definition) {
+	fmt.Fprintf(sb, "type %s %q %q\n", def.Kind, def.Name, def.Description)
+	writeCanonicalDirectives(sb, def.Directives)
+	for _, iface := range def.Interfaces {
+		fmt.Fprintf(sb, " implements %q\n", iface)
+	}
+	for _, member := range def.Types {
+		fmt.Fprintf(sb, " member %q\n", member)
+	}
+	for _, value := range def.EnumValues {
+		fmt.Fprintf(sb, " value %q %q\n", value.Name, value.Description)
+		writeCanonicalDirectives(sb, value.Directives)
+	}
+	for _, field := range def.Fields {
+		fmt.Fprintf(sb, " field %q %q %s%s\n", field.Name, field.Description, canonicalType(field.Type), canonicalValue(field.DefaultValue))
+		writeCanonicalArguments(sb, field.Arguments)
+		writeCanonicalDirectives(sb, field.Directives)
+	}
+}
+
+func writeCanonicalArguments(sb *strings.Builder, args ast.ArgumentDefinitionList) {
+	for _, arg := range args {
+		fmt.Fprintf(sb, "  arg %q %q %s%s\n", arg.Name, arg.Description, canonicalType(arg.Type), canonicalValue(arg.DefaultValue))
+		writeCanonicalDirectives(sb, arg.Directives)
+	}
+}
+
+func writeCanonicalDirectives(sb *strings.Builder, directives ast.DirectiveList) {
+	for _, dir := range directives {
+		fmt.Fprintf(sb, "  @%q", dir.Name)
+		for _, arg := range dir.Arguments {
+			fmt.Fprintf(sb, " %q%s", arg.Name, canonicalValue(arg.Value))
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// canonicalType renders a type reference, e.g. [String!]
+func canonicalType(t *ast.Type) string {
+	if t == nil {
+		return ""
+	}
+	return t.String()
+}
+
+// canonicalValue renders a default or argument value after "=", or nothing
+// when it is unset
+func canonicalValue(v *ast.Value) string {
+	if v == nil {
+		return ""
+	}
+	return "=" + strconv.Quote(v.String())
+}
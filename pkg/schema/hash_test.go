@@ -0,0 +1,117 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalHash(t *testing.T) {
+	base := `
+		"A user"
+		type User implements Node {
+			id: ID!
+			name(format: String = "full"): String @deprecated(reason: "use displayName")
+		}
+
+		interface Node { id: ID! }
+
+		enum Role { ADMIN MEMBER }
+
+		type Query {
+			user(id: ID!): User
+			roles: [Role!]!
+		}
+	`
+	hash := CanonicalHash(parseSchema(t, base))
+
+	t.Run("deterministic", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, hash, NewSchema(parseSchema(t, base), "schema.graphql").Hash())
+		}
+	})
+
+	t.Run("ignores formatting, comments and type order", func(t *testing.T) {
+		reformatted := `
+# Roles a user can have
+enum Role {
+  ADMIN
+  MEMBER
+}
+
+type Query {
+  user(id: ID!): User
+  roles: [Role!]!
+}
+
+interface Node {
+  id: ID!
+}
+
+"A user"
+type User implements Node {
+  id: ID!   # primary key
+  name(format: String = "full"): String @deprecated(reason: "use displayName")
+}
+`
+		assert.Equal(t, hash, CanonicalHash(parseSchema(t, reformatted)))
+	})
+
+	changes := map[string]string{
+		"field type": `
+			"A user"
+			type User implements Node {
+				id: ID!
+				name(format: String = "full"): String! @deprecated(reason: "use displayName")
+			}
+			interface Node { id: ID! }
+			enum Role { ADMIN MEMBER }
+			type Query { user(id: ID!): User roles: [Role!]! }
+		`,
+		"default value": `
+			"A user"
+			type User implements Node {
+				id: ID!
+				name(format: String = "short"): String @deprecated(reason: "use displayName")
+			}
+			interface Node { id: ID! }
+			enum Role { ADMIN MEMBER }
+			type Query { user(id: ID!): User roles: [Role!]! }
+		`,
+		"enum value": `
+			"A user"
+			type User implements Node {
+				id: ID!
+				name(format: String = "full"): String @deprecated(reason: "use displayName")
+			}
+			interface Node { id: ID! }
+			enum Role { ADMIN MEMBER GUEST }
+			type Query { user(id: ID!): User roles: [Role!]! }
+		`,
+		"deprecation": `
+			"A user"
+			type User implements Node {
+				id: ID!
+				name(format: String = "full"): String
+			}
+			interface Node { id: ID! }
+			enum Role { ADMIN MEMBER }
+			type Query { user(id: ID!): User roles: [Role!]! }
+		`,
+		"description": `
+			"A person"
+			type User implements Node {
+				id: ID!
+				name(format: String = "full"): String @deprecated(reason: "use displayName")
+			}
+			interface Node { id: ID! }
+			enum Role { ADMIN MEMBER }
+			type Query { user(id: ID!): User roles: [Role!]! }
+		`,
+	}
+	for name, sdl := range changes {
+		t.Run("changes with "+name, func(t *testing.T) {
+			assert.NotEqual(t, hash, CanonicalHash(parseSchema(t, sdl)))
+		})
+	}
+}
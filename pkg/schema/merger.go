@@ -3,6 +3,7 @@ package schema
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/vektah/gqlparser/v2/ast"
@@ -22,8 +23,37 @@ type MergeOptions struct {
 
 	// AllowEmptySchema allows merging to continue even if some schemas are empty
 	AllowEmptySchema bool
+
+	// CollectAllConflicts keeps merging past conflicts that have no resolver,
+	// keeping the first definition, and reports all of them at the end as a
+	// *MergeConflictsError instead of failing on the first one
+	CollectAllConflicts bool
+
+	// StrictOrder treats enums and unions with the same values or members in
+	// a different order as conflicting, for consumers whose generated code
+	// follows declaration order
+	StrictOrder bool
+
+	// DescriptionStrategy controls how descriptions of types and fields defined
+	// in several sources are combined. Defaults to DescriptionConcatenate.
+	DescriptionStrategy DescriptionStrategy
 }
 
+// DescriptionStrategy names a way of combining two non-empty descriptions.
+// A non-empty description always wins over an empty one.
+type DescriptionStrategy string
+
+const (
+	// DescriptionKeepFirst keeps the description from the first source
+	DescriptionKeepFirst DescriptionStrategy = "keepFirst"
+
+	// DescriptionKeepLast keeps the description from the last source
+	DescriptionKeepLast DescriptionStrategy = "keepLast"
+
+	// DescriptionConcatenate joins distinct descriptions, first source first
+	DescriptionConcatenate DescriptionStrategy = "concatenate"
+)
+
 // SchemaConflict represents a conflict between two schema definitions
 type SchemaConflict struct {
 	TypeName     string
@@ -38,6 +68,36 @@ func (c SchemaConflict) Error() string {
 		c.TypeName, c.LeftSource, c.RightSource, c.ConflictType, c.Details)
 }
 
+// MergeConflictsError is returned when MergeOptions.CollectAllConflicts is set
+// and the merge found one or more conflicts
+type MergeConflictsError struct {
+	conflicts []SchemaConflict
+}
+
+func (e *MergeConflictsError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("found %d schema conflicts:", len(e.conflicts)))
+	for _, conflict := range e.conflicts {
+		sb.WriteString("\n  - ")
+		sb.WriteString(conflict.Error())
+	}
+	return sb.String()
+}
+
+// Unwrap returns every conflict as an error, so errors.As can match them
+func (e *MergeConflictsError) Unwrap() []error {
+	errs := make([]error, len(e.conflicts))
+	for i := range e.conflicts {
+		errs[i] = &e.conflicts[i]
+	}
+	return errs
+}
+
+// Conflicts returns the conflicts in the order they were found
+func (e *MergeConflictsError) Conflicts() []SchemaConflict {
+	return append([]SchemaConflict(nil), e.conflicts...)
+}
+
 // SchemaMerger handles merging multiple GraphQL schemas
 type SchemaMerger struct {
 	options   MergeOptions
@@ -107,6 +167,10 @@ func (m *SchemaMerger) Merge(ctx context.Context, schemas []*ast.Schema, sources
 		}
 	}
 
+	if len(m.conflicts) > 0 {
+		return nil, &MergeConflictsError{conflicts: m.conflicts}
+	}
+
 	// Validate the merged schema has required types
 	if err := m.validateMergedSchema(merged); err != nil {
 		return nil, err
@@ -117,7 +181,15 @@ func (m *SchemaMerger) Merge(ctx context.Context, schemas []*ast.Schema, sources
 
 // mergeTypes merges type definitions from source into target
 func (m *SchemaMerger) mergeTypes(target, source *ast.Schema, sourceName string) error {
-	for typeName, sourceType := range source.Types {
+	// Visit types in a stable order so conflicts are reported deterministically
+	typeNames := make([]string, 0, len(source.Types))
+	for typeName := range source.Types {
+		typeNames = append(typeNames, typeName)
+	}
+	sort.Strings(typeNames)
+
+	for _, typeName := range typeNames {
+		sourceType := source.Types[typeName]
 		// Skip built-in types
 		if strings.HasPrefix(typeName, "__") {
 			continue
@@ -166,8 +238,12 @@ func (m *SchemaMerger) mergeTypes(target, source *ast.Schema, sourceName string)
 				conflict.TypeName = typeName
 				conflict.LeftSource = existingSource
 				conflict.RightSource = sourceName
-				return conflict
+				if err := m.reportConflict(conflict); err != nil {
+					return err
+				}
 			}
+		} else {
+			target.Types[typeName] = m.mergeDefinitionDescriptions(existingType, sourceType)
 		}
 	}
 
@@ -322,6 +398,18 @@ func (m *SchemaMerger) detectEnumConflict(left, right *ast.Definition) (*SchemaC
 		}
 	}
 
+	if m.options.StrictOrder {
+		leftNames := make([]string, 0, len(left.EnumValues))
+		for _, val := range left.EnumValues {
+			leftNames = append(leftNames, val.Name)
+		}
+		rightNames := make([]string, 0, len(right.EnumValues))
+		for _, val := range right.EnumValues {
+			rightNames = append(rightNames, val.Name)
+		}
+		return orderConflict(left.Name, "enum", "enum values", leftNames, rightNames), nil
+	}
+
 	return nil, nil
 }
 
@@ -356,32 +444,74 @@ func (m *SchemaMerger) detectUnionConflict(left, right *ast.Definition) (*Schema
 		}
 	}
 
+	if m.options.StrictOrder {
+		return orderConflict(left.Name, "union", "union member types", left.Types, right.Types), nil
+	}
+
 	return nil, nil
 }
 
-// mergeDirectives merges directive definitions
+// orderConflict reports two lists holding the same names in a different
+// order, for StrictOrder
+func orderConflict(typeName, conflictType, what string, left, right []string) *SchemaConflict {
+	for i := range left {
+		if i >= len(right) || left[i] != right[i] {
+			return &SchemaConflict{
+				TypeName:     typeName,
+				ConflictType: conflictType,
+				Details: fmt.Sprintf("%s are ordered differently: %s vs %s",
+					what, strings.Join(left, ", "), strings.Join(right, ", ")),
+			}
+		}
+	}
+	return nil
+}
+
+// mergeDirectives merges directive definitions. Definitions that only differ
+// in their locations are combined to allow every location of both; differing
+// arguments are a directive conflict, resolved by OnTypeConflict if set.
 func (m *SchemaMerger) mergeDirectives(target, source *ast.Schema, sourceName string) error {
-	for name, sourceDir := range source.Directives {
+	names := make([]string, 0, len(source.Directives))
+	for name := range source.Directives {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		sourceDir := source.Directives[name]
 		if existingDir, exists := target.Directives[name]; exists {
-			// Check for conflicts in directive definitions
-			if !directivesEqual(existingDir, sourceDir) {
-				if m.options.OnTypeConflict != nil {
-					// For now, just keep the first directive
-					// TODO: Add specific directive conflict resolution
-					continue
-				} else {
-					existingSource := "unknown"
-					if m.options.TrackSources && m.sources[name] != "" {
-						existingSource = m.sources[name]
-					}
-					return &SchemaConflict{
-						TypeName:     name,
-						LeftSource:   existingSource,
-						RightSource:  sourceName,
-						ConflictType: "directive",
-						Details:      fmt.Sprintf("directive %q has conflicting definitions", name),
-					}
+			if directivesEqual(existingDir, sourceDir) {
+				continue
+			}
+
+			existingSource := "unknown"
+			if m.options.TrackSources && m.sources[name] != "" {
+				existingSource = m.sources[name]
+			}
+
+			details := directiveArgumentsConflict(existingDir, sourceDir)
+			if details == "" {
+				target.Directives[name] = m.unionDirectiveLocations(existingDir, sourceDir, existingDir.Arguments)
+			} else if m.options.OnTypeConflict != nil {
+				resolved, err := m.resolveDirectiveConflict(existingDir, sourceDir)
+				if err != nil {
+					return fmt.Errorf("conflict resolution failed for directive @%s: %w", name, err)
 				}
+				target.Directives[name] = resolved
+			} else {
+				if err := m.reportConflict(&SchemaConflict{
+					TypeName:     name,
+					LeftSource:   existingSource,
+					RightSource:  sourceName,
+					ConflictType: "directive",
+					Details:      fmt.Sprintf("directive %q has conflicting definitions: %s", name, details),
+				}); err != nil {
+					return err
+				}
+				continue
+			}
+			if m.options.TrackSources {
+				m.sources[name] = fmt.Sprintf("%s+%s", existingSource, sourceName)
 			}
 		} else {
 			target.Directives[name] = sourceDir
@@ -393,6 +523,108 @@ func (m *SchemaMerger) mergeDirectives(target, source *ast.Schema, sourceName st
 	return nil
 }
 
+// directiveArgumentsConflict describes how the arguments or repeatability of
+// two directive definitions differ, or returns "" when only their locations
+// or descriptions do
+func directiveArgumentsConflict(left, right *ast.DirectiveDefinition) string {
+	if left.IsRepeatable != right.IsRepeatable {
+		return "repeatable in one definition but not the other"
+	}
+	for _, rightArg := range right.Arguments {
+		leftArg := left.Arguments.ForName(rightArg.Name)
+		if leftArg == nil {
+			return fmt.Sprintf("argument %q exists in one definition but not the other", rightArg.Name)
+		}
+		if !typesEqual(leftArg.Type, rightArg.Type) {
+			return fmt.Sprintf("argument %q has different types: %s vs %s", rightArg.Name, leftArg.Type.String(), rightArg.Type.String())
+		}
+		if (leftArg.DefaultValue != nil) != (rightArg.DefaultValue != nil) {
+			return fmt.Sprintf("argument %q has a default value in one definition but not the other", rightArg.Name)
+		}
+	}
+	for _, leftArg := range left.Arguments {
+		if right.Arguments.ForName(leftArg.Name) == nil {
+			return fmt.Sprintf("argument %q exists in one definition but not the other", leftArg.Name)
+		}
+	}
+	return ""
+}
+
+// unionDirectiveLocations returns a copy of left with args and the locations
+// of both definitions, left's first
+func (m *SchemaMerger) unionDirectiveLocations(left, right *ast.DirectiveDefinition, args ast.ArgumentDefinitionList) *ast.DirectiveDefinition {
+	merged := *left
+	merged.Description = m.mergeDescription(left.Description, right.Description)
+	merged.Arguments = args
+
+	merged.Locations = make([]ast.DirectiveLocation, 0, len(left.Locations)+len(right.Locations))
+	seen := make(map[ast.DirectiveLocation]bool)
+	for _, loc := range append(append([]ast.DirectiveLocation{}, left.Locations...), right.Locations...) {
+		if seen[loc] {
+			continue
+		}
+		seen[loc] = true
+		merged.Locations = append(merged.Locations, loc)
+	}
+	return &merged
+}
+
+// resolveDirectiveConflict passes two directive definitions with conflicting
+// arguments to OnTypeConflict as input objects, with the arguments as
+// fields, and conflict type "directive". A resolver keeping either side
+// keeps that definition; any other result supplies the arguments, and the
+// locations of both definitions are kept.
+func (m *SchemaMerger) resolveDirectiveConflict(left, right *ast.DirectiveDefinition) (*ast.DirectiveDefinition, error) {
+	leftDef, rightDef := directiveAsDefinition(left), directiveAsDefinition(right)
+	resolved, err := m.options.OnTypeConflict(leftDef, rightDef, "directive")
+	if err != nil {
+		return nil, err
+	}
+
+	switch resolved {
+	case leftDef:
+		return left, nil
+	case rightDef:
+		return right, nil
+	}
+
+	args := make(ast.ArgumentDefinitionList, 0, len(resolved.Fields))
+	for _, field := range resolved.Fields {
+		args = append(args, &ast.ArgumentDefinition{
+			Description:  field.Description,
+			Name:         field.Name,
+			DefaultValue: field.DefaultValue,
+			Type:         field.Type,
+			Directives:   field.Directives,
+			Position:     field.Position,
+		})
+	}
+	return m.unionDirectiveLocations(left, right, args), nil
+}
+
+// directiveAsDefinition represents a directive definition as an input object
+// with its arguments as fields, the shape conflict resolvers understand
+func directiveAsDefinition(dir *ast.DirectiveDefinition) *ast.Definition {
+	fields := make(ast.FieldList, 0, len(dir.Arguments))
+	for _, arg := range dir.Arguments {
+		fields = append(fields, &ast.FieldDefinition{
+			Description:  arg.Description,
+			Name:         arg.Name,
+			DefaultValue: arg.DefaultValue,
+			Type:         arg.Type,
+			Directives:   arg.Directives,
+			Position:     arg.Position,
+		})
+	}
+	return &ast.Definition{
+		Kind:        ast.InputObject,
+		Description: dir.Description,
+		Name:        dir.Name,
+		Fields:      fields,
+		Position:    dir.Position,
+	}
+}
+
 // mergeSchemaDefinition merges Query, Mutation, and Subscription types
 func (m *SchemaMerger) mergeSchemaDefinition(target, source *ast.Schema, sourceName string) error {
 	// Merge Query type
@@ -412,7 +644,7 @@ func (m *SchemaMerger) mergeSchemaDefinition(target, source *ast.Schema, sourceN
 			}
 
 			// Check for conflicts before merging
-			if err := m.checkObjectFieldConflicts(targetQuery, sourceQuery, "Query"); err != nil {
+			if err := m.checkObjectFieldConflicts(targetQuery, sourceQuery, "Query", sourceName); err != nil {
 				return err
 			}
 
@@ -440,7 +672,7 @@ func (m *SchemaMerger) mergeSchemaDefinition(target, source *ast.Schema, sourceN
 			}
 
 			// Check for conflicts before merging
-			if err := m.checkObjectFieldConflicts(targetMutation, sourceMutation, "Mutation"); err != nil {
+			if err := m.checkObjectFieldConflicts(targetMutation, sourceMutation, "Mutation", sourceName); err != nil {
 				return err
 			}
 
@@ -468,7 +700,7 @@ func (m *SchemaMerger) mergeSchemaDefinition(target, source *ast.Schema, sourceN
 			}
 
 			// Check for conflicts before merging
-			if err := m.checkObjectFieldConflicts(targetSubscription, sourceSubscription, "Subscription"); err != nil {
+			if err := m.checkObjectFieldConflicts(targetSubscription, sourceSubscription, "Subscription", sourceName); err != nil {
 				return err
 			}
 
@@ -483,43 +715,66 @@ func (m *SchemaMerger) mergeSchemaDefinition(target, source *ast.Schema, sourceN
 }
 
 // checkObjectFieldConflicts checks for field conflicts when merging object types
-func (m *SchemaMerger) checkObjectFieldConflicts(target, source *ast.Definition, typeName string) error {
+func (m *SchemaMerger) checkObjectFieldConflicts(target, source *ast.Definition, typeName string, sourceName string) error {
+	if m.options.OnTypeConflict != nil {
+		return nil
+	}
+
 	for _, sourceField := range source.Fields {
 		targetField := findField(target.Fields, sourceField.Name)
-		if targetField != nil {
+		if targetField == nil {
+			continue
+		}
+
+		var conflict *SchemaConflict
+		if !typesEqual(targetField.Type, sourceField.Type) {
 			// Check if field types match
-			if !typesEqual(targetField.Type, sourceField.Type) {
-				if m.options.OnTypeConflict == nil {
-					return &SchemaConflict{
-						TypeName:     typeName,
-						ConflictType: "field",
-						Details:      fmt.Sprintf("field %q has different types: %s vs %s", sourceField.Name, targetField.Type.String(), sourceField.Type.String()),
-					}
-				}
+			conflict = &SchemaConflict{
+				TypeName:     typeName,
+				ConflictType: "field",
+				Details:      fmt.Sprintf("field %q has different types: %s vs %s", sourceField.Name, targetField.Type.String(), sourceField.Type.String()),
 			}
-
+		} else if !argumentsEqual(targetField.Arguments, sourceField.Arguments) {
 			// Check for argument conflicts
-			if !argumentsEqual(targetField.Arguments, sourceField.Arguments) {
-				if m.options.OnTypeConflict == nil {
-					return &SchemaConflict{
-						TypeName:     typeName,
-						ConflictType: "argument",
-						Details:      fmt.Sprintf("field %q has different arguments", sourceField.Name),
-					}
-				}
+			conflict = &SchemaConflict{
+				TypeName:     typeName,
+				ConflictType: "argument",
+				Details:      fmt.Sprintf("field %q has different arguments", sourceField.Name),
 			}
 		}
+		if conflict == nil {
+			continue
+		}
+
+		conflict.LeftSource = m.sources[typeName]
+		if conflict.LeftSource == "" {
+			conflict.LeftSource = "unknown"
+		}
+		conflict.RightSource = sourceName
+		if err := m.reportConflict(conflict); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// reportConflict records a conflict that has no resolver. It returns the
+// conflict as an error unless CollectAllConflicts is set.
+func (m *SchemaMerger) reportConflict(conflict *SchemaConflict) error {
+	if !m.options.CollectAllConflicts {
+		return conflict
+	}
+	m.conflicts = append(m.conflicts, *conflict)
+	return nil
+}
+
 // mergeObjectFields merges fields from source into target, creating a new Definition
 func (m *SchemaMerger) mergeObjectFields(target, source *ast.Definition) *ast.Definition {
 	// Create a new definition with merged fields
 	merged := &ast.Definition{
 		Kind:        target.Kind,
 		Name:        target.Name,
-		Description: target.Description,
+		Description: m.mergeDescription(target.Description, source.Description),
 		Fields:      make(ast.FieldList, 0),
 		Interfaces:  target.Interfaces,
 		Directives:  target.Directives,
@@ -530,6 +785,9 @@ func (m *SchemaMerger) mergeObjectFields(target, source *ast.Definition) *ast.De
 	// Add all fields from target
 	fieldMap := make(map[string]*ast.FieldDefinition)
 	for _, field := range target.Fields {
+		if sourceField := findField(source.Fields, field.Name); sourceField != nil {
+			field = m.mergeFieldDescription(field, sourceField)
+		}
 		fieldMap[field.Name] = field
 		merged.Fields = append(merged.Fields, field)
 	}
@@ -559,6 +817,69 @@ func (m *SchemaMerger) mergeObjectFields(target, source *ast.Definition) *ast.De
 	return merged
 }
 
+// mergeDescription combines two descriptions according to the DescriptionStrategy
+func (m *SchemaMerger) mergeDescription(first, last string) string {
+	if last == "" || first == last {
+		return first
+	}
+	if first == "" {
+		return last
+	}
+
+	switch m.options.DescriptionStrategy {
+	case DescriptionKeepFirst:
+		return first
+	case DescriptionKeepLast:
+		return last
+	default:
+		// Skip descriptions that were already concatenated by an earlier merge
+		for _, part := range strings.Split(first, "\n\n") {
+			if part == last {
+				return first
+			}
+		}
+		return first + "\n\n" + last
+	}
+}
+
+// mergeFieldDescription returns field with its description merged with other's,
+// copying the field only when the description changes
+func (m *SchemaMerger) mergeFieldDescription(field, other *ast.FieldDefinition) *ast.FieldDefinition {
+	description := m.mergeDescription(field.Description, other.Description)
+	if description == field.Description {
+		return field
+	}
+
+	merged := *field
+	merged.Description = description
+	return &merged
+}
+
+// mergeDefinitionDescriptions merges the type and field descriptions of two
+// compatible definitions into a copy of target
+func (m *SchemaMerger) mergeDefinitionDescriptions(target, source *ast.Definition) *ast.Definition {
+	merged := *target
+	merged.Description = m.mergeDescription(target.Description, source.Description)
+
+	changed := merged.Description != target.Description
+	if len(target.Fields) > 0 && len(source.Fields) > 0 {
+		fields := make(ast.FieldList, len(target.Fields))
+		for i, field := range target.Fields {
+			fields[i] = field
+			if sourceField := findField(source.Fields, field.Name); sourceField != nil {
+				fields[i] = m.mergeFieldDescription(field, sourceField)
+				changed = changed || fields[i] != field
+			}
+		}
+		merged.Fields = fields
+	}
+
+	if !changed {
+		return target
+	}
+	return &merged
+}
+
 // argumentsEqual checks if two argument lists are equal
 func argumentsEqual(left, right ast.ArgumentDefinitionList) bool {
 	if len(left) != len(right) {
@@ -611,9 +932,56 @@ func (m *SchemaMerger) validateMergedSchema(schema *ast.Schema) error {
 		}
 	}
 
+	if dangling := danglingReferences(schema); len(dangling) > 0 {
+		return fmt.Errorf("merged schema references %d undefined types:\n  - %s", len(dangling), strings.Join(dangling, "\n  - "))
+	}
+
 	return nil
 }
 
+// danglingReferences lists the union members, implemented interfaces, field
+// types and argument types that name no type of the schema, e.g. because
+// conflict resolution dropped the source defining them
+func danglingReferences(schema *ast.Schema) []string {
+	var dangling []string
+	check := func(name, reference string) {
+		if name != "" && schema.Types[name] == nil {
+			dangling = append(dangling, fmt.Sprintf("%s: undefined type %q", reference, name))
+		}
+	}
+
+	typeNames := make([]string, 0, len(schema.Types))
+	for name := range schema.Types {
+		typeNames = append(typeNames, name)
+	}
+	sort.Strings(typeNames)
+
+	for _, typeName := range typeNames {
+		def := schema.Types[typeName]
+		if strings.HasPrefix(typeName, "__") {
+			continue
+		}
+		for _, member := range def.Types {
+			check(member, fmt.Sprintf("member of union %s", typeName))
+		}
+		for _, iface := range def.Interfaces {
+			check(iface, fmt.Sprintf("interface of %s", typeName))
+		}
+		for _, field := range def.Fields {
+			if strings.HasPrefix(field.Name, "__") || field.Type == nil {
+				continue
+			}
+			check(field.Type.Name(), fmt.Sprintf("field %s.%s", typeName, field.Name))
+			for _, arg := range field.Arguments {
+				if arg.Type != nil {
+					check(arg.Type.Name(), fmt.Sprintf("argument %s.%s(%s)", typeName, field.Name, arg.Name))
+				}
+			}
+		}
+	}
+	return dangling
+}
+
 // Helper functions
 
 func findField(fields ast.FieldList, name string) *ast.FieldDefinition {
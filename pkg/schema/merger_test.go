@@ -2,6 +2,7 @@ package schema
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -475,6 +476,111 @@ func TestMergeSchemas_DirectiveConflict(t *testing.T) {
 	assert.Contains(t, err.Error(), "directive")
 }
 
+func TestMergeSchemas_DirectiveLocationsUnion(t *testing.T) {
+	ctx := context.Background()
+
+	schema1 := parseSchema(t, `
+		directive @auth(role: String!) on FIELD_DEFINITION
+
+		type Query {
+			user: String @auth(role: "USER")
+		}
+	`)
+
+	schema2 := parseSchema(t, `
+		directive @auth(role: String!) on OBJECT | FIELD_DEFINITION
+
+		type Query {
+			admin: String @auth(role: "ADMIN")
+		}
+	`)
+
+	for name, resolver := range map[string]ConflictResolver{"no resolver": nil, "prefer left": ResolvePreferLeft} {
+		t.Run(name, func(t *testing.T) {
+			merged, err := MergeSchemas(ctx, []*ast.Schema{schema1, schema2}, []string{"schema1", "schema2"}, MergeOptions{OnTypeConflict: resolver})
+			require.NoError(t, err)
+
+			auth := merged.Directives["auth"]
+			require.NotNil(t, auth)
+			assert.Equal(t, []ast.DirectiveLocation{ast.LocationFieldDefinition, ast.LocationObject}, auth.Locations)
+			require.Len(t, auth.Arguments, 1)
+			assert.Equal(t, "role", auth.Arguments[0].Name)
+			assert.Equal(t, []ast.DirectiveLocation{ast.LocationFieldDefinition}, schema1.Directives["auth"].Locations, "inputs are not modified")
+		})
+	}
+}
+
+func TestMergeSchemas_DirectiveArgumentConflict(t *testing.T) {
+	ctx := context.Background()
+
+	schema1 := parseSchema(t, `
+		directive @auth(role: String!) on FIELD_DEFINITION
+
+		type Query {
+			user: String @auth(role: "USER")
+		}
+	`)
+
+	schema2 := parseSchema(t, `
+		directive @auth(role: Int!, scope: String) on OBJECT
+
+		type Query {
+			admin: String
+		}
+	`)
+	schemas := []*ast.Schema{schema1, schema2}
+	names := []string{"schema1", "schema2"}
+
+	t.Run("no resolver", func(t *testing.T) {
+		_, err := MergeSchemas(ctx, schemas, names, MergeOptions{})
+		var conflict *SchemaConflict
+		require.ErrorAs(t, err, &conflict)
+		assert.Equal(t, "directive", conflict.ConflictType)
+		assert.Contains(t, conflict.Details, `argument "role" has different types: String! vs Int!`)
+	})
+
+	t.Run("resolver is called", func(t *testing.T) {
+		var conflictTypes []string
+		resolver := func(left, right *ast.Definition, conflictType string) (*ast.Definition, error) {
+			conflictTypes = append(conflictTypes, conflictType)
+			return right, nil
+		}
+		merged, err := MergeSchemas(ctx, schemas, names, MergeOptions{OnTypeConflict: resolver})
+		require.NoError(t, err)
+		assert.Contains(t, conflictTypes, "directive")
+
+		auth := merged.Directives["auth"]
+		assert.Equal(t, "Int!", auth.Arguments.ForName("role").Type.String())
+		assert.NotNil(t, auth.Arguments.ForName("scope"))
+		assert.Equal(t, []ast.DirectiveLocation{ast.LocationObject}, auth.Locations)
+	})
+
+	t.Run("union resolver reports incompatible arguments", func(t *testing.T) {
+		_, err := MergeSchemas(ctx, schemas, names, MergeOptions{OnTypeConflict: ResolveUnionFields})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "conflict resolution failed for directive @auth")
+		assert.Contains(t, err.Error(), `field "role" has different types`)
+	})
+
+	t.Run("union resolver combines compatible arguments", func(t *testing.T) {
+		schema3 := parseSchema(t, `
+			directive @auth(role: String!, scope: String) on OBJECT
+
+			type Query {
+				admin: String
+			}
+		`)
+		merged, err := MergeSchemas(ctx, []*ast.Schema{schema1, schema3}, names, MergeOptions{OnTypeConflict: ResolveUnionFields})
+		require.NoError(t, err)
+
+		auth := merged.Directives["auth"]
+		require.Len(t, auth.Arguments, 2)
+		assert.Equal(t, "role", auth.Arguments[0].Name)
+		assert.Equal(t, "scope", auth.Arguments[1].Name)
+		assert.Equal(t, []ast.DirectiveLocation{ast.LocationFieldDefinition, ast.LocationObject}, auth.Locations)
+	})
+}
+
 func TestMergeSchemas_MutationAndSubscription(t *testing.T) {
 	ctx := context.Background()
 
@@ -521,4 +627,400 @@ func TestMergeSchemas_MutationAndSubscription(t *testing.T) {
 	// Check Subscription exists
 	assert.NotNil(t, merged.Subscription)
 	assert.Equal(t, 1, len(merged.Subscription.Fields))
-}
\ No newline at end of file
+}
+func TestMergeSchemas_ResolveUnionFields(t *testing.T) {
+	ctx := context.Background()
+
+	schema1 := parseSchema(t, `
+		type Query {
+			users: [User]
+		}
+
+		type User {
+			id: ID!
+			name: String
+			email: String
+		}
+
+		enum Status {
+			ACTIVE
+			INACTIVE
+		}
+	`)
+
+	schema2 := parseSchema(t, `
+		type Query {
+			getUser: User
+		}
+
+		type User {
+			id: ID!
+			name: String
+			age: Int
+		}
+
+		enum Status {
+			ACTIVE
+			PENDING
+		}
+	`)
+
+	merged, err := MergeSchemas(ctx, []*ast.Schema{schema1, schema2}, []string{"schema1", "schema2"}, MergeOptions{
+		OnTypeConflict: ResolveUnionFields,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, merged)
+
+	user := merged.Types["User"]
+	require.NotNil(t, user)
+
+	var fieldNames []string
+	for _, field := range user.Fields {
+		fieldNames = append(fieldNames, field.Name)
+	}
+	assert.Equal(t, []string{"id", "name", "email", "age"}, fieldNames)
+
+	status := merged.Types["Status"]
+	require.NotNil(t, status)
+
+	var values []string
+	for _, val := range status.EnumValues {
+		values = append(values, val.Name)
+	}
+	assert.Equal(t, []string{"ACTIVE", "INACTIVE", "PENDING"}, values)
+}
+
+func TestMergeSchemas_ResolveUnionFields_IncompatibleField(t *testing.T) {
+	ctx := context.Background()
+
+	schema1 := parseSchema(t, `
+		type Query {
+			user: User
+		}
+
+		type User {
+			id: ID!
+			email: String
+		}
+	`)
+
+	schema2 := parseSchema(t, `
+		type Query {
+			getUser: User
+		}
+
+		type User {
+			id: String!
+			age: Int
+		}
+	`)
+
+	_, err := MergeSchemas(ctx, []*ast.Schema{schema1, schema2}, []string{"schema1", "schema2"}, MergeOptions{
+		OnTypeConflict: ResolveUnionFields,
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "User")
+}
+
+func TestConflictStrategy_Resolver(t *testing.T) {
+	left := &ast.Definition{Kind: ast.Union, Name: "SearchResult", Types: []string{"User", "Post"}}
+	right := &ast.Definition{Kind: ast.Union, Name: "SearchResult", Types: []string{"Post", "Comment"}}
+
+	assert.Nil(t, ConflictStrategyError.Resolver())
+
+	resolved, err := ConflictStrategyPreferLeft.Resolver()(left, right, "union")
+	require.NoError(t, err)
+	assert.Same(t, left, resolved)
+
+	resolved, err = ConflictStrategyPreferRight.Resolver()(left, right, "union")
+	require.NoError(t, err)
+	assert.Same(t, right, resolved)
+
+	resolved, err = ConflictStrategyUnion.Resolver()(left, right, "union")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"User", "Post", "Comment"}, resolved.Types)
+	assert.Equal(t, []string{"User", "Post"}, left.Types, "left definition must not be modified")
+}
+
+func TestMergeSchemas_Descriptions(t *testing.T) {
+	ctx := context.Background()
+
+	schema1 := parseSchema(t, `
+		type Query {
+			"Look up a user"
+			user(id: ID!): User
+		}
+
+		"A registered user"
+		type User {
+			id: ID!
+			"Display name"
+			name: String
+			email: String
+		}
+	`)
+
+	schema2 := parseSchema(t, `
+		type Query {
+			user(id: ID!): User
+			"List all users"
+			users: [User!]!
+		}
+
+		"Account owner"
+		type User {
+			"Unique identifier"
+			id: ID!
+			name: String
+			"Primary email address"
+			email: String
+		}
+	`)
+
+	tests := []struct {
+		name     string
+		strategy DescriptionStrategy
+		userDesc string
+	}{
+		{"default concatenates", "", "A registered user\n\nAccount owner"},
+		{"concatenate", DescriptionConcatenate, "A registered user\n\nAccount owner"},
+		{"keep first", DescriptionKeepFirst, "A registered user"},
+		{"keep last", DescriptionKeepLast, "Account owner"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, err := MergeSchemas(ctx, []*ast.Schema{schema1, schema2}, []string{"schema1", "schema2"}, MergeOptions{
+				DescriptionStrategy: tt.strategy,
+			})
+			require.NoError(t, err)
+
+			user := merged.Types["User"]
+			require.NotNil(t, user)
+			assert.Equal(t, tt.userDesc, user.Description)
+
+			// Fields prefer whichever side has a description
+			assert.Equal(t, "Unique identifier", user.Fields.ForName("id").Description)
+			assert.Equal(t, "Display name", user.Fields.ForName("name").Description)
+			assert.Equal(t, "Primary email address", user.Fields.ForName("email").Description)
+
+			assert.Equal(t, "Look up a user", merged.Query.Fields.ForName("user").Description)
+			assert.Equal(t, "List all users", merged.Query.Fields.ForName("users").Description)
+		})
+	}
+
+	// Inputs must not be modified by the merge
+	assert.Equal(t, "A registered user", schema1.Types["User"].Description)
+	assert.Empty(t, schema1.Types["User"].Fields.ForName("id").Description)
+}
+
+func TestMergeSchemas_DescriptionsDeduped(t *testing.T) {
+	ctx := context.Background()
+
+	schema := `
+		type Query {
+			hello: String
+		}
+
+		"A registered user"
+		type User {
+			id: ID!
+		}
+	`
+
+	merged, err := MergeSchemas(ctx,
+		[]*ast.Schema{parseSchema(t, schema), parseSchema(t, schema), parseSchema(t, schema)},
+		[]string{"schema1", "schema2", "schema3"},
+		MergeOptions{DescriptionStrategy: DescriptionConcatenate},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "A registered user", merged.Types["User"].Description)
+}
+
+func TestMergeSchemas_CollectAllConflicts(t *testing.T) {
+	ctx := context.Background()
+
+	schema1 := parseSchema(t, `
+		type Query {
+			user(id: ID!): User
+			count: Int
+		}
+
+		type User {
+			id: ID!
+			name: String
+		}
+
+		enum Role {
+			ADMIN
+			USER
+		}
+	`)
+
+	schema2 := parseSchema(t, `
+		type Query {
+			user(id: ID!): User
+			count: String
+		}
+
+		type User {
+			id: ID!
+			email: String
+		}
+
+		enum Role {
+			ADMIN
+			GUEST
+		}
+	`)
+
+	merged, err := MergeSchemas(ctx, []*ast.Schema{schema1, schema2}, []string{"schema1", "schema2"}, MergeOptions{
+		TrackSources:        true,
+		CollectAllConflicts: true,
+	})
+	require.Error(t, err)
+	assert.Nil(t, merged)
+
+	var conflictsErr *MergeConflictsError
+	require.True(t, errors.As(err, &conflictsErr))
+
+	conflicts := conflictsErr.Conflicts()
+	require.Len(t, conflicts, 3)
+	assert.Equal(t, "Role", conflicts[0].TypeName)
+	assert.Equal(t, "User", conflicts[1].TypeName)
+	assert.Equal(t, "Query", conflicts[2].TypeName)
+	assert.Equal(t, "field", conflicts[2].ConflictType)
+	for _, conflict := range conflicts {
+		assert.Equal(t, "schema2", conflict.RightSource)
+	}
+
+	assert.Len(t, conflictsErr.Unwrap(), 3)
+	var single *SchemaConflict
+	require.True(t, errors.As(err, &single))
+	assert.Equal(t, "Role", single.TypeName)
+	assert.Contains(t, err.Error(), "found 3 schema conflicts")
+
+	// Without the option the merge still stops at the first conflict
+	_, err = MergeSchemas(ctx, []*ast.Schema{schema1, schema2}, []string{"schema1", "schema2"}, MergeOptions{})
+	require.Error(t, err)
+	assert.False(t, errors.As(err, &conflictsErr))
+}
+
+func TestMergeSchemas_DanglingReferences(t *testing.T) {
+	ctx := context.Background()
+
+	schema1 := parseSchema(t, `
+		interface Node { id: ID! }
+		type User implements Node { id: ID! name: String }
+		union SearchResult = User
+
+		type Query {
+			search(term: String): [SearchResult!]!
+		}
+	`)
+
+	schema2 := parseSchema(t, `
+		type User { id: ID! email: String }
+
+		type Query {
+			user(id: ID!): User
+		}
+	`)
+
+	// A resolver building its own definition can name types no source has
+	resolver := func(left, right *ast.Definition, conflictType string) (*ast.Definition, error) {
+		merged := *left
+		merged.Interfaces = []string{"Node", "Entity"}
+		merged.Fields = append(ast.FieldList{}, left.Fields...)
+		merged.Fields = append(merged.Fields, &ast.FieldDefinition{
+			Name:      "avatar",
+			Type:      ast.NamedType("Image", nil),
+			Arguments: ast.ArgumentDefinitionList{{Name: "size", Type: ast.NonNullNamedType("ImageSize", nil)}},
+		})
+		return &merged, nil
+	}
+
+	_, err := MergeSchemas(ctx, []*ast.Schema{schema1, schema2}, []string{"schema1", "schema2"}, MergeOptions{OnTypeConflict: resolver})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "merged schema references 3 undefined types")
+	assert.Contains(t, err.Error(), `interface of User: undefined type "Entity"`)
+	assert.Contains(t, err.Error(), `field User.avatar: undefined type "Image"`)
+	assert.Contains(t, err.Error(), `argument User.avatar(size): undefined type "ImageSize"`)
+
+	t.Run("union members", func(t *testing.T) {
+		s := parseSchema(t, `
+			type User { id: ID! }
+			type Post { id: ID! }
+			union SearchResult = User | Post
+			type Query { search: [SearchResult!]! }
+		`)
+		delete(s.Types, "Post")
+		assert.Equal(t, []string{`member of union SearchResult: undefined type "Post"`}, danglingReferences(s))
+	})
+
+	t.Run("complete schema", func(t *testing.T) {
+		merged, err := MergeSchemas(ctx, []*ast.Schema{schema1, schema2}, []string{"schema1", "schema2"}, MergeOptions{OnTypeConflict: ResolveUnionFields})
+		require.NoError(t, err)
+		assert.Empty(t, danglingReferences(merged))
+	})
+}
+
+func TestMergeSchemas_StrictOrder(t *testing.T) {
+	ctx := context.Background()
+
+	schema1 := parseSchema(t, `
+		enum Role { ADMIN MEMBER GUEST }
+		type User { id: ID! }
+		type Post { id: ID! }
+		union SearchResult = User | Post
+
+		type Query {
+			roles: [Role!]!
+			search: [SearchResult!]!
+		}
+	`)
+
+	reorderedEnum := parseSchema(t, `
+		enum Role { GUEST MEMBER ADMIN }
+
+		type Query {
+			role: Role
+		}
+	`)
+
+	reorderedUnion := parseSchema(t, `
+		type User { id: ID! }
+		type Post { id: ID! }
+		union SearchResult = Post | User
+
+		type Query {
+			first: SearchResult
+		}
+	`)
+
+	for name, other := range map[string]*ast.Schema{"enum": reorderedEnum, "union": reorderedUnion} {
+		t.Run(name, func(t *testing.T) {
+			schemas := []*ast.Schema{schema1, other}
+			names := []string{"schema1", "schema2"}
+
+			_, err := MergeSchemas(ctx, schemas, names, MergeOptions{})
+			require.NoError(t, err, "order is ignored by default")
+
+			_, err = MergeSchemas(ctx, schemas, names, MergeOptions{StrictOrder: true})
+			var conflict *SchemaConflict
+			require.ErrorAs(t, err, &conflict)
+			assert.Equal(t, name, conflict.ConflictType)
+			assert.Contains(t, conflict.Details, "ordered differently")
+
+			merged, err := MergeSchemas(ctx, schemas, names, MergeOptions{StrictOrder: true, OnTypeConflict: ResolvePreferLeft})
+			require.NoError(t, err, "resolvers handle order conflicts")
+			assert.Equal(t, []string{"User", "Post"}, merged.Types["SearchResult"].Types)
+			assert.Equal(t, "ADMIN", merged.Types["Role"].EnumValues[0].Name)
+		})
+	}
+
+	t.Run("same order", func(t *testing.T) {
+		_, err := MergeSchemas(ctx, []*ast.Schema{schema1, schema1}, []string{"schema1", "schema2"}, MergeOptions{StrictOrder: true})
+		require.NoError(t, err)
+	})
+}
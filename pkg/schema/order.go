@@ -0,0 +1,55 @@
+package schema
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// kindOrder is the position of each type kind in sorted schema output
+var kindOrder = map[ast.DefinitionKind]int{
+	ast.Scalar:      0,
+	ast.Enum:        1,
+	ast.Interface:   2,
+	ast.Union:       3,
+	ast.InputObject: 4,
+	ast.Object:      5,
+}
+
+// KindOrder returns the position of a type kind in sorted schema output:
+// scalars, enums, interfaces, unions, inputs, then objects. The kinds are
+// the introspection __TypeKind names, so introspection results sort the
+// same way as parsed schemas. Unknown kinds go last.
+func KindOrder(kind ast.DefinitionKind) int {
+	if order, ok := kindOrder[kind]; ok {
+		return order
+	}
+	return len(kindOrder)
+}
+
+// LessDefinition reports whether a type of kind a named nameA sorts before
+// one of kind b named nameB: by kind group, then alphabetically
+func LessDefinition(a ast.DefinitionKind, nameA string, b ast.DefinitionKind, nameB string) bool {
+	if orderA, orderB := KindOrder(a), KindOrder(b); orderA != orderB {
+		return orderA < orderB
+	}
+	return nameA < nameB
+}
+
+// SortedDefinitions returns the types of s in sorted schema output order,
+// skipping built-in and introspection types. Fields keep their declaration
+// order.
+func SortedDefinitions(s *ast.Schema) ast.DefinitionList {
+	var defs ast.DefinitionList
+	for _, def := range s.Types {
+		if def.BuiltIn || strings.HasPrefix(def.Name, "__") {
+			continue
+		}
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool {
+		return LessDefinition(defs[i].Kind, defs[i].Name, defs[j].Kind, defs[j].Name)
+	})
+	return defs
+}
@@ -0,0 +1,132 @@
+package schema
+
+import (
+	"fmt"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// ConflictStrategy names one of the prebuilt conflict resolvers
+type ConflictStrategy string
+
+const (
+	// ConflictStrategyError fails the merge on the first conflict (default)
+	ConflictStrategyError ConflictStrategy = "error"
+
+	// ConflictStrategyPreferLeft keeps the definition that was merged first
+	ConflictStrategyPreferLeft ConflictStrategy = "preferLeft"
+
+	// ConflictStrategyPreferRight keeps the definition that was merged last
+	ConflictStrategyPreferRight ConflictStrategy = "preferRight"
+
+	// ConflictStrategyUnion combines compatible fields, enum values and union members
+	ConflictStrategyUnion ConflictStrategy = "union"
+)
+
+// Resolver returns the ConflictResolver for the strategy.
+// It returns nil for ConflictStrategyError and unknown strategies, which makes
+// the merger fail on conflict.
+func (s ConflictStrategy) Resolver() ConflictResolver {
+	switch s {
+	case ConflictStrategyPreferLeft:
+		return ResolvePreferLeft
+	case ConflictStrategyPreferRight:
+		return ResolvePreferRight
+	case ConflictStrategyUnion:
+		return ResolveUnionFields
+	default:
+		return nil
+	}
+}
+
+// ResolvePreferLeft resolves a conflict by keeping the existing (left) definition
+func ResolvePreferLeft(left *ast.Definition, right *ast.Definition, conflictType string) (*ast.Definition, error) {
+	return left, nil
+}
+
+// ResolvePreferRight resolves a conflict by keeping the incoming (right) definition
+func ResolvePreferRight(left *ast.Definition, right *ast.Definition, conflictType string) (*ast.Definition, error) {
+	return right, nil
+}
+
+// ResolveUnionFields resolves a conflict by combining both definitions.
+// Objects, interfaces and input objects get the union of their fields (fields
+// present on both sides must have identical types and arguments), enums get
+// the union of their values and unions get the union of their member types.
+// Definitions of different kinds cannot be combined.
+func ResolveUnionFields(left *ast.Definition, right *ast.Definition, conflictType string) (*ast.Definition, error) {
+	if left.Kind != right.Kind {
+		return nil, fmt.Errorf("cannot union %s %q with %s %q", left.Kind, left.Name, right.Kind, right.Name)
+	}
+
+	merged := *left
+
+	switch left.Kind {
+	case ast.Object, ast.Interface, ast.InputObject:
+		fields, err := unionFields(left, right)
+		if err != nil {
+			return nil, err
+		}
+		merged.Fields = fields
+		merged.Interfaces = unionNames(left.Interfaces, right.Interfaces)
+
+	case ast.Enum:
+		values := make(ast.EnumValueList, 0, len(left.EnumValues)+len(right.EnumValues))
+		seen := make(map[string]bool)
+		for _, val := range append(append(ast.EnumValueList{}, left.EnumValues...), right.EnumValues...) {
+			if seen[val.Name] {
+				continue
+			}
+			seen[val.Name] = true
+			values = append(values, val)
+		}
+		merged.EnumValues = values
+
+	case ast.Union:
+		merged.Types = unionNames(left.Types, right.Types)
+	}
+
+	return &merged, nil
+}
+
+// unionFields combines the field lists of two definitions, keeping left order first
+func unionFields(left, right *ast.Definition) (ast.FieldList, error) {
+	fields := make(ast.FieldList, 0, len(left.Fields)+len(right.Fields))
+	fields = append(fields, left.Fields...)
+
+	for _, rightField := range right.Fields {
+		leftField := findField(left.Fields, rightField.Name)
+		if leftField == nil {
+			fields = append(fields, rightField)
+			continue
+		}
+
+		if !typesEqual(leftField.Type, rightField.Type) {
+			return nil, fmt.Errorf("cannot union type %q: field %q has different types: %s vs %s",
+				left.Name, rightField.Name, leftField.Type.String(), rightField.Type.String())
+		}
+		if !argumentsEqual(leftField.Arguments, rightField.Arguments) {
+			return nil, fmt.Errorf("cannot union type %q: field %q has different arguments", left.Name, rightField.Name)
+		}
+	}
+
+	return fields, nil
+}
+
+// unionNames combines two name lists, dropping duplicates and keeping first-seen order
+func unionNames(left, right []string) []string {
+	if len(left) == 0 && len(right) == 0 {
+		return left
+	}
+
+	names := make([]string, 0, len(left)+len(right))
+	seen := make(map[string]bool)
+	for _, name := range append(append([]string{}, left...), right...) {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
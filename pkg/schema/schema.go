@@ -4,14 +4,15 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
-	"strings"
+	"time"
 
 	"github.com/vektah/gqlparser/v2/ast"
 )
 
 // Schema represents a parsed and validated GraphQL schema using gqlparser
 type Schema interface {
-	// Hash returns a unique hash of the schema content
+	// Hash returns the schema's CanonicalHash, which is unchanged by
+	// reformatting the SDL or reordering its types
 	Hash() string
 
 	// Raw returns the underlying gqlparser schema
@@ -40,6 +41,46 @@ type Source struct {
 	Path    string            // File path for file-based schemas
 	URL     string            // URL for remote schemas
 	Headers map[string]string // HTTP headers for remote schemas
+
+	// CacheFile stores the last successfully loaded remote schema as SDL.
+	// It is used when the remote source is unreachable.
+	CacheFile string
+
+	// Timeout overrides the loader's HTTP timeout for this source; zero uses
+	// the loader default
+	Timeout time.Duration
+
+	// Introspection configures how the introspection query is sent
+	Introspection IntrospectionOptions
+
+	// RetryableStatusCodes are the HTTP statuses retried for this source;
+	// empty uses the loader's defaults
+	RetryableStatusCodes []int
+}
+
+// IntrospectionOptions configure the introspection request for gateways
+// that restrict how queries are sent
+type IntrospectionOptions struct {
+	// Method is "GET" or "POST"; empty means POST. GET sends the request as
+	// query string parameters.
+	Method string
+
+	// OperationName is sent with the query, naming its operation, when set
+	OperationName string
+
+	// PersistedQuery sends the query's SHA-256 hash first, as an automatic
+	// persisted query, and the full query only if the server does not know
+	// the hash
+	PersistedQuery bool
+
+	// Variables are sent as the request's variables, for servers that
+	// expect them with introspection
+	Variables map[string]interface{}
+
+	// Body holds extra top-level fields of the request, e.g. a client name
+	// a gateway requires. They cannot replace query, operationName,
+	// variables or extensions. GET sends them as query string parameters.
+	Body map[string]interface{}
 }
 
 // SourceID uniquely identifies a schema source
@@ -69,17 +110,9 @@ type schemaImpl struct {
 
 // NewSchema creates a new Schema from gqlparser AST
 func NewSchema(astSchema *ast.Schema, source string) Schema {
-	// Compute hash from the schema types
-	// Create a string representation for hashing
-	var sb strings.Builder
-	for name := range astSchema.Types {
-		sb.WriteString(name)
-	}
-	hash := sha256.Sum256([]byte(sb.String()))
-
 	return &schemaImpl{
 		schema: astSchema,
-		hash:   hex.EncodeToString(hash[:]),
+		hash:   CanonicalHash(astSchema),
 		source: source,
 	}
 }